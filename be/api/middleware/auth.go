@@ -1,14 +1,41 @@
 package middleware
 
 import (
+	"log"
 	"net/http"
 	"os"
 
+	"milestone3/be/internal/utils"
+
 	"github.com/golang-jwt/jwt/v5"
 	echojwt "github.com/labstack/echo-jwt/v4"
 	"github.com/labstack/echo/v4"
 )
 
+// TokenDenyList tells whether an access token was revoked before it expired
+type TokenDenyList interface {
+	IsDenied(jti string) (bool, error)
+}
+
+var tokenDenyList TokenDenyList
+
+// UseTokenDenyList makes JWTMiddleware reject the access tokens on the deny list, set it before serving
+func UseTokenDenyList(denyList TokenDenyList) {
+	tokenDenyList = denyList
+}
+
+// SuspensionList tells whether an admin suspended the user
+type SuspensionList interface {
+	IsSuspended(userID uint) (bool, error)
+}
+
+var suspensionList SuspensionList
+
+// UseSuspensionList makes JWTMiddleware reject suspended users, set it before serving
+func UseSuspensionList(list SuspensionList) {
+	suspensionList = list
+}
+
 // JWTMiddleware validates JWT and stores claims into context
 func JWTMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
 	jwtSecretKey := os.Getenv("SECRET_KEY")
@@ -29,23 +56,76 @@ func JWTMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
 			return jwtErrorHandler(c, echo.ErrUnauthorized)
 		}
 
+		// tokens without an id cannot be revoked, they predate the deny list
+		jti, ok := claims["jti"].(string)
+		if !ok || jti == "" {
+			return jwtErrorHandler(c, echo.ErrUnauthorized)
+		}
+		if tokenDenyList != nil {
+			denied, err := tokenDenyList.IsDenied(jti)
+			if err != nil {
+				// a revoked token must not get through while the list is unreachable
+				log.Printf("failed checking token deny list: %v", err)
+				return jwtErrorHandler(c, echo.ErrUnauthorized)
+			}
+			if denied {
+				return jwtErrorHandler(c, echo.ErrUnauthorized)
+			}
+		}
+		c.Set("jti", jti)
+		if exp, err := claims.GetExpirationTime(); err == nil && exp != nil {
+			c.Set("token_expires_at", exp.Time)
+		}
+
 		if id, ok := claims["id"].(float64); ok {
+			if suspensionList != nil {
+				suspended, err := suspensionList.IsSuspended(uint(id))
+				if err != nil {
+					log.Printf("failed checking user suspension: %v", err)
+					return jwtErrorHandler(c, echo.ErrUnauthorized)
+				}
+				if suspended {
+					return utils.ForbiddenResponse(c, "account suspended")
+				}
+			}
 			c.Set("user_id", uint(id))
 		}
 		if role, ok := claims["role"].(string); ok {
 			c.Set("role", role)
-			c.Set("is_admin", role == "admin")
-		} else {
-			c.Set("is_admin", false)
 		}
+		// tokens issued before permissions carry none until they are refreshed
+		permissions := []string{}
+		if list, ok := claims["permissions"].([]interface{}); ok {
+			for _, p := range list {
+				if name, ok := p.(string); ok {
+					permissions = append(permissions, name)
+				}
+			}
+		}
+		c.Set("permissions", permissions)
 		if email, ok := claims["email"].(string); ok {
 			c.Set("email", email)
 		}
+		// tokens issued before the claim count as unverified until they are refreshed
+		emailVerified, _ := claims["email_verified"].(bool)
+		c.Set("email_verified", emailVerified)
 
 		return next(c)
 	})
 }
 
+// OptionalJWTMiddleware lets anonymous requests through and validates the token of the others like
+// JWTMiddleware, for public routes that show more to some callers
+func OptionalJWTMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
+	withToken := JWTMiddleware(next)
+	return func(c echo.Context) error {
+		if c.Request().Header.Get(echo.HeaderAuthorization) == "" {
+			return next(c)
+		}
+		return withToken(c)
+	}
+}
+
 func jwtErrorHandler(c echo.Context, err error) error {
 	return c.JSON(http.StatusUnauthorized, map[string]interface{}{
 		"message": "you are unauthorized",
@@ -0,0 +1,222 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"milestone3/be/internal/utils"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeDenyList struct {
+	denied map[string]bool
+	err    error
+}
+
+func (f fakeDenyList) IsDenied(jti string) (bool, error) {
+	return f.denied[jti], f.err
+}
+
+type fakeSuspensionList struct {
+	suspended map[uint]bool
+	err       error
+}
+
+func (f fakeSuspensionList) IsSuspended(userID uint) (bool, error) {
+	return f.suspended[userID], f.err
+}
+
+func TestJWTMiddleware(t *testing.T) {
+	t.Setenv("SECRET_KEY", "test-secret")
+
+	valid, validJTI, err := utils.GenerateJwtToken("test@example.com", "verifier", 1, true, []string{"donation:verify"}, time.Now().Add(time.Minute))
+	assert.NoError(t, err)
+	revoked, revokedJTI, err := utils.GenerateJwtToken("test@example.com", "verifier", 1, true, []string{"donation:verify"}, time.Now().Add(time.Minute))
+	assert.NoError(t, err)
+	// a token issued before the deny list, without a jti
+	legacy, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"id": 1, "role": "donor", "exp": time.Now().Add(time.Minute).Unix(),
+	}).SignedString([]byte("test-secret"))
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name           string
+		token          string
+		denyList       TokenDenyList
+		expectedStatus int
+	}{
+		{name: "valid token", token: valid, denyList: fakeDenyList{denied: map[string]bool{revokedJTI: true}}, expectedStatus: http.StatusOK},
+		{name: "denied token", token: revoked, denyList: fakeDenyList{denied: map[string]bool{revokedJTI: true}}, expectedStatus: http.StatusUnauthorized},
+		{name: "token without jti", token: legacy, denyList: fakeDenyList{}, expectedStatus: http.StatusUnauthorized},
+		{name: "deny list unreachable", token: valid, denyList: fakeDenyList{err: errors.New("redis down")}, expectedStatus: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			UseTokenDenyList(tt.denyList)
+			defer UseTokenDenyList(nil)
+
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set(echo.HeaderAuthorization, "Bearer "+tt.token)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			err := JWTMiddleware(func(c echo.Context) error {
+				assert.Equal(t, validJTI, c.Get("jti"))
+				userID, _ := utils.GetUserID(c)
+				assert.Equal(t, uint(1), userID)
+				_, ok := c.Get("token_expires_at").(time.Time)
+				assert.True(t, ok)
+				assert.True(t, utils.IsEmailVerified(c))
+				assert.Equal(t, "verifier", utils.GetRole(c))
+				assert.Equal(t, []string{"donation:verify"}, utils.GetPermissions(c))
+				return c.NoContent(http.StatusOK)
+			})(c)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+		})
+	}
+}
+
+func TestJWTMiddleware_Suspension(t *testing.T) {
+	t.Setenv("SECRET_KEY", "test-secret")
+
+	token, _, err := utils.GenerateJwtToken("test@example.com", "user", 1, true, []string{}, time.Now().Add(time.Minute))
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name           string
+		suspensions    SuspensionList
+		expectedStatus int
+	}{
+		{name: "active user", suspensions: fakeSuspensionList{suspended: map[uint]bool{2: true}}, expectedStatus: http.StatusOK},
+		{name: "suspended user", suspensions: fakeSuspensionList{suspended: map[uint]bool{1: true}}, expectedStatus: http.StatusForbidden},
+		{name: "suspension list unreachable", suspensions: fakeSuspensionList{err: errors.New("redis down")}, expectedStatus: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			UseSuspensionList(tt.suspensions)
+			defer UseSuspensionList(nil)
+
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set(echo.HeaderAuthorization, "Bearer "+token)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			err := JWTMiddleware(func(c echo.Context) error {
+				return c.NoContent(http.StatusOK)
+			})(c)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+		})
+	}
+}
+
+func TestRequireVerifiedEmail(t *testing.T) {
+	tests := []struct {
+		name           string
+		emailVerified  interface{}
+		expectedStatus int
+	}{
+		{name: "verified", emailVerified: true, expectedStatus: http.StatusOK},
+		{name: "unverified", emailVerified: false, expectedStatus: http.StatusForbidden},
+		{name: "not set", emailVerified: nil, expectedStatus: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			rec := httptest.NewRecorder()
+			c := e.NewContext(httptest.NewRequest(http.MethodPost, "/", nil), rec)
+			if tt.emailVerified != nil {
+				c.Set("email_verified", tt.emailVerified)
+			}
+
+			err := RequireVerifiedEmail(func(c echo.Context) error {
+				return c.NoContent(http.StatusOK)
+			})(c)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+		})
+	}
+}
+
+func TestRequirePermission(t *testing.T) {
+	tests := []struct {
+		name           string
+		permissions    interface{}
+		expectedStatus int
+	}{
+		{name: "granted", permissions: []string{"donation:read_all", "donation:verify"}, expectedStatus: http.StatusOK},
+		{name: "other permission", permissions: []string{"donation:distribute"}, expectedStatus: http.StatusForbidden},
+		{name: "no permission", permissions: []string{}, expectedStatus: http.StatusForbidden},
+		{name: "not set", permissions: nil, expectedStatus: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			rec := httptest.NewRecorder()
+			c := e.NewContext(httptest.NewRequest(http.MethodPatch, "/", nil), rec)
+			if tt.permissions != nil {
+				c.Set("permissions", tt.permissions)
+			}
+
+			err := RequirePermission("donation:verify")(func(c echo.Context) error {
+				return c.NoContent(http.StatusOK)
+			})(c)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+		})
+	}
+}
+
+func TestOptionalJWTMiddleware(t *testing.T) {
+	t.Setenv("SECRET_KEY", "test-secret")
+	token, _, err := utils.GenerateJwtToken("test@example.com", "admin", 1, true, []string{"auction:manage"}, time.Now().Add(time.Minute))
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name            string
+		authorization   string
+		wantPermissions []string
+		expectedStatus  int
+	}{
+		{name: "anonymous", authorization: "", wantPermissions: nil, expectedStatus: http.StatusOK},
+		{name: "valid token", authorization: "Bearer " + token, wantPermissions: []string{"auction:manage"}, expectedStatus: http.StatusOK},
+		{name: "invalid token", authorization: "Bearer not-a-token", expectedStatus: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.authorization != "" {
+				req.Header.Set(echo.HeaderAuthorization, tt.authorization)
+			}
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			err := OptionalJWTMiddleware(func(c echo.Context) error {
+				assert.Equal(t, tt.wantPermissions, utils.GetPermissions(c))
+				return c.NoContent(http.StatusOK)
+			})(c)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+		})
+	}
+}
@@ -0,0 +1,19 @@
+package middleware
+
+import (
+	"milestone3/be/internal/utils"
+
+	"github.com/labstack/echo/v4"
+)
+
+// RequirePermission ensures the role of the request holds permission (permissions set by auth middleware)
+func RequirePermission(permission string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if !utils.HasPermission(c, permission) {
+				return utils.ForbiddenResponse(c, "forbidden")
+			}
+			return next(c)
+		}
+	}
+}
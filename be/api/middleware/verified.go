@@ -0,0 +1,17 @@
+package middleware
+
+import (
+	"milestone3/be/internal/utils"
+
+	"github.com/labstack/echo/v4"
+)
+
+// RequireVerifiedEmail ensures the user verified their email (email_verified set by auth middleware)
+func RequireVerifiedEmail(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if !utils.IsEmailVerified(c) {
+			return utils.ForbiddenResponse(c, "verify your email first")
+		}
+		return next(c)
+	}
+}
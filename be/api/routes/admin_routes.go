@@ -3,6 +3,7 @@ package routes
 import (
 	"milestone3/be/api/middleware"
 	"milestone3/be/internal/controller"
+	"milestone3/be/internal/entity"
 )
 
 func (r *EchoRouter) RegisterAdminRoutes(adminCtrl *controller.AdminController) {
@@ -11,6 +12,24 @@ func (r *EchoRouter) RegisterAdminRoutes(adminCtrl *controller.AdminController)
 	adminRoutes.Use(middleware.LoggingMiddleware)
 
 	//admin endpoint
-	adminRoutes.GET("/dashboard", adminCtrl.AdminDashboard)
+	adminRoutes.GET("/dashboard", adminCtrl.AdminDashboard, middleware.RequirePermission(entity.PermReportRead))
 	// adminRoutes.GET("/reports", adminCtrl.AdminReport)
-}
\ No newline at end of file
+}
+
+func (r *EchoRouter) RegisterReconciliationRoutes(reconciliationCtrl *controller.ReconciliationController) {
+	reportRoutes := r.echo.Group("/admin/reports")
+	reportRoutes.Use(middleware.JWTMiddleware)
+	reportRoutes.Use(middleware.LoggingMiddleware)
+
+	reportRoutes.GET("/reconciliation", reconciliationCtrl.GetReport, middleware.RequirePermission(entity.PermReportRead))
+}
+
+func (r *EchoRouter) RegisterRoleRoutes(roleCtrl *controller.RoleController) {
+	roleRoutes := r.echo.Group("/admin")
+	roleRoutes.Use(middleware.JWTMiddleware)
+	roleRoutes.Use(middleware.LoggingMiddleware)
+	roleRoutes.Use(middleware.RequirePermission(entity.PermRoleAssign))
+
+	roleRoutes.GET("/roles", roleCtrl.ListRoles)
+	roleRoutes.PUT("/users/:id/role", roleCtrl.AssignRole)
+}
@@ -3,28 +3,41 @@ package routes
 import (
 	"milestone3/be/api/middleware"
 	"milestone3/be/internal/controller"
+	"milestone3/be/internal/entity"
 )
 
 func (r *EchoRouter) RegisterAuctionRoutes(auctionCtrl *controller.AuctionController) {
 	g := r.echo.Group("/auction/items")
-	g.Use(middleware.JWTMiddleware)
 	g.Use(middleware.LoggingMiddleware)
 
-	g.GET("", auctionCtrl.GetAllAuctionItems)
-	g.GET("/:id", auctionCtrl.GetAuctionItemByID)
-	g.POST("", auctionCtrl.CreateAuctionItem)
-	g.PATCH("/:id", auctionCtrl.UpdateAuctionItem)
-	g.DELETE("/:id", auctionCtrl.DeleteAuctionItem)
+	// public, drafts only for auction:manage
+	g.GET("", auctionCtrl.GetAllAuctionItems, middleware.OptionalJWTMiddleware)
+	g.GET("/:id", auctionCtrl.GetAuctionItemByID, middleware.OptionalJWTMiddleware)
+
+	// auction:manage only
+	admin := g.Group("")
+	admin.Use(middleware.JWTMiddleware)
+	admin.Use(middleware.RequirePermission(entity.PermAuctionManage))
+
+	admin.POST("", auctionCtrl.CreateAuctionItem)
+	admin.PATCH("/:id", auctionCtrl.UpdateAuctionItem)
+	admin.DELETE("/:id", auctionCtrl.DeleteAuctionItem)
 }
 
 func (r *EchoRouter) RegisterAuctionSessionRoutes(sessionCtrl *controller.AuctionSessionController) {
 	g := r.echo.Group("/auction/sessions")
-	g.Use(middleware.JWTMiddleware)
 	g.Use(middleware.LoggingMiddleware)
 
+	// public
 	g.GET("", sessionCtrl.GetAllAuctionSessions)
 	g.GET("/:id", sessionCtrl.GetAuctionSessionByID)
-	g.POST("", sessionCtrl.CreateAuctionSession)
-	g.PUT("/:id", sessionCtrl.UpdateAuctionSession)
-	g.DELETE("/:id", sessionCtrl.DeleteAuctionSession)
+
+	// auction:manage only
+	admin := g.Group("")
+	admin.Use(middleware.JWTMiddleware)
+	admin.Use(middleware.RequirePermission(entity.PermAuctionManage))
+
+	admin.POST("", sessionCtrl.CreateAuctionSession)
+	admin.PUT("/:id", sessionCtrl.UpdateAuctionSession)
+	admin.DELETE("/:id", sessionCtrl.DeleteAuctionSession)
 }
@@ -0,0 +1,191 @@
+package routes
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"milestone3/be/internal/controller"
+	"milestone3/be/internal/dto"
+	"milestone3/be/internal/entity"
+	"milestone3/be/internal/mocks"
+	"milestone3/be/internal/service"
+	"milestone3/be/internal/utils"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+// roleStatus is the status each kind of caller gets from an endpoint
+type roleStatus struct {
+	anonymous  int
+	unverified int
+	user       int
+	verifier   int
+	admin      int
+}
+
+// newAuctionTestServer registers the auction, session and bid routes on services that answer
+// whatever reaches them, so a status only tells who the middleware let through
+func newAuctionTestServer(t *testing.T) *echo.Echo {
+	ctrl := gomock.NewController(t)
+
+	items := mocks.NewMockAuctionItemService(ctrl)
+	items.EXPECT().GetAll(gomock.Any()).Return([]dto.AuctionItemDTO{}, nil).AnyTimes()
+	// item 2 is a draft, only found for callers allowed to see drafts
+	items.EXPECT().GetByID(gomock.Any(), gomock.Any()).DoAndReturn(func(id int64, includeDrafts bool) (dto.AuctionItemDTO, error) {
+		if id == 2 && !includeDrafts {
+			return dto.AuctionItemDTO{}, service.ErrAuctionNotFoundID
+		}
+		return dto.AuctionItemDTO{ID: id}, nil
+	}).AnyTimes()
+	items.EXPECT().Create(gomock.Any()).Return(dto.AuctionItemDTO{ID: 1}, nil).AnyTimes()
+	items.EXPECT().Update(gomock.Any(), gomock.Any()).Return(dto.AuctionItemDTO{ID: 1}, nil).AnyTimes()
+	items.EXPECT().Delete(gomock.Any()).Return(nil).AnyTimes()
+
+	sessions := mocks.NewMockAuctionSessionService(ctrl)
+	session := dto.AuctionSessionDTO{ID: 1, Name: "Weekly", StartTime: time.Now(), EndTime: time.Now().Add(time.Hour)}
+	sessions.EXPECT().GetAll().Return([]dto.AuctionSessionDTO{session}, nil).AnyTimes()
+	sessions.EXPECT().GetByID(gomock.Any()).Return(session, nil).AnyTimes()
+	sessions.EXPECT().Create(gomock.Any()).Return(session, nil).AnyTimes()
+	sessions.EXPECT().Update(gomock.Any(), gomock.Any()).Return(session, nil).AnyTimes()
+	sessions.EXPECT().Delete(gomock.Any()).Return(nil).AnyTimes()
+
+	bids := mocks.NewMockBidService(ctrl)
+	bids.EXPECT().PlaceBid(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	bids.EXPECT().RegisterProxyBid(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	bids.EXPECT().GetProxyBid(gomock.Any(), gomock.Any(), gomock.Any()).Return(50000.0, nil).AnyTimes()
+	bids.EXPECT().GetHighestBid(gomock.Any(), gomock.Any()).Return(dto.HighestBidDTO{SessionID: 1, ItemID: 1}, nil).AnyTimes()
+	bids.EXPECT().GetBidHistory(gomock.Any(), gomock.Any(), gomock.Any()).Return([]dto.BidHistoryDTO{}, int64(0), nil).AnyTimes()
+	bids.EXPECT().GetIncrementLadder(gomock.Any(), gomock.Any()).Return([]dto.BidIncrementTierDTO{}, nil).AnyTimes()
+	bids.EXPECT().SetIncrementLadder(gomock.Any(), gomock.Any(), gomock.Any()).Return([]dto.BidIncrementTierDTO{}, nil).AnyTimes()
+	bids.EXPECT().SubscribeBidEvents(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ interface{}, _, _ int64) (<-chan dto.BidEventDTO, error) {
+			// closed right away so the stream ends after the snapshot
+			events := make(chan dto.BidEventDTO)
+			close(events)
+			return events, nil
+		}).AnyTimes()
+
+	validate := validator.New()
+	e := echo.New()
+	router := NewRouter(e)
+	router.RegisterAuctionRoutes(controller.NewAuctionController(items, validate))
+	router.RegisterAuctionSessionRoutes(controller.NewAuctionSessionController(sessions, validate))
+	router.RegisterBidRoutes(controller.NewBidController(bids, sessions, validate))
+	return e
+}
+
+func TestAuctionRoutes_RoleAccess(t *testing.T) {
+	t.Setenv("SECRET_KEY", "test-secret")
+	e := newAuctionTestServer(t)
+
+	token := func(role string, emailVerified bool, permissions []string) string {
+		signed, _, err := utils.GenerateJwtToken(role+"@example.com", role, 2, emailVerified, permissions, time.Now().Add(time.Minute))
+		assert.NoError(t, err)
+		return signed
+	}
+	tokens := map[string]string{
+		"anonymous":  "",
+		"unverified": token(entity.RoleUser, false, []string{}),
+		"user":       token(entity.RoleUser, true, []string{}),
+		"verifier":   token(entity.RoleVerifier, true, []string{entity.PermDonationReadAll, entity.PermDonationVerify}),
+		"admin":      token(entity.RoleAdmin, true, []string{entity.PermAuctionManage, entity.PermDonationVerify}),
+	}
+
+	public := roleStatus{http.StatusOK, http.StatusOK, http.StatusOK, http.StatusOK, http.StatusOK}
+	bidder := roleStatus{http.StatusUnauthorized, http.StatusOK, http.StatusOK, http.StatusOK, http.StatusOK}
+	verifiedBidder := roleStatus{http.StatusUnauthorized, http.StatusForbidden, http.StatusOK, http.StatusOK, http.StatusOK}
+	admin := roleStatus{http.StatusUnauthorized, http.StatusForbidden, http.StatusForbidden, http.StatusForbidden, http.StatusOK}
+	draft := roleStatus{http.StatusNotFound, http.StatusNotFound, http.StatusNotFound, http.StatusNotFound, http.StatusOK}
+	adminCreate := admin
+	adminCreate.admin = http.StatusCreated
+
+	itemBody := `{"title":"Laptop","description":"14 inch","category":"Electronics","donation_id":1}`
+	sessionBody := `{"name":"Weekly","start_time":"2030-01-01T10:00:00Z","end_time":"2030-01-01T12:00:00Z"}`
+	ladderBody := `{"tiers":[{"min_price":0,"increment":5000}]}`
+
+	tests := []struct {
+		method string
+		path   string
+		body   string
+		want   roleStatus
+	}{
+		// auction items
+		{http.MethodGet, "/auction/items", "", public},
+		{http.MethodGet, "/auction/items/1", "", public},
+		{http.MethodGet, "/auction/items/2", "", draft},
+		{http.MethodPost, "/auction/items", itemBody, adminCreate},
+		{http.MethodPatch, "/auction/items/1", `{"title":"Laptop 14"}`, admin},
+		{http.MethodDelete, "/auction/items/1", "", admin},
+		{http.MethodGet, "/auction/items/1/bids", "", bidder},
+		{http.MethodGet, "/auction/items/1/increments", "", public},
+		{http.MethodPut, "/auction/items/1/increments", ladderBody, admin},
+
+		// auction sessions
+		{http.MethodGet, "/auction/sessions", "", public},
+		{http.MethodGet, "/auction/sessions/1", "", public},
+		{http.MethodPost, "/auction/sessions", sessionBody, adminCreate},
+		{http.MethodPut, "/auction/sessions/1", sessionBody, admin},
+		{http.MethodDelete, "/auction/sessions/1", "", admin},
+		{http.MethodGet, "/auction/sessions/1/increments", "", public},
+		{http.MethodPut, "/auction/sessions/1/increments", ladderBody, admin},
+
+		// bidding
+		{http.MethodGet, "/auction/sessions/1/items/1/highest-bid", "", public},
+		{http.MethodPost, "/auction/sessions/1/items/1/bid", `{"amount":60000}`, verifiedBidder},
+		{http.MethodPost, "/auction/sessions/1/items/1/proxy-bid", `{"max_amount":90000}`, verifiedBidder},
+		{http.MethodGet, "/auction/sessions/1/items/1/proxy-bid", "", bidder},
+		{http.MethodGet, "/auction/sessions/1/items/1/stream", "", public},
+
+		// global increment ladder
+		{http.MethodGet, "/auction/increments", "", public},
+		{http.MethodPut, "/auction/increments", ladderBody, admin},
+	}
+
+	for _, tt := range tests {
+		for _, role := range []struct {
+			name string
+			want int
+		}{
+			{"anonymous", tt.want.anonymous},
+			{"unverified", tt.want.unverified},
+			{"user", tt.want.user},
+			{"verifier", tt.want.verifier},
+			{"admin", tt.want.admin},
+		} {
+			t.Run(tt.method+" "+tt.path+" as "+role.name, func(t *testing.T) {
+				req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+				req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+				if tokens[role.name] != "" {
+					req.Header.Set(echo.HeaderAuthorization, "Bearer "+tokens[role.name])
+				}
+				rec := httptest.NewRecorder()
+
+				e.ServeHTTP(rec, req)
+
+				assert.Equal(t, role.want, rec.Code, rec.Body.String())
+			})
+		}
+	}
+}
+
+func TestBidRoutes_StreamWithoutAuthorizationHeader(t *testing.T) {
+	t.Setenv("SECRET_KEY", "test-secret")
+	e := newAuctionTestServer(t)
+
+	// what a browser EventSource sends: no Authorization header, only Accept
+	req := httptest.NewRequest(http.MethodGet, "/auction/sessions/1/items/1/stream", nil)
+	req.Header.Set("Accept", "text/event-stream")
+	rec := httptest.NewRecorder()
+
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "text/event-stream", rec.Header().Get(echo.HeaderContentType))
+	assert.Contains(t, rec.Body.String(), "event: snapshot")
+}
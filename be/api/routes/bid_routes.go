@@ -3,14 +3,63 @@ package routes
 import (
 	"milestone3/be/api/middleware"
 	"milestone3/be/internal/controller"
+	"milestone3/be/internal/entity"
 )
 
 func (r *EchoRouter) RegisterBidRoutes(bidCtrl *controller.BidController) {
 	g := r.echo.Group("/auction/sessions")
-
-	g.Use(middleware.JWTMiddleware)
 	g.Use(middleware.LoggingMiddleware)
 
-	g.POST("/:sessionID/items/:itemID/bid", bidCtrl.PlaceBid)
+	// public
 	g.GET("/:sessionID/items/:itemID/highest-bid", bidCtrl.GetHighestBid)
+	g.GET("/:sessionID/increments", bidCtrl.GetIncrementLadder)
+	// EventSource cannot send an Authorization header, the stream carries no more than highest-bid
+	g.GET("/:sessionID/items/:itemID/stream", bidCtrl.StreamBids)
+
+	// bidders, placing a bid needs a verified email
+	bidder := g.Group("")
+	bidder.Use(middleware.JWTMiddleware)
+
+	bidder.POST("/:sessionID/items/:itemID/bid", bidCtrl.PlaceBid, middleware.RequireVerifiedEmail)
+	bidder.POST("/:sessionID/items/:itemID/proxy-bid", bidCtrl.RegisterProxyBid, middleware.RequireVerifiedEmail)
+	bidder.GET("/:sessionID/items/:itemID/proxy-bid", bidCtrl.GetProxyBid)
+
+	// auction:manage only
+	admin := g.Group("")
+	admin.Use(middleware.JWTMiddleware)
+	admin.Use(middleware.RequirePermission(entity.PermAuctionManage))
+
+	admin.PUT("/:sessionID/increments", bidCtrl.SetIncrementLadder)
+
+	items := r.echo.Group("/auction/items")
+	items.Use(middleware.LoggingMiddleware)
+
+	// public
+	items.GET("/:id/increments", bidCtrl.GetIncrementLadder)
+
+	// bidders
+	itemBidder := items.Group("")
+	itemBidder.Use(middleware.JWTMiddleware)
+
+	itemBidder.GET("/:id/bids", bidCtrl.GetBidHistory)
+
+	// auction:manage only
+	itemAdmin := items.Group("")
+	itemAdmin.Use(middleware.JWTMiddleware)
+	itemAdmin.Use(middleware.RequirePermission(entity.PermAuctionManage))
+
+	itemAdmin.PUT("/:id/increments", bidCtrl.SetIncrementLadder)
+
+	increments := r.echo.Group("/auction/increments")
+	increments.Use(middleware.LoggingMiddleware)
+
+	// public
+	increments.GET("", bidCtrl.GetIncrementLadder)
+
+	// auction:manage only
+	incrementAdmin := increments.Group("")
+	incrementAdmin.Use(middleware.JWTMiddleware)
+	incrementAdmin.Use(middleware.RequirePermission(entity.PermAuctionManage))
+
+	incrementAdmin.PUT("", bidCtrl.SetIncrementLadder)
 }
@@ -11,8 +11,11 @@ func (r *EchoRouter) RegisterDonationRoutes(donationCtrl *controller.DonationCon
 
 	donationRoutes.GET("", donationCtrl.GetAllDonations)
 	donationRoutes.GET("/:id", donationCtrl.GetDonationByID)
-	donationRoutes.POST("", donationCtrl.CreateDonation)
+	donationRoutes.GET("/:id/history", donationCtrl.GetDonationHistory)
+	donationRoutes.POST("", donationCtrl.CreateDonation, middleware.RequireVerifiedEmail)
 	donationRoutes.PUT("/:id", donationCtrl.UpdateDonation)
 	donationRoutes.PATCH("/:id", donationCtrl.PatchDonation)
 	donationRoutes.DELETE("/:id", donationCtrl.DeleteDonation)
+	donationRoutes.POST("/:id/photos", donationCtrl.AddDonationPhotos)
+	donationRoutes.DELETE("/:id/photos/:photoId", donationCtrl.RemoveDonationPhoto)
 }
@@ -0,0 +1,13 @@
+package routes
+
+import (
+	"milestone3/be/internal/controller"
+)
+
+// RegisterFileRoutes serves the files of the local storage backend, access to private files is
+// checked against the signature of the URL, so the routes need no token
+func (r *EchoRouter) RegisterFileRoutes(fileCtrl *controller.FileController) {
+	fileRoutes := r.echo.Group("/files")
+
+	fileRoutes.GET("/:bucket/*", fileCtrl.ServeFile)
+}
@@ -3,16 +3,22 @@ package routes
 import (
 	"milestone3/be/api/middleware"
 	"milestone3/be/internal/controller"
+	"milestone3/be/internal/entity"
 )
 
 func (r *EchoRouter) RegisterPaymentRoutes(paymentCtrl *controller.PaymentController) {
+	// public, the gateway authenticates itself with the notification signature
+	r.echo.POST("/payments/notifications", paymentCtrl.HandleNotification)
+
 	paymentRoutes := r.echo.Group("/payments")
 	paymentRoutes.Use(middleware.JWTMiddleware)
 	paymentRoutes.Use(middleware.LoggingMiddleware)
 
 	//payment endpoint
 	paymentRoutes.POST("/:auctionId", paymentCtrl.CreatePayment)
-	paymentRoutes.GET("/status/:id", paymentCtrl.CheckPaymentStatusMidtrans)
+	paymentRoutes.GET("/status/:id", paymentCtrl.CheckPaymentStatus)
 	paymentRoutes.GET("/:id", paymentCtrl.GetPaymentById)
+	paymentRoutes.POST("/:id/refunds", paymentCtrl.RefundPayment, middleware.RequirePermission(entity.PermPaymentRefund))
+	paymentRoutes.GET("/:id/refunds", paymentCtrl.GetRefunds, middleware.RequirePermission(entity.PermPaymentRefund))
 	paymentRoutes.GET("", paymentCtrl.GetAllPayment)
 }
\ No newline at end of file
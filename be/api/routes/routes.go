@@ -16,8 +16,11 @@ type Router interface {
 	// RegisterAuthRoutes(authCtrl *controller.AuthController)
 	RegisterAuctionRoutes(auctionCtrl *controller.AuctionController)
 	RegisterAdminRoutes(adminCtrl *controller.AdminController)
+	RegisterReconciliationRoutes(reconciliationCtrl *controller.ReconciliationController)
+	RegisterRoleRoutes(roleCtrl *controller.RoleController)
 	RegisterAuctionSessionRoutes(sessionCtrl *controller.AuctionSessionController)
 	RegisterBidRoutes(bidCtrl *controller.BidController)
+	RegisterFileRoutes(fileCtrl *controller.FileController)
 }
 
 type EchoRouter struct {
@@ -3,6 +3,7 @@ package routes
 import (
 	"milestone3/be/api/middleware"
 	"milestone3/be/internal/controller"
+	"milestone3/be/internal/entity"
 )
 
 func (r *EchoRouter) RegisterUserRoutes(userCtrl *controller.UserController) {
@@ -12,4 +13,33 @@ func (r *EchoRouter) RegisterUserRoutes(userCtrl *controller.UserController) {
 	// auth endpoint
 	userRoutes.POST("/register", userCtrl.CreateUser)
 	userRoutes.POST("/login", userCtrl.LoginUser)
+	userRoutes.POST("/refresh", userCtrl.RefreshToken)
+	userRoutes.POST("/logout", userCtrl.Logout, middleware.JWTMiddleware)
+
+	// email verification and password reset
+	userRoutes.GET("/verify-email", userCtrl.VerifyEmail)
+	userRoutes.POST("/verify-email", userCtrl.VerifyEmail)
+	userRoutes.POST("/resend-verification", userCtrl.ResendVerificationEmail)
+	userRoutes.POST("/forgot-password", userCtrl.ForgotPassword)
+	userRoutes.POST("/reset-password", userCtrl.ResetPassword)
+
+	// profile and account of the signed in user
+	me := r.echo.Group("/users/me")
+	me.Use(middleware.JWTMiddleware)
+	me.Use(middleware.LoggingMiddleware)
+
+	me.GET("", userCtrl.GetMe)
+	me.PATCH("", userCtrl.UpdateMe)
+	me.PUT("/password", userCtrl.ChangePassword)
+	me.DELETE("", userCtrl.DeleteMe)
+
+	// user:manage only
+	admin := r.echo.Group("/admin/users")
+	admin.Use(middleware.JWTMiddleware)
+	admin.Use(middleware.LoggingMiddleware)
+	admin.Use(middleware.RequirePermission(entity.PermUserManage))
+
+	admin.GET("", userCtrl.ListUsers)
+	admin.POST("/:id/suspend", userCtrl.SuspendUser)
+	admin.DELETE("/:id/suspend", userCtrl.UnsuspendUser)
 }
@@ -33,10 +33,12 @@ import (
 	"github.com/labstack/echo/v4"
 	"github.com/swaggo/echo-swagger"
 
+	"milestone3/be/api/middleware"
 	"milestone3/be/api/routes"
 	"milestone3/be/config"
 	"milestone3/be/internal/controller"
 	scheduler "milestone3/be/internal/cron"
+	"milestone3/be/internal/entity"
 	"milestone3/be/internal/repository"
 	"milestone3/be/internal/service"
 	_ "milestone3/be/docs" // swagger docs
@@ -50,36 +52,15 @@ func main() {
 	db := config.ConnectionDb()
 	validate := validator.New()
 
-	// GCP PUBLIC BUCKET
-	var gcpPublicRepo repository.GCPStorageRepo
-	publicBucket := os.Getenv("PUBLIC_BUCKET")
-
-	if publicBucket != "" {
-		client, err := storage.NewClient(ctx)
-		if err != nil {
-			log.Fatalf("failed to create public gcs client: %v", err)
-		}
-		gcpPublicRepo = repository.NewGCPStorageRepo(client, publicBucket, true)
-	} else {
-		log.Println("PUBLIC_BUCKET NOT SET")
-	}
-
-	// GCP PRIVATE BUCKET
-	var gcpPrivateRepo repository.GCPStorageRepo
-	privateBucket := os.Getenv("PRIVATE_BUCKET")
-
-	if privateBucket != "" {
-		client, err := storage.NewClient(ctx)
-		if err != nil {
-			log.Fatalf("failed to create private gcs client: %v", err)
-		}
-		gcpPrivateRepo = repository.NewGCPStorageRepo(client, privateBucket, false)
-	} else {
-		log.Println("PRIVATE_BUCKET NOT SET")
-	}
+	// object storage (gcs, s3 or the local filesystem)
+	storageCfg := config.LoadStorageConfig()
+	publicStore, privateStore, fileCtrl := newObjectStores(ctx, storageCfg)
 
 	// repositories
 	userRepo := repository.NewUserRepo(db, ctx)
+	refreshTokenRepo := repository.NewRefreshTokenRepo(db, ctx)
+	userTokenRepo := repository.NewUserTokenRepo(db, ctx)
+	roleRepo := repository.NewRoleRepo(db, ctx)
 	articleRepo := repository.NewArticleRepo(db)
 	donationRepo := repository.NewDonationRepo(db)
 	finalDonationRepo := repository.NewFinalDonationRepository(db)
@@ -88,43 +69,70 @@ func main() {
 	auctionItemRepo := repository.NewAuctionItemRepository(db)
 	auctionSessionRepo := repository.NewAuctionSessionRepository(db)
 	bidRepo := repository.NewBidRepository(db)
+	bidIncrementRepo := repository.NewBidIncrementRepository(db)
+	outboxRepo := repository.NewOutboxRepository(db)
+	paymentDeadlineRepo := repository.NewPaymentDeadlineRepository(db)
+	paymentReconciliationRepo := repository.NewPaymentReconciliationRepository(db)
 	redisClient := config.ConnectRedis(ctx)
 	redisRepo := repository.NewBidRedisRepository(redisClient, ctx)
+	bidEventRepo := repository.NewBidEventRepository(redisClient, ctx)
+	tokenDenyListRepo := repository.NewTokenDenyListRepo(redisClient, ctx)
+	userSuspensionRepo := repository.NewUserSuspensionRepo(redisClient, ctx)
 	aiRepo := repository.NewAIRepository(logger, os.Getenv("GEMINI_API_KEY"))
+	paymentGatewayCfg := config.LoadPaymentGatewayConfig()
+	var paymentGateway service.PaymentGateway = repository.NewMidtransGateway(paymentGatewayCfg.ServerKey, paymentGatewayCfg.Environment == config.MidtransProduction)
+	if paymentGatewayCfg.Provider == config.PaymentGatewayFake {
+		paymentGateway = repository.NewFakeGateway(paymentGatewayCfg.ServerKey)
+	}
+
+	mailCfg := config.LoadMailConfig()
+	var mailer service.Mailer = repository.NewSMTPMailer(mailCfg.SMTPHost, mailCfg.SMTPPort, mailCfg.SMTPUsername, mailCfg.SMTPPassword, mailCfg.From)
+	if mailCfg.Mailer == config.MailerLog {
+		log.Printf("writing emails to %s instead of sending them", mailCfg.LogDir)
+		mailer = repository.NewLogMailer(mailCfg.LogDir, mailCfg.From)
+	}
 
 	// services
-	userSvc := service.NewUserService(userRepo)
+	userSvc := service.NewUserService(userRepo, refreshTokenRepo, userTokenRepo, tokenDenyListRepo, userSuspensionRepo, mailer, privateStore, config.LoadAuthConfig())
+	roleSvc := service.NewRoleService(roleRepo, refreshTokenRepo, tokenDenyListRepo)
 	articleSvc := service.NewArticleService(articleRepo)
-	donationSvc := service.NewDonationService(donationRepo, gcpPrivateRepo)
+	donationSvc := service.NewDonationService(donationRepo, privateStore, aiRepo)
 	finalDonationSvc := service.NewFinalDonationService(finalDonationRepo, donationRepo)
-	paymentSvc := service.NewPaymentService(paymentRepo)
+	paymentDeadlineCfg := config.LoadPaymentDeadlineConfig()
+	paymentSvc := service.NewPaymentService(paymentRepo, paymentGateway, paymentDeadlineCfg.Deadline)
 	adminSvc := service.NewAdminService(adminRepo)
-	auctionSvc := service.NewAuctionItemService(auctionItemRepo, aiRepo, logger)
+	auctionSvc := service.NewAuctionItemService(auctionItemRepo, aiRepo, bidEventRepo, privateStore, logger)
 	auctionSessionSvc := service.NewAuctionSessionService(auctionSessionRepo, logger)
-	bidSvc := service.NewBidService(redisRepo, bidRepo, auctionItemRepo, auctionSessionRepo, logger)
+	bidSvc := service.NewBidService(redisRepo, bidRepo, auctionItemRepo, auctionSessionRepo, bidEventRepo, bidIncrementRepo, config.LoadSoftCloseConfig(), logger)
+
+	outboxSvc := service.NewOutboxService(outboxRepo, map[string]service.OutboxHandler{
+		entity.OutboxItemWon:             paymentSvc.OpenWinnerPayment,
+		entity.OutboxSecondChanceOffered: paymentSvc.OpenWinnerPayment,
+	}, logger)
+	paymentDeadlineSvc := service.NewPaymentDeadlineService(paymentDeadlineRepo, auctionItemRepo, paymentDeadlineCfg, logger)
+	paymentReconciliationSvc := service.NewPaymentReconciliationService(paymentReconciliationRepo, paymentGateway, logger)
 
-	// bid scheduler (now also handles auction auto-start)
-	bidScheduler := scheduler.NewBidScheduler(bidSvc, auctionSvc, logger)
+	// bid scheduler (now also handles auction auto-start and the outbox)
+	bidScheduler := scheduler.NewBidScheduler(bidSvc, auctionSvc, outboxSvc, paymentDeadlineSvc, paymentReconciliationSvc, logger)
 	bidScheduler.Start()
 
 	// controllers
 	userCtrl := controller.NewUserController(validate, userSvc)
 	adminCtrl := controller.NewAdminController(adminSvc)
-	articleCtrl := controller.NewArticleController(articleSvc, gcpPublicRepo)
+	roleCtrl := controller.NewRoleController(validate, roleSvc)
+	articleCtrl := controller.NewArticleController(articleSvc, publicStore)
 
-	var donationCtrl *controller.DonationController
-	if gcpPrivateRepo != nil {
-		donationCtrl = controller.NewDonationController(donationSvc, gcpPrivateRepo)
-	} else {
-		donationCtrl = controller.NewDonationController(donationSvc, nil)
-	}
+	donationCtrl := controller.NewDonationController(donationSvc, privateStore)
 	finalDonationCtrl := controller.NewFinalDonationController(finalDonationSvc)
 	paymentCtrl := controller.NewPaymentController(validate, paymentSvc)
+	reconciliationCtrl := controller.NewReconciliationController(paymentReconciliationSvc)
 	auctionCtrl := controller.NewAuctionController(auctionSvc, validate)
 	auctionSessionCtrl := controller.NewAuctionSessionController(auctionSessionSvc, validate)
 	bidCtrl := controller.NewBidController(bidSvc, auctionSessionSvc, validate)
 
 	// echo + router
+	middleware.UseTokenDenyList(tokenDenyListRepo)
+	middleware.UseSuspensionList(userSuspensionRepo)
 	e := echo.New()
 	router := routes.NewRouter(e)
 
@@ -137,9 +145,14 @@ func main() {
 	router.RegisterFinalDonationRoutes(finalDonationCtrl)
 	router.RegisterPaymentRoutes(paymentCtrl)
 	router.RegisterAdminRoutes(adminCtrl)
+	router.RegisterReconciliationRoutes(reconciliationCtrl)
+	router.RegisterRoleRoutes(roleCtrl)
 	router.RegisterAuctionRoutes(auctionCtrl)
 	router.RegisterAuctionSessionRoutes(auctionSessionCtrl)
 	router.RegisterBidRoutes(bidCtrl)
+	if fileCtrl != nil {
+		router.RegisterFileRoutes(fileCtrl)
+	}
 
 	port := os.Getenv("PORT")
 	if port == "" {
@@ -149,3 +162,39 @@ func main() {
 		log.Fatalf("failed to start server: %v", err)
 	}
 }
+
+// newObjectStores opens the public and private buckets of the configured backend. the file
+// controller is only set for the local backend, whose files the app serves itself
+func newObjectStores(ctx context.Context, cfg config.StorageConfig) (repository.ObjectStore, repository.ObjectStore, *controller.FileController) {
+	switch cfg.Backend {
+	case config.StorageGCS:
+		client, err := storage.NewClient(ctx)
+		if err != nil {
+			log.Fatalf("failed to create gcs client: %v", err)
+		}
+		return repository.NewGCSObjectStore(client, cfg.PublicBucket, true), repository.NewGCSObjectStore(client, cfg.PrivateBucket, false), nil
+
+	case config.StorageS3:
+		s3Cfg := repository.S3Config{
+			Endpoint:  cfg.S3Endpoint,
+			Region:    cfg.S3Region,
+			AccessKey: cfg.S3AccessKey,
+			SecretKey: cfg.S3SecretKey,
+			PathStyle: cfg.S3PathStyle,
+		}
+		publicStore, err := repository.NewS3ObjectStore(s3Cfg, cfg.PublicBucket, true)
+		if err != nil {
+			log.Fatalf("failed to create s3 public store: %v", err)
+		}
+		privateStore, err := repository.NewS3ObjectStore(s3Cfg, cfg.PrivateBucket, false)
+		if err != nil {
+			log.Fatalf("failed to create s3 private store: %v", err)
+		}
+		return publicStore, privateStore, nil
+	}
+
+	log.Printf("storing files on the local filesystem in %s", cfg.LocalDir)
+	publicStore := repository.NewLocalObjectStore(cfg.LocalDir, cfg.PublicBucket, cfg.BaseURL, []byte(cfg.SigningKey), true)
+	privateStore := repository.NewLocalObjectStore(cfg.LocalDir, cfg.PrivateBucket, cfg.BaseURL, []byte(cfg.SigningKey), false)
+	return publicStore, privateStore, controller.NewFileController(publicStore, privateStore)
+}
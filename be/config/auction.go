@@ -0,0 +1,89 @@
+package config
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"time"
+)
+
+const (
+	SoftCloseScopeItem    = "item"
+	SoftCloseScopeSession = "session"
+
+	WinnerDefaultSecondChance = "second_chance"
+	WinnerDefaultRelist       = "relist"
+)
+
+// SoftCloseConfig controls anti-sniping: a bid placed within Window of the end
+// pushes the end time of the item, or of the whole session, back by Extension.
+// A zero Window disables it.
+type SoftCloseConfig struct {
+	Window    time.Duration
+	Extension time.Duration
+	Scope     string
+}
+
+func LoadSoftCloseConfig() SoftCloseConfig {
+	cfg := SoftCloseConfig{
+		Window:    envMinutes("SOFT_CLOSE_WINDOW_MINUTES", 2),
+		Extension: envMinutes("SOFT_CLOSE_EXTENSION_MINUTES", 2),
+		Scope:     os.Getenv("SOFT_CLOSE_SCOPE"),
+	}
+
+	if cfg.Scope == "" {
+		cfg.Scope = SoftCloseScopeItem
+	}
+	if cfg.Scope != SoftCloseScopeItem && cfg.Scope != SoftCloseScopeSession {
+		log.Fatalf("invalid SOFT_CLOSE_SCOPE %q, expected %q or %q", cfg.Scope, SoftCloseScopeItem, SoftCloseScopeSession)
+	}
+
+	return cfg
+}
+
+// PaymentDeadlineConfig controls what happens when a winner does not pay within Deadline.
+// With the second chance policy the next-highest bidder is offered the item, up to
+// MaxSecondChances offers, after that or with the relist policy the item goes back to scheduled.
+type PaymentDeadlineConfig struct {
+	Deadline         time.Duration
+	Policy           string
+	MaxSecondChances int
+}
+
+func LoadPaymentDeadlineConfig() PaymentDeadlineConfig {
+	cfg := PaymentDeadlineConfig{
+		Deadline:         time.Duration(envInt("WINNER_PAYMENT_DEADLINE_HOURS", 48)) * time.Hour,
+		Policy:           os.Getenv("WINNER_DEFAULT_POLICY"),
+		MaxSecondChances: envInt("SECOND_CHANCE_MAX_OFFERS", 2),
+	}
+
+	if cfg.Deadline == 0 {
+		log.Fatalf("WINNER_PAYMENT_DEADLINE_HOURS must be greater than 0")
+	}
+	if cfg.Policy == "" {
+		cfg.Policy = WinnerDefaultSecondChance
+	}
+	if cfg.Policy != WinnerDefaultSecondChance && cfg.Policy != WinnerDefaultRelist {
+		log.Fatalf("invalid WINNER_DEFAULT_POLICY %q, expected %q or %q", cfg.Policy, WinnerDefaultSecondChance, WinnerDefaultRelist)
+	}
+
+	return cfg
+}
+
+func envMinutes(key string, fallback int) time.Duration {
+	return time.Duration(envInt(key, fallback)) * time.Minute
+}
+
+func envInt(key string, fallback int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		log.Fatalf("invalid %s: %q", key, value)
+	}
+
+	return n
+}
@@ -0,0 +1,53 @@
+package config
+
+import (
+	"log"
+	"os"
+	"time"
+)
+
+// AuthConfig sets how long tokens live. Access tokens are short-lived and cannot be refreshed
+// themselves, a client gets a new one with its refresh token, which rotates on every use.
+// The tokens mailed to verify an email or reset a password link to AppURL, which defaults to
+// localhost only in development.
+type AuthConfig struct {
+	AccessTokenTTL       time.Duration
+	RefreshTokenTTL      time.Duration
+	EmailVerificationTTL time.Duration
+	PasswordResetTTL     time.Duration
+	AppURL               string
+}
+
+func LoadAuthConfig() AuthConfig {
+	cfg := AuthConfig{
+		AccessTokenTTL:       envMinutes("ACCESS_TOKEN_TTL_MINUTES", 15),
+		RefreshTokenTTL:      time.Duration(envInt("REFRESH_TOKEN_TTL_HOURS", 720)) * time.Hour,
+		EmailVerificationTTL: time.Duration(envInt("EMAIL_VERIFICATION_TTL_HOURS", 48)) * time.Hour,
+		PasswordResetTTL:     envMinutes("PASSWORD_RESET_TTL_MINUTES", 30),
+		AppURL:               os.Getenv("APP_URL"),
+	}
+
+	if cfg.AccessTokenTTL <= 0 || cfg.RefreshTokenTTL <= 0 {
+		log.Fatalf("ACCESS_TOKEN_TTL_MINUTES and REFRESH_TOKEN_TTL_HOURS must be greater than 0")
+	}
+	if cfg.RefreshTokenTTL <= cfg.AccessTokenTTL {
+		log.Fatalf("REFRESH_TOKEN_TTL_HOURS must outlast ACCESS_TOKEN_TTL_MINUTES")
+	}
+	if cfg.EmailVerificationTTL <= 0 || cfg.PasswordResetTTL <= 0 {
+		log.Fatalf("EMAIL_VERIFICATION_TTL_HOURS and PASSWORD_RESET_TTL_MINUTES must be greater than 0")
+	}
+
+	if cfg.AppURL == "" {
+		// the links in verification and reset emails must reach the deployed app
+		if os.Getenv("APP_ENV") != AppEnvDevelopment {
+			log.Fatalf("APP_URL is required outside APP_ENV=%s", AppEnvDevelopment)
+		}
+		port := os.Getenv("PORT")
+		if port == "" {
+			port = "8080"
+		}
+		cfg.AppURL = "http://localhost:" + port
+	}
+
+	return cfg
+}
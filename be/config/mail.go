@@ -0,0 +1,68 @@
+package config
+
+import (
+	"log"
+	"os"
+)
+
+const (
+	MailerSMTP = "smtp"
+	MailerLog  = "log"
+)
+
+// MailConfig selects how emails go out. Without MAILER, SMTP is used when SMTP_HOST is set and the
+// log mailer only in development. The log mailer writes every email to LogDir and the app log
+// instead of sending it, so the links in them can be followed without a mail server.
+type MailConfig struct {
+	Mailer string
+	From   string
+
+	SMTPHost     string
+	SMTPPort     int
+	SMTPUsername string
+	SMTPPassword string
+
+	LogDir string
+}
+
+func LoadMailConfig() MailConfig {
+	cfg := MailConfig{
+		Mailer:       os.Getenv("MAILER"),
+		From:         os.Getenv("MAIL_FROM"),
+		SMTPHost:     os.Getenv("SMTP_HOST"),
+		SMTPPort:     envInt("SMTP_PORT", 587),
+		SMTPUsername: os.Getenv("SMTP_USERNAME"),
+		SMTPPassword: os.Getenv("SMTP_PASSWORD"),
+		LogDir:       os.Getenv("MAIL_LOG_DIR"),
+	}
+
+	if cfg.Mailer == "" {
+		switch {
+		case cfg.SMTPHost != "":
+			cfg.Mailer = MailerSMTP
+		case os.Getenv("APP_ENV") == AppEnvDevelopment:
+			log.Printf("MAILER not set, writing emails to the log in %s", AppEnvDevelopment)
+			cfg.Mailer = MailerLog
+		default:
+			log.Fatalf("MAILER or SMTP_HOST is required outside APP_ENV=%s, set MAILER=%s to write emails to the log", AppEnvDevelopment, MailerLog)
+		}
+	}
+
+	switch cfg.Mailer {
+	case MailerSMTP:
+		if cfg.SMTPHost == "" || cfg.From == "" {
+			log.Fatalf("SMTP_HOST and MAIL_FROM are required with MAILER=%s", MailerSMTP)
+		}
+	case MailerLog:
+		if cfg.From == "" {
+			cfg.From = "no-reply@localhost"
+		}
+		if cfg.LogDir == "" {
+			cfg.LogDir = "mail"
+		}
+	default:
+		log.Fatalf("invalid MAILER %q, expected %q or %q", cfg.Mailer, MailerSMTP, MailerLog)
+	}
+
+	return cfg
+}
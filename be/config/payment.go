@@ -0,0 +1,74 @@
+package config
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"log"
+	"os"
+)
+
+const (
+	PaymentGatewayMidtrans = "midtrans"
+	PaymentGatewayFake     = "fake"
+
+	MidtransSandbox    = "sandbox"
+	MidtransProduction = "production"
+)
+
+// PaymentGatewayConfig selects the payment gateway. The fake gateway runs in process and
+// settles every charge on the first status check, so the payment flow works offline. It is only
+// allowed with APP_ENV=development: whoever knows its server key can sign a notification that
+// marks a payment paid, so without MIDTRANS_SERVER_KEY it signs with a random key of its own.
+type PaymentGatewayConfig struct {
+	Provider    string
+	Environment string
+	ServerKey   string
+}
+
+func LoadPaymentGatewayConfig() PaymentGatewayConfig {
+	cfg := PaymentGatewayConfig{
+		Provider:    os.Getenv("PAYMENT_GATEWAY"),
+		Environment: os.Getenv("MIDTRANS_ENV"),
+		ServerKey:   os.Getenv("MIDTRANS_SERVER_KEY"),
+	}
+
+	if cfg.Provider == "" {
+		cfg.Provider = PaymentGatewayMidtrans
+	}
+	if cfg.Provider != PaymentGatewayMidtrans && cfg.Provider != PaymentGatewayFake {
+		log.Fatalf("invalid PAYMENT_GATEWAY %q, expected %q or %q", cfg.Provider, PaymentGatewayMidtrans, PaymentGatewayFake)
+	}
+
+	if cfg.Environment == "" {
+		cfg.Environment = MidtransSandbox
+	}
+	if cfg.Environment != MidtransSandbox && cfg.Environment != MidtransProduction {
+		log.Fatalf("invalid MIDTRANS_ENV %q, expected %q or %q", cfg.Environment, MidtransSandbox, MidtransProduction)
+	}
+
+	if cfg.Provider == PaymentGatewayMidtrans && cfg.ServerKey == "" {
+		if cfg.Environment == MidtransProduction {
+			log.Fatalf("MIDTRANS_SERVER_KEY is required with MIDTRANS_ENV=%s", MidtransProduction)
+		}
+		log.Printf("MIDTRANS_SERVER_KEY is not set, charges and notifications will fail")
+	}
+	if cfg.Provider == PaymentGatewayFake {
+		if os.Getenv("APP_ENV") != AppEnvDevelopment {
+			log.Fatalf("PAYMENT_GATEWAY=%s is only allowed with APP_ENV=%s", PaymentGatewayFake, AppEnvDevelopment)
+		}
+		if cfg.ServerKey == "" {
+			cfg.ServerKey = randomServerKey()
+			log.Printf("MIDTRANS_SERVER_KEY is not set, the fake gateway signs its notifications with a random key")
+		}
+	}
+
+	return cfg
+}
+
+func randomServerKey() string {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		log.Fatalf("failed to generate a server key for the fake gateway: %v", err)
+	}
+	return hex.EncodeToString(key)
+}
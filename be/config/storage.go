@@ -0,0 +1,118 @@
+package config
+
+import (
+	"log"
+	"os"
+	"strconv"
+)
+
+const (
+	StorageGCS   = "gcs"
+	StorageLocal = "local"
+	StorageS3    = "s3"
+)
+
+// AppEnvDevelopment is the APP_ENV of a developer machine, where missing settings may fall back to local defaults
+const AppEnvDevelopment = "development"
+
+// StorageConfig selects where uploaded files are kept. Without STORAGE_BACKEND the buckets decide:
+// GCS when PUBLIC_BUCKET and PRIVATE_BUCKET are set. The local filesystem is only picked on its own
+// with APP_ENV=development, so uploads work there without cloud credentials; anywhere else it takes
+// STORAGE_BACKEND=local, a deployment missing its bucket settings fails to start instead of keeping
+// files on a disk that may not outlive it. Local files are served by the app under /files and
+// private ones need a download URL signed with SigningKey.
+type StorageConfig struct {
+	Backend       string
+	PublicBucket  string
+	PrivateBucket string
+
+	LocalDir   string
+	BaseURL    string
+	SigningKey string
+
+	S3Endpoint  string
+	S3Region    string
+	S3AccessKey string
+	S3SecretKey string
+	S3PathStyle bool
+}
+
+func LoadStorageConfig() StorageConfig {
+	cfg := StorageConfig{
+		Backend:       os.Getenv("STORAGE_BACKEND"),
+		PublicBucket:  os.Getenv("PUBLIC_BUCKET"),
+		PrivateBucket: os.Getenv("PRIVATE_BUCKET"),
+		LocalDir:      os.Getenv("STORAGE_LOCAL_DIR"),
+		BaseURL:       os.Getenv("STORAGE_BASE_URL"),
+		SigningKey:    os.Getenv("STORAGE_SIGNING_KEY"),
+		S3Endpoint:    os.Getenv("S3_ENDPOINT"),
+		S3Region:      os.Getenv("S3_REGION"),
+		S3AccessKey:   os.Getenv("S3_ACCESS_KEY"),
+		S3SecretKey:   os.Getenv("S3_SECRET_KEY"),
+		S3PathStyle:   true,
+	}
+
+	if cfg.Backend == "" {
+		switch {
+		case cfg.PublicBucket != "" && cfg.PrivateBucket != "":
+			cfg.Backend = StorageGCS
+		case os.Getenv("APP_ENV") == AppEnvDevelopment:
+			log.Printf("STORAGE_BACKEND not set, keeping files on the local filesystem in %s", AppEnvDevelopment)
+			cfg.Backend = StorageLocal
+		default:
+			log.Fatalf("STORAGE_BACKEND is required outside APP_ENV=%s, set STORAGE_BACKEND=%s to keep files on the local filesystem", AppEnvDevelopment, StorageLocal)
+		}
+	}
+
+	switch cfg.Backend {
+	case StorageGCS:
+		if cfg.PublicBucket == "" || cfg.PrivateBucket == "" {
+			log.Fatalf("PUBLIC_BUCKET and PRIVATE_BUCKET are required with STORAGE_BACKEND=%s", StorageGCS)
+		}
+	case StorageS3:
+		if cfg.PublicBucket == "" || cfg.PrivateBucket == "" {
+			log.Fatalf("PUBLIC_BUCKET and PRIVATE_BUCKET are required with STORAGE_BACKEND=%s", StorageS3)
+		}
+		if cfg.S3Endpoint == "" || cfg.S3AccessKey == "" || cfg.S3SecretKey == "" {
+			log.Fatalf("S3_ENDPOINT, S3_ACCESS_KEY and S3_SECRET_KEY are required with STORAGE_BACKEND=%s", StorageS3)
+		}
+		if cfg.S3Region == "" {
+			cfg.S3Region = "us-east-1"
+		}
+		if v := os.Getenv("S3_PATH_STYLE"); v != "" {
+			pathStyle, err := strconv.ParseBool(v)
+			if err != nil {
+				log.Fatalf("invalid S3_PATH_STYLE %q, expected true or false", v)
+			}
+			cfg.S3PathStyle = pathStyle
+		}
+	case StorageLocal:
+		if cfg.PublicBucket == "" {
+			cfg.PublicBucket = "public"
+		}
+		if cfg.PrivateBucket == "" {
+			cfg.PrivateBucket = "private"
+		}
+		if cfg.LocalDir == "" {
+			cfg.LocalDir = "storage"
+		}
+		if cfg.BaseURL == "" {
+			port := os.Getenv("PORT")
+			if port == "" {
+				port = "8080"
+			}
+			cfg.BaseURL = "http://localhost:" + port
+		}
+		if cfg.SigningKey == "" {
+			// the JWT secret is only a fallback, rotating it would break the download URLs handed out
+			cfg.SigningKey = os.Getenv("SECRET_KEY")
+		}
+		if cfg.SigningKey == "" {
+			log.Fatalf("STORAGE_SIGNING_KEY or SECRET_KEY is required with STORAGE_BACKEND=%s", StorageLocal)
+		}
+	default:
+		log.Fatalf("invalid STORAGE_BACKEND %q, expected %q, %q or %q", cfg.Backend, StorageGCS, StorageLocal, StorageS3)
+	}
+
+	return cfg
+}
@@ -6,6 +6,9 @@ echo "Generating mocks..."
 # User Service
 mockgen -source=internal/service/user_service.go -destination=internal/mocks/mock_user_repository.go -package=mocks UserRepository
 
+# Role Service
+mockgen -source=internal/service/role_service.go -destination=internal/mocks/mock_role_repository.go -package=mocks RoleRepository
+
 # Payment Service  
 mockgen -source=internal/service/payment_service.go -destination=internal/mocks/mock_payment_repository.go -package=mocks PaymentRepository
 
@@ -14,4 +17,9 @@ mockgen -source=internal/controller/user_controller.go -destination=internal/moc
 mockgen -source=internal/controller/payment_controller.go -destination=internal/mocks/mock_payment_service.go -package=mocks PaymentService
 mockgen -source=internal/controller/admin_controller.go -destination=internal/mocks/mock_admin_service.go -package=mocks AdminService
 
+# Service interfaces used by the route tests
+mockgen -source=internal/service/auction_item_service.go -destination=internal/mocks/mock_auction_item_service.go -package=mocks AuctionItemService
+mockgen -source=internal/service/auction_session_service.go -destination=internal/mocks/mock_auction_session_service.go -package=mocks AuctionSessionService
+mockgen -source=internal/service/bid_service.go -destination=internal/mocks/mock_bid_service.go -package=mocks BidService
+
 echo "Mocks generated successfully!"
\ No newline at end of file
@@ -2,9 +2,9 @@ package controller
 
 import (
 	"milestone3/be/internal/dto"
+	"milestone3/be/internal/entity"
 	"milestone3/be/internal/utils"
 
-	"github.com/golang-jwt/jwt/v5"
 	"github.com/labstack/echo/v4"
 )
 
@@ -30,18 +30,14 @@ func NewAdminController(as AdminService) *AdminController {
 // @Security BearerAuth
 // @Success 200 {object} utils.SuccessResponseData "ok"
 // @Failure 401 {object} utils.ErrorResponse "Unauthorized - Invalid or missing token"
-// @Failure 403 {object} utils.ErrorResponse "Forbidden - Admin access required"
+// @Failure 403 {object} utils.ErrorResponse "Forbidden - report:read required"
 // @Failure 500 {object} utils.ErrorResponse "Internal server error"
 // @Router /admin/dashboard [get]
 func (ac *AdminController) AdminDashboard(c echo.Context) error {
-	user := c.Get("user").(*jwt.Token)
-	claim := user.Claims.(jwt.MapClaims)
-	role := claim["role"].(string)
-
-	if role != "admin" {
+	if !utils.HasPermission(c, entity.PermReportRead) {
 		return utils.ForbiddenResponse(c, "forbidden request")
 	}
-	
+
 	resp, err := ac.adminService.AdminDashboard()
 	if err != nil {
 		return utils.InternalServerErrorResponse(c, "internal server error")
@@ -1,12 +1,11 @@
 package controller
 
 import (
-	"fmt"
 	"strconv"
 	"strings"
-	"time"
 
 	"milestone3/be/internal/dto"
+	"milestone3/be/internal/entity"
 	"milestone3/be/internal/repository"
 	"milestone3/be/internal/service"
 	"milestone3/be/internal/utils"
@@ -16,13 +15,15 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
+const articleImageMaxBytes = 5 << 20
+
 type ArticleController struct {
 	svc           service.ArticleService
-	storagePublic repository.GCPStorageRepo
+	storagePublic repository.ObjectStore
 	validator     *validator.Validate
 }
 
-func NewArticleController(s service.ArticleService, storage repository.GCPStorageRepo) *ArticleController {
+func NewArticleController(s service.ArticleService, storage repository.ObjectStore) *ArticleController {
 	return &ArticleController{
 		svc:           s,
 		storagePublic: storage,
@@ -112,12 +113,12 @@ func (h *ArticleController) GetArticleByID(c echo.Context) error {
 // @Success 201 {object} utils.SuccessResponseData "article created"
 // @Failure 400 {object} utils.ErrorResponse "Bad request - Invalid payload or image"
 // @Failure 401 {object} utils.ErrorResponse "Unauthorized - Invalid or missing token"
-// @Failure 403 {object} utils.ErrorResponse "Forbidden - Admin access required"
+// @Failure 403 {object} utils.ErrorResponse "Forbidden - article:manage required"
 // @Failure 500 {object} utils.ErrorResponse "Internal server error"
 // @Router /articles [post]
 func (h *ArticleController) CreateArticle(c echo.Context) error {
-	if !utils.IsAdmin(c) {
-		return utils.ForbiddenResponse(c, "admin only")
+	if !utils.HasPermission(c, entity.PermArticleManage) {
+		return utils.ForbiddenResponse(c, "forbidden")
 	}
 
 	contentType := c.Request().Header.Get("Content-Type")
@@ -151,37 +152,20 @@ func (h *ArticleController) CreateArticle(c echo.Context) error {
 		if fhs, ok := form.File["image"]; ok && len(fhs) > 0 {
 			fh := fhs[0]
 
-			// Validate file size (max 5MB)
-			if fh.Size > 5*1024*1024 {
+			// Validate file size (max 5MB), checked on the content as well
+			if fh.Size > articleImageMaxBytes {
 				return utils.BadRequestResponse(c, "image size exceeds 5MB limit")
 			}
 
-			// Validate file type (only images)
-			contentType := fh.Header.Get("Content-Type")
-			if !strings.HasPrefix(contentType, "image/") {
-				return utils.BadRequestResponse(c, "only image files are allowed")
-			}
-
-			file, err := fh.Open()
-			if err != nil {
-				return utils.BadRequestResponse(c, "failed open image")
-			}
-			defer file.Close()
-
-			// Sanitize filename
-			safeFilename := strings.ReplaceAll(fh.Filename, "..", "")
-			safeFilename = strings.ReplaceAll(safeFilename, "/", "")
-			safeFilename = strings.ReplaceAll(safeFilename, "\\", "")
-
-			objName := fmt.Sprintf("articles/%d_%s", time.Now().UnixNano(), safeFilename)
-
-			//  upload to public storage
-			url, err := h.storagePublic.UploadFile(c.Request().Context(), file, objName)
+			//  upload to public storage, with the resized variants
+			img, err := uploadImage(c.Request().Context(), h.storagePublic, fh, "articles", articleImageMaxBytes)
 			if err != nil {
-				return utils.InternalServerErrorResponse(c, "failed uploading image")
+				return imageUploadErrorResponse(c, err)
 			}
 
-			payload.Image = url
+			payload.Image = img.Original
+			payload.ImageDisplay = img.Display
+			payload.ImageThumbnail = img.Thumbnail
 		}
 
 	} else {
@@ -219,13 +203,13 @@ func (h *ArticleController) CreateArticle(c echo.Context) error {
 // @Success 200 {object} utils.SuccessResponseData "article updated"
 // @Failure 400 {object} utils.ErrorResponse "Bad request - Invalid ID or payload"
 // @Failure 401 {object} utils.ErrorResponse "Unauthorized - Invalid or missing token"
-// @Failure 403 {object} utils.ErrorResponse "Forbidden - Admin access required"
+// @Failure 403 {object} utils.ErrorResponse "Forbidden - article:manage required"
 // @Failure 404 {object} utils.ErrorResponse "Article not found"
 // @Failure 500 {object} utils.ErrorResponse "Internal server error"
 // @Router /articles/{id} [put]
 func (h *ArticleController) UpdateArticle(c echo.Context) error {
-	if !utils.IsAdmin(c) {
-		return utils.ForbiddenResponse(c, "admin only")
+	if !utils.HasPermission(c, entity.PermArticleManage) {
+		return utils.ForbiddenResponse(c, "forbidden")
 	}
 	var payload dto.ArticleDTO
 	if err := c.Bind(&payload); err != nil {
@@ -257,13 +241,13 @@ func (h *ArticleController) UpdateArticle(c echo.Context) error {
 // @Success 204 "Article deleted successfully"
 // @Failure 400 {object} utils.ErrorResponse "Bad request - Invalid article ID"
 // @Failure 401 {object} utils.ErrorResponse "Unauthorized - Invalid or missing token"
-// @Failure 403 {object} utils.ErrorResponse "Forbidden - Admin access required"
+// @Failure 403 {object} utils.ErrorResponse "Forbidden - article:manage required"
 // @Failure 404 {object} utils.ErrorResponse "Article not found"
 // @Failure 500 {object} utils.ErrorResponse "Internal server error"
 // @Router /articles/{id} [delete]
 func (h *ArticleController) DeleteArticle(c echo.Context) error {
-	if !utils.IsAdmin(c) {
-		return utils.ForbiddenResponse(c, "admin only")
+	if !utils.HasPermission(c, entity.PermArticleManage) {
+		return utils.ForbiddenResponse(c, "forbidden")
 	}
 	idParam := c.Param("id")
 	id64, err := strconv.ParseUint(idParam, 10, 64)
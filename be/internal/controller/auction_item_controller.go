@@ -2,6 +2,7 @@ package controller
 
 import (
 	"milestone3/be/internal/dto"
+	"milestone3/be/internal/entity"
 	"milestone3/be/internal/service"
 	"milestone3/be/internal/utils"
 	"strconv"
@@ -39,22 +40,6 @@ func getUserIDFromTokenItem(c echo.Context) (int64, error) {
 	return int64(userIDFloat), nil
 }
 
-// Helper function to check if user is admin from JWT token
-func isAdminFromTokenItem(c echo.Context) bool {
-	token := c.Get("user")
-	if token == nil {
-		return false
-	}
-
-	claims, ok := token.(*jwt.Token).Claims.(jwt.MapClaims)
-	if !ok {
-		return false
-	}
-
-	role, ok := claims["role"].(string)
-	return ok && role == "admin"
-}
-
 // CreateAuctionItem godoc
 // @Summary Create new auction item
 // @Description Create a new auction item from verified donation
@@ -66,15 +51,10 @@ func isAdminFromTokenItem(c echo.Context) bool {
 // @Success 201 {object} utils.SuccessResponseData "auction item created successfully"
 // @Failure 400 {object} utils.ErrorResponse "Bad request - Invalid payload"
 // @Failure 401 {object} utils.ErrorResponse "Unauthorized - Invalid or missing token"
-// @Failure 403 {object} utils.ErrorResponse "Forbidden - Admin access required"
+// @Failure 403 {object} utils.ErrorResponse "Forbidden - auction:manage required"
 // @Failure 500 {object} utils.ErrorResponse "Internal server error"
 // @Router /auction/items [post]
 func (h *AuctionController) CreateAuctionItem(c echo.Context) error {
-	// Check if user is admin
-	if !isAdminFromTokenItem(c) {
-		return utils.ForbiddenResponse(c, "only admin can create auction items")
-	}
-
 	// Get user ID from JWT token
 	userID, err := getUserIDFromTokenItem(c)
 	if err != nil {
@@ -93,7 +73,12 @@ func (h *AuctionController) CreateAuctionItem(c echo.Context) error {
 	payload.UserID = userID
 	createdItem, err := h.svc.Create(&payload)
 	if err != nil {
-		return utils.InternalServerErrorResponse(c, "failed creating auction item")
+		switch err {
+		case service.ErrInvalidPriceRules:
+			return utils.BadRequestResponse(c, err.Error())
+		default:
+			return utils.InternalServerErrorResponse(c, "failed creating auction item")
+		}
 	}
 
 	return utils.CreatedResponse(c, "auction item created successfully", createdItem)
@@ -101,7 +86,7 @@ func (h *AuctionController) CreateAuctionItem(c echo.Context) error {
 
 // GetAllAuctionItems godoc
 // @Summary Get all auction items
-// @Description Retrieve all available auction items
+// @Description Retrieve all available auction items, drafts are included for callers with auction:manage
 // @Tags Your Donate Rise API - Auction Items
 // @Accept json
 // @Produce json
@@ -109,7 +94,7 @@ func (h *AuctionController) CreateAuctionItem(c echo.Context) error {
 // @Failure 500 {object} utils.ErrorResponse "Internal server error"
 // @Router /auction/items [get]
 func (h *AuctionController) GetAllAuctionItems(c echo.Context) error {
-	items, err := h.svc.GetAll()
+	items, err := h.svc.GetAll(utils.HasPermission(c, entity.PermAuctionManage))
 	if err != nil {
 		return utils.InternalServerErrorResponse(c, "failed retrieving auction items")
 	}
@@ -118,7 +103,7 @@ func (h *AuctionController) GetAllAuctionItems(c echo.Context) error {
 
 // GetAuctionItemByID godoc
 // @Summary Get auction item by ID
-// @Description Retrieve a specific auction item by its ID
+// @Description Retrieve a specific auction item by its ID, a draft is not found unless the caller has auction:manage
 // @Tags Your Donate Rise API - Auction Items
 // @Accept json
 // @Produce json
@@ -136,7 +121,7 @@ func (h *AuctionController) GetAuctionItemByID(c echo.Context) error {
 		return utils.BadRequestResponse(c, "invalid auction item ID")
 	}
 
-	item, err := h.svc.GetByID(id)
+	item, err := h.svc.GetByID(id, utils.HasPermission(c, entity.PermAuctionManage))
 	if err != nil {
 		switch err {
 		case service.ErrAuctionNotFoundID:
@@ -161,15 +146,11 @@ func (h *AuctionController) GetAuctionItemByID(c echo.Context) error {
 // @Success 200 {object} utils.SuccessResponseData "auction item updated successfully"
 // @Failure 400 {object} utils.ErrorResponse "Bad request - Invalid ID or payload"
 // @Failure 401 {object} utils.ErrorResponse "Unauthorized - Invalid or missing token"
-// @Failure 403 {object} utils.ErrorResponse "Forbidden - Admin access required"
+// @Failure 403 {object} utils.ErrorResponse "Forbidden - auction:manage required"
 // @Failure 404 {object} utils.ErrorResponse "Auction item not found"
 // @Failure 500 {object} utils.ErrorResponse "Internal server error"
-// @Router /auction/items/{id} [put]
+// @Router /auction/items/{id} [patch]
 func (h *AuctionController) UpdateAuctionItem(c echo.Context) error {
-	if !isAdminFromTokenItem(c) {
-		return utils.ForbiddenResponse(c, "only admin can update auction items")
-	}
-
 	idStr := c.Param("id")
 	id, err := strconv.ParseInt(idStr, 10, 64)
 	if err != nil {
@@ -196,7 +177,7 @@ func (h *AuctionController) UpdateAuctionItem(c echo.Context) error {
 			return utils.ConflictResponse(c, err.Error())
 		case service.ErrActiveSession:
 			return utils.ConflictResponse(c, err.Error())
-		case service.ErrInvalidAuction:
+		case service.ErrInvalidAuction, service.ErrInvalidPriceRules:
 			return utils.BadRequestResponse(c, err.Error())
 		default:
 			return utils.InternalServerErrorResponse(c, "failed updating auction item")
@@ -217,15 +198,11 @@ func (h *AuctionController) UpdateAuctionItem(c echo.Context) error {
 // @Success 200 {object} utils.SuccessResponseData "auction item deleted successfully"
 // @Failure 400 {object} utils.ErrorResponse "Bad request - Invalid ID or cannot delete item"
 // @Failure 401 {object} utils.ErrorResponse "Unauthorized - Invalid or missing token"
-// @Failure 403 {object} utils.ErrorResponse "Forbidden - Admin access required"
+// @Failure 403 {object} utils.ErrorResponse "Forbidden - auction:manage required"
 // @Failure 404 {object} utils.ErrorResponse "Auction item not found"
 // @Failure 500 {object} utils.ErrorResponse "Internal server error"
 // @Router /auction/items/{id} [delete]
 func (h *AuctionController) DeleteAuctionItem(c echo.Context) error {
-	if !isAdminFromTokenItem(c) {
-		return utils.ForbiddenResponse(c, "only admin can delete auction items")
-	}
-
 	idStr := c.Param("id")
 	id, err := strconv.ParseInt(idStr, 10, 64)
 	if err != nil {
@@ -7,7 +7,6 @@ import (
 	"strconv"
 
 	"github.com/go-playground/validator/v10"
-	"github.com/golang-jwt/jwt/v5"
 	"github.com/labstack/echo/v4"
 )
 
@@ -20,21 +19,6 @@ func NewAuctionSessionController(s service.AuctionSessionService, validate *vali
 	return &AuctionSessionController{svc: s, validate: validate}
 }
 
-func isAdminFromTokenSession(c echo.Context) bool {
-	token := c.Get("user")
-	if token == nil {
-		return false
-	}
-
-	claims, ok := token.(*jwt.Token).Claims.(jwt.MapClaims)
-	if !ok {
-		return false
-	}
-
-	role, ok := claims["role"].(string)
-	return ok && role == "admin"
-}
-
 // CreateAuctionSession godoc
 // @Summary Create new auction session
 // @Description Create a new auction session with start and end times
@@ -46,14 +30,10 @@ func isAdminFromTokenSession(c echo.Context) bool {
 // @Success 201 {object} utils.SuccessResponseData "auction session created successfully"
 // @Failure 400 {object} utils.ErrorResponse "Bad request - Invalid payload"
 // @Failure 401 {object} utils.ErrorResponse "Unauthorized - Invalid or missing token"
-// @Failure 403 {object} utils.ErrorResponse "Forbidden - Admin access required"
+// @Failure 403 {object} utils.ErrorResponse "Forbidden - auction:manage required"
 // @Failure 500 {object} utils.ErrorResponse "Internal server error"
 // @Router /auction/sessions [post]
 func (h *AuctionSessionController) CreateAuctionSession(c echo.Context) error {
-	if !isAdminFromTokenSession(c) {
-		return utils.ForbiddenResponse(c, "only admin can create auction sessions")
-	}
-
 	var payload dto.AuctionSessionDTO
 	if err := c.Bind(&payload); err != nil {
 		return utils.BadRequestResponse(c, "invalid payload")
@@ -148,15 +128,11 @@ func (h *AuctionSessionController) GetAllAuctionSessions(c echo.Context) error {
 // @Success 200 {object} utils.SuccessResponseData "auction session updated successfully"
 // @Failure 400 {object} utils.ErrorResponse "Bad request - Invalid ID, payload, or session is active"
 // @Failure 401 {object} utils.ErrorResponse "Unauthorized - Invalid or missing token"
-// @Failure 403 {object} utils.ErrorResponse "Forbidden - Admin access required"
+// @Failure 403 {object} utils.ErrorResponse "Forbidden - auction:manage required"
 // @Failure 404 {object} utils.ErrorResponse "Auction session not found"
 // @Failure 500 {object} utils.ErrorResponse "Internal server error"
 // @Router /auction/sessions/{id} [put]
 func (h *AuctionSessionController) UpdateAuctionSession(c echo.Context) error {
-	if !isAdminFromTokenSession(c) {
-		return utils.ForbiddenResponse(c, "only admin can update auction sessions")
-	}
-
 	idStr := c.Param("id")
 	id, err := strconv.ParseInt(idStr, 10, 64)
 	if err != nil {
@@ -204,15 +180,11 @@ func (h *AuctionSessionController) UpdateAuctionSession(c echo.Context) error {
 // @Success 200 {object} utils.SuccessResponseData "auction session deleted successfully"
 // @Failure 400 {object} utils.ErrorResponse "Bad request - Invalid ID or session is active"
 // @Failure 401 {object} utils.ErrorResponse "Unauthorized - Invalid or missing token"
-// @Failure 403 {object} utils.ErrorResponse "Forbidden - Admin access required"
+// @Failure 403 {object} utils.ErrorResponse "Forbidden - auction:manage required"
 // @Failure 404 {object} utils.ErrorResponse "Auction session not found"
 // @Failure 500 {object} utils.ErrorResponse "Internal server error"
 // @Router /auction/sessions/{id} [delete]
 func (h *AuctionSessionController) DeleteAuctionSession(c echo.Context) error {
-	if !isAdminFromTokenSession(c) {
-		return utils.ForbiddenResponse(c, "only admin can delete auction sessions")
-	}
-
 	idStr := c.Param("id")
 	id, err := strconv.ParseInt(idStr, 10, 64)
 	if err != nil {
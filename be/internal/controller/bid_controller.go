@@ -1,10 +1,15 @@
 package controller
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
 	"milestone3/be/internal/dto"
 	"milestone3/be/internal/service"
 	"milestone3/be/internal/utils"
+	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/golang-jwt/jwt/v5"
@@ -40,6 +45,15 @@ func getUserIDFromToken(c echo.Context) (int64, error) {
 	return int64(userIDFloat), nil
 }
 
+// rejectedBidResponse tells the bidder the lowest amount the next bid must reach
+func rejectedBidResponse(c echo.Context, rejected *service.BidRejectedError) error {
+	data := map[string]interface{}{"min_next_bid": rejected.MinNextBid}
+	if errors.Is(rejected.Err, service.ErrBidTooLow) {
+		return utils.BadRequestResponseWithData(c, rejected.Error(), data)
+	}
+	return utils.ConflictResponseWithData(c, rejected.Error(), data)
+}
+
 // PlaceBid godoc
 // @Summary Place bid on auction item
 // @Description Place a bid on a specific auction item within an active session
@@ -51,10 +65,10 @@ func getUserIDFromToken(c echo.Context) (int64, error) {
 // @Param itemID path int true "Auction Item ID"
 // @Param bid body dto.BidDTO true "Bid amount"
 // @Success 200 {object} utils.SuccessResponseData "bid placed successfully"
-// @Failure 400 {object} utils.ErrorResponse "Bad request - Invalid parameters or bid too low"
+// @Failure 400 {object} utils.ErrorResponse "Bad request - Invalid parameters or bid too low, data carries min_next_bid"
 // @Failure 401 {object} utils.ErrorResponse "Unauthorized - Invalid or missing token"
 // @Failure 404 {object} utils.ErrorResponse "Auction session or item not found"
-// @Failure 409 {object} utils.ErrorResponse "Conflict - Invalid auction state"
+// @Failure 409 {object} utils.ErrorResponse "Conflict - Invalid auction state or outbid by an automatic bid, data carries min_next_bid"
 // @Failure 500 {object} utils.ErrorResponse "Internal server error"
 // @Router /auction/sessions/{sessionID}/items/{itemID}/bid [post]
 func (h *BidController) PlaceBid(c echo.Context) error {
@@ -108,6 +122,10 @@ func (h *BidController) PlaceBid(c echo.Context) error {
 
 	if err != nil {
 		c.Logger().Errorf("PlaceBid error: %v", err)
+		var rejected *service.BidRejectedError
+		if errors.As(err, &rejected) {
+			return rejectedBidResponse(c, rejected)
+		}
 		switch err {
 		case service.ErrBidTooLow, service.ErrInvalidBidding:
 			return utils.BadRequestResponse(c, err.Error())
@@ -119,6 +137,8 @@ func (h *BidController) PlaceBid(c echo.Context) error {
 			return utils.ConflictResponse(c, err.Error())
 		case service.ErrAlreadyHighestBidder:
 			return utils.ConflictResponse(c, "you are already the highest bidder")
+		case service.ErrOutbidByProxy:
+			return utils.ConflictResponse(c, err.Error())
 		default:
 			return utils.InternalServerErrorResponse(c, "failed placing bid")
 		}
@@ -129,9 +149,129 @@ func (h *BidController) PlaceBid(c echo.Context) error {
 	return utils.SuccessResponse(c, "bid placed successfully", nil)
 }
 
+// RegisterProxyBid godoc
+// @Summary Register proxy bid on auction item
+// @Description Register or raise a hidden maximum amount, the system bids on your behalf in increment ladder steps up to it. Equal maximums go to the earliest registration.
+// @Tags Your Donate Rise API - Bidding
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param sessionID path int true "Auction Session ID"
+// @Param itemID path int true "Auction Item ID"
+// @Param bid body dto.ProxyBidDTO true "Maximum amount"
+// @Success 200 {object} utils.SuccessResponseData "proxy bid registered successfully"
+// @Failure 400 {object} utils.ErrorResponse "Bad request - Invalid parameters or maximum too low, data carries min_next_bid"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized - Invalid or missing token"
+// @Failure 404 {object} utils.ErrorResponse "Auction session or item not found"
+// @Failure 409 {object} utils.ErrorResponse "Conflict - Invalid auction state or outbid by an automatic bid, data carries min_next_bid"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /auction/sessions/{sessionID}/items/{itemID}/proxy-bid [post]
+func (h *BidController) RegisterProxyBid(c echo.Context) error {
+	sessionID, err := strconv.ParseInt(c.Param("sessionID"), 10, 64)
+	if err != nil {
+		return utils.BadRequestResponse(c, "invalid sessionID")
+	}
+
+	itemID, err := strconv.ParseInt(c.Param("itemID"), 10, 64)
+	if err != nil {
+		return utils.BadRequestResponse(c, "invalid itemID")
+	}
+
+	var payload dto.ProxyBidDTO
+	if err = c.Bind(&payload); err != nil {
+		return utils.BadRequestResponse(c, "invalid payload")
+	}
+
+	if err = h.validate.Struct(payload); err != nil {
+		return utils.BadRequestResponse(c, err.Error())
+	}
+
+	userID, err := getUserIDFromToken(c)
+	if err != nil {
+		return utils.UnauthorizedResponse(c, "unauthenticated")
+	}
+
+	session, err := h.sessionSvc.GetByID(sessionID)
+	if err != nil {
+		return utils.NotFoundResponse(c, "auction session not found")
+	}
+
+	err = h.svc.RegisterProxyBid(sessionID, itemID, userID, payload.MaxAmount, session.EndTime)
+	if err != nil {
+		var rejected *service.BidRejectedError
+		if errors.As(err, &rejected) {
+			return rejectedBidResponse(c, rejected)
+		}
+		switch err {
+		case service.ErrBidTooLow, service.ErrInvalidBidding:
+			return utils.BadRequestResponse(c, err.Error())
+		case service.ErrAuctionNotFound:
+			return utils.NotFoundResponse(c, err.Error())
+		case service.ErrInvalidAuction, service.ErrOutbidByProxy:
+			return utils.ConflictResponse(c, err.Error())
+		default:
+			return utils.InternalServerErrorResponse(c, "failed registering proxy bid")
+		}
+	}
+
+	return utils.SuccessResponse(c, "proxy bid registered successfully", nil)
+}
+
+// GetProxyBid godoc
+// @Summary Get my proxy bid for auction item
+// @Description Retrieve the maximum amount registered by the current user, other users' maximums are never returned
+// @Tags Your Donate Rise API - Bidding
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param sessionID path int true "Auction Session ID"
+// @Param itemID path int true "Auction Item ID"
+// @Success 200 {object} utils.SuccessResponseData "proxy bid retrieved successfully"
+// @Failure 400 {object} utils.ErrorResponse "Bad request - Invalid session or item ID"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized - Invalid or missing token"
+// @Failure 404 {object} utils.ErrorResponse "Auction not found"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /auction/sessions/{sessionID}/items/{itemID}/proxy-bid [get]
+func (h *BidController) GetProxyBid(c echo.Context) error {
+	sessionID, err := strconv.ParseInt(c.Param("sessionID"), 10, 64)
+	if err != nil {
+		return utils.BadRequestResponse(c, "invalid sessionID")
+	}
+
+	itemID, err := strconv.ParseInt(c.Param("itemID"), 10, 64)
+	if err != nil {
+		return utils.BadRequestResponse(c, "invalid itemID")
+	}
+
+	userID, err := getUserIDFromToken(c)
+	if err != nil {
+		return utils.UnauthorizedResponse(c, "unauthenticated")
+	}
+
+	maxAmount, err := h.svc.GetProxyBid(sessionID, itemID, userID)
+	if err != nil {
+		switch err {
+		case service.ErrAuctionNotFound:
+			return utils.NotFoundResponse(c, err.Error())
+		case service.ErrInvalidAuction:
+			return utils.ConflictResponse(c, err.Error())
+		default:
+			return utils.InternalServerErrorResponse(c, "failed retrieving proxy bid")
+		}
+	}
+
+	resp := map[string]interface{}{
+		"session_id": sessionID,
+		"item_id":    itemID,
+		"max_amount": maxAmount,
+	}
+
+	return utils.SuccessResponse(c, "proxy bid retrieved successfully", resp)
+}
+
 // GetHighestBid godoc
 // @Summary Get highest bid for auction item
-// @Description Retrieve the current highest bid for a specific auction item
+// @Description Retrieve the current highest bid for a specific auction item and the minimum amount the next bid must reach
 // @Tags Your Donate Rise API - Bidding
 // @Accept json
 // @Produce json
@@ -156,7 +296,7 @@ func (h *BidController) GetHighestBid(c echo.Context) error {
 		return utils.BadRequestResponse(c, "invalid itemID")
 	}
 
-	highest, bidder, err := h.svc.GetHighestBid(sessionID, itemID)
+	resp, err := h.svc.GetHighestBid(sessionID, itemID)
 	if err != nil {
 		switch err {
 		case service.ErrAuctionNotFound:
@@ -166,12 +306,260 @@ func (h *BidController) GetHighestBid(c echo.Context) error {
 		}
 	}
 
-	resp := map[string]interface{}{
-		"session_id":  sessionID,
-		"item_id":     itemID,
-		"highest_bid": highest,
-		"bidder_id":   bidder,
+	return utils.SuccessResponse(c, "highest bid retrieved successfully", resp)
+}
+
+// GetBidHistory godoc
+// @Summary Get bid history for auction item
+// @Description Retrieve every accepted bid for an auction item, newest first, with pagination
+// @Tags Your Donate Rise API - Bidding
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Auction Item ID"
+// @Param page query int false "Page number (default: 1)"
+// @Param limit query int false "Items per page (default: 10, max: 100)"
+// @Success 200 {object} utils.SuccessResponseData "bid history retrieved successfully"
+// @Failure 400 {object} utils.ErrorResponse "Bad request - Invalid auction item ID"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized - Invalid or missing token"
+// @Failure 404 {object} utils.ErrorResponse "Auction not found"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /auction/items/{id}/bids [get]
+func (h *BidController) GetBidHistory(c echo.Context) error {
+	itemID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return utils.BadRequestResponse(c, "invalid auction item ID")
 	}
 
-	return utils.SuccessResponse(c, "highest bid retrieved successfully", resp)
+	// Parse pagination params
+	page, _ := strconv.Atoi(c.QueryParam("page"))
+	if page < 1 {
+		page = 1
+	}
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+	if limit < 1 {
+		limit = 10
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	bids, total, err := h.svc.GetBidHistory(itemID, page, limit)
+	if err != nil {
+		switch err {
+		case service.ErrAuctionNotFound:
+			return utils.NotFoundResponse(c, err.Error())
+		default:
+			return utils.InternalServerErrorResponse(c, "failed retrieving bid history")
+		}
+	}
+
+	response := map[string]interface{}{
+		"bids":  bids,
+		"page":  page,
+		"limit": limit,
+		"total": total,
+	}
+	return utils.SuccessResponse(c, "bid history retrieved successfully", response)
+}
+
+// StreamBids godoc
+// @Summary Stream bid events for auction item
+// @Description Server-Sent Events stream that pushes every accepted bid and item status change (ongoing, finished, scheduled). The first event is a snapshot of the current highest bid.
+// @Tags Your Donate Rise API - Bidding
+// @Produce text/event-stream
+// @Param sessionID path int true "Auction Session ID"
+// @Param itemID path int true "Auction Item ID"
+// @Success 200 {object} dto.BidEventDTO "bid event stream"
+// @Failure 400 {object} utils.ErrorResponse "Bad request - Invalid session or item ID"
+// @Failure 404 {object} utils.ErrorResponse "Auction not found"
+// @Failure 409 {object} utils.ErrorResponse "Conflict - Item is not part of the session"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /auction/sessions/{sessionID}/items/{itemID}/stream [get]
+func (h *BidController) StreamBids(c echo.Context) error {
+	sessionIDStr := c.Param("sessionID")
+	itemIDStr := c.Param("itemID")
+
+	sessionID, err := strconv.ParseInt(sessionIDStr, 10, 64)
+	if err != nil {
+		return utils.BadRequestResponse(c, "invalid sessionID")
+	}
+
+	itemID, err := strconv.ParseInt(itemIDStr, 10, 64)
+	if err != nil {
+		return utils.BadRequestResponse(c, "invalid itemID")
+	}
+
+	ctx := c.Request().Context()
+	events, err := h.svc.SubscribeBidEvents(ctx, sessionID, itemID)
+	if err != nil {
+		switch err {
+		case service.ErrAuctionNotFound:
+			return utils.NotFoundResponse(c, err.Error())
+		case service.ErrInvalidAuction:
+			return utils.ConflictResponse(c, err.Error())
+		default:
+			return utils.InternalServerErrorResponse(c, "failed subscribing to bid stream")
+		}
+	}
+
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, "text/event-stream")
+	res.Header().Set(echo.HeaderCacheControl, "no-cache")
+	res.Header().Set(echo.HeaderConnection, "keep-alive")
+	res.Header().Set("X-Accel-Buffering", "no")
+	res.WriteHeader(http.StatusOK)
+
+	// subscribed before reading the snapshot, so no bid falls in between
+	highest, err := h.svc.GetHighestBid(sessionID, itemID)
+	if err == nil {
+		snapshot := dto.BidEventDTO{
+			Type:      dto.BidEventSnapshot,
+			SessionID: sessionID,
+			ItemID:    itemID,
+			Amount:    highest.HighestBid,
+			BidderID:  highest.BidderID,
+			Timestamp: time.Now(),
+		}
+		if err := writeBidEvent(res, snapshot); err != nil {
+			return nil
+		}
+	}
+
+	// keep idle connections alive behind proxies / Cloud Run
+	heartbeat := time.NewTicker(15 * time.Second)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-events:
+			if !ok {
+				return nil
+			}
+			if err := writeBidEvent(res, event); err != nil {
+				return nil
+			}
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(res, ": ping\n\n"); err != nil {
+				return nil
+			}
+			res.Flush()
+		}
+	}
+}
+
+func writeBidEvent(res *echo.Response, event dto.BidEventDTO) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(res, "event: %s\ndata: %s\n\n", event.Type, payload); err != nil {
+		return err
+	}
+	res.Flush()
+	return nil
+}
+
+// ladderScope reads the optional session and item ids of the increment ladder routes, none means the global ladder
+func ladderScope(c echo.Context) (*int64, *int64, error) {
+	var sessionID, itemID *int64
+	if raw := c.Param("sessionID"); raw != "" {
+		id, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return nil, nil, errors.New("invalid sessionID")
+		}
+		sessionID = &id
+	}
+	if raw := c.Param("id"); raw != "" {
+		id, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return nil, nil, errors.New("invalid auction item ID")
+		}
+		itemID = &id
+	}
+	return sessionID, itemID, nil
+}
+
+// GetIncrementLadder godoc
+// @Summary Get bid increment ladder
+// @Description Retrieve the increment tiers defined on the global ladder, a session or an item. An empty list on a session or item means it inherits the broader ladder.
+// @Tags Your Donate Rise API - Bidding
+// @Produce json
+// @Param sessionID path int false "Auction Session ID"
+// @Param id path int false "Auction Item ID"
+// @Success 200 {object} utils.SuccessResponseData "bid increment ladder retrieved successfully"
+// @Failure 400 {object} utils.ErrorResponse "Bad request - Invalid session or item ID"
+// @Failure 404 {object} utils.ErrorResponse "Auction session or item not found"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /auction/increments [get]
+// @Router /auction/sessions/{sessionID}/increments [get]
+// @Router /auction/items/{id}/increments [get]
+func (h *BidController) GetIncrementLadder(c echo.Context) error {
+	sessionID, itemID, err := ladderScope(c)
+	if err != nil {
+		return utils.BadRequestResponse(c, err.Error())
+	}
+
+	tiers, err := h.svc.GetIncrementLadder(sessionID, itemID)
+	if err != nil {
+		switch err {
+		case service.ErrAuctionNotFound, service.ErrSessionNotFoundID:
+			return utils.NotFoundResponse(c, err.Error())
+		default:
+			return utils.InternalServerErrorResponse(c, "failed retrieving bid increment ladder")
+		}
+	}
+
+	return utils.SuccessResponse(c, "bid increment ladder retrieved successfully", map[string]interface{}{"tiers": tiers})
+}
+
+// SetIncrementLadder godoc
+// @Summary Replace bid increment ladder (Admin only)
+// @Description Replace the increment tiers of the global ladder, a session or an item. Each tier applies from min_price upward, the step is increment plus percent of the current bid. An empty list removes a session or item override.
+// @Tags Your Donate Rise API - Bidding
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param sessionID path int false "Auction Session ID"
+// @Param id path int false "Auction Item ID"
+// @Param ladder body dto.BidIncrementLadderDTO true "Increment tiers"
+// @Success 200 {object} utils.SuccessResponseData "bid increment ladder updated successfully"
+// @Failure 400 {object} utils.ErrorResponse "Bad request - Invalid tiers"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized - Invalid or missing token"
+// @Failure 403 {object} utils.ErrorResponse "Forbidden - auction:manage required"
+// @Failure 404 {object} utils.ErrorResponse "Auction session or item not found"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /auction/increments [put]
+// @Router /auction/sessions/{sessionID}/increments [put]
+// @Router /auction/items/{id}/increments [put]
+func (h *BidController) SetIncrementLadder(c echo.Context) error {
+	sessionID, itemID, err := ladderScope(c)
+	if err != nil {
+		return utils.BadRequestResponse(c, err.Error())
+	}
+
+	var payload dto.BidIncrementLadderDTO
+	if err = c.Bind(&payload); err != nil {
+		return utils.BadRequestResponse(c, "invalid payload")
+	}
+
+	if err = h.validate.Struct(payload); err != nil {
+		return utils.BadRequestResponse(c, err.Error())
+	}
+
+	tiers, err := h.svc.SetIncrementLadder(sessionID, itemID, payload.Tiers)
+	if err != nil {
+		switch err {
+		case service.ErrInvalidIncrementLadder:
+			return utils.BadRequestResponse(c, err.Error())
+		case service.ErrAuctionNotFound, service.ErrSessionNotFoundID:
+			return utils.NotFoundResponse(c, err.Error())
+		default:
+			return utils.InternalServerErrorResponse(c, "failed updating bid increment ladder")
+		}
+	}
+
+	return utils.SuccessResponse(c, "bid increment ladder updated successfully", map[string]interface{}{"tiers": tiers})
 }
@@ -2,10 +2,9 @@ package controller
 
 import (
 	"errors"
-	"fmt"
+	"mime/multipart"
 	"strconv"
 	"strings"
-	"time"
 
 	"milestone3/be/internal/dto"
 	"milestone3/be/internal/entity"
@@ -18,13 +17,15 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
+const donationPhotoMaxBytes = 10 << 20
+
 type DonationController struct {
 	svc          service.DonationService
-	privateStore repository.GCPStorageRepo
+	privateStore repository.ObjectStore
 	validator    *validator.Validate
 }
 
-func NewDonationController(s service.DonationService, privateStore repository.GCPStorageRepo) *DonationController {
+func NewDonationController(s service.DonationService, privateStore repository.ObjectStore) *DonationController {
 	return &DonationController{
 		svc:          s,
 		privateStore: privateStore,
@@ -52,6 +53,8 @@ func NewDonationController(s service.DonationService, privateStore repository.GC
 // @Router /donations [post]
 func (h *DonationController) CreateDonation(c echo.Context) error {
 	var payload dto.DonationDTO
+	// only what this request stored is cleaned up on failure, never names sent by the client
+	var uploaded []string
 
 	contentType := c.Request().Header.Get("Content-Type")
 	if strings.HasPrefix(contentType, "multipart/form-data") {
@@ -80,45 +83,15 @@ func (h *DonationController) CreateDonation(c echo.Context) error {
 		payload.Category = form.Value["category"][0]
 		payload.Condition = form.Value["condition"][0]
 
-		if v, ok := form.Value["status"]; ok {
-			payload.Status = entity.StatusDonation(v[0])
-		}
-
 		// FILE HANDLING PRIVATE ONLY
 		if fhs, ok := form.File["photos"]; ok {
-			for _, fh := range fhs {
-				// Validate file size (max 10MB per file)
-				if fh.Size > 10*1024*1024 {
-					return utils.BadRequestResponse(c, "file size exceeds 10MB limit")
-				}
-
-				// Validate file type (only images)
-				contentType := fh.Header.Get("Content-Type")
-				if !strings.HasPrefix(contentType, "image/") {
-					return utils.BadRequestResponse(c, "only image files are allowed")
-				}
-
-				f, err := fh.Open()
-				if err != nil {
-					return utils.BadRequestResponse(c, "cannot open file")
-				}
-
-				// Sanitize filename to prevent path traversal
-				safeFilename := strings.ReplaceAll(fh.Filename, "..", "")
-				safeFilename = strings.ReplaceAll(safeFilename, "/", "")
-				safeFilename = strings.ReplaceAll(safeFilename, "\\", "")
-
-				objName := fmt.Sprintf("donations/private/%d_%s", time.Now().UnixNano(), safeFilename)
-				objectName, err := h.privateStore.UploadFile(c.Request().Context(), f, objName)
-				_ = f.Close()
-
-				if err != nil {
-					return utils.InternalServerErrorResponse(c, "failed upload")
-				}
-
-				// SAVE PRIVATE STORAGE (objectName)
-				payload.Photos = append(payload.Photos, objectName)
+			photos, objectNames, err := h.uploadPhotos(c, fhs)
+			if err != nil {
+				return err
 			}
+			// SAVE PRIVATE STORAGE (objectName)
+			payload.PhotoItems = photos
+			uploaded = objectNames
 		}
 
 	} else {
@@ -129,24 +102,25 @@ func (h *DonationController) CreateDonation(c echo.Context) error {
 
 	userID, ok := utils.GetUserID(c)
 	if !ok || userID == 0 {
+		deleteObjects(c.Request().Context(), h.privateStore, uploaded)
 		return utils.UnauthorizedResponse(c, "unauthenticated")
 	}
 	payload.UserID = userID
 
-	// Set default status if not provided
-	if payload.Status == "" {
-		payload.Status = entity.StatusPending
-	}
+	// every donation starts pending, it moves on through PATCH /donations/:id
+	payload.Status = entity.StatusPending
 
 	if err := h.validator.Struct(payload); err != nil {
+		deleteObjects(c.Request().Context(), h.privateStore, uploaded)
 		return utils.BadRequestResponse(c, err.Error())
 	}
 
 	if err := h.svc.CreateDonation(payload); err != nil {
+		deleteObjects(c.Request().Context(), h.privateStore, uploaded)
 		logrus.WithError(err).WithFields(logrus.Fields{
 			"user_id": userID,
 			"title":   payload.Title,
-			"photos":  len(payload.Photos),
+			"photos":  len(payload.Photos) + len(payload.PhotoItems),
 		}).Error("Failed to create donation in database")
 		return utils.InternalServerErrorResponse(c, "failed creating donation")
 	}
@@ -154,9 +128,39 @@ func (h *DonationController) CreateDonation(c echo.Context) error {
 	return utils.CreatedResponse(c, "donation created successfully", nil)
 }
 
+// uploadPhotos stores the photos and their variants in the private bucket and returns them with the
+// object names stored. on failure the response is written, the error returned is the one of writing
+// it, and nothing stays uploaded
+func (h *DonationController) uploadPhotos(c echo.Context, fhs []*multipart.FileHeader) ([]dto.DonationPhotoDTO, []string, error) {
+	if h.privateStore == nil {
+		return nil, nil, utils.InternalServerErrorResponse(c, "photo storage is not configured")
+	}
+
+	ctx := c.Request().Context()
+	photos := make([]dto.DonationPhotoDTO, 0, len(fhs))
+	var objectNames []string
+	for _, fh := range fhs {
+		// max 10MB per file, checked on the content as well
+		if fh.Size > donationPhotoMaxBytes {
+			deleteObjects(ctx, h.privateStore, objectNames)
+			return nil, nil, utils.BadRequestResponse(c, "file size exceeds 10MB limit")
+		}
+
+		img, err := uploadImage(ctx, h.privateStore, fh, "donations/private", donationPhotoMaxBytes)
+		if err != nil {
+			deleteObjects(ctx, h.privateStore, objectNames)
+			return nil, nil, imageUploadErrorResponse(c, err)
+		}
+
+		photos = append(photos, dto.DonationPhotoDTO{URL: img.Original, DisplayURL: img.Display, ThumbnailURL: img.Thumbnail})
+		objectNames = append(objectNames, img.objects...)
+	}
+	return photos, objectNames, nil
+}
+
 // GetAllDonations godoc
 // @Summary Get all donations
-// @Description Get all donations (admin sees all, users see only their own) with pagination
+// @Description Get all donations (donation:read_all sees all, users see only their own) with pagination
 // @Tags Your Donate Rise API - Donations
 // @Accept json
 // @Produce json
@@ -169,9 +173,9 @@ func (h *DonationController) CreateDonation(c echo.Context) error {
 // @Router /donations [get]
 func (h *DonationController) GetAllDonations(c echo.Context) error {
 	userID, _ := utils.GetUserID(c)
-	isAdm := utils.IsAdmin(c)
+	readAll := utils.HasPermission(c, entity.PermDonationReadAll)
 
-	if !isAdm {
+	if !readAll {
 		if userID == 0 {
 			return utils.UnauthorizedResponse(c, "unauthenticated")
 		}
@@ -190,7 +194,7 @@ func (h *DonationController) GetAllDonations(c echo.Context) error {
 		limit = 100
 	}
 
-	donations, total, err := h.svc.GetAllDonations(userID, isAdm, page, limit)
+	donations, total, err := h.svc.GetAllDonations(userID, readAll, page, limit)
 	if err != nil {
 		return utils.InternalServerErrorResponse(c, "failed fetching donations")
 	}
@@ -206,7 +210,7 @@ func (h *DonationController) GetAllDonations(c echo.Context) error {
 
 // GetDonationByID godoc
 // @Summary Get donation by ID
-// @Description Retrieve a specific donation by ID (owner or admin only)
+// @Description Retrieve a specific donation by ID (owner or donation:read_all only)
 // @Tags Your Donate Rise API - Donations
 // @Accept json
 // @Produce json
@@ -234,8 +238,8 @@ func (h *DonationController) GetDonationByID(c echo.Context) error {
 		return utils.InternalServerErrorResponse(c, "failed fetching donation")
 	}
 
-	// permission check: owner or admin
-	if !utils.IsAdmin(c) {
+	// permission check: owner or donation:read_all
+	if !utils.HasPermission(c, entity.PermDonationReadAll) {
 		userID, ok := utils.GetUserID(c)
 		if !ok {
 			return utils.UnauthorizedResponse(c, "unauthenticated")
@@ -250,7 +254,7 @@ func (h *DonationController) GetDonationByID(c echo.Context) error {
 
 // UpdateDonation godoc
 // @Summary Update donation
-// @Description Update an existing donation (owner or admin only)
+// @Description Update an existing donation (owner or donation:manage only) while it is pending or awaiting pickup
 // @Tags Your Donate Rise API - Donations
 // @Accept json
 // @Produce json
@@ -262,6 +266,7 @@ func (h *DonationController) GetDonationByID(c echo.Context) error {
 // @Failure 401 {object} utils.ErrorResponse "Unauthorized - Invalid or missing token"
 // @Failure 403 {object} utils.ErrorResponse "Forbidden - Access denied"
 // @Failure 404 {object} utils.ErrorResponse "Donation not found"
+// @Failure 409 {object} utils.ErrorResponse "Donation already received"
 // @Failure 500 {object} utils.ErrorResponse "Internal server error"
 // @Router /donations/{id} [put]
 func (h *DonationController) UpdateDonation(c echo.Context) error {
@@ -281,15 +286,18 @@ func (h *DonationController) UpdateDonation(c echo.Context) error {
 	if !ok {
 		return utils.UnauthorizedResponse(c, "unauthenticated")
 	}
-	isAdm := utils.IsAdmin(c)
+	canManage := utils.HasPermission(c, entity.PermDonationManage)
 
-	if err := h.svc.UpdateDonation(payload, userID, isAdm); err != nil {
+	if err := h.svc.UpdateDonation(payload, userID, canManage); err != nil {
 		if errors.Is(err, service.ErrDonationNotFound) {
 			return utils.NotFoundResponse(c, "donation not found")
 		}
 		if errors.Is(err, service.ErrForbidden) {
 			return utils.ForbiddenResponse(c, "forbidden")
 		}
+		if errors.Is(err, service.ErrInvalidDonationTransition) {
+			return utils.ConflictResponse(c, "donation can no longer be changed once the item is received")
+		}
 		return utils.InternalServerErrorResponse(c, "failed updating donation")
 	}
 	return utils.SuccessResponse(c, "donation updated", nil)
@@ -297,7 +305,7 @@ func (h *DonationController) UpdateDonation(c echo.Context) error {
 
 // DeleteDonation godoc
 // @Summary Delete donation
-// @Description Delete a donation by ID (owner or admin only)
+// @Description Delete a donation by ID (owner or donation:manage only) while it is pending, awaiting pickup or cancelled
 // @Tags Your Donate Rise API - Donations
 // @Accept json
 // @Produce json
@@ -308,6 +316,7 @@ func (h *DonationController) UpdateDonation(c echo.Context) error {
 // @Failure 401 {object} utils.ErrorResponse "Unauthorized - Invalid or missing token"
 // @Failure 403 {object} utils.ErrorResponse "Forbidden - Access denied"
 // @Failure 404 {object} utils.ErrorResponse "Donation not found"
+// @Failure 409 {object} utils.ErrorResponse "Donation already received"
 // @Failure 500 {object} utils.ErrorResponse "Internal server error"
 // @Router /donations/{id} [delete]
 func (h *DonationController) DeleteDonation(c echo.Context) error {
@@ -321,41 +330,41 @@ func (h *DonationController) DeleteDonation(c echo.Context) error {
 	if !ok {
 		return utils.UnauthorizedResponse(c, "unauthenticated")
 	}
-	isAdm := utils.IsAdmin(c)
+	canManage := utils.HasPermission(c, entity.PermDonationManage)
 
-	if err := h.svc.DeleteDonation(uint(id64), userID, isAdm); err != nil {
+	if err := h.svc.DeleteDonation(uint(id64), userID, canManage); err != nil {
 		if errors.Is(err, service.ErrDonationNotFound) {
 			return utils.NotFoundResponse(c, "donation not found")
 		}
 		if errors.Is(err, service.ErrForbidden) {
 			return utils.ForbiddenResponse(c, "forbidden")
 		}
+		if errors.Is(err, service.ErrInvalidDonationTransition) {
+			return utils.ConflictResponse(c, "donation can no longer be changed once the item is received")
+		}
 		return utils.InternalServerErrorResponse(c, "failed deleting donation")
 	}
 	return utils.NoContentResponse(c)
 }
 
 // PatchDonation godoc
-// @Summary Approve/update donation status
-// @Description Update donation status for approval (admin only)
+// @Summary Move donation to its next status
+// @Description Move a donation along its lifecycle: pending -> awaiting_pickup -> received -> verified_for_auction / verified_for_donation -> auctioned / distributed. Holders of donation:verify make every move except distributed, which needs donation:distribute, and cancelled_by_donor, which only the donor can make before the item is received. Rejecting needs a reason.
 // @Tags Your Donate Rise API - Donations
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param id path int true "Donation ID"
-// @Param approval body dto.DonationApprovalDTO true "Approval status"
+// @Param approval body dto.DonationApprovalDTO true "Next status and reason"
 // @Success 200 {object} utils.SuccessResponseData "donation patched"
-// @Failure 400 {object} utils.ErrorResponse "Bad request - Invalid ID or payload"
+// @Failure 400 {object} utils.ErrorResponse "Bad request - Invalid ID or payload, or rejection without a reason"
 // @Failure 401 {object} utils.ErrorResponse "Unauthorized - Invalid or missing token"
-// @Failure 403 {object} utils.ErrorResponse "Forbidden - Admin only"
+// @Failure 403 {object} utils.ErrorResponse "Forbidden - Role lacks the permission for this transition"
 // @Failure 404 {object} utils.ErrorResponse "Donation not found"
+// @Failure 409 {object} utils.ErrorResponse "Transition not allowed from the current status, or the auction item is already scheduled"
 // @Failure 500 {object} utils.ErrorResponse "Internal server error"
 // @Router /donations/{id} [patch]
 func (h *DonationController) PatchDonation(c echo.Context) error {
-	if !utils.IsAdmin(c) {
-		return utils.ForbiddenResponse(c, "admin only")
-	}
-
 	idParam := c.Param("id")
 	id64, err := strconv.ParseUint(idParam, 10, 64)
 	if err != nil {
@@ -371,18 +380,181 @@ func (h *DonationController) PatchDonation(c echo.Context) error {
 		return utils.BadRequestResponse(c, err.Error())
 	}
 
-	var payload dto.DonationDTO
-	payload.ID = uint(id64)
-	payload.Status = approvalPayload.Status
+	userID, ok := utils.GetUserID(c)
+	if !ok {
+		return utils.UnauthorizedResponse(c, "unauthenticated")
+	}
+
+	if err := h.svc.TransitionDonation(uint(id64), approvalPayload, userID, utils.GetRole(c), utils.GetPermissions(c)); err != nil {
+		switch {
+		case errors.Is(err, service.ErrDonationNotFound):
+			return utils.NotFoundResponse(c, "donation not found")
+		case errors.Is(err, service.ErrForbidden):
+			return utils.ForbiddenResponse(c, "forbidden")
+		case errors.Is(err, service.ErrInvalidDonationTransition), errors.Is(err, service.ErrDonationItemInAuction):
+			return utils.ConflictResponse(c, err.Error())
+		case errors.Is(err, service.ErrRejectionReasonRequired):
+			return utils.BadRequestResponse(c, err.Error())
+		}
+		return utils.InternalServerErrorResponse(c, "failed patching donation")
+	}
+	return utils.SuccessResponse(c, "donation patched", nil)
+}
 
-	if err := h.svc.PatchDonation(payload, 0, true); err != nil {
+// GetDonationHistory godoc
+// @Summary Get donation status history
+// @Description List every status transition of a donation, oldest first (owner or donation:read_all only)
+// @Tags Your Donate Rise API - Donations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Donation ID"
+// @Success 200 {object} utils.SuccessResponseData "donation history fetched"
+// @Failure 400 {object} utils.ErrorResponse "Bad request - Invalid donation ID"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized - Invalid or missing token"
+// @Failure 403 {object} utils.ErrorResponse "Forbidden - Access denied"
+// @Failure 404 {object} utils.ErrorResponse "Donation not found"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /donations/{id}/history [get]
+func (h *DonationController) GetDonationHistory(c echo.Context) error {
+	idParam := c.Param("id")
+	id64, err := strconv.ParseUint(idParam, 10, 64)
+	if err != nil {
+		return utils.BadRequestResponse(c, "invalid id")
+	}
+
+	userID, ok := utils.GetUserID(c)
+	if !ok {
+		return utils.UnauthorizedResponse(c, "unauthenticated")
+	}
+	readAll := utils.HasPermission(c, entity.PermDonationReadAll)
+
+	history, err := h.svc.GetDonationHistory(uint(id64), userID, readAll)
+	if err != nil {
 		if errors.Is(err, service.ErrDonationNotFound) {
 			return utils.NotFoundResponse(c, "donation not found")
 		}
 		if errors.Is(err, service.ErrForbidden) {
 			return utils.ForbiddenResponse(c, "forbidden")
 		}
-		return utils.InternalServerErrorResponse(c, "failed patching donation")
+		return utils.InternalServerErrorResponse(c, "failed fetching donation history")
 	}
-	return utils.SuccessResponse(c, "donation patched", nil)
+	return utils.SuccessResponse(c, "donation history fetched", history)
+}
+
+// AddDonationPhotos godoc
+// @Summary Add photos to a donation
+// @Description Upload more photos to an existing donation (owner or donation:manage only). The response carries signed photo URLs.
+// @Tags Your Donate Rise API - Donations
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Donation ID"
+// @Param photos formData file true "Donation photos (multiple files allowed)"
+// @Success 201 {object} utils.SuccessResponseData "donation photos added"
+// @Failure 400 {object} utils.ErrorResponse "Bad request - Invalid ID or file upload"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized - Invalid or missing token"
+// @Failure 403 {object} utils.ErrorResponse "Forbidden - Access denied"
+// @Failure 404 {object} utils.ErrorResponse "Donation not found"
+// @Failure 409 {object} utils.ErrorResponse "Donation already received"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /donations/{id}/photos [post]
+func (h *DonationController) AddDonationPhotos(c echo.Context) error {
+	idParam := c.Param("id")
+	id64, err := strconv.ParseUint(idParam, 10, 64)
+	if err != nil {
+		return utils.BadRequestResponse(c, "invalid id")
+	}
+
+	userID, ok := utils.GetUserID(c)
+	if !ok {
+		return utils.UnauthorizedResponse(c, "unauthenticated")
+	}
+	canManage := utils.HasPermission(c, entity.PermDonationManage)
+
+	// nothing is read, resized or stored for a donation the caller cannot change
+	if err := h.svc.CheckDonationEditable(uint(id64), userID, canManage); err != nil {
+		return donationPhotosErrorResponse(c, err)
+	}
+
+	if err := c.Request().ParseMultipartForm(32 << 20); err != nil {
+		return utils.BadRequestResponse(c, "invalid multipart form")
+	}
+	fhs := c.Request().MultipartForm.File["photos"]
+	if len(fhs) == 0 {
+		return utils.BadRequestResponse(c, "photos is required")
+	}
+
+	photos, objectNames, err := h.uploadPhotos(c, fhs)
+	if err != nil || photos == nil {
+		return err
+	}
+
+	d, err := h.svc.AddDonationPhotos(uint(id64), photos, userID, canManage)
+	if err != nil {
+		deleteObjects(c.Request().Context(), h.privateStore, objectNames)
+		return donationPhotosErrorResponse(c, err)
+	}
+	return utils.CreatedResponse(c, "donation photos added", d)
+}
+
+func donationPhotosErrorResponse(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, service.ErrDonationNotFound):
+		return utils.NotFoundResponse(c, "donation not found")
+	case errors.Is(err, service.ErrForbidden):
+		return utils.ForbiddenResponse(c, "forbidden")
+	case errors.Is(err, service.ErrInvalidDonationTransition):
+		return utils.ConflictResponse(c, "donation can no longer be changed once the item is received")
+	}
+	return utils.InternalServerErrorResponse(c, "failed adding donation photos")
+}
+
+// RemoveDonationPhoto godoc
+// @Summary Remove a donation photo
+// @Description Remove a photo from a donation and delete the stored object (owner or donation:manage only)
+// @Tags Your Donate Rise API - Donations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Donation ID"
+// @Param photoId path int true "Photo ID"
+// @Success 204 "Photo removed successfully"
+// @Failure 400 {object} utils.ErrorResponse "Bad request - Invalid ID"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized - Invalid or missing token"
+// @Failure 403 {object} utils.ErrorResponse "Forbidden - Access denied"
+// @Failure 404 {object} utils.ErrorResponse "Donation or photo not found"
+// @Failure 409 {object} utils.ErrorResponse "Donation already received"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /donations/{id}/photos/{photoId} [delete]
+func (h *DonationController) RemoveDonationPhoto(c echo.Context) error {
+	id64, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return utils.BadRequestResponse(c, "invalid id")
+	}
+	photoID64, err := strconv.ParseUint(c.Param("photoId"), 10, 64)
+	if err != nil {
+		return utils.BadRequestResponse(c, "invalid photo id")
+	}
+
+	userID, ok := utils.GetUserID(c)
+	if !ok {
+		return utils.UnauthorizedResponse(c, "unauthenticated")
+	}
+	canManage := utils.HasPermission(c, entity.PermDonationManage)
+
+	if err := h.svc.RemoveDonationPhoto(uint(id64), uint(photoID64), userID, canManage); err != nil {
+		switch {
+		case errors.Is(err, service.ErrDonationNotFound):
+			return utils.NotFoundResponse(c, "donation not found")
+		case errors.Is(err, service.ErrDonationPhotoNotFound):
+			return utils.NotFoundResponse(c, "photo not found")
+		case errors.Is(err, service.ErrForbidden):
+			return utils.ForbiddenResponse(c, "forbidden")
+		case errors.Is(err, service.ErrInvalidDonationTransition):
+			return utils.ConflictResponse(c, "donation can no longer be changed once the item is received")
+		}
+		return utils.InternalServerErrorResponse(c, "failed removing donation photo")
+	}
+	return utils.NoContentResponse(c)
 }
@@ -0,0 +1,79 @@
+package controller
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"milestone3/be/internal/mocks"
+	"milestone3/be/internal/service"
+
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDonationController_AddDonationPhotos_ChecksAccessFirst(t *testing.T) {
+	tests := []struct {
+		name           string
+		setup          func(mockService *mocks.MockDonationService)
+		expectedStatus int
+	}{
+		{
+			name: "missing donation",
+			setup: func(mockService *mocks.MockDonationService) {
+				mockService.EXPECT().CheckDonationEditable(uint(1), uint(3), false).Return(service.ErrDonationNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name: "donation of another user",
+			setup: func(mockService *mocks.MockDonationService) {
+				mockService.EXPECT().CheckDonationEditable(uint(1), uint(3), false).Return(service.ErrForbidden)
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name: "donation already received",
+			setup: func(mockService *mocks.MockDonationService) {
+				mockService.EXPECT().CheckDonationEditable(uint(1), uint(3), false).Return(service.ErrInvalidDonationTransition)
+			},
+			expectedStatus: http.StatusConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mocks.NewMockDonationService(ctrl)
+			// no upload reaches the store and the service never attaches anything
+			mockStore := mocks.NewMockObjectStore(ctrl)
+			tt.setup(mockService)
+
+			body := &bytes.Buffer{}
+			writer := multipart.NewWriter(body)
+			part, err := writer.CreateFormFile("photos", "photo.jpg")
+			assert.NoError(t, err)
+			_, err = part.Write([]byte("not an image"))
+			assert.NoError(t, err)
+			assert.NoError(t, writer.Close())
+
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodPost, "/donations/1/photos", body)
+			req.Header.Set(echo.HeaderContentType, writer.FormDataContentType())
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetParamNames("id")
+			c.SetParamValues("1")
+			c.Set("user_id", uint(3))
+
+			err = NewDonationController(mockService, mockStore).AddDonationPhotos(c)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+
+	"milestone3/be/internal/repository"
+	"milestone3/be/internal/utils"
+
+	"github.com/labstack/echo/v4"
+)
+
+// FileController serves the objects of the local filesystem storage backend
+type FileController struct {
+	stores map[string]repository.LocalObjectStore
+}
+
+func NewFileController(stores ...repository.LocalObjectStore) *FileController {
+	byBucket := make(map[string]repository.LocalObjectStore, len(stores))
+	for _, s := range stores {
+		byBucket[s.Bucket()] = s
+	}
+	return &FileController{stores: byBucket}
+}
+
+// ServeFile godoc
+// @Summary Download a stored file
+// @Description Serves a file of the local storage backend. Files of the private bucket need the expires and signature query of a signed URL handed out by the API.
+// @Tags Your Donate Rise API - Files
+// @Produce octet-stream
+// @Param bucket path string true "Bucket"
+// @Param object path string true "Object name"
+// @Param expires query int false "Expiry of the signed URL, unix seconds"
+// @Param signature query string false "Signature of the signed URL"
+// @Success 200 {file} file "File content"
+// @Failure 403 {object} utils.ErrorResponse "Forbidden - Invalid or expired signature"
+// @Failure 404 {object} utils.ErrorResponse "File not found"
+// @Router /files/{bucket}/{object} [get]
+func (h *FileController) ServeFile(c echo.Context) error {
+	store, ok := h.stores[c.Param("bucket")]
+	if !ok {
+		return utils.NotFoundResponse(c, "file not found")
+	}
+	objectName, err := url.PathUnescape(c.Param("*"))
+	if err != nil {
+		return utils.NotFoundResponse(c, "file not found")
+	}
+
+	if err := store.Authorize(objectName, c.QueryParam("expires"), c.QueryParam("signature")); err != nil {
+		if errors.Is(err, repository.ErrSignatureExpired) {
+			return utils.ForbiddenResponse(c, "download link expired")
+		}
+		return utils.ForbiddenResponse(c, "invalid download link")
+	}
+
+	f, info, err := store.Open(objectName)
+	if err != nil {
+		if errors.Is(err, repository.ErrObjectNotFound) || errors.Is(err, repository.ErrInvalidObjectName) {
+			return utils.NotFoundResponse(c, "file not found")
+		}
+		return utils.InternalServerErrorResponse(c, "failed reading file")
+	}
+	defer f.Close()
+
+	c.Response().Header().Set(echo.HeaderContentType, info.ContentType)
+	http.ServeContent(c.Response(), c.Request(), info.Name, info.UpdatedAt, f)
+	return nil
+}
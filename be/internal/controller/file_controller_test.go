@@ -0,0 +1,89 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"milestone3/be/internal/repository"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileController_ServeFile(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	public := repository.NewLocalObjectStore(dir, "public", "http://localhost:8080", []byte("signing-key"), true)
+	private := repository.NewLocalObjectStore(dir, "private", "http://localhost:8080", []byte("signing-key"), false)
+
+	publicURL, err := public.Upload(ctx, strings.NewReader("article"), "articles/1_a b.png", "image/png")
+	assert.NoError(t, err)
+	_, err = private.Upload(ctx, strings.NewReader("photo"), "donations/private/1_a.jpg", "image/jpeg")
+	assert.NoError(t, err)
+	signedURL, err := private.SignedURL(ctx, "donations/private/1_a.jpg", 10*time.Minute)
+	assert.NoError(t, err)
+
+	e := echo.New()
+	e.GET("/files/:bucket/*", NewFileController(public, private).ServeFile)
+
+	tests := []struct {
+		name           string
+		target         string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "public file without signature",
+			target:         publicURL,
+			expectedStatus: http.StatusOK,
+			expectedBody:   "article",
+		},
+		{
+			name:           "private file with signature",
+			target:         signedURL,
+			expectedStatus: http.StatusOK,
+			expectedBody:   "photo",
+		},
+		{
+			name:           "private file without signature",
+			target:         "/files/private/donations/private/1_a.jpg",
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "signature of another file",
+			target:         strings.Replace(signedURL, "1_a.jpg", "2_b.jpg", 1),
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "missing file",
+			target:         "/files/public/articles/missing.png",
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "unknown bucket",
+			target:         "/files/other/articles/1_a.png",
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, err := url.Parse(tt.target)
+			assert.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodGet, u.RequestURI(), nil)
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			if tt.expectedBody != "" {
+				assert.Equal(t, tt.expectedBody, rec.Body.String())
+			}
+		})
+	}
+}
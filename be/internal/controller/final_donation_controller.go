@@ -2,6 +2,7 @@ package controller
 
 import (
 	"milestone3/be/internal/dto"
+	"milestone3/be/internal/entity"
 	"milestone3/be/internal/service"
 	"milestone3/be/internal/utils"
 	"strconv"
@@ -24,7 +25,7 @@ func NewFinalDonationController(finalDonationService service.FinalDonationServic
 
 // GetAllFinalDonations godoc
 // @Summary Get all final donations
-// @Description Retrieve all items that were directly donated to institutions with pagination (admin sees all, user sees own)
+// @Description Retrieve all items that were directly donated to institutions with pagination (final_donation:read_all sees all, user sees own)
 // @Tags Your Donate Rise API - Final Donations
 // @Accept json
 // @Produce json
@@ -41,10 +42,8 @@ func (h *FinalDonationController) GetAllFinalDonations(c echo.Context) error {
 		return utils.UnauthorizedResponse(c, "unauthenticated")
 	}
 
-	isAdmin := utils.IsAdmin(c)
-
-	// Admin sees all with pagination, user sees own
-	if isAdmin {
+	// final_donation:read_all sees all with pagination, user sees own
+	if utils.HasPermission(c, entity.PermFinalDonationReadAll) {
 		page, _ := strconv.Atoi(c.QueryParam("page"))
 		if page < 1 {
 			page = 1
@@ -104,7 +103,7 @@ func (h *FinalDonationController) GetMyFinalDonations(c echo.Context) error {
 
 // GetAllFinalDonationsByUserID godoc
 // @Summary Get final donations by user ID
-// @Description Retrieve all final donations made by a specific user (admin only)
+// @Description Retrieve all final donations made by a specific user (final_donation:read_all only)
 // @Tags Your Donate Rise API - Final Donations
 // @Accept json
 // @Produce json
@@ -113,12 +112,12 @@ func (h *FinalDonationController) GetMyFinalDonations(c echo.Context) error {
 // @Success 200 {object} utils.SuccessResponseData "Final donations fetched successfully"
 // @Failure 400 {object} utils.ErrorResponse "Bad request - Invalid user ID"
 // @Failure 401 {object} utils.ErrorResponse "Unauthorized - Invalid or missing token"
-// @Failure 403 {object} utils.ErrorResponse "Forbidden - Admin access required"
+// @Failure 403 {object} utils.ErrorResponse "Forbidden - final_donation:read_all required"
 // @Failure 500 {object} utils.ErrorResponse "Internal server error"
 // @Router /donations/final/user/{user_id} [get]
 func (h *FinalDonationController) GetAllFinalDonationsByUserID(c echo.Context) error {
-	if !utils.IsAdmin(c) {
-		return utils.ForbiddenResponse(c, "admin only")
+	if !utils.HasPermission(c, entity.PermFinalDonationReadAll) {
+		return utils.ForbiddenResponse(c, "forbidden")
 	}
 
 	userIDStr := c.Param("user_id")
@@ -0,0 +1,89 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"path"
+	"strings"
+	"time"
+
+	"milestone3/be/internal/repository"
+	"milestone3/be/internal/utils"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+)
+
+// uploadedImage holds what the store returned for an image and its variants, URLs for a public
+// store and object names for a private one. objects are the object names, for cleaning up
+type uploadedImage struct {
+	Original  string
+	Display   string
+	Thumbnail string
+	objects   []string
+}
+
+// uploadImage runs the file through the image pipeline and stores the cleaned original with its
+// display and thumbnail variants under prefix. nothing stays stored when it fails
+func uploadImage(ctx context.Context, store repository.ObjectStore, fh *multipart.FileHeader, prefix string, maxBytes int64) (uploadedImage, error) {
+	f, err := fh.Open()
+	if err != nil {
+		return uploadedImage{}, err
+	}
+	defer f.Close()
+
+	processed, err := utils.ProcessImage(f, maxBytes)
+	if err != nil {
+		return uploadedImage{}, err
+	}
+
+	// Sanitize filename to prevent path traversal, the extension follows the re-encoded format
+	safeFilename := strings.ReplaceAll(fh.Filename, "..", "")
+	safeFilename = strings.ReplaceAll(safeFilename, "/", "")
+	safeFilename = strings.ReplaceAll(safeFilename, "\\", "")
+	safeFilename = strings.TrimSuffix(safeFilename, path.Ext(safeFilename))
+	base := fmt.Sprintf("%s/%d_%s", prefix, time.Now().UnixNano(), safeFilename)
+
+	var res uploadedImage
+	for _, v := range []struct {
+		variant utils.ImageVariant
+		name    string
+		dst     *string
+	}{
+		{processed.Original, base + processed.Original.Ext, &res.Original},
+		{processed.Display, base + "_display" + processed.Display.Ext, &res.Display},
+		{processed.Thumbnail, base + "_thumb" + processed.Thumbnail.Ext, &res.Thumbnail},
+	} {
+		stored, err := store.Upload(ctx, bytes.NewReader(v.variant.Data), v.name, v.variant.ContentType)
+		if err != nil {
+			deleteObjects(ctx, store, res.objects)
+			return uploadedImage{}, err
+		}
+		*v.dst = stored
+		res.objects = append(res.objects, v.name)
+	}
+	return res, nil
+}
+
+// deleteObjects removes objects uploaded for a request that failed, so they do not stay orphaned
+func deleteObjects(ctx context.Context, store repository.ObjectStore, objectNames []string) {
+	if store == nil {
+		return
+	}
+	for _, name := range objectNames {
+		if err := store.Delete(ctx, name); err != nil {
+			logrus.WithError(err).WithField("object", name).Warn("Failed to delete uploaded object")
+		}
+	}
+}
+
+// imageUploadErrorResponse answers a failed uploadImage, a rejected file is the client's fault
+func imageUploadErrorResponse(c echo.Context, err error) error {
+	if errors.Is(err, utils.ErrUnsupportedImage) || errors.Is(err, utils.ErrImageTooLarge) {
+		return utils.BadRequestResponse(c, err.Error())
+	}
+	return utils.InternalServerErrorResponse(c, "failed upload")
+}
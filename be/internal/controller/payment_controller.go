@@ -1,7 +1,9 @@
 package controller
 
 import (
+	"io"
 	"milestone3/be/internal/dto"
+	"milestone3/be/internal/service"
 	"milestone3/be/internal/utils"
 	"strconv"
 
@@ -12,9 +14,12 @@ import (
 
 type PaymentService interface {
 	CreatePayment(req dto.PaymentRequest, userId int, auctionItemId int) (res dto.PaymentResponse, err error)
-	CheckPaymentStatusMidtrans(orderId string) (res dto.CheckPaymentStatusResponse, err error)
+	CheckPaymentStatus(orderId string) (res dto.CheckPaymentStatusResponse, err error)
+	HandleNotification(body []byte) error
 	GetPaymentById(id int) (res dto.PaymentInfoResponse, err error)
 	GetAllPayment() (res []dto.PaymentInfoResponse, err error)
+	RefundPayment(paymentId int, actorId int, req dto.PaymentRefundRequest) (res dto.PaymentRefundResponse, err error)
+	GetRefunds(paymentId int) (res []dto.PaymentRefundResponse, err error)
 }
 
 type PaymentController struct {
@@ -28,7 +33,7 @@ func NewPaymentController(validate *validator.Validate, ps PaymentService) *Paym
 
 // CreatePayment godoc
 // @Summary Create payment for auction item
-// @Description Create a payment transaction for a won auction item
+// @Description Charge the winner of a finished auction item with the chosen payment method. The amount is the winning bid, or the own bid of a bidder offered a second chance.
 // @Tags Your Donate Rise API - Payments
 // @Accept json
 // @Produce json
@@ -38,6 +43,9 @@ func NewPaymentController(validate *validator.Validate, ps PaymentService) *Paym
 // @Success 201 {object} utils.SuccessResponseData "create"
 // @Failure 400 {object} utils.ErrorResponse "Bad request - Invalid payload or auction ID"
 // @Failure 401 {object} utils.ErrorResponse "Unauthorized - Invalid or missing token"
+// @Failure 403 {object} utils.ErrorResponse "Forbidden - Not the winning bidder"
+// @Failure 404 {object} utils.ErrorResponse "Auction item not found"
+// @Failure 409 {object} utils.ErrorResponse "Item not finished, already paid or a payment already in progress"
 // @Failure 500 {object} utils.ErrorResponse "Internal server error"
 // @Router /payments/auction/{auctionId} [post]
 func (pc *PaymentController) CreatePayment(c echo.Context) error {
@@ -62,15 +70,24 @@ func (pc *PaymentController) CreatePayment(c echo.Context) error {
 
 	resp, err := pc.paymentService.CreatePayment(*req, userId, auctionId)
 	if err != nil {
-		return utils.InternalServerErrorResponse(c, "internal server error")
+		switch err {
+		case service.ErrAuctionNotFoundID:
+			return utils.NotFoundResponse(c, err.Error())
+		case service.ErrNotAuctionWinner:
+			return utils.ForbiddenResponse(c, err.Error())
+		case service.ErrItemNotFinished, service.ErrItemAlreadyPaid, service.ErrWinnerPaymentNotOpen, service.ErrDuplicatePayment:
+			return utils.ConflictResponse(c, err.Error())
+		default:
+			return utils.InternalServerErrorResponse(c, "internal server error")
+		}
 	}
 
 	return utils.CreatedResponse(c, "create", resp)
 }
 
-// CheckPaymentStatusMidtrans godoc
-// @Summary Check payment status via Midtrans
-// @Description Check the payment status of an order through Midtrans payment gateway
+// CheckPaymentStatus godoc
+// @Summary Check payment status with the gateway
+// @Description Check the payment status of an order with the configured payment gateway
 // @Tags Your Donate Rise API - Payments
 // @Accept json
 // @Produce json
@@ -78,9 +95,9 @@ func (pc *PaymentController) CreatePayment(c echo.Context) error {
 // @Success 200 {object} utils.SuccessResponseData "ok"
 // @Failure 500 {object} utils.ErrorResponse "Internal server error"
 // @Router /payments/status/{id} [get]
-func (pc *PaymentController) CheckPaymentStatusMidtrans(c echo.Context) error {
+func (pc *PaymentController) CheckPaymentStatus(c echo.Context) error {
 	orderId := c.Param("id")
-	resp, err := pc.paymentService.CheckPaymentStatusMidtrans(orderId)
+	resp, err := pc.paymentService.CheckPaymentStatus(orderId)
 	if err != nil {
 		return utils.InternalServerErrorResponse(c, "internal server error")
 	}
@@ -88,6 +105,44 @@ func (pc *PaymentController) CheckPaymentStatusMidtrans(c echo.Context) error {
 	return utils.SuccessResponse(c, "ok", resp)
 }
 
+// HandleNotification godoc
+// @Summary Payment gateway HTTP notification
+// @Description Public webhook for payment gateway notifications in the Midtrans format. The signature_key is verified against the server key, delivering the same notification again is harmless.
+// @Tags Your Donate Rise API - Payments
+// @Accept json
+// @Produce json
+// @Param notification body dto.MidtransNotification true "Midtrans notification"
+// @Success 200 {object} utils.SuccessResponseData "ok"
+// @Failure 400 {object} utils.ErrorResponse "Bad request - Invalid payload or amount mismatch"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized - Invalid signature"
+// @Failure 404 {object} utils.ErrorResponse "Payment not found"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /payments/notifications [post]
+func (pc *PaymentController) HandleNotification(c echo.Context) error {
+	// the gateway verifies the raw body, not a re-encoded one
+	body, err := io.ReadAll(c.Request().Body)
+	if err != nil {
+		return utils.BadRequestResponse(c, "invalid payload")
+	}
+
+	err = pc.paymentService.HandleNotification(body)
+	if err != nil {
+		switch err {
+		case service.ErrInvalidSignature:
+			return utils.UnauthorizedResponse(c, err.Error())
+		case service.ErrPaymentNotFound:
+			return utils.NotFoundResponse(c, err.Error())
+		case service.ErrInvalidNotification, service.ErrPaymentAmountMismatch:
+			return utils.BadRequestResponse(c, err.Error())
+		default:
+			// midtrans retries the notification on a non 2xx response
+			return utils.InternalServerErrorResponse(c, "internal server error")
+		}
+	}
+
+	return utils.SuccessResponse(c, "ok", nil)
+}
+
 // GetPaymentById godoc
 // @Summary Get payment by ID
 // @Description Retrieve payment information by payment ID
@@ -129,5 +184,87 @@ func (pc *PaymentController) GetAllPayment(c echo.Context) error {
 		return utils.InternalServerErrorResponse(c, "internal server error")
 	}
 
+	return utils.SuccessResponse(c, "ok", resp)
+}
+
+// RefundPayment godoc
+// @Summary Refund a payment (admin only)
+// @Description Refund a paid payment in full or in part through the payment gateway. Without an amount everything left on the payment is refunded. reopen_item puts the auction item back to scheduled.
+// @Tags Your Donate Rise API - Payments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Payment ID"
+// @Param refund body dto.PaymentRefundRequest true "Refund details"
+// @Success 201 {object} utils.SuccessResponseData "refunded"
+// @Failure 400 {object} utils.ErrorResponse "Bad request - Invalid payload or amount above what is left"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized - Invalid or missing token"
+// @Failure 403 {object} utils.ErrorResponse "Forbidden - Admin access required"
+// @Failure 404 {object} utils.ErrorResponse "Payment not found"
+// @Failure 409 {object} utils.ErrorResponse "Payment not paid or a refund is in progress"
+// @Failure 422 {object} utils.ErrorResponse "The payment gateway rejected the refund"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /payments/{id}/refunds [post]
+func (pc *PaymentController) RefundPayment(c echo.Context) error {
+	user := c.Get("user").(*jwt.Token)
+	claim := user.Claims.(jwt.MapClaims)
+	actorId := int(claim["id"].(float64))
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return utils.BadRequestResponse(c, err.Error())
+	}
+
+	req := new(dto.PaymentRefundRequest)
+	if err := c.Bind(req); err != nil {
+		return utils.BadRequestResponse(c, err.Error())
+	}
+
+	if err := pc.validate.Struct(req); err != nil {
+		return utils.BadRequestResponse(c, err.Error())
+	}
+
+	resp, err := pc.paymentService.RefundPayment(id, actorId, *req)
+	if err != nil {
+		switch err {
+		case service.ErrPaymentNotFound:
+			return utils.NotFoundResponse(c, err.Error())
+		case service.ErrPaymentNotRefundable:
+			return utils.ConflictResponse(c, err.Error())
+		case service.ErrRefundExceedsPayment:
+			return utils.BadRequestResponse(c, err.Error())
+		case service.ErrRefundRejected:
+			return utils.UnprocessableEntityResponse(c, err.Error())
+		default:
+			return utils.InternalServerErrorResponse(c, "internal server error")
+		}
+	}
+
+	return utils.CreatedResponse(c, "refunded", resp)
+}
+
+// GetRefunds godoc
+// @Summary List the refunds of a payment (admin only)
+// @Description List every refund issued on a payment, including refunds the gateway rejected
+// @Tags Your Donate Rise API - Payments
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Payment ID"
+// @Success 200 {object} utils.SuccessResponseData "ok"
+// @Failure 400 {object} utils.ErrorResponse "Bad request - Invalid payment ID"
+// @Failure 403 {object} utils.ErrorResponse "Forbidden - Admin access required"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /payments/{id}/refunds [get]
+func (pc *PaymentController) GetRefunds(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return utils.BadRequestResponse(c, err.Error())
+	}
+
+	resp, err := pc.paymentService.GetRefunds(id)
+	if err != nil {
+		return utils.InternalServerErrorResponse(c, "internal server error")
+	}
+
 	return utils.SuccessResponse(c, "ok", resp)
 }
\ No newline at end of file
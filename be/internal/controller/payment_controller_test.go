@@ -3,18 +3,26 @@ package controller
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"milestone3/be/internal/dto"
+	"milestone3/be/internal/entity"
 	"milestone3/be/internal/mocks"
+	"milestone3/be/internal/repository"
+	"milestone3/be/internal/service"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/golang/mock/gomock"
 	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
 )
 
 func TestPaymentController_CreatePayment(t *testing.T) {
@@ -37,7 +45,7 @@ func TestPaymentController_CreatePayment(t *testing.T) {
 			name:      "successful payment creation",
 			auctionId: "1",
 			requestBody: dto.PaymentRequest{
-				Amount: 100000,
+				PaymentMethod: dto.PaymentMethodBcaVa,
 			},
 			setupAuth: func(c echo.Context) {
 				token := &jwt.Token{
@@ -49,9 +57,10 @@ func TestPaymentController_CreatePayment(t *testing.T) {
 			},
 			setupMock: func() {
 				mockService.EXPECT().CreatePayment(gomock.Any(), 1, 1).Return(dto.PaymentResponse{
-					OrderId:        "YDR-123",
-					TransactionId:  "TXN-123",
-					PaymentLinkUrl: "https://payment.link",
+					OrderId:       "YDR-123",
+					TransactionId: "TXN-123",
+					PaymentMethod: "qris",
+					Qris:          &dto.QrisPayment{QrString: "00020101021226"},
 				}, nil)
 			},
 			expectedStatus: http.StatusCreated,
@@ -60,7 +69,7 @@ func TestPaymentController_CreatePayment(t *testing.T) {
 			name:      "invalid auction id",
 			auctionId: "invalid",
 			requestBody: dto.PaymentRequest{
-				Amount: 100000,
+				PaymentMethod: dto.PaymentMethodBcaVa,
 			},
 			setupAuth: func(c echo.Context) {
 				token := &jwt.Token{
@@ -157,4 +166,436 @@ func TestPaymentController_GetAllPayment(t *testing.T) {
 			}
 		})
 	}
-}
\ No newline at end of file
+}
+
+// recorded notifications are signed with this sandbox server key
+const testMidtransServerKey = "SB-Mid-server-TESTKEY123"
+
+func TestPaymentController_HandleNotification(t *testing.T) {
+	tests := []struct {
+		name           string
+		payload        string
+		setupMock      func(repo *mocks.MockPaymentRepository)
+		expectedStatus int
+	}{
+		{
+			name:    "settlement marks the payment paid",
+			payload: "qris_settlement.json",
+			setupMock: func(repo *mocks.MockPaymentRepository) {
+				repo.EXPECT().GetByOrderId("YDR-W42").Return(entity.Payment{Id: 1, OrderId: "YDR-W42", Amount: 150000, Status: "pending"}, nil)
+				repo.EXPECT().ApplyStatus("YDR-W42", "paid").Return(false, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:    "settlement after the winner payment closed is recorded as orphaned",
+			payload: "qris_settlement.json",
+			setupMock: func(repo *mocks.MockPaymentRepository) {
+				repo.EXPECT().GetByOrderId("YDR-W42").Return(entity.Payment{Id: 1, OrderId: "YDR-W42", Amount: 150000, Status: "pending"}, nil)
+				repo.EXPECT().ApplyStatus("YDR-W42", "paid").Return(true, nil)
+				repo.EXPECT().RecordOrphan(gomock.Any()).DoAndReturn(func(finding *entity.PaymentReconciliation) error {
+					assert.Equal(t, entity.ReconciliationOrphaned, finding.Outcome)
+					assert.Equal(t, "YDR-W42", finding.OrderID)
+					assert.Equal(t, "settlement", finding.GatewayStatus)
+					assert.Equal(t, "paid", finding.AppliedStatus)
+					return nil
+				})
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:    "pending leaves the payment unchanged",
+			payload: "qris_pending.json",
+			setupMock: func(repo *mocks.MockPaymentRepository) {
+				repo.EXPECT().GetByOrderId("YDR-W42").Return(entity.Payment{Id: 1, OrderId: "YDR-W42", Amount: 150000, Status: "pending"}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:    "expire marks the attempt failed",
+			payload: "qris_expire.json",
+			setupMock: func(repo *mocks.MockPaymentRepository) {
+				repo.EXPECT().GetByOrderId("YDR-W42").Return(entity.Payment{Id: 1, OrderId: "YDR-W42", Amount: 150000, Status: "pending"}, nil)
+				repo.EXPECT().ApplyStatus("YDR-W42", "failed").Return(false, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:    "challenged card capture waits for review",
+			payload: "card_capture_challenge.json",
+			setupMock: func(repo *mocks.MockPaymentRepository) {
+				repo.EXPECT().GetByOrderId("YDR-W42").Return(entity.Payment{Id: 1, OrderId: "YDR-W42", Amount: 150000, Status: "pending"}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "tampered amount fails the signature",
+			payload:        "qris_settlement_tampered.json",
+			setupMock:      func(repo *mocks.MockPaymentRepository) {},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:    "amount not matching the payment",
+			payload: "qris_settlement.json",
+			setupMock: func(repo *mocks.MockPaymentRepository) {
+				repo.EXPECT().GetByOrderId("YDR-W42").Return(entity.Payment{Id: 1, OrderId: "YDR-W42", Amount: 175000, Status: "pending"}, nil)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:    "unknown order",
+			payload: "qris_settlement.json",
+			setupMock: func(repo *mocks.MockPaymentRepository) {
+				repo.EXPECT().GetByOrderId("YDR-W42").Return(entity.Payment{}, gorm.ErrRecordNotFound)
+				repo.EXPECT().RecordOrphan(gomock.Any()).DoAndReturn(func(finding *entity.PaymentReconciliation) error {
+					assert.Equal(t, entity.PaymentReconciliation{OrderID: "YDR-W42", Outcome: entity.ReconciliationOrphaned, GatewayStatus: "settlement", GatewayAmount: 150000}, *finding)
+					return nil
+				})
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:    "order lookup fails",
+			payload: "qris_settlement.json",
+			setupMock: func(repo *mocks.MockPaymentRepository) {
+				repo.EXPECT().GetByOrderId("YDR-W42").Return(entity.Payment{}, errors.New("db down"))
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockPaymentRepository(ctrl)
+			controller := NewPaymentController(validator.New(), service.NewPaymentService(mockRepo, repository.NewMidtransGateway(testMidtransServerKey, false), 48*time.Hour))
+			tt.setupMock(mockRepo)
+
+			body, err := os.ReadFile(filepath.Join("testdata", "midtrans", tt.payload))
+			assert.NoError(t, err)
+
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodPost, "/payments/notifications", bytes.NewReader(body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			err = controller.HandleNotification(c)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+		})
+	}
+}
+
+func TestPaymentController_HandleNotification_Redelivered(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockPaymentRepository(ctrl)
+	controller := NewPaymentController(validator.New(), service.NewPaymentService(mockRepo, repository.NewMidtransGateway(testMidtransServerKey, false), 48*time.Hour))
+
+	body, err := os.ReadFile(filepath.Join("testdata", "midtrans", "qris_settlement.json"))
+	assert.NoError(t, err)
+
+	// midtrans delivers until it gets a 2xx, both deliveries succeed and the repository
+	// transaction leaves an already paid payment untouched
+	mockRepo.EXPECT().GetByOrderId("YDR-W42").Return(entity.Payment{Id: 1, OrderId: "YDR-W42", Amount: 150000, Status: "pending"}, nil)
+	mockRepo.EXPECT().GetByOrderId("YDR-W42").Return(entity.Payment{Id: 1, OrderId: "YDR-W42", Amount: 150000, Status: "paid"}, nil)
+	mockRepo.EXPECT().ApplyStatus("YDR-W42", "paid").Return(false, nil).Times(2)
+
+	for i := 0; i < 2; i++ {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/payments/notifications", bytes.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+
+		assert.NoError(t, controller.HandleNotification(e.NewContext(req, rec)))
+		assert.Equal(t, http.StatusOK, rec.Code)
+	}
+}
+
+func postCreatePayment(t *testing.T, controller *PaymentController, req dto.PaymentRequest) *httptest.ResponseRecorder {
+	body, err := json.Marshal(req)
+	assert.NoError(t, err)
+
+	e := echo.New()
+	httpReq := httptest.NewRequest(http.MethodPost, "/payments/7", bytes.NewReader(body))
+	httpReq.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(httpReq, rec)
+	c.SetParamNames("auctionId")
+	c.SetParamValues("7")
+	c.Set("user", &jwt.Token{Claims: jwt.MapClaims{"id": float64(3)}})
+
+	assert.NoError(t, controller.CreatePayment(c))
+	return rec
+}
+
+// expectOpenWinnerPayment sets up item 7 finished with user 3 owing 150000 on winner payment 40
+func expectOpenWinnerPayment(repo *mocks.MockPaymentRepository) {
+	repo.EXPECT().GetAuctionItem(7).Return(entity.AuctionItem{ID: 7, Status: "finished"}, nil)
+	repo.EXPECT().GetOpenWinnerPayment(7).Return(entity.Payment{Id: 40, UserId: 3, AuctionItemId: 7, Amount: 150000, Status: "pending", OrderId: "YDR-W42"}, nil)
+}
+
+func TestPaymentController_FakeGatewayFlow(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockPaymentRepository(ctrl)
+	gateway := repository.NewFakeGateway(testMidtransServerKey)
+	gateway.Script(repository.FakeOutcome{Statuses: []string{"pending", "settlement"}})
+	controller := NewPaymentController(validator.New(), service.NewPaymentService(mockRepo, gateway, 48*time.Hour))
+
+	var orderId string
+	expectOpenWinnerPayment(mockRepo)
+	mockRepo.EXPECT().CreateAttempt(gomock.Any(), 40).DoAndReturn(func(payment *entity.Payment, winnerPaymentId int) error {
+		assert.Equal(t, float64(150000), payment.Amount)
+		orderId = payment.OrderId
+		return nil
+	})
+
+	rec := postCreatePayment(t, controller, dto.PaymentRequest{})
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.NotEmpty(t, orderId)
+
+	// the first status check is still pending and applies nothing
+	e := echo.New()
+	rec = httptest.NewRecorder()
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/payments/status/"+orderId, nil), rec)
+	c.SetParamNames("id")
+	c.SetParamValues(orderId)
+	assert.NoError(t, controller.CheckPaymentStatus(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	// then the gateway notifies the settlement
+	notif, err := gateway.Notification(orderId)
+	assert.NoError(t, err)
+	assert.Equal(t, "settlement", notif.TransactionStatus)
+	body, err := json.Marshal(notif)
+	assert.NoError(t, err)
+
+	mockRepo.EXPECT().GetByOrderId(orderId).Return(entity.Payment{Id: 1, OrderId: orderId, Amount: 150000, Status: "pending"}, nil)
+	mockRepo.EXPECT().ApplyStatus(orderId, "paid").Return(false, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/payments/notifications", bytes.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec = httptest.NewRecorder()
+	assert.NoError(t, controller.HandleNotification(e.NewContext(req, rec)))
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestPaymentController_CreatePayment_ChargeFails(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockPaymentRepository(ctrl)
+	gateway := repository.NewFakeGateway(testMidtransServerKey)
+	gateway.Script(repository.FakeOutcome{ChargeErr: errors.New("gateway unavailable")})
+	controller := NewPaymentController(validator.New(), service.NewPaymentService(mockRepo, gateway, 48*time.Hour))
+
+	// the attempt the gateway refused is failed so the winner can try again
+	expectOpenWinnerPayment(mockRepo)
+	mockRepo.EXPECT().CreateAttempt(gomock.Any(), 40).Return(nil)
+	mockRepo.EXPECT().ApplyStatus(gomock.Any(), "failed").Return(false, nil)
+
+	rec := postCreatePayment(t, controller, dto.PaymentRequest{})
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+}
+
+func TestPaymentController_CreatePayment_Methods(t *testing.T) {
+	tests := []struct {
+		name           string
+		method         string
+		expectedStatus int
+		check          func(t *testing.T, data dto.PaymentResponse)
+	}{
+		{
+			name:           "defaults to qris",
+			method:         "",
+			expectedStatus: http.StatusCreated,
+			check: func(t *testing.T, data dto.PaymentResponse) {
+				assert.Equal(t, dto.PaymentMethodQris, data.PaymentMethod)
+				assert.NotEmpty(t, data.Qris.QrString)
+			},
+		},
+		{
+			name:           "bank transfer returns a virtual account",
+			method:         dto.PaymentMethodBriVa,
+			expectedStatus: http.StatusCreated,
+			check: func(t *testing.T, data dto.PaymentResponse) {
+				assert.Equal(t, "bri", data.VirtualAccount.Bank)
+				assert.NotEmpty(t, data.VirtualAccount.VaNumber)
+				assert.Nil(t, data.Qris)
+			},
+		},
+		{
+			name:           "e-wallet returns a deeplink",
+			method:         dto.PaymentMethodShopeePay,
+			expectedStatus: http.StatusCreated,
+			check: func(t *testing.T, data dto.PaymentResponse) {
+				assert.NotEmpty(t, data.EWallet.DeeplinkUrl)
+			},
+		},
+		{
+			name:           "card goes through a redirect",
+			method:         dto.PaymentMethodCard,
+			expectedStatus: http.StatusCreated,
+			check: func(t *testing.T, data dto.PaymentResponse) {
+				assert.NotEmpty(t, data.Redirect.RedirectUrl)
+			},
+		},
+		{
+			name:           "unknown method",
+			method:         "cash",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockPaymentRepository(ctrl)
+			controller := NewPaymentController(validator.New(), service.NewPaymentService(mockRepo, repository.NewFakeGateway(testMidtransServerKey), 48*time.Hour))
+			if tt.expectedStatus == http.StatusCreated {
+				expectOpenWinnerPayment(mockRepo)
+				mockRepo.EXPECT().CreateAttempt(gomock.Any(), 40).DoAndReturn(func(payment *entity.Payment, winnerPaymentId int) error {
+					assert.NotEmpty(t, payment.PaymentMethod)
+					return nil
+				})
+			}
+
+			rec := postCreatePayment(t, controller, dto.PaymentRequest{PaymentMethod: tt.method})
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+
+			if tt.check != nil {
+				var body struct {
+					Data dto.PaymentResponse `json:"data"`
+				}
+				assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+				tt.check(t, body.Data)
+			}
+		})
+	}
+}
+
+func TestPaymentController_RefundPayment(t *testing.T) {
+	paid := entity.Payment{Id: 5, OrderId: "YDR-W42", AuctionItemId: 9, Amount: 150000, Status: "paid"}
+
+	tests := []struct {
+		name           string
+		body           string
+		outcome        repository.FakeOutcome
+		setupMock      func(repo *mocks.MockPaymentRepository)
+		expectedStatus int
+	}{
+		{
+			name: "full refund reopens the item",
+			body: `{"reason":"damaged on handover","reopen_item":true}`,
+			setupMock: func(repo *mocks.MockPaymentRepository) {
+				repo.EXPECT().BeginRefund(gomock.Any()).DoAndReturn(func(refund *entity.PaymentRefund) (entity.Payment, error) {
+					assert.Equal(t, 5, refund.PaymentId)
+					assert.Equal(t, 1, refund.ActorId)
+					assert.Equal(t, "damaged on handover", refund.Reason)
+					refund.Id = 3
+					refund.Amount = 150000
+					return paid, nil
+				})
+				repo.EXPECT().CompleteRefund(gomock.Any(), 9, "refund").DoAndReturn(func(refund *entity.PaymentRefund, itemId int, status string) error {
+					assert.True(t, refund.ReopenItem)
+					return nil
+				})
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name: "partial refund",
+			body: `{"amount":50000,"reason":"scratch not in the photos"}`,
+			setupMock: func(repo *mocks.MockPaymentRepository) {
+				repo.EXPECT().BeginRefund(gomock.Any()).DoAndReturn(func(refund *entity.PaymentRefund) (entity.Payment, error) {
+					assert.Equal(t, float64(50000), refund.Amount)
+					refund.Id = 4
+					return paid, nil
+				})
+				repo.EXPECT().CompleteRefund(gomock.Any(), 9, "partial_refund").Return(nil)
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name: "gateway rejects the refund",
+			body: `{"reason":"damaged on handover"}`,
+			outcome: repository.FakeOutcome{Statuses: []string{"settlement"}, RefundErr: errors.New("refund not allowed")},
+			setupMock: func(repo *mocks.MockPaymentRepository) {
+				repo.EXPECT().BeginRefund(gomock.Any()).DoAndReturn(func(refund *entity.PaymentRefund) (entity.Payment, error) {
+					refund.Id = 5
+					refund.Amount = 150000
+					return paid, nil
+				})
+				repo.EXPECT().FailRefund(gomock.Any(), "refund not allowed").Return(nil)
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "payment not paid",
+			body: `{"reason":"damaged on handover"}`,
+			setupMock: func(repo *mocks.MockPaymentRepository) {
+				repo.EXPECT().BeginRefund(gomock.Any()).Return(entity.Payment{}, repository.ErrPaymentNotRefundable)
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name: "more than what is left",
+			body: `{"amount":200000,"reason":"damaged on handover"}`,
+			setupMock: func(repo *mocks.MockPaymentRepository) {
+				repo.EXPECT().BeginRefund(gomock.Any()).Return(entity.Payment{}, repository.ErrRefundExceedsPayment)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "unknown payment",
+			body: `{"reason":"damaged on handover"}`,
+			setupMock: func(repo *mocks.MockPaymentRepository) {
+				repo.EXPECT().BeginRefund(gomock.Any()).Return(entity.Payment{}, gorm.ErrRecordNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "reason is required",
+			body:           `{"amount":50000}`,
+			setupMock:      func(repo *mocks.MockPaymentRepository) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			gateway := repository.NewFakeGateway(testMidtransServerKey)
+			gateway.Script(tt.outcome)
+			_, err := gateway.Charge(dto.ChargeRequest{OrderId: paid.OrderId, PaymentMethod: dto.PaymentMethodBcaVa, Amount: paid.Amount})
+			assert.NoError(t, err)
+
+			mockRepo := mocks.NewMockPaymentRepository(ctrl)
+			controller := NewPaymentController(validator.New(), service.NewPaymentService(mockRepo, gateway, 48*time.Hour))
+			tt.setupMock(mockRepo)
+
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodPost, "/payments/5/refunds", bytes.NewReader([]byte(tt.body)))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetParamNames("id")
+			c.SetParamValues("5")
+			c.Set("user", &jwt.Token{Claims: jwt.MapClaims{"id": float64(1), "role": "admin"}})
+
+			assert.NoError(t, controller.RefundPayment(c))
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+		})
+	}
+}
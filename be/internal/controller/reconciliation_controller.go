@@ -0,0 +1,137 @@
+package controller
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"milestone3/be/internal/dto"
+	"milestone3/be/internal/service"
+	"milestone3/be/internal/utils"
+
+	"github.com/labstack/echo/v4"
+)
+
+const reconciliationDateLayout = "2006-01-02"
+
+type ReconciliationController struct {
+	svc service.PaymentReconciliationService
+}
+
+func NewReconciliationController(s service.PaymentReconciliationService) *ReconciliationController {
+	return &ReconciliationController{svc: s}
+}
+
+// GetReport godoc
+// @Summary Download the payment reconciliation report
+// @Description Findings of the reconciliation job between the local payments and the gateway: matched, mismatched amounts, orphaned gateway transactions and payments the gateway never received. Covers a month (YYYY-MM) or a from/to date range (YYYY-MM-DD, both inclusive), the current month by default. CSV unless format=json.
+// @Tags Your Donate Rise API - Admin
+// @Produce text/csv
+// @Produce json
+// @Security BearerAuth
+// @Param month query string false "Month, YYYY-MM"
+// @Param from query string false "First day, YYYY-MM-DD"
+// @Param to query string false "Last day, YYYY-MM-DD"
+// @Param format query string false "csv (default) or json"
+// @Success 200 {object} utils.SuccessResponseData "ok"
+// @Failure 400 {object} utils.ErrorResponse "Bad request - Invalid period"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized - Invalid or missing token"
+// @Failure 403 {object} utils.ErrorResponse "Forbidden - Admin access required"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /admin/reports/reconciliation [get]
+func (h *ReconciliationController) GetReport(c echo.Context) error {
+	from, to, err := reconciliationPeriod(c.QueryParam("month"), c.QueryParam("from"), c.QueryParam("to"), time.Now())
+	if err != nil {
+		return utils.BadRequestResponse(c, err.Error())
+	}
+
+	report, err := h.svc.Report(from, to)
+	if err != nil {
+		if err == service.ErrInvalidDate {
+			return utils.BadRequestResponse(c, err.Error())
+		}
+		return utils.InternalServerErrorResponse(c, "failed to get reconciliation report")
+	}
+
+	switch c.QueryParam("format") {
+	case "json":
+		return utils.SuccessResponse(c, "ok", report)
+	case "", "csv":
+	default:
+		return utils.BadRequestResponse(c, "format must be csv or json")
+	}
+
+	body, err := reconciliationCSV(report)
+	if err != nil {
+		return utils.InternalServerErrorResponse(c, "failed to write reconciliation report")
+	}
+
+	filename := fmt.Sprintf("reconciliation_%s_%s.csv", from.Format(reconciliationDateLayout), to.AddDate(0, 0, -1).Format(reconciliationDateLayout))
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", filename))
+	return c.Blob(http.StatusOK, "text/csv; charset=utf-8", body)
+}
+
+// reconciliationPeriod turns the query into a [from, to) range, the current month when nothing is given
+func reconciliationPeriod(month, fromParam, toParam string, now time.Time) (from, to time.Time, err error) {
+	if month != "" {
+		if fromParam != "" || toParam != "" {
+			return time.Time{}, time.Time{}, fmt.Errorf("use either month or from/to")
+		}
+		from, err = time.ParseInLocation("2006-01", month, now.Location())
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("month must be YYYY-MM")
+		}
+		return from, from.AddDate(0, 1, 0), nil
+	}
+
+	if fromParam == "" && toParam == "" {
+		from = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+		return from, from.AddDate(0, 1, 0), nil
+	}
+
+	if fromParam == "" || toParam == "" {
+		return time.Time{}, time.Time{}, fmt.Errorf("from and to are both required")
+	}
+	from, err = time.ParseInLocation(reconciliationDateLayout, fromParam, now.Location())
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("from must be YYYY-MM-DD")
+	}
+	to, err = time.ParseInLocation(reconciliationDateLayout, toParam, now.Location())
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("to must be YYYY-MM-DD")
+	}
+	// to is the last day of the report
+	return from, to.AddDate(0, 0, 1), nil
+}
+
+func reconciliationCSV(report dto.ReconciliationReport) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+
+	rows := [][]string{{"created_at", "order_id", "payment_id", "outcome", "local_status", "gateway_status", "local_amount", "gateway_amount", "applied_status"}}
+	for _, f := range report.Findings {
+		paymentID := ""
+		if f.PaymentID != nil {
+			paymentID = strconv.Itoa(*f.PaymentID)
+		}
+		rows = append(rows, []string{
+			f.CreatedAt.Format(time.RFC3339),
+			f.OrderID,
+			paymentID,
+			f.Outcome,
+			f.LocalStatus,
+			f.GatewayStatus,
+			strconv.FormatFloat(f.LocalAmount, 'f', 2, 64),
+			strconv.FormatFloat(f.GatewayAmount, 'f', 2, 64),
+			f.AppliedStatus,
+		})
+	}
+
+	if err := w.WriteAll(rows); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
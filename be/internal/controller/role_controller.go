@@ -0,0 +1,98 @@
+package controller
+
+import (
+	"errors"
+	"strconv"
+
+	"milestone3/be/internal/dto"
+	"milestone3/be/internal/service"
+	"milestone3/be/internal/utils"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+)
+
+type RoleService interface {
+	ListRoles() (res []dto.RoleResponse, err error)
+	AssignRole(actorID int, userID int, role string) error
+}
+
+type RoleController struct {
+	roleService RoleService
+	validate    *validator.Validate
+}
+
+func NewRoleController(validate *validator.Validate, rs RoleService) *RoleController {
+	return &RoleController{validate: validate, roleService: rs}
+}
+
+// ListRoles godoc
+// @Summary List roles
+// @Description List the roles a user can be given, with the permissions each grants
+// @Tags Your Donate Rise API - Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.SuccessResponseData{data=[]dto.RoleResponse} "roles fetched"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized - Invalid or missing token"
+// @Failure 403 {object} utils.ErrorResponse "Forbidden - role:assign required"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /admin/roles [get]
+func (rc *RoleController) ListRoles(c echo.Context) error {
+	resp, err := rc.roleService.ListRoles()
+	if err != nil {
+		return utils.InternalServerErrorResponse(c, "internal server error")
+	}
+
+	return utils.SuccessResponse(c, "roles fetched", resp)
+}
+
+// AssignRole godoc
+// @Summary Assign a role to a user
+// @Description Give a user a role. The access tokens the user holds are revoked, the next refresh carries the permissions of the new role. Admins cannot change their own role
+// @Tags Your Donate Rise API - Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Param role body dto.AssignRoleRequest true "Role name"
+// @Success 200 {object} utils.SuccessResponseData "role assigned"
+// @Failure 400 {object} utils.ErrorResponse "Bad request - Invalid user ID, payload or role"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized - Invalid or missing token"
+// @Failure 403 {object} utils.ErrorResponse "Forbidden - role:assign required, or changing your own role"
+// @Failure 404 {object} utils.ErrorResponse "User not found"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /admin/users/{id}/role [put]
+func (rc *RoleController) AssignRole(c echo.Context) error {
+	userID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || userID <= 0 {
+		return utils.BadRequestResponse(c, "invalid user id")
+	}
+
+	req := new(dto.AssignRoleRequest)
+	if err := c.Bind(req); err != nil {
+		return utils.BadRequestResponse(c, err.Error())
+	}
+	if err := rc.validate.Struct(req); err != nil {
+		return utils.BadRequestResponse(c, err.Error())
+	}
+
+	actorID, ok := utils.GetUserID(c)
+	if !ok {
+		return utils.UnauthorizedResponse(c, "unauthenticated")
+	}
+
+	if err := rc.roleService.AssignRole(int(actorID), userID, req.Role); err != nil {
+		switch {
+		case errors.Is(err, service.ErrRoleNotFound):
+			return utils.BadRequestResponse(c, err.Error())
+		case errors.Is(err, service.ErrUserNotFound):
+			return utils.NotFoundResponse(c, err.Error())
+		case errors.Is(err, service.ErrCannotChangeOwnRole):
+			return utils.ForbiddenResponse(c, err.Error())
+		}
+		return utils.InternalServerErrorResponse(c, "internal server error")
+	}
+
+	return utils.SuccessResponse(c, "role assigned", nil)
+}
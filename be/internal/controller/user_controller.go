@@ -2,6 +2,8 @@ package controller
 
 import (
 	"errors"
+	"strconv"
+	"time"
 
 	"milestone3/be/internal/dto"
 	"milestone3/be/internal/service"
@@ -13,7 +15,20 @@ import (
 
 type UserService interface {
 	CreateUser(req dto.UserRequest) (res dto.UserResponse, err error)
-	GetUserByEmail(email, password string) (accessToken string, err error)
+	GetUserByEmail(email, password string) (res dto.TokenResponse, err error)
+	RefreshToken(refreshToken string) (res dto.TokenResponse, err error)
+	Logout(userID int, accessJTI string, accessExpiresAt time.Time, refreshToken string) error
+	VerifyEmail(token string) error
+	ResendVerificationEmail(email string) error
+	ForgotPassword(email string) error
+	ResetPassword(token, password string) error
+	GetUserById(id int) (res dto.UserResponse, err error)
+	UpdateProfile(id int, req dto.UpdateProfileRequest) (res dto.UserResponse, err error)
+	ChangePassword(id int, currentPassword, newPassword string) (res dto.TokenResponse, err error)
+	DeleteAccount(id int, password string) error
+	ListUsers(req dto.UserListRequest) (res []dto.AdminUserResponse, total int64, err error)
+	SuspendUser(actorID, userID int, reason string) error
+	UnsuspendUser(userID int) error
 }
 
 type UserController struct {
@@ -27,7 +42,7 @@ func NewUserController(validate *validator.Validate, us UserService) *UserContro
 
 // CreateUser godoc
 // @Summary Register new user
-// @Description Register a new user account in the system
+// @Description Register a new user account in the system and mail a link to verify its email. Bidding and donating need a verified email
 // @Tags Your Donate Rise API - Authentication
 // @Accept json
 // @Produce json
@@ -57,14 +72,15 @@ func (uc *UserController) CreateUser(c echo.Context) error {
 
 // LoginUser godoc
 // @Summary User login
-// @Description Authenticate user and return access token
+// @Description Authenticate user and return a short-lived access token with a refresh token
 // @Tags Your Donate Rise API - Authentication
 // @Accept json
 // @Produce json
 // @Param credentials body dto.UserLoginRequest true "User login credentials"
-// @Success 200 {object} utils.SuccessResponseData{data=string} "success login"
+// @Success 200 {object} utils.SuccessResponseData{data=dto.TokenResponse} "success login"
 // @Failure 400 {object} utils.ErrorResponse "Bad request - Invalid credentials format"
 // @Failure 401 {object} utils.ErrorResponse "Unauthorized - Invalid email or password"
+// @Failure 403 {object} utils.ErrorResponse "Forbidden - Account suspended"
 // @Failure 500 {object} utils.ErrorResponse "Internal server error"
 // @Router /auth/login [post]
 func (uc *UserController) LoginUser(c echo.Context) error {
@@ -81,8 +97,469 @@ func (uc *UserController) LoginUser(c echo.Context) error {
 		if errors.Is(err, service.ErrInvalidCredential) {
 			return utils.UnauthorizedResponse(c, "invalid email or password")
 		}
+		if errors.Is(err, service.ErrAccountSuspended) {
+			return utils.ForbiddenResponse(c, err.Error())
+		}
 		return utils.InternalServerErrorResponse(c, "internal server error")
 	}
 
 	return utils.SuccessResponse(c, "success login", resp)
 }
+
+// RefreshToken godoc
+// @Summary Refresh access token
+// @Description Trade a refresh token for a new access token and a new refresh token. The refresh token used is revoked, presenting it again revokes every token of the login.
+// @Tags Your Donate Rise API - Authentication
+// @Accept json
+// @Produce json
+// @Param token body dto.RefreshTokenRequest true "Refresh token"
+// @Success 200 {object} utils.SuccessResponseData{data=dto.TokenResponse} "token refreshed"
+// @Failure 400 {object} utils.ErrorResponse "Bad request - Invalid payload"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized - Invalid, expired or reused refresh token"
+// @Failure 403 {object} utils.ErrorResponse "Forbidden - Account suspended"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /auth/refresh [post]
+func (uc *UserController) RefreshToken(c echo.Context) error {
+	req := new(dto.RefreshTokenRequest)
+	if err := c.Bind(req); err != nil {
+		return utils.BadRequestResponse(c, err.Error())
+	}
+	if err := uc.validate.Struct(req); err != nil {
+		return utils.BadRequestResponse(c, err.Error())
+	}
+
+	resp, err := uc.userService.RefreshToken(req.RefreshToken)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidRefreshToken) || errors.Is(err, service.ErrRefreshTokenReused) {
+			return utils.UnauthorizedResponse(c, err.Error())
+		}
+		if errors.Is(err, service.ErrAccountSuspended) {
+			return utils.ForbiddenResponse(c, err.Error())
+		}
+		return utils.InternalServerErrorResponse(c, "internal server error")
+	}
+
+	return utils.SuccessResponse(c, "token refreshed", resp)
+}
+
+// Logout godoc
+// @Summary User logout
+// @Description Revoke the access token of the request and, when given, the refresh token with every token rotated from it
+// @Tags Your Donate Rise API - Authentication
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param token body dto.LogoutRequest false "Refresh token to revoke"
+// @Success 204 "Logged out"
+// @Failure 400 {object} utils.ErrorResponse "Bad request - Invalid payload"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized - Invalid or missing token"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /auth/logout [post]
+func (uc *UserController) Logout(c echo.Context) error {
+	req := new(dto.LogoutRequest)
+	if err := c.Bind(req); err != nil {
+		return utils.BadRequestResponse(c, err.Error())
+	}
+
+	userID, ok := utils.GetUserID(c)
+	jti, _ := c.Get("jti").(string)
+	if !ok || jti == "" {
+		return utils.UnauthorizedResponse(c, "unauthenticated")
+	}
+	expiresAt, _ := c.Get("token_expires_at").(time.Time)
+
+	if err := uc.userService.Logout(int(userID), jti, expiresAt, req.RefreshToken); err != nil {
+		return utils.InternalServerErrorResponse(c, "internal server error")
+	}
+
+	return utils.NoContentResponse(c)
+}
+
+// VerifyEmail godoc
+// @Summary Verify email
+// @Description Verify the email of an account with the token of the link mailed to it. The link opens this endpoint directly, the token can be posted as well. Tokens work once
+// @Tags Your Donate Rise API - Authentication
+// @Accept json
+// @Produce json
+// @Param token query string false "Verification token"
+// @Param body body dto.VerifyEmailRequest false "Verification token"
+// @Success 200 {object} utils.SuccessResponseData "email verified"
+// @Failure 400 {object} utils.ErrorResponse "Bad request - Invalid, used or expired token"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /auth/verify-email [get]
+// @Router /auth/verify-email [post]
+func (uc *UserController) VerifyEmail(c echo.Context) error {
+	req := new(dto.VerifyEmailRequest)
+	if err := c.Bind(req); err != nil {
+		return utils.BadRequestResponse(c, err.Error())
+	}
+	if err := uc.validate.Struct(req); err != nil {
+		return utils.BadRequestResponse(c, err.Error())
+	}
+
+	if err := uc.userService.VerifyEmail(req.Token); err != nil {
+		if errors.Is(err, service.ErrInvalidVerificationToken) {
+			return utils.BadRequestResponse(c, err.Error())
+		}
+		return utils.InternalServerErrorResponse(c, "internal server error")
+	}
+
+	return utils.SuccessResponse(c, "email verified", nil)
+}
+
+// ResendVerificationEmail godoc
+// @Summary Resend verification email
+// @Description Mail a new verification link to an unverified account, the earlier links stop working. The answer is the same whether or not the account exists
+// @Tags Your Donate Rise API - Authentication
+// @Accept json
+// @Produce json
+// @Param body body dto.EmailRequest true "Account email"
+// @Success 200 {object} utils.SuccessResponseData "verification email sent"
+// @Failure 400 {object} utils.ErrorResponse "Bad request - Invalid payload"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /auth/resend-verification [post]
+func (uc *UserController) ResendVerificationEmail(c echo.Context) error {
+	req := new(dto.EmailRequest)
+	if err := c.Bind(req); err != nil {
+		return utils.BadRequestResponse(c, err.Error())
+	}
+	if err := uc.validate.Struct(req); err != nil {
+		return utils.BadRequestResponse(c, err.Error())
+	}
+
+	if err := uc.userService.ResendVerificationEmail(req.Email); err != nil {
+		return utils.InternalServerErrorResponse(c, "internal server error")
+	}
+
+	return utils.SuccessResponse(c, "if the account needs verifying, a verification email was sent", nil)
+}
+
+// ForgotPassword godoc
+// @Summary Forgot password
+// @Description Mail a single-use password reset link to the account. The answer is the same whether or not the account exists
+// @Tags Your Donate Rise API - Authentication
+// @Accept json
+// @Produce json
+// @Param body body dto.EmailRequest true "Account email"
+// @Success 200 {object} utils.SuccessResponseData "password reset email sent"
+// @Failure 400 {object} utils.ErrorResponse "Bad request - Invalid payload"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /auth/forgot-password [post]
+func (uc *UserController) ForgotPassword(c echo.Context) error {
+	req := new(dto.EmailRequest)
+	if err := c.Bind(req); err != nil {
+		return utils.BadRequestResponse(c, err.Error())
+	}
+	if err := uc.validate.Struct(req); err != nil {
+		return utils.BadRequestResponse(c, err.Error())
+	}
+
+	if err := uc.userService.ForgotPassword(req.Email); err != nil {
+		return utils.InternalServerErrorResponse(c, "internal server error")
+	}
+
+	return utils.SuccessResponse(c, "if the account exists, a password reset email was sent", nil)
+}
+
+// ResetPassword godoc
+// @Summary Reset password
+// @Description Set a new password with the token of the reset link. The token works once, and every session of the account is signed out
+// @Tags Your Donate Rise API - Authentication
+// @Accept json
+// @Produce json
+// @Param body body dto.ResetPasswordRequest true "Reset token and new password"
+// @Success 200 {object} utils.SuccessResponseData "password reset"
+// @Failure 400 {object} utils.ErrorResponse "Bad request - Invalid payload, or invalid, used or expired token"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /auth/reset-password [post]
+func (uc *UserController) ResetPassword(c echo.Context) error {
+	req := new(dto.ResetPasswordRequest)
+	if err := c.Bind(req); err != nil {
+		return utils.BadRequestResponse(c, err.Error())
+	}
+	if err := uc.validate.Struct(req); err != nil {
+		return utils.BadRequestResponse(c, err.Error())
+	}
+
+	if err := uc.userService.ResetPassword(req.Token, req.Password); err != nil {
+		if errors.Is(err, service.ErrInvalidResetToken) {
+			return utils.BadRequestResponse(c, err.Error())
+		}
+		return utils.InternalServerErrorResponse(c, "internal server error")
+	}
+
+	return utils.SuccessResponse(c, "password reset, sign in with the new password", nil)
+}
+
+// GetMe godoc
+// @Summary Get my profile
+// @Description Get the profile of the signed in user, with the phone and address won items are shipped to
+// @Tags Your Donate Rise API - Users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.SuccessResponseData{data=dto.UserResponse} "profile fetched"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized - Invalid or missing token"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /users/me [get]
+func (uc *UserController) GetMe(c echo.Context) error {
+	userID, ok := utils.GetUserID(c)
+	if !ok {
+		return utils.UnauthorizedResponse(c, "unauthenticated")
+	}
+
+	resp, err := uc.userService.GetUserById(int(userID))
+	if err != nil {
+		return utils.InternalServerErrorResponse(c, "internal server error")
+	}
+
+	return utils.SuccessResponse(c, "profile fetched", resp)
+}
+
+// UpdateMe godoc
+// @Summary Update my profile
+// @Description Change the name, phone (E.164, e.g. +6281234567890) or address of the signed in user. Fields left out stay as they are, an empty phone or address clears it
+// @Tags Your Donate Rise API - Users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param profile body dto.UpdateProfileRequest true "Profile fields to change"
+// @Success 200 {object} utils.SuccessResponseData{data=dto.UserResponse} "profile updated"
+// @Failure 400 {object} utils.ErrorResponse "Bad request - Invalid payload or validation error"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized - Invalid or missing token"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /users/me [patch]
+func (uc *UserController) UpdateMe(c echo.Context) error {
+	req := new(dto.UpdateProfileRequest)
+	if err := c.Bind(req); err != nil {
+		return utils.BadRequestResponse(c, err.Error())
+	}
+	if err := uc.validate.Struct(req); err != nil {
+		return utils.BadRequestResponse(c, err.Error())
+	}
+
+	userID, ok := utils.GetUserID(c)
+	if !ok {
+		return utils.UnauthorizedResponse(c, "unauthenticated")
+	}
+
+	resp, err := uc.userService.UpdateProfile(int(userID), *req)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidUser) {
+			return utils.BadRequestResponse(c, "name must have at least 3 characters")
+		}
+		return utils.InternalServerErrorResponse(c, "internal server error")
+	}
+
+	return utils.SuccessResponse(c, "profile updated", resp)
+}
+
+// ChangePassword godoc
+// @Summary Change my password
+// @Description Set a new password, the current one is asked again. Every session of the account is signed out and new tokens are returned for this one
+// @Tags Your Donate Rise API - Users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param body body dto.ChangePasswordRequest true "Current and new password"
+// @Success 200 {object} utils.SuccessResponseData{data=dto.TokenResponse} "password changed"
+// @Failure 400 {object} utils.ErrorResponse "Bad request - Invalid payload or validation error"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized - Invalid or missing token"
+// @Failure 403 {object} utils.ErrorResponse "Forbidden - Current password is incorrect"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /users/me/password [put]
+func (uc *UserController) ChangePassword(c echo.Context) error {
+	req := new(dto.ChangePasswordRequest)
+	if err := c.Bind(req); err != nil {
+		return utils.BadRequestResponse(c, err.Error())
+	}
+	if err := uc.validate.Struct(req); err != nil {
+		return utils.BadRequestResponse(c, err.Error())
+	}
+
+	userID, ok := utils.GetUserID(c)
+	if !ok {
+		return utils.UnauthorizedResponse(c, "unauthenticated")
+	}
+
+	resp, err := uc.userService.ChangePassword(int(userID), req.CurrentPassword, req.NewPassword)
+	if err != nil {
+		if errors.Is(err, service.ErrWrongPassword) {
+			return utils.ForbiddenResponse(c, err.Error())
+		}
+		return utils.InternalServerErrorResponse(c, "internal server error")
+	}
+
+	return utils.SuccessResponse(c, "password changed", resp)
+}
+
+// DeleteMe godoc
+// @Summary Delete my account
+// @Description Delete the account of the signed in user, the password is asked again. Everything personal is wiped and every session signed out, past bids and donations stay without telling who made them. Running bids, payments and donations have to finish first
+// @Tags Your Donate Rise API - Users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param body body dto.DeleteAccountRequest true "Password"
+// @Success 204 "Account deleted"
+// @Failure 400 {object} utils.ErrorResponse "Bad request - Invalid payload"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized - Invalid or missing token"
+// @Failure 403 {object} utils.ErrorResponse "Forbidden - Password is incorrect"
+// @Failure 409 {object} utils.ErrorResponse "Conflict - A bid, payment or donation is still running"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /users/me [delete]
+func (uc *UserController) DeleteMe(c echo.Context) error {
+	req := new(dto.DeleteAccountRequest)
+	if err := c.Bind(req); err != nil {
+		return utils.BadRequestResponse(c, err.Error())
+	}
+	if err := uc.validate.Struct(req); err != nil {
+		return utils.BadRequestResponse(c, err.Error())
+	}
+
+	userID, ok := utils.GetUserID(c)
+	if !ok {
+		return utils.UnauthorizedResponse(c, "unauthenticated")
+	}
+
+	if err := uc.userService.DeleteAccount(int(userID), req.Password); err != nil {
+		switch {
+		case errors.Is(err, service.ErrWrongPassword):
+			return utils.ForbiddenResponse(c, "password is incorrect")
+		case errors.Is(err, service.ErrAccountHasOpenActivity):
+			return utils.ConflictResponse(c, err.Error())
+		}
+		return utils.InternalServerErrorResponse(c, "internal server error")
+	}
+
+	return utils.NoContentResponse(c)
+}
+
+// ListUsers godoc
+// @Summary List users
+// @Description List users newest first, searching the name or email and filtering by role or status
+// @Tags Your Donate Rise API - Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param search query string false "Part of the name or email"
+// @Param role query string false "Role name"
+// @Param status query string false "active, suspended or deleted"
+// @Param page query int false "Page number (default: 1)"
+// @Param limit query int false "Items per page (default: 10, max: 100)"
+// @Success 200 {object} utils.SuccessResponseData{data=[]dto.AdminUserResponse} "users fetched"
+// @Failure 400 {object} utils.ErrorResponse "Bad request - Invalid filter"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized - Invalid or missing token"
+// @Failure 403 {object} utils.ErrorResponse "Forbidden - user:manage required"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /admin/users [get]
+func (uc *UserController) ListUsers(c echo.Context) error {
+	req := new(dto.UserListRequest)
+	if err := c.Bind(req); err != nil {
+		return utils.BadRequestResponse(c, err.Error())
+	}
+	if err := uc.validate.Struct(req); err != nil {
+		return utils.BadRequestResponse(c, err.Error())
+	}
+	if req.Page < 1 {
+		req.Page = 1
+	}
+	if req.Limit < 1 {
+		req.Limit = 10
+	}
+	if req.Limit > 100 {
+		req.Limit = 100
+	}
+
+	users, total, err := uc.userService.ListUsers(*req)
+	if err != nil {
+		return utils.InternalServerErrorResponse(c, "internal server error")
+	}
+
+	response := map[string]interface{}{
+		"users": users,
+		"page":  req.Page,
+		"limit": req.Limit,
+		"total": total,
+	}
+	return utils.SuccessResponse(c, "users fetched", response)
+}
+
+// SuspendUser godoc
+// @Summary Suspend a user
+// @Description Keep a user out: every session of the user is signed out and further requests, logins and refreshes are refused until the suspension is lifted. Admins cannot suspend themselves
+// @Tags Your Donate Rise API - Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Param body body dto.SuspendUserRequest true "Reason"
+// @Success 200 {object} utils.SuccessResponseData "user suspended"
+// @Failure 400 {object} utils.ErrorResponse "Bad request - Invalid user ID or payload"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized - Invalid or missing token"
+// @Failure 403 {object} utils.ErrorResponse "Forbidden - user:manage required, or suspending yourself"
+// @Failure 404 {object} utils.ErrorResponse "User not found"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /admin/users/{id}/suspend [post]
+func (uc *UserController) SuspendUser(c echo.Context) error {
+	userID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || userID <= 0 {
+		return utils.BadRequestResponse(c, "invalid user id")
+	}
+
+	req := new(dto.SuspendUserRequest)
+	if err := c.Bind(req); err != nil {
+		return utils.BadRequestResponse(c, err.Error())
+	}
+	if err := uc.validate.Struct(req); err != nil {
+		return utils.BadRequestResponse(c, err.Error())
+	}
+
+	actorID, ok := utils.GetUserID(c)
+	if !ok {
+		return utils.UnauthorizedResponse(c, "unauthenticated")
+	}
+
+	if err := uc.userService.SuspendUser(int(actorID), userID, req.Reason); err != nil {
+		switch {
+		case errors.Is(err, service.ErrUserNotFound):
+			return utils.NotFoundResponse(c, err.Error())
+		case errors.Is(err, service.ErrCannotSuspendSelf):
+			return utils.ForbiddenResponse(c, err.Error())
+		}
+		return utils.InternalServerErrorResponse(c, "internal server error")
+	}
+
+	return utils.SuccessResponse(c, "user suspended", nil)
+}
+
+// UnsuspendUser godoc
+// @Summary Lift a suspension
+// @Description Let a suspended user sign in again
+// @Tags Your Donate Rise API - Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Success 200 {object} utils.SuccessResponseData "suspension lifted"
+// @Failure 400 {object} utils.ErrorResponse "Bad request - Invalid user ID"
+// @Failure 401 {object} utils.ErrorResponse "Unauthorized - Invalid or missing token"
+// @Failure 403 {object} utils.ErrorResponse "Forbidden - user:manage required"
+// @Failure 404 {object} utils.ErrorResponse "User not found"
+// @Failure 500 {object} utils.ErrorResponse "Internal server error"
+// @Router /admin/users/{id}/suspend [delete]
+func (uc *UserController) UnsuspendUser(c echo.Context) error {
+	userID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || userID <= 0 {
+		return utils.BadRequestResponse(c, "invalid user id")
+	}
+
+	if err := uc.userService.UnsuspendUser(userID); err != nil {
+		if errors.Is(err, service.ErrUserNotFound) {
+			return utils.NotFoundResponse(c, err.Error())
+		}
+		return utils.InternalServerErrorResponse(c, "internal server error")
+	}
+
+	return utils.SuccessResponse(c, "suspension lifted", nil)
+}
@@ -9,16 +9,22 @@ import (
 )
 
 type BidScheduler struct {
-	bidSvc     service.BidService
-	auctionSvc service.AuctionItemService
-	logger     *slog.Logger
+	bidSvc       service.BidService
+	auctionSvc   service.AuctionItemService
+	outboxSvc    service.OutboxService
+	deadlineSvc  service.PaymentDeadlineService
+	reconcileSvc service.PaymentReconciliationService
+	logger       *slog.Logger
 }
 
-func NewBidScheduler(bidService service.BidService, auctionService service.AuctionItemService, logger *slog.Logger) *BidScheduler {
+func NewBidScheduler(bidService service.BidService, auctionService service.AuctionItemService, outboxService service.OutboxService, deadlineService service.PaymentDeadlineService, reconciliationService service.PaymentReconciliationService, logger *slog.Logger) *BidScheduler {
 	return &BidScheduler{
-		bidSvc:     bidService,
-		auctionSvc: auctionService,
-		logger:     logger,
+		bidSvc:       bidService,
+		auctionSvc:   auctionService,
+		outboxSvc:    outboxService,
+		deadlineSvc:  deadlineService,
+		reconcileSvc: reconciliationService,
+		logger:       logger,
 	}
 }
 
@@ -55,6 +61,43 @@ func (s *BidScheduler) Start() {
 		return
 	}
 
+	// deliver outbox events (winner payment, ...) every 10 seconds
+	_, err = scheduler.Every(10).Seconds().Do(func() {
+		if outboxErr := s.outboxSvc.ProcessPending(); outboxErr != nil {
+			s.logger.Error("Failed to process outbox events", "error", outboxErr)
+		}
+	})
+
+	if err != nil {
+		s.logger.Error("Failed to schedule outbox worker", "error", err)
+		return
+	}
+
+	// default winners past their payment deadline every 1 minute
+	_, err = scheduler.Every(1).Minute().Do(func() {
+		if deadlineErr := s.deadlineSvc.EnforcePaymentDeadlines(); deadlineErr != nil {
+			s.logger.Error("Failed to enforce winner payment deadlines", "error", deadlineErr)
+		}
+	})
+
+	if err != nil {
+		s.logger.Error("Failed to schedule payment deadline job", "error", err)
+		return
+	}
+
+	// reconcile pending, failed and expired payments with the gateway every 30 minutes, for notifications that never arrived
+	_, err = scheduler.Every(30).Minutes().Do(func() {
+		s.logger.Info("Reconciling pending payments with the gateway...")
+		if reconcileErr := s.reconcileSvc.Reconcile(); reconcileErr != nil {
+			s.logger.Error("Failed to reconcile payments", "error", reconcileErr)
+		}
+	})
+
+	if err != nil {
+		s.logger.Error("Failed to schedule payment reconciliation", "error", err)
+		return
+	}
+
 	// delete key value at 12 AM daily
 	_, err = scheduler.Every(1).Day().At("00:00").Do(func() {
 		s.logger.Info("Running midnight Redis cleanup...")
@@ -72,5 +115,8 @@ func (s *BidScheduler) Start() {
 	s.logger.Info("Bid scheduler started")
 	s.logger.Info("- Auto-start auctions: every 1 minute")
 	s.logger.Info("- Sync to DB: every 1 minute")
+	s.logger.Info("- Outbox worker: every 10 seconds")
+	s.logger.Info("- Winner payment deadlines: every 1 minute")
+	s.logger.Info("- Payment reconciliation: every 30 minutes")
 	s.logger.Info("- Redis cleanup: daily at 00:00")
 }
@@ -6,35 +6,42 @@ import (
 )
 
 type ArticleDTO struct {
-	ID        uint      `json:"id,omitempty" validate:"omitempty"`
-	Title     string    `json:"title,omitempty" validate:"required"`
-	Content   string    `json:"content,omitempty" validate:"required"`
-	Week      int       `json:"week,omitempty" validate:"required"` // Format: YYYYMMDD (e.g., 20241204)
-	Image     string    `json:"image,omitempty" validate:"omitempty"`
-	CreatedAt time.Time `json:"created_at,omitempty"`
+	ID      uint   `json:"id,omitempty" validate:"omitempty"`
+	Title   string `json:"title,omitempty" validate:"required"`
+	Content string `json:"content,omitempty" validate:"required"`
+	Week    int    `json:"week,omitempty" validate:"required"` // Format: YYYYMMDD (e.g., 20241204)
+	Image   string `json:"image,omitempty" validate:"omitempty"`
+	// ImageThumbnail and ImageDisplay are the resized variants of Image, set by uploading it
+	ImageThumbnail string    `json:"image_thumbnail,omitempty" validate:"omitempty"`
+	ImageDisplay   string    `json:"image_display,omitempty" validate:"omitempty"`
+	CreatedAt      time.Time `json:"created_at,omitempty"`
 }
 
 // ArticleRequest converts DTO to entity.Article
 func ArticleRequest(a ArticleDTO) (entity.Article, error) {
 	return entity.Article{
-		ID:        a.ID,
-		Title:     a.Title,
-		Content:   a.Content,
-		Week:      a.Week,
-		Image:     a.Image,
-		CreatedAt: a.CreatedAt,
+		ID:             a.ID,
+		Title:          a.Title,
+		Content:        a.Content,
+		Week:           a.Week,
+		Image:          a.Image,
+		ImageThumbnail: a.ImageThumbnail,
+		ImageDisplay:   a.ImageDisplay,
+		CreatedAt:      a.CreatedAt,
 	}, nil
 }
 
 // ArticleResponse converts entity.Article to DTO
 func ArticleResponse(m entity.Article) ArticleDTO {
 	return ArticleDTO{
-		ID:        m.ID,
-		Title:     m.Title,
-		Content:   m.Content,
-		Week:      m.Week,
-		Image:     m.Image,
-		CreatedAt: m.CreatedAt,
+		ID:             m.ID,
+		Title:          m.Title,
+		Content:        m.Content,
+		Week:           m.Week,
+		Image:          m.Image,
+		ImageThumbnail: m.ImageThumbnail,
+		ImageDisplay:   m.ImageDisplay,
+		CreatedAt:      m.CreatedAt,
 	}
 }
 
@@ -27,8 +27,18 @@ type AuctionItemDTO struct {
 	DonationID    int64   `json:"donation_id,omitempty" validate:"required"`
 	SessionID     *int64  `json:"session_id,omitempty"`
 	StartingPrice float64 `json:"starting_price,omitempty"`
-	// Photos        []string  `json:"photos,omitempty" validate:"dive,url"`
-	CreatedAt time.Time `json:"created_at,omitempty"`
+	// ReservePrice is write only, responses never return it so bidders cannot see it
+	ReservePrice *float64 `json:"reserve_price,omitempty" validate:"omitempty,gt=0"`
+	BuyNowPrice  *float64 `json:"buy_now_price,omitempty" validate:"omitempty,gt=0"`
+	Photos       []string `json:"photos,omitempty"`
+	// PhotoItems are the photos with their thumbnails, the URLs of a drafted item are short-lived signed URLs
+	PhotoItems []AuctionItemPhotoDTO `json:"photo_items,omitempty"`
+	CreatedAt  time.Time             `json:"created_at,omitempty"`
+}
+
+type AuctionItemPhotoDTO struct {
+	URL          string `json:"url"`
+	ThumbnailURL string `json:"thumbnail_url,omitempty"`
 }
 
 // AuctionItemUpdateDTO for partial updates (all fields optional)
@@ -36,8 +46,10 @@ type AuctionItemUpdateDTO struct {
 	Title         *string  `json:"title,omitempty"`
 	Description   *string  `json:"description,omitempty"`
 	Category      *string  `json:"category,omitempty"`
-	Status        *string  `json:"status,omitempty" validate:"omitempty,oneof=scheduled ongoing finished"`
+	Status        *string  `json:"status,omitempty" validate:"omitempty,oneof=draft scheduled ongoing finished unsold"`
 	StartingPrice *float64 `json:"starting_price,omitempty" validate:"omitempty,min=0"`
+	ReservePrice  *float64 `json:"reserve_price,omitempty" validate:"omitempty,gt=0"`
+	BuyNowPrice   *float64 `json:"buy_now_price,omitempty" validate:"omitempty,gt=0"`
 	SessionID     *int64   `json:"session_id,omitempty"`
 	DonationID    *int64   `json:"donation_id,omitempty"`
 }
@@ -48,6 +60,11 @@ func AuctionItemRequest(d AuctionItemDTO) (entity.AuctionItem, error) {
 		status = "scheduled"
 	}
 
+	photos := make([]entity.AuctionItemPhoto, 0, len(d.Photos))
+	for _, u := range d.Photos {
+		photos = append(photos, entity.AuctionItemPhoto{URL: u})
+	}
+
 	return entity.AuctionItem{
 		ID:            d.ID,
 		DonationID:    d.DonationID,
@@ -57,11 +74,20 @@ func AuctionItemRequest(d AuctionItemDTO) (entity.AuctionItem, error) {
 		Category:      d.Category,
 		Status:        status,
 		StartingPrice: d.StartingPrice,
+		ReservePrice:  d.ReservePrice,
+		BuyNowPrice:   d.BuyNowPrice,
+		Photos:        photos,
 		CreatedAt:     d.CreatedAt,
 	}, nil
 }
 
 func AuctionItemResponse(m entity.AuctionItem) AuctionItemDTO {
+	var photos []string
+	var photoItems []AuctionItemPhotoDTO
+	for _, p := range m.Photos {
+		photos = append(photos, p.URL)
+		photoItems = append(photoItems, AuctionItemPhotoDTO{URL: p.URL, ThumbnailURL: p.ThumbnailURL})
+	}
 	return AuctionItemDTO{
 		ID:            m.ID,
 		DonationID:    m.DonationID,
@@ -71,6 +97,9 @@ func AuctionItemResponse(m entity.AuctionItem) AuctionItemDTO {
 		Category:      m.Category,
 		Status:        m.Status,
 		StartingPrice: m.StartingPrice,
+		BuyNowPrice:   m.BuyNowPrice,
+		Photos:        photos,
+		PhotoItems:    photoItems,
 		CreatedAt:     m.CreatedAt.In(wibLocation),
 	}
 }
@@ -1,6 +1,8 @@
 package dto
 
 import (
+	"time"
+
 	"milestone3/be/internal/entity"
 )
 
@@ -27,3 +29,93 @@ func BidResponses(ms []entity.Bid) []BidDTO {
 	}
 	return res
 }
+
+// ProxyBidDTO registers a hidden ceiling the system bids up to on the user's behalf
+type ProxyBidDTO struct {
+	MaxAmount float64 `json:"max_amount" validate:"required,gt=0"`
+}
+
+const (
+	BidEventSnapshot      = "snapshot"
+	BidEventPlaced        = "bid_placed"
+	BidEventStatusChanged = "status_changed"
+	BidEventExtended      = "end_time_extended"
+)
+
+// BidEventDTO is pushed to stream subscribers of a session item
+type BidEventDTO struct {
+	Type      string     `json:"type"`
+	SessionID int64      `json:"session_id"`
+	ItemID    int64      `json:"item_id"`
+	Amount    float64    `json:"amount,omitempty"`
+	BidderID  int64      `json:"bidder_id,omitempty"`
+	Status    string     `json:"status,omitempty"`
+	EndTime   *time.Time `json:"end_time,omitempty"`
+	Timestamp time.Time  `json:"timestamp"`
+}
+
+type BidHistoryDTO struct {
+	ID        int64     `json:"id"`
+	ItemID    int64     `json:"auction_item_id"`
+	UserID    int64     `json:"user_id"`
+	Amount    float64   `json:"amount"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func BidHistoryResponses(ms []entity.Bid) []BidHistoryDTO {
+	res := make([]BidHistoryDTO, 0, len(ms))
+	for _, m := range ms {
+		res = append(res, BidHistoryDTO{
+			ID:        m.ID,
+			ItemID:    m.ItemID,
+			UserID:    m.UserID,
+			Amount:    m.Amount,
+			CreatedAt: m.CreatedAt.In(wibLocation),
+		})
+	}
+	return res
+}
+
+// HighestBidDTO is the current state of an item, MinNextBid already applies the increment ladder
+type HighestBidDTO struct {
+	SessionID  int64   `json:"session_id"`
+	ItemID     int64   `json:"item_id"`
+	HighestBid float64 `json:"highest_bid"`
+	BidderID   int64   `json:"bidder_id"`
+	MinNextBid float64 `json:"min_next_bid"`
+}
+
+// BidIncrementTierDTO applies from MinPrice upward, the step is Increment plus Percent of the current bid
+type BidIncrementTierDTO struct {
+	MinPrice  float64 `json:"min_price" validate:"gte=0"`
+	Increment float64 `json:"increment" validate:"gte=0"`
+	Percent   float64 `json:"percent" validate:"gte=0,lte=100"`
+}
+
+type BidIncrementLadderDTO struct {
+	Tiers []BidIncrementTierDTO `json:"tiers" validate:"dive"`
+}
+
+func BidIncrementTierRequests(ds []BidIncrementTierDTO) []entity.BidIncrementTier {
+	res := make([]entity.BidIncrementTier, 0, len(ds))
+	for _, d := range ds {
+		res = append(res, entity.BidIncrementTier{
+			MinPrice:  d.MinPrice,
+			Increment: d.Increment,
+			Percent:   d.Percent,
+		})
+	}
+	return res
+}
+
+func BidIncrementTierResponses(ms []entity.BidIncrementTier) []BidIncrementTierDTO {
+	res := make([]BidIncrementTierDTO, 0, len(ms))
+	for _, m := range ms {
+		res = append(res, BidIncrementTierDTO{
+			MinPrice:  m.MinPrice,
+			Increment: m.Increment,
+			Percent:   m.Percent,
+		})
+	}
+	return res
+}
@@ -14,19 +14,45 @@ type DonationDTO struct {
 	Category    string                `json:"category,omitempty" validate:"required"`
 	Condition   string                `json:"condition,omitempty" validate:"required"`
 	Status      entity.StatusDonation `json:"status,omitempty" validate:"omitempty"`
-	Photos      []string              `json:"photos,omitempty" validate:"omitempty"`
-	CreatedAt   time.Time             `json:"created_at,omitempty"`
+	// RejectionReason is read-only, set by rejecting the donation
+	RejectionReason string   `json:"rejection_reason,omitempty"`
+	Photos          []string `json:"photos,omitempty" validate:"omitempty"`
+	// PhotoItems are the photos with their id for removal and their resized variants
+	PhotoItems []DonationPhotoDTO `json:"photo_items,omitempty"`
+	CreatedAt  time.Time          `json:"created_at,omitempty"`
 }
 
+// DonationPhotoDTO is a photo of a donation, the URLs are short-lived signed URLs in responses
+type DonationPhotoDTO struct {
+	ID           uint   `json:"id"`
+	URL          string `json:"url"`
+	ThumbnailURL string `json:"thumbnail_url,omitempty"`
+	DisplayURL   string `json:"display_url,omitempty"`
+}
+
+// DonationApprovalDTO moves a donation to its next status, a rejection needs a reason
 type DonationApprovalDTO struct {
-	Status entity.StatusDonation `json:"status" validate:"required,oneof=pending verified_for_auction verified_for_donation"`
+	Status entity.StatusDonation `json:"status" validate:"required,oneof=verified_for_auction verified_for_donation rejected awaiting_pickup received auctioned distributed cancelled_by_donor"`
+	Reason string                `json:"reason" validate:"omitempty,max=1000"`
+}
+
+type DonationStatusHistoryDTO struct {
+	FromStatus entity.StatusDonation `json:"from_status"`
+	ToStatus   entity.StatusDonation `json:"to_status"`
+	Reason     string                `json:"reason,omitempty"`
+	ActorRole  string                `json:"actor_role"`
+	CreatedAt  time.Time             `json:"created_at"`
 }
 
 // DonationRequest converts DTO to entity.Donation
 func DonationRequest(d DonationDTO) (entity.Donation, error) {
 	photos := make([]entity.DonationPhoto, 0, len(d.Photos))
-	for _, u := range d.Photos {
-		photos = append(photos, entity.DonationPhoto{URL: u})
+	if len(d.PhotoItems) > 0 {
+		photos = DonationPhotoRequests(d.PhotoItems)
+	} else {
+		for _, u := range d.Photos {
+			photos = append(photos, entity.DonationPhoto{URL: u})
+		}
 	}
 	return entity.Donation{
 		ID:          d.ID,
@@ -44,22 +70,35 @@ func DonationRequest(d DonationDTO) (entity.Donation, error) {
 // DonationResponse converts entity.Donation to DTO
 func DonationResponse(m entity.Donation) DonationDTO {
 	photos := make([]string, 0, len(m.Photos))
+	photoItems := make([]DonationPhotoDTO, 0, len(m.Photos))
 	for _, p := range m.Photos {
 		photos = append(photos, p.URL)
+		photoItems = append(photoItems, DonationPhotoDTO{ID: p.ID, URL: p.URL, ThumbnailURL: p.ThumbnailURL, DisplayURL: p.DisplayURL})
 	}
 	return DonationDTO{
-		ID:          m.ID,
-		UserID:      m.UserID,
-		Title:       m.Title,
-		Description: m.Description,
-		Category:    m.Category,
-		Condition:   m.Condition,
-		Status:      m.Status,
-		Photos:      photos,
-		CreatedAt:   m.CreatedAt,
+		ID:              m.ID,
+		UserID:          m.UserID,
+		Title:           m.Title,
+		Description:     m.Description,
+		Category:        m.Category,
+		Condition:       m.Condition,
+		Status:          m.Status,
+		RejectionReason: m.RejectionReason,
+		Photos:          photos,
+		PhotoItems:      photoItems,
+		CreatedAt:       m.CreatedAt,
 	}
 }
 
+// DonationPhotoRequests converts uploaded photos to entity.DonationPhoto
+func DonationPhotoRequests(ps []DonationPhotoDTO) []entity.DonationPhoto {
+	photos := make([]entity.DonationPhoto, 0, len(ps))
+	for _, p := range ps {
+		photos = append(photos, entity.DonationPhoto{URL: p.URL, ThumbnailURL: p.ThumbnailURL, DisplayURL: p.DisplayURL})
+	}
+	return photos
+}
+
 // DonationResponses converts slice of entity.Donation to slice of DTOs
 func DonationResponses(ms []entity.Donation) []DonationDTO {
 	res := make([]DonationDTO, 0, len(ms))
@@ -68,3 +107,18 @@ func DonationResponses(ms []entity.Donation) []DonationDTO {
 	}
 	return res
 }
+
+// DonationHistoryResponses converts the status history of a donation, the actor stays private
+func DonationHistoryResponses(ms []entity.DonationStatusHistory) []DonationStatusHistoryDTO {
+	res := make([]DonationStatusHistoryDTO, 0, len(ms))
+	for _, m := range ms {
+		res = append(res, DonationStatusHistoryDTO{
+			FromStatus: m.FromStatus,
+			ToStatus:   m.ToStatus,
+			Reason:     m.Reason,
+			ActorRole:  m.ActorRole,
+			CreatedAt:  m.CreatedAt,
+		})
+	}
+	return res
+}
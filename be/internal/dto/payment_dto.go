@@ -1,24 +1,120 @@
 package dto
 
-import "milestone3/be/internal/entity"
+import (
+	"milestone3/be/internal/entity"
+	"time"
+)
+
+// payment methods a payer can choose, card payments go through a Snap redirect
+const (
+	PaymentMethodQris = "qris"
+	PaymentMethodBcaVa = "bca_va"
+	PaymentMethodBniVa = "bni_va"
+	PaymentMethodBriVa = "bri_va"
+	PaymentMethodPermataVa = "permata_va"
+	PaymentMethodGopay = "gopay"
+	PaymentMethodShopeePay = "shopeepay"
+	PaymentMethodCard = "card"
+)
 
 type PaymentRequest struct {
 	UserId int `json:"user_id"`
 	AuctionItemId float64 `json:"auction_item_id"`
-	Amount float64 `json:"amount" validate:"required"`
+	// defaults to qris
+	PaymentMethod string `json:"payment_method" validate:"omitempty,oneof=qris bca_va bni_va bri_va permata_va gopay shopeepay card"`
 }
 
+// PaymentResponse carries the instructions of the chosen method, only its own section is set
 type PaymentResponse struct {
-	PaymentLinkUrl string `json:"payment_link_url"`
-	TransactionId string `json:"transaction_id"`
-	ExpiryTime string `json:"expiry_time"`
 	OrderId string `json:"order_id"`
+	TransactionId string `json:"transaction_id,omitempty"`
+	PaymentMethod string `json:"payment_method"`
+	ExpiryTime string `json:"expiry_time,omitempty"`
+	VirtualAccount *VirtualAccountPayment `json:"virtual_account,omitempty"`
+	EWallet *EWalletPayment `json:"ewallet,omitempty"`
+	Qris *QrisPayment `json:"qris,omitempty"`
+	Redirect *RedirectPayment `json:"redirect,omitempty"`
+}
+
+type VirtualAccountPayment struct {
+	Bank string `json:"bank"`
+	VaNumber string `json:"va_number"`
+}
+
+type EWalletPayment struct {
+	// opens the e-wallet app on mobile
+	DeeplinkUrl string `json:"deeplink_url"`
+	// gopay also returns a qr code to scan from another device
+	QrCodeUrl string `json:"qr_code_url,omitempty"`
+}
+
+type QrisPayment struct {
+	QrString string `json:"qr_string"`
+	QrCodeUrl string `json:"qr_code_url,omitempty"`
+}
+
+// RedirectPayment is a hosted payment page, used for cards
+type RedirectPayment struct {
+	Token string `json:"token"`
+	RedirectUrl string `json:"redirect_url"`
+}
+
+// ChargeRequest is what the service asks a payment gateway to charge
+type ChargeRequest struct {
+	OrderId string
+	PaymentMethod string
+	Amount float64
+	CustomerName string
+	CustomerEmail string
+}
+
+// GatewayStatus is the state of a transaction as a payment gateway reports it, PaymentStatus
+// is already mapped to ours, "" while nothing changes yet
+type GatewayStatus struct {
+	OrderId string
+	TransactionId string
+	TransactionStatus string
+	FraudStatus string
+	Amount float64
+	PaymentStatus string
+}
+
+type RefundRequest struct {
+	OrderId string
+	// the gateway refunds a key only once, retrying with the same key is safe
+	RefundKey string
+	Amount float64
+	Reason string
+}
+
+type RefundResponse struct {
+	OrderId string
+	RefundKey string
+	Amount float64
+	Status string
 }
 
 type CheckPaymentStatusResponse struct {
 	OrderId string `json:"order_id"`
 	TransactionId string `json:"transaction_id"`
 	PaymentStatus string `json:"payment_status"`
+	FraudStatus string `json:"fraud_status,omitempty"`
+}
+
+// MidtransNotification is the body Midtrans posts to the HTTP notification url
+type MidtransNotification struct {
+	TransactionTime string `json:"transaction_time"`
+	TransactionStatus string `json:"transaction_status"`
+	TransactionId string `json:"transaction_id"`
+	StatusMessage string `json:"status_message"`
+	StatusCode string `json:"status_code"`
+	SignatureKey string `json:"signature_key"`
+	PaymentType string `json:"payment_type"`
+	OrderId string `json:"order_id"`
+	MerchantId string `json:"merchant_id"`
+	GrossAmount string `json:"gross_amount"`
+	FraudStatus string `json:"fraud_status"`
+	Currency string `json:"currency"`
 }
 
 type PaymentInfoResponse struct {
@@ -29,4 +125,26 @@ type PaymentInfoResponse struct {
 	Status string `json:"payment_status"`
 	// PaymentStatus entity.PaymentStatus `json:"payment_status"`
 	Amount float64 `json:"amount"`
-}
\ No newline at end of file
+	PaymentMethod string `json:"payment_method,omitempty"`
+}
+// PaymentRefundRequest refunds a paid payment, without an amount all that is left of it is refunded
+type PaymentRefundRequest struct {
+	Amount float64 `json:"amount" validate:"omitempty,gt=0"`
+	Reason string `json:"reason" validate:"required"`
+	// ReopenItem puts the auction item back up for auction, e.g. when it was damaged on handover
+	ReopenItem bool `json:"reopen_item"`
+}
+
+type PaymentRefundResponse struct {
+	Id int `json:"id"`
+	PaymentId int `json:"payment_id"`
+	Amount float64 `json:"amount"`
+	Reason string `json:"reason"`
+	ActorId int `json:"actor_id"`
+	Status string `json:"status"`
+	GatewayStatus string `json:"gateway_status,omitempty"`
+	LastError string `json:"last_error,omitempty"`
+	ReopenItem bool `json:"reopen_item"`
+	CreatedAt time.Time `json:"created_at"`
+	RefundedAt *time.Time `json:"refunded_at,omitempty"`
+}
@@ -0,0 +1,22 @@
+package dto
+
+import (
+	"time"
+
+	"milestone3/be/internal/entity"
+)
+
+type ReconciliationSummary struct {
+	Matched          int `json:"matched"`
+	AmountMismatch   int `json:"amount_mismatch"`
+	Orphaned         int `json:"orphaned"`
+	MissingAtGateway int `json:"missing_at_gateway"`
+}
+
+// ReconciliationReport lists the findings of the reconciliation job recorded in [From, To)
+type ReconciliationReport struct {
+	From     time.Time                      `json:"from"`
+	To       time.Time                      `json:"to"`
+	Summary  ReconciliationSummary          `json:"summary"`
+	Findings []entity.PaymentReconciliation `json:"findings"`
+}
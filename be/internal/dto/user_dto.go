@@ -1,5 +1,7 @@
 package dto
 
+import "time"
+
 type UserRequest struct {
 	Name string `json:"name" validate:"required,gte=3"` 
 	Email string `json:"email" validate:"required,email"` 
@@ -11,9 +13,90 @@ type UserResponse struct {
 	Name string `json:"name"`
 	Email string `json:"email"`
 	Role string `json:"role"`
+	EmailVerified bool `json:"email_verified"`
+	Phone string `json:"phone"`
+	Address string `json:"address"`
 }
 
 type UserLoginRequest struct {
 	Email string `json:"email" validate:"required,email"` 
 	Password string `json:"password" validate:"required,gte=8"` 	
-}
\ No newline at end of file
+}
+
+// TokenResponse is returned on login and refresh, ExpiresIn is the lifetime of the access token in seconds
+type TokenResponse struct {
+	AccessToken string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	TokenType string `json:"token_type"`
+	ExpiresIn int `json:"expires_in"`
+}
+
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
+// LogoutRequest revokes the refresh token along with the access token, when given
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// VerifyEmailRequest carries the token of the verification link, in the query or the body
+type VerifyEmailRequest struct {
+	Token string `json:"token" query:"token" validate:"required"`
+}
+
+// EmailRequest asks for an email to be sent to the account, forgot password or a new verification link
+type EmailRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+type ResetPasswordRequest struct {
+	Token string `json:"token" validate:"required"`
+	Password string `json:"password" validate:"required,gte=8"`
+}
+
+// UpdateProfileRequest changes the fields given, an empty phone or address clears it
+type UpdateProfileRequest struct {
+	Name *string `json:"name" validate:"omitempty,gte=3,max=255"`
+	Phone *string `json:"phone" validate:"omitempty,e164"`
+	Address *string `json:"address" validate:"omitempty,max=500"`
+}
+
+// ChangePasswordRequest needs the current password again, a stolen access token alone is not enough
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" validate:"required"`
+	NewPassword string `json:"new_password" validate:"required,gte=8"`
+}
+
+// DeleteAccountRequest confirms the deletion with the password
+type DeleteAccountRequest struct {
+	Password string `json:"password" validate:"required"`
+}
+
+// UserListRequest filters the admin user list, search matches the name or email
+type UserListRequest struct {
+	Search string `query:"search" validate:"max=100"`
+	Role string `query:"role"`
+	Status string `query:"status" validate:"omitempty,oneof=active suspended deleted"`
+	Page int `query:"page"`
+	Limit int `query:"limit"`
+}
+
+// AdminUserResponse is a user as admins see it, Status is active, suspended or deleted
+type AdminUserResponse struct {
+	Id int `json:"id"`
+	Name string `json:"name"`
+	Email string `json:"email"`
+	Role string `json:"role"`
+	EmailVerified bool `json:"email_verified"`
+	Phone string `json:"phone"`
+	Status string `json:"status"`
+	SuspendedAt *time.Time `json:"suspended_at,omitempty"`
+	SuspensionReason string `json:"suspension_reason,omitempty"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type SuspendUserRequest struct {
+	Reason string `json:"reason" validate:"required,max=500"`
+}
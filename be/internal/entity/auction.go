@@ -19,8 +19,23 @@ type AuctionItem struct {
 	ID            int64     `gorm:"primaryKey;autoIncrement" json:"id"`
 	DonationID    int64     `gorm:"not null" json:"donation_id"`
 	StartingPrice float64   `gorm:"not null" json:"starting_price"`
+	ReservePrice  *float64  `gorm:"null" json:"-"` // hidden from bidders
+	BuyNowPrice   *float64  `gorm:"null" json:"buy_now_price,omitempty"`
 	SessionID     *int64    `gorm:"null" json:"session_id"`
 	CreatedAt     time.Time `gorm:"autoCreateTime" json:"created_at"`
 
-	Session *AuctionSession `gorm:"foreignKey:SessionID" json:"session,omitempty"`
+	Session  *AuctionSession    `gorm:"foreignKey:SessionID" json:"session,omitempty"`
+	Donation *Donation          `gorm:"foreignKey:DonationID" json:"-"`
+	Photos   []AuctionItemPhoto `gorm:"foreignKey:AuctionItemID;constraint:OnDelete:CASCADE" json:"photos,omitempty"`
+}
+
+// AuctionItemPhoto is a photo of the donated item, copied from the donation when the item is drafted.
+// a copied photo is Private, its URL and ThumbnailURL are object names in the private bucket that
+// responses sign
+type AuctionItemPhoto struct {
+	ID            int64  `gorm:"primaryKey;autoIncrement" json:"id"`
+	AuctionItemID int64  `gorm:"not null" json:"auction_item_id"`
+	URL           string `gorm:"size:255" json:"url"`
+	ThumbnailURL  string `gorm:"size:255" json:"thumbnail_url"`
+	Private       bool   `gorm:"not null;default:false" json:"-"`
 }
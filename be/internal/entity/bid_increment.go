@@ -0,0 +1,17 @@
+package entity
+
+// BidIncrementTier is one step of the bid increment ladder. It applies from MinPrice
+// upward until the next tier, the step is Increment plus Percent of the current price.
+// Tiers without session and item are the global ladder, a session or item ladder overrides it.
+type BidIncrementTier struct {
+	ID        int64   `gorm:"primaryKey;autoIncrement" json:"id"`
+	SessionID *int64  `gorm:"index" json:"session_id,omitempty"`
+	ItemID    *int64  `gorm:"column:auction_item_id;index" json:"auction_item_id,omitempty"`
+	MinPrice  float64 `gorm:"not null" json:"min_price"`
+	Increment float64 `gorm:"not null;default:0" json:"increment"`
+	Percent   float64 `gorm:"not null;default:0" json:"percent"`
+}
+
+func (BidIncrementTier) TableName() string {
+	return "bid_increment_tiers"
+}
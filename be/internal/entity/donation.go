@@ -10,6 +10,12 @@ var (
 	StatusPending             StatusDonation = "pending"
 	StatusVerifiedForAuction  StatusDonation = "verified_for_auction"
 	StatusVerifiedForDonation StatusDonation = "verified_for_donation"
+	StatusRejected            StatusDonation = "rejected"
+	StatusAwaitingPickup      StatusDonation = "awaiting_pickup"
+	StatusReceived            StatusDonation = "received"
+	StatusAuctioned           StatusDonation = "auctioned"
+	StatusDistributed         StatusDonation = "distributed"
+	StatusCancelledByDonor    StatusDonation = "cancelled_by_donor"
 )
 
 type Donation struct {
@@ -20,14 +26,18 @@ type Donation struct {
 	Description string         `gorm:"type:text" json:"description"`
 	Category    string         `gorm:"size:255" json:"category"`
 	Condition   string         `gorm:"size:255" json:"condition"`
-	Status      StatusDonation `gorm:"type:donation_status;default:'pending';not null" json:"status"` // enum: pending, verified_for_auction, verified_for_donation
-	CreatedAt   time.Time      `gorm:"autoCreateTime" json:"created_at"`
+	Status      StatusDonation `gorm:"type:donation_status;default:'pending';not null" json:"status"` // enum: see the Status* values above
+	// RejectionReason is set when the donation is rejected
+	RejectionReason string    `gorm:"type:text" json:"rejection_reason,omitempty"`
+	CreatedAt       time.Time `gorm:"autoCreateTime" json:"created_at"`
 
 	Photos []DonationPhoto `gorm:"foreignKey:DonationID;constraint:OnDelete:CASCADE" json:"photos,omitempty"`
 }
 
 type DonationPhoto struct {
-	ID         uint   `gorm:"primaryKey;autoIncrement" json:"id"`
-	DonationID uint   `gorm:"not null" json:"donation_id"`
-	URL        string `gorm:"size:255" json:"url"`
+	ID           uint   `gorm:"primaryKey;autoIncrement" json:"id"`
+	DonationID   uint   `gorm:"not null" json:"donation_id"`
+	URL          string `gorm:"size:255" json:"url"`
+	ThumbnailURL string `gorm:"size:255" json:"thumbnail_url"`
+	DisplayURL   string `gorm:"size:255" json:"display_url"`
 }
@@ -0,0 +1,19 @@
+package entity
+
+import "time"
+
+// DonationStatusHistory records one status transition of a donation, who made it and why
+type DonationStatusHistory struct {
+	ID         uint           `gorm:"primaryKey;autoIncrement" json:"id"`
+	DonationID uint           `gorm:"not null" json:"donation_id"`
+	FromStatus StatusDonation `gorm:"type:donation_status;not null" json:"from_status"`
+	ToStatus   StatusDonation `gorm:"type:donation_status;not null" json:"to_status"`
+	Reason     string         `gorm:"type:text" json:"reason,omitempty"`
+	ActorID    uint           `gorm:"not null" json:"actor_id"`
+	ActorRole  string         `gorm:"size:20;not null" json:"actor_role"`
+	CreatedAt  time.Time      `gorm:"autoCreateTime" json:"created_at"`
+}
+
+func (DonationStatusHistory) TableName() string {
+	return "donation_status_history"
+}
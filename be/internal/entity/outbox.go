@@ -0,0 +1,45 @@
+package entity
+
+import "time"
+
+const (
+	OutboxItemWon             = "auction.item_won"
+	OutboxItemUnsold          = "auction.item_unsold"
+	OutboxSecondChanceOffered = "auction.second_chance_offered"
+	OutboxItemRelisted        = "auction.item_relisted"
+
+	OutboxStatusPending   = "pending"
+	OutboxStatusProcessed = "processed"
+	OutboxStatusFailed    = "failed"
+)
+
+// OutboxEvent is written in the same transaction as the change it announces and delivered
+// afterwards by the outbox worker. DedupKey keeps a retried transaction from announcing twice.
+type OutboxEvent struct {
+	ID          int64      `gorm:"primaryKey;autoIncrement" json:"id"`
+	EventType   string     `gorm:"not null" json:"event_type"`
+	AggregateID int64      `gorm:"not null;index" json:"aggregate_id"`
+	DedupKey    string     `gorm:"uniqueIndex;not null" json:"dedup_key"`
+	Payload     string     `gorm:"type:jsonb;not null" json:"payload"`
+	Status      string     `gorm:"not null;default:pending" json:"status"`
+	Attempts    int        `gorm:"not null;default:0" json:"attempts"`
+	LastError   string     `json:"last_error,omitempty"`
+	AvailableAt time.Time  `gorm:"not null" json:"available_at"`
+	LockedUntil *time.Time `json:"locked_until,omitempty"`
+	ProcessedAt *time.Time `json:"processed_at,omitempty"`
+	CreatedAt   time.Time  `gorm:"autoCreateTime" json:"created_at"`
+}
+
+func (OutboxEvent) TableName() string {
+	return "outbox_events"
+}
+
+// AuctionOutcome is the payload of the auction outcome events, for a second chance offer
+// WinnerID is the bidder the item is offered to and DefaultedUserID the one who did not pay
+type AuctionOutcome struct {
+	SessionID       int64   `json:"session_id"`
+	ItemID          int64   `json:"auction_item_id"`
+	WinnerID        int64   `json:"winner_id,omitempty"`
+	Amount          float64 `json:"amount"`
+	DefaultedUserID int64   `json:"defaulted_user_id,omitempty"`
+}
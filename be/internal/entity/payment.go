@@ -1,5 +1,7 @@
 package entity
 
+import "time"
+
 type Payment struct {
 	Id int
 	UserId int
@@ -9,6 +11,10 @@ type Payment struct {
 	OrderId string
 	// PaymentStatus PaymentStatus `gorm:"foreignKey:StatusId;references:Id"`
 	Amount float64
+	// PaymentMethod is how the payer chose to pay, empty on a winner payment not charged yet
+	PaymentMethod string
+	// DueAt is set on winner payments, unpaid after it the winner defaults
+	DueAt *time.Time
 }
 
 // type PaymentStatus struct {
@@ -0,0 +1,31 @@
+package entity
+
+import "time"
+
+const (
+	ReconciliationMatched          = "matched"
+	ReconciliationAmountMismatch   = "amount_mismatch"
+	ReconciliationOrphaned         = "orphaned"
+	ReconciliationMissingAtGateway = "missing_at_gateway"
+)
+
+// PaymentReconciliation is one finding of the reconciliation job: a pending payment the gateway
+// settled or failed (matched), a gateway amount differing from ours, a gateway transaction we have
+// no payment for or a failed attempt settled after the item stopped being owed (orphaned), or a
+// pending payment the gateway never received.
+type PaymentReconciliation struct {
+	ID            int64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	PaymentID     *int      `json:"payment_id"`
+	OrderID       string    `gorm:"size:200;not null" json:"order_id"`
+	Outcome       string    `gorm:"size:30;not null" json:"outcome"`
+	LocalStatus   string    `gorm:"size:30" json:"local_status"`
+	GatewayStatus string    `gorm:"size:50;not null;default:''" json:"gateway_status"`
+	LocalAmount   float64   `json:"local_amount"`
+	GatewayAmount float64   `json:"gateway_amount"`
+	AppliedStatus string    `gorm:"size:30" json:"applied_status"`
+	CreatedAt     time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+func (PaymentReconciliation) TableName() string {
+	return "payment_reconciliations"
+}
@@ -0,0 +1,22 @@
+package entity
+
+import "time"
+
+// PaymentRefund is one full or partial refund of a paid payment, issued by an admin.
+// its status goes pending -> refunded, or failed when the gateway refuses it
+type PaymentRefund struct {
+	Id        int
+	PaymentId int
+	Amount    float64
+	Reason    string
+	// ActorId is the admin who issued the refund
+	ActorId int
+	Status  string
+	// GatewayStatus is what the gateway answered, refund or partial_refund on midtrans
+	GatewayStatus string
+	LastError     string
+	// ReopenItem puts the auction item back to scheduled once refunded
+	ReopenItem bool
+	CreatedAt  time.Time
+	RefundedAt *time.Time
+}
@@ -0,0 +1,23 @@
+package entity
+
+import "time"
+
+// RefreshToken is one link of a rotation chain. Every refresh revokes the token used and issues
+// the next one in the same family, so a revoked token showing up again means it was stolen and
+// the whole family is revoked. Only the SHA-256 hash of the token is kept
+type RefreshToken struct {
+	ID        uint   `gorm:"primaryKey;autoIncrement"`
+	UserID    int    `gorm:"not null;index"`
+	FamilyID  string `gorm:"size:36;not null;index"`
+	TokenHash string `gorm:"size:64;not null;uniqueIndex"`
+	// AccessJTI is the access token issued along, denied when the family is revoked
+	AccessJTI       string    `gorm:"column:access_jti;size:36"`
+	AccessExpiresAt time.Time `gorm:"not null"`
+	ExpiresAt       time.Time `gorm:"not null"`
+	RevokedAt       *time.Time
+	CreatedAt       time.Time `gorm:"autoCreateTime"`
+}
+
+func (RefreshToken) TableName() string {
+	return "refresh_tokens"
+}
@@ -0,0 +1,50 @@
+package entity
+
+// roles a user can hold, every new account is a user
+const (
+	RoleUser        = "user"
+	RoleVerifier    = "verifier"
+	RoleInstitution = "institution"
+	RoleAdmin       = "admin"
+)
+
+// permissions are granted to roles in role_permissions, access tokens carry the ones of their role
+const (
+	// PermDonationReadAll lists and reads every donation, not only the caller's own
+	PermDonationReadAll = "donation:read_all"
+	// PermDonationVerify moves donations through pickup, inspection and the verification decision
+	PermDonationVerify = "donation:verify"
+	// PermDonationDistribute confirms a donation verified for donation reached its institution
+	PermDonationDistribute = "donation:distribute"
+	// PermDonationManage edits and deletes any donation and its photos
+	PermDonationManage       = "donation:manage"
+	PermFinalDonationReadAll = "final_donation:read_all"
+	PermAuctionManage        = "auction:manage"
+	PermArticleManage        = "article:manage"
+	PermPaymentRefund        = "payment:refund"
+	PermReportRead           = "report:read"
+	PermRoleAssign           = "role:assign"
+	// PermUserManage lists, searches and suspends users
+	PermUserManage = "user:manage"
+)
+
+type Role struct {
+	ID          uint         `gorm:"primaryKey;autoIncrement" json:"id"`
+	Name        string       `gorm:"size:32;not null;uniqueIndex" json:"name"`
+	Description string       `json:"description"`
+	Permissions []Permission `gorm:"many2many:role_permissions" json:"permissions"`
+}
+
+func (Role) TableName() string {
+	return "roles"
+}
+
+type Permission struct {
+	ID          uint   `gorm:"primaryKey;autoIncrement" json:"id"`
+	Name        string `gorm:"size:64;not null;uniqueIndex" json:"name"`
+	Description string `json:"description"`
+}
+
+func (Permission) TableName() string {
+	return "permissions"
+}
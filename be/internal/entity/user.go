@@ -1,15 +1,23 @@
 package entity
 
+import "time"
+
 type Users struct {
 	Id int
 	Name string
 	Email string
 	Password string `json:"-"`
+	// Role is the name of the role, its permissions are in role_permissions
 	Role string
-	// Role Role `gorm:"foreignKey:RoleId;references:Id"`
-}
-
-// type Role struct {
-// 	Id int
-// 	Name string
-// }
\ No newline at end of file
+	// EmailVerifiedAt is nil until the user follows the link of the verification email
+	EmailVerifiedAt *time.Time
+	// Phone and Address are where won items are shipped to
+	Phone string
+	Address string
+	// SuspendedAt is set while an admin keeps the user out, with the reason given
+	SuspendedAt *time.Time
+	SuspensionReason string
+	// DeletedAt is set once the user deleted the account, the row stays with nothing personal left
+	DeletedAt *time.Time
+	CreatedAt time.Time
+}
\ No newline at end of file
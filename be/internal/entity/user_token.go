@@ -0,0 +1,24 @@
+package entity
+
+import "time"
+
+const (
+	UserTokenEmailVerification = "email_verification"
+	UserTokenPasswordReset     = "password_reset"
+)
+
+// UserToken is a single-use token mailed to a user, to verify their email or reset their password.
+// Only the SHA-256 hash of the token is kept, and a new token of a purpose voids the older ones
+type UserToken struct {
+	ID        uint      `gorm:"primaryKey;autoIncrement"`
+	UserID    int       `gorm:"not null;index"`
+	Purpose   string    `gorm:"size:32;not null"`
+	TokenHash string    `gorm:"size:64;not null;uniqueIndex"`
+	ExpiresAt time.Time `gorm:"not null"`
+	UsedAt    *time.Time
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
+
+func (UserToken) TableName() string {
+	return "user_tokens"
+}
@@ -63,18 +63,18 @@ func (mr *MockAuctionItemRepositoryMockRecorder) Delete(id interface{}) *gomock.
 }
 
 // GetAll mocks base method.
-func (m *MockAuctionItemRepository) GetAll() ([]entity.AuctionItem, error) {
+func (m *MockAuctionItemRepository) GetAll(includeDrafts bool) ([]entity.AuctionItem, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAll")
+	ret := m.ctrl.Call(m, "GetAll", includeDrafts)
 	ret0, _ := ret[0].([]entity.AuctionItem)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetAll indicates an expected call of GetAll.
-func (mr *MockAuctionItemRepositoryMockRecorder) GetAll() *gomock.Call {
+func (mr *MockAuctionItemRepositoryMockRecorder) GetAll(includeDrafts interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockAuctionItemRepository)(nil).GetAll))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockAuctionItemRepository)(nil).GetAll), includeDrafts)
 }
 
 // GetByID mocks base method.
@@ -0,0 +1,123 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/service/auction_item_service.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	dto "milestone3/be/internal/dto"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockAuctionItemService is a mock of AuctionItemService interface.
+type MockAuctionItemService struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuctionItemServiceMockRecorder
+}
+
+// MockAuctionItemServiceMockRecorder is the mock recorder for MockAuctionItemService.
+type MockAuctionItemServiceMockRecorder struct {
+	mock *MockAuctionItemService
+}
+
+// NewMockAuctionItemService creates a new mock instance.
+func NewMockAuctionItemService(ctrl *gomock.Controller) *MockAuctionItemService {
+	mock := &MockAuctionItemService{ctrl: ctrl}
+	mock.recorder = &MockAuctionItemServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuctionItemService) EXPECT() *MockAuctionItemServiceMockRecorder {
+	return m.recorder
+}
+
+// CheckAndStartScheduledItems mocks base method.
+func (m *MockAuctionItemService) CheckAndStartScheduledItems() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckAndStartScheduledItems")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CheckAndStartScheduledItems indicates an expected call of CheckAndStartScheduledItems.
+func (mr *MockAuctionItemServiceMockRecorder) CheckAndStartScheduledItems() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckAndStartScheduledItems", reflect.TypeOf((*MockAuctionItemService)(nil).CheckAndStartScheduledItems))
+}
+
+// Create mocks base method.
+func (m *MockAuctionItemService) Create(item *dto.AuctionItemDTO) (dto.AuctionItemDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", item)
+	ret0, _ := ret[0].(dto.AuctionItemDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockAuctionItemServiceMockRecorder) Create(item interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockAuctionItemService)(nil).Create), item)
+}
+
+// Delete mocks base method.
+func (m *MockAuctionItemService) Delete(id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockAuctionItemServiceMockRecorder) Delete(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockAuctionItemService)(nil).Delete), id)
+}
+
+// GetAll mocks base method.
+func (m *MockAuctionItemService) GetAll(includeDrafts bool) ([]dto.AuctionItemDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", includeDrafts)
+	ret0, _ := ret[0].([]dto.AuctionItemDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockAuctionItemServiceMockRecorder) GetAll(includeDrafts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockAuctionItemService)(nil).GetAll), includeDrafts)
+}
+
+// GetByID mocks base method.
+func (m *MockAuctionItemService) GetByID(id int64, includeDrafts bool) (dto.AuctionItemDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", id, includeDrafts)
+	ret0, _ := ret[0].(dto.AuctionItemDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockAuctionItemServiceMockRecorder) GetByID(id, includeDrafts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockAuctionItemService)(nil).GetByID), id, includeDrafts)
+}
+
+// Update mocks base method.
+func (m *MockAuctionItemService) Update(id int64, item *dto.AuctionItemUpdateDTO) (dto.AuctionItemDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", id, item)
+	ret0, _ := ret[0].(dto.AuctionItemDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockAuctionItemServiceMockRecorder) Update(id, item interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockAuctionItemService)(nil).Update), id, item)
+}
@@ -4,6 +4,7 @@ import (
 	"milestone3/be/internal/entity"
 	gomock "github.com/golang/mock/gomock"
 	reflect "reflect"
+	time "time"
 )
 
 // MockAuctionSessionRepository is a mock of AuctionSessionRepository interface.
@@ -72,6 +73,20 @@ func (mr *MockAuctionSessionRepositoryMockRecorder) GetActiveSessions() *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActiveSessions", reflect.TypeOf((*MockAuctionSessionRepository)(nil).GetActiveSessions))
 }
 
+// ExtendEndTime mocks base method.
+func (m *MockAuctionSessionRepository) ExtendEndTime(id int64, endTime time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExtendEndTime", id, endTime)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExtendEndTime indicates an expected call of ExtendEndTime.
+func (mr *MockAuctionSessionRepositoryMockRecorder) ExtendEndTime(id, endTime interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExtendEndTime", reflect.TypeOf((*MockAuctionSessionRepository)(nil).ExtendEndTime), id, endTime)
+}
+
 // GetAll mocks base method.
 func (m *MockAuctionSessionRepository) GetAll() ([]*entity.AuctionSession, error) {
 	m.ctrl.T.Helper()
@@ -0,0 +1,109 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/service/auction_session_service.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	dto "milestone3/be/internal/dto"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockAuctionSessionService is a mock of AuctionSessionService interface.
+type MockAuctionSessionService struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuctionSessionServiceMockRecorder
+}
+
+// MockAuctionSessionServiceMockRecorder is the mock recorder for MockAuctionSessionService.
+type MockAuctionSessionServiceMockRecorder struct {
+	mock *MockAuctionSessionService
+}
+
+// NewMockAuctionSessionService creates a new mock instance.
+func NewMockAuctionSessionService(ctrl *gomock.Controller) *MockAuctionSessionService {
+	mock := &MockAuctionSessionService{ctrl: ctrl}
+	mock.recorder = &MockAuctionSessionServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuctionSessionService) EXPECT() *MockAuctionSessionServiceMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockAuctionSessionService) Create(session *dto.AuctionSessionDTO) (dto.AuctionSessionDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", session)
+	ret0, _ := ret[0].(dto.AuctionSessionDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockAuctionSessionServiceMockRecorder) Create(session interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockAuctionSessionService)(nil).Create), session)
+}
+
+// Delete mocks base method.
+func (m *MockAuctionSessionService) Delete(id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockAuctionSessionServiceMockRecorder) Delete(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockAuctionSessionService)(nil).Delete), id)
+}
+
+// GetAll mocks base method.
+func (m *MockAuctionSessionService) GetAll() ([]dto.AuctionSessionDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll")
+	ret0, _ := ret[0].([]dto.AuctionSessionDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockAuctionSessionServiceMockRecorder) GetAll() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockAuctionSessionService)(nil).GetAll))
+}
+
+// GetByID mocks base method.
+func (m *MockAuctionSessionService) GetByID(id int64) (dto.AuctionSessionDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", id)
+	ret0, _ := ret[0].(dto.AuctionSessionDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockAuctionSessionServiceMockRecorder) GetByID(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockAuctionSessionService)(nil).GetByID), id)
+}
+
+// Update mocks base method.
+func (m *MockAuctionSessionService) Update(id int64, session *dto.AuctionSessionDTO) (dto.AuctionSessionDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", id, session)
+	ret0, _ := ret[0].(dto.AuctionSessionDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockAuctionSessionServiceMockRecorder) Update(id, session interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockAuctionSessionService)(nil).Update), id, session)
+}
@@ -0,0 +1,65 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repository/bid_event_redis_repo.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	dto "milestone3/be/internal/dto"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockBidEventRepository is a mock of BidEventRepository interface.
+type MockBidEventRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockBidEventRepositoryMockRecorder
+}
+
+// MockBidEventRepositoryMockRecorder is the mock recorder for MockBidEventRepository.
+type MockBidEventRepositoryMockRecorder struct {
+	mock *MockBidEventRepository
+}
+
+// NewMockBidEventRepository creates a new mock instance.
+func NewMockBidEventRepository(ctrl *gomock.Controller) *MockBidEventRepository {
+	mock := &MockBidEventRepository{ctrl: ctrl}
+	mock.recorder = &MockBidEventRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBidEventRepository) EXPECT() *MockBidEventRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Publish mocks base method.
+func (m *MockBidEventRepository) Publish(event dto.BidEventDTO) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Publish", event)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Publish indicates an expected call of Publish.
+func (mr *MockBidEventRepositoryMockRecorder) Publish(event interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Publish", reflect.TypeOf((*MockBidEventRepository)(nil).Publish), event)
+}
+
+// Subscribe mocks base method.
+func (m *MockBidEventRepository) Subscribe(ctx context.Context, sessionID, itemID int64) (<-chan dto.BidEventDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Subscribe", ctx, sessionID, itemID)
+	ret0, _ := ret[0].(<-chan dto.BidEventDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Subscribe indicates an expected call of Subscribe.
+func (mr *MockBidEventRepositoryMockRecorder) Subscribe(ctx, sessionID, itemID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Subscribe", reflect.TypeOf((*MockBidEventRepository)(nil).Subscribe), ctx, sessionID, itemID)
+}
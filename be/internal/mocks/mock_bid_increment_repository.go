@@ -0,0 +1,79 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repository/bid_increment_repo.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	entity "milestone3/be/internal/entity"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockBidIncrementRepository is a mock of BidIncrementRepository interface.
+type MockBidIncrementRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockBidIncrementRepositoryMockRecorder
+}
+
+// MockBidIncrementRepositoryMockRecorder is the mock recorder for MockBidIncrementRepository.
+type MockBidIncrementRepositoryMockRecorder struct {
+	mock *MockBidIncrementRepository
+}
+
+// NewMockBidIncrementRepository creates a new mock instance.
+func NewMockBidIncrementRepository(ctrl *gomock.Controller) *MockBidIncrementRepository {
+	mock := &MockBidIncrementRepository{ctrl: ctrl}
+	mock.recorder = &MockBidIncrementRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBidIncrementRepository) EXPECT() *MockBidIncrementRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetLadder mocks base method.
+func (m *MockBidIncrementRepository) GetLadder(sessionID, itemID int64) ([]entity.BidIncrementTier, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLadder", sessionID, itemID)
+	ret0, _ := ret[0].([]entity.BidIncrementTier)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLadder indicates an expected call of GetLadder.
+func (mr *MockBidIncrementRepositoryMockRecorder) GetLadder(sessionID, itemID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLadder", reflect.TypeOf((*MockBidIncrementRepository)(nil).GetLadder), sessionID, itemID)
+}
+
+// GetScopeLadder mocks base method.
+func (m *MockBidIncrementRepository) GetScopeLadder(sessionID, itemID *int64) ([]entity.BidIncrementTier, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetScopeLadder", sessionID, itemID)
+	ret0, _ := ret[0].([]entity.BidIncrementTier)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetScopeLadder indicates an expected call of GetScopeLadder.
+func (mr *MockBidIncrementRepositoryMockRecorder) GetScopeLadder(sessionID, itemID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetScopeLadder", reflect.TypeOf((*MockBidIncrementRepository)(nil).GetScopeLadder), sessionID, itemID)
+}
+
+// ReplaceLadder mocks base method.
+func (m *MockBidIncrementRepository) ReplaceLadder(sessionID, itemID *int64, tiers []entity.BidIncrementTier) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplaceLadder", sessionID, itemID, tiers)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReplaceLadder indicates an expected call of ReplaceLadder.
+func (mr *MockBidIncrementRepositoryMockRecorder) ReplaceLadder(sessionID, itemID, tiers interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceLadder", reflect.TypeOf((*MockBidIncrementRepository)(nil).ReplaceLadder), sessionID, itemID, tiers)
+}
@@ -49,6 +49,21 @@ func (mr *MockBidRedisRepositoryMockRecorder) CheckDuplicateBid(userID, itemID,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckDuplicateBid", reflect.TypeOf((*MockBidRedisRepository)(nil).CheckDuplicateBid), userID, itemID, amount, ttl)
 }
 
+// CompareAndSetHighestBid mocks base method.
+func (m *MockBidRedisRepository) CompareAndSetHighestBid(sessionID, itemID int64, amount float64, userID int64, rules repository.BidRules) (repository.BidResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompareAndSetHighestBid", sessionID, itemID, amount, userID, rules)
+	ret0, _ := ret[0].(repository.BidResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CompareAndSetHighestBid indicates an expected call of CompareAndSetHighestBid.
+func (mr *MockBidRedisRepositoryMockRecorder) CompareAndSetHighestBid(sessionID, itemID, amount, userID, rules interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompareAndSetHighestBid", reflect.TypeOf((*MockBidRedisRepository)(nil).CompareAndSetHighestBid), sessionID, itemID, amount, userID, rules)
+}
+
 // DeleteKey mocks base method.
 func (m *MockBidRedisRepository) DeleteKey(key string) error {
 	m.ctrl.T.Helper()
@@ -63,6 +78,34 @@ func (mr *MockBidRedisRepositoryMockRecorder) DeleteKey(key interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteKey", reflect.TypeOf((*MockBidRedisRepository)(nil).DeleteKey), key)
 }
 
+// DeleteUnsavedBids mocks base method.
+func (m *MockBidRedisRepository) DeleteUnsavedBids(sessionID, itemID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUnsavedBids", sessionID, itemID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUnsavedBids indicates an expected call of DeleteUnsavedBids.
+func (mr *MockBidRedisRepositoryMockRecorder) DeleteUnsavedBids(sessionID, itemID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUnsavedBids", reflect.TypeOf((*MockBidRedisRepository)(nil).DeleteUnsavedBids), sessionID, itemID)
+}
+
+// ExtendSessionEndTime mocks base method.
+func (m *MockBidRedisRepository) ExtendSessionEndTime(sessionID int64, endTime time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExtendSessionEndTime", sessionID, endTime)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExtendSessionEndTime indicates an expected call of ExtendSessionEndTime.
+func (mr *MockBidRedisRepositoryMockRecorder) ExtendSessionEndTime(sessionID, endTime interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExtendSessionEndTime", reflect.TypeOf((*MockBidRedisRepository)(nil).ExtendSessionEndTime), sessionID, endTime)
+}
+
 // GetBidByKey mocks base method.
 func (m *MockBidRedisRepository) GetBidByKey(key string) (repository.BidEntry, error) {
 	m.ctrl.T.Helper()
@@ -109,31 +152,76 @@ func (mr *MockBidRedisRepositoryMockRecorder) GetHighestBid(sessionID, itemID in
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHighestBid", reflect.TypeOf((*MockBidRedisRepository)(nil).GetHighestBid), sessionID, itemID)
 }
 
-// ScanKeys mocks base method.
-func (m *MockBidRedisRepository) ScanKeys(pattern string) ([]string, error) {
+// GetProxyBid mocks base method.
+func (m *MockBidRedisRepository) GetProxyBid(sessionID, itemID, userID int64) (float64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ScanKeys", pattern)
-	ret0, _ := ret[0].([]string)
+	ret := m.ctrl.Call(m, "GetProxyBid", sessionID, itemID, userID)
+	ret0, _ := ret[0].(float64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// ScanKeys indicates an expected call of ScanKeys.
-func (mr *MockBidRedisRepositoryMockRecorder) ScanKeys(pattern interface{}) *gomock.Call {
+// GetProxyBid indicates an expected call of GetProxyBid.
+func (mr *MockBidRedisRepositoryMockRecorder) GetProxyBid(sessionID, itemID, userID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ScanKeys", reflect.TypeOf((*MockBidRedisRepository)(nil).ScanKeys), pattern)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProxyBid", reflect.TypeOf((*MockBidRedisRepository)(nil).GetProxyBid), sessionID, itemID, userID)
 }
 
-// SetHighestBid mocks base method.
-func (m *MockBidRedisRepository) SetHighestBid(sessionID, itemID int64, amount float64, userID int64, sessionEndTime time.Time) error {
+// GetUnsavedBids mocks base method.
+func (m *MockBidRedisRepository) GetUnsavedBids(sessionID, itemID int64) ([]repository.BidEntry, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SetHighestBid", sessionID, itemID, amount, userID, sessionEndTime)
+	ret := m.ctrl.Call(m, "GetUnsavedBids", sessionID, itemID)
+	ret0, _ := ret[0].([]repository.BidEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUnsavedBids indicates an expected call of GetUnsavedBids.
+func (mr *MockBidRedisRepositoryMockRecorder) GetUnsavedBids(sessionID, itemID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUnsavedBids", reflect.TypeOf((*MockBidRedisRepository)(nil).GetUnsavedBids), sessionID, itemID)
+}
+
+// QueueUnsavedBids mocks base method.
+func (m *MockBidRedisRepository) QueueUnsavedBids(sessionID, itemID int64, bids []repository.BidEntry) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueueUnsavedBids", sessionID, itemID, bids)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// SetHighestBid indicates an expected call of SetHighestBid.
-func (mr *MockBidRedisRepositoryMockRecorder) SetHighestBid(sessionID, itemID, amount, userID, sessionEndTime interface{}) *gomock.Call {
+// QueueUnsavedBids indicates an expected call of QueueUnsavedBids.
+func (mr *MockBidRedisRepositoryMockRecorder) QueueUnsavedBids(sessionID, itemID, bids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueueUnsavedBids", reflect.TypeOf((*MockBidRedisRepository)(nil).QueueUnsavedBids), sessionID, itemID, bids)
+}
+
+// RegisterProxyBid mocks base method.
+func (m *MockBidRedisRepository) RegisterProxyBid(sessionID, itemID, userID int64, maxAmount float64, rules repository.BidRules) (repository.BidResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegisterProxyBid", sessionID, itemID, userID, maxAmount, rules)
+	ret0, _ := ret[0].(repository.BidResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RegisterProxyBid indicates an expected call of RegisterProxyBid.
+func (mr *MockBidRedisRepositoryMockRecorder) RegisterProxyBid(sessionID, itemID, userID, maxAmount, rules interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetHighestBid", reflect.TypeOf((*MockBidRedisRepository)(nil).SetHighestBid), sessionID, itemID, amount, userID, sessionEndTime)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterProxyBid", reflect.TypeOf((*MockBidRedisRepository)(nil).RegisterProxyBid), sessionID, itemID, userID, maxAmount, rules)
+}
+
+// ScanKeys mocks base method.
+func (m *MockBidRedisRepository) ScanKeys(pattern string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ScanKeys", pattern)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ScanKeys indicates an expected call of ScanKeys.
+func (mr *MockBidRedisRepositoryMockRecorder) ScanKeys(pattern interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ScanKeys", reflect.TypeOf((*MockBidRedisRepository)(nil).ScanKeys), pattern)
 }
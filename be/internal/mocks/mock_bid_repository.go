@@ -34,16 +34,46 @@ func (m *MockBidRepository) EXPECT() *MockBidRepositoryMockRecorder {
 	return m.recorder
 }
 
-// SaveFinalBid mocks base method.
-func (m *MockBidRepository) SaveFinalBid(bid *entity.Bid) error {
+// FinalizeItem mocks base method.
+func (m *MockBidRepository) FinalizeItem(itemID int64, status string, unsavedBids []entity.Bid, winningBid *entity.Bid, event *entity.OutboxEvent) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SaveFinalBid", bid)
+	ret := m.ctrl.Call(m, "FinalizeItem", itemID, status, unsavedBids, winningBid, event)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// SaveFinalBid indicates an expected call of SaveFinalBid.
-func (mr *MockBidRepositoryMockRecorder) SaveFinalBid(bid interface{}) *gomock.Call {
+// FinalizeItem indicates an expected call of FinalizeItem.
+func (mr *MockBidRepositoryMockRecorder) FinalizeItem(itemID, status, unsavedBids, winningBid, event interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveFinalBid", reflect.TypeOf((*MockBidRepository)(nil).SaveFinalBid), bid)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FinalizeItem", reflect.TypeOf((*MockBidRepository)(nil).FinalizeItem), itemID, status, unsavedBids, winningBid, event)
+}
+
+// GetByItemID mocks base method.
+func (m *MockBidRepository) GetByItemID(itemID int64, page, limit int) ([]entity.Bid, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByItemID", itemID, page, limit)
+	ret0, _ := ret[0].([]entity.Bid)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetByItemID indicates an expected call of GetByItemID.
+func (mr *MockBidRepositoryMockRecorder) GetByItemID(itemID, page, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByItemID", reflect.TypeOf((*MockBidRepository)(nil).GetByItemID), itemID, page, limit)
+}
+
+// SaveBid mocks base method.
+func (m *MockBidRepository) SaveBid(bid *entity.Bid) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveBid", bid)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveBid indicates an expected call of SaveBid.
+func (mr *MockBidRepositoryMockRecorder) SaveBid(bid interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveBid", reflect.TypeOf((*MockBidRepository)(nil).SaveBid), bid)
 }
@@ -0,0 +1,198 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/service/bid_service.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	dto "milestone3/be/internal/dto"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockBidService is a mock of BidService interface.
+type MockBidService struct {
+	ctrl     *gomock.Controller
+	recorder *MockBidServiceMockRecorder
+}
+
+// MockBidServiceMockRecorder is the mock recorder for MockBidService.
+type MockBidServiceMockRecorder struct {
+	mock *MockBidService
+}
+
+// NewMockBidService creates a new mock instance.
+func NewMockBidService(ctrl *gomock.Controller) *MockBidService {
+	mock := &MockBidService{ctrl: ctrl}
+	mock.recorder = &MockBidServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBidService) EXPECT() *MockBidServiceMockRecorder {
+	return m.recorder
+}
+
+// CloseExpiredItemsWithoutBids mocks base method.
+func (m *MockBidService) CloseExpiredItemsWithoutBids() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloseExpiredItemsWithoutBids")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CloseExpiredItemsWithoutBids indicates an expected call of CloseExpiredItemsWithoutBids.
+func (mr *MockBidServiceMockRecorder) CloseExpiredItemsWithoutBids() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseExpiredItemsWithoutBids", reflect.TypeOf((*MockBidService)(nil).CloseExpiredItemsWithoutBids))
+}
+
+// DeleteKeyValue mocks base method.
+func (m *MockBidService) DeleteKeyValue() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteKeyValue")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteKeyValue indicates an expected call of DeleteKeyValue.
+func (mr *MockBidServiceMockRecorder) DeleteKeyValue() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteKeyValue", reflect.TypeOf((*MockBidService)(nil).DeleteKeyValue))
+}
+
+// GetBidHistory mocks base method.
+func (m *MockBidService) GetBidHistory(itemID int64, page, limit int) ([]dto.BidHistoryDTO, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBidHistory", itemID, page, limit)
+	ret0, _ := ret[0].([]dto.BidHistoryDTO)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetBidHistory indicates an expected call of GetBidHistory.
+func (mr *MockBidServiceMockRecorder) GetBidHistory(itemID, page, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBidHistory", reflect.TypeOf((*MockBidService)(nil).GetBidHistory), itemID, page, limit)
+}
+
+// GetHighestBid mocks base method.
+func (m *MockBidService) GetHighestBid(sessionID, itemID int64) (dto.HighestBidDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHighestBid", sessionID, itemID)
+	ret0, _ := ret[0].(dto.HighestBidDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHighestBid indicates an expected call of GetHighestBid.
+func (mr *MockBidServiceMockRecorder) GetHighestBid(sessionID, itemID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHighestBid", reflect.TypeOf((*MockBidService)(nil).GetHighestBid), sessionID, itemID)
+}
+
+// GetIncrementLadder mocks base method.
+func (m *MockBidService) GetIncrementLadder(sessionID, itemID *int64) ([]dto.BidIncrementTierDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIncrementLadder", sessionID, itemID)
+	ret0, _ := ret[0].([]dto.BidIncrementTierDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIncrementLadder indicates an expected call of GetIncrementLadder.
+func (mr *MockBidServiceMockRecorder) GetIncrementLadder(sessionID, itemID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIncrementLadder", reflect.TypeOf((*MockBidService)(nil).GetIncrementLadder), sessionID, itemID)
+}
+
+// GetProxyBid mocks base method.
+func (m *MockBidService) GetProxyBid(sessionID, itemID, userID int64) (float64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProxyBid", sessionID, itemID, userID)
+	ret0, _ := ret[0].(float64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProxyBid indicates an expected call of GetProxyBid.
+func (mr *MockBidServiceMockRecorder) GetProxyBid(sessionID, itemID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProxyBid", reflect.TypeOf((*MockBidService)(nil).GetProxyBid), sessionID, itemID, userID)
+}
+
+// PlaceBid mocks base method.
+func (m *MockBidService) PlaceBid(sessionID, itemID, userID int64, amount float64, sessionEndTime time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PlaceBid", sessionID, itemID, userID, amount, sessionEndTime)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PlaceBid indicates an expected call of PlaceBid.
+func (mr *MockBidServiceMockRecorder) PlaceBid(sessionID, itemID, userID, amount, sessionEndTime interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PlaceBid", reflect.TypeOf((*MockBidService)(nil).PlaceBid), sessionID, itemID, userID, amount, sessionEndTime)
+}
+
+// RegisterProxyBid mocks base method.
+func (m *MockBidService) RegisterProxyBid(sessionID, itemID, userID int64, maxAmount float64, sessionEndTime time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegisterProxyBid", sessionID, itemID, userID, maxAmount, sessionEndTime)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RegisterProxyBid indicates an expected call of RegisterProxyBid.
+func (mr *MockBidServiceMockRecorder) RegisterProxyBid(sessionID, itemID, userID, maxAmount, sessionEndTime interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterProxyBid", reflect.TypeOf((*MockBidService)(nil).RegisterProxyBid), sessionID, itemID, userID, maxAmount, sessionEndTime)
+}
+
+// SaveKeyToDB mocks base method.
+func (m *MockBidService) SaveKeyToDB() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveKeyToDB")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveKeyToDB indicates an expected call of SaveKeyToDB.
+func (mr *MockBidServiceMockRecorder) SaveKeyToDB() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveKeyToDB", reflect.TypeOf((*MockBidService)(nil).SaveKeyToDB))
+}
+
+// SetIncrementLadder mocks base method.
+func (m *MockBidService) SetIncrementLadder(sessionID, itemID *int64, tiers []dto.BidIncrementTierDTO) ([]dto.BidIncrementTierDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetIncrementLadder", sessionID, itemID, tiers)
+	ret0, _ := ret[0].([]dto.BidIncrementTierDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetIncrementLadder indicates an expected call of SetIncrementLadder.
+func (mr *MockBidServiceMockRecorder) SetIncrementLadder(sessionID, itemID, tiers interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetIncrementLadder", reflect.TypeOf((*MockBidService)(nil).SetIncrementLadder), sessionID, itemID, tiers)
+}
+
+// SubscribeBidEvents mocks base method.
+func (m *MockBidService) SubscribeBidEvents(ctx context.Context, sessionID, itemID int64) (<-chan dto.BidEventDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubscribeBidEvents", ctx, sessionID, itemID)
+	ret0, _ := ret[0].(<-chan dto.BidEventDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SubscribeBidEvents indicates an expected call of SubscribeBidEvents.
+func (mr *MockBidServiceMockRecorder) SubscribeBidEvents(ctx, sessionID, itemID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscribeBidEvents", reflect.TypeOf((*MockBidService)(nil).SubscribeBidEvents), ctx, sessionID, itemID)
+}
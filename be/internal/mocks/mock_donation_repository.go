@@ -1,11 +1,8 @@
 package mocks
 
 import (
-	"context"
-	"io"
 	"milestone3/be/internal/entity"
 	reflect "reflect"
-	"time"
 
 	gomock "github.com/golang/mock/gomock"
 )
@@ -38,6 +35,20 @@ func (m *MockDonationRepo) EXPECT() *MockDonationRepoMockRecorder {
 	return m.recorder
 }
 
+// AddDonationPhotos mocks base method.
+func (m *MockDonationRepo) AddDonationPhotos(photos []entity.DonationPhoto) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddDonationPhotos", photos)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddDonationPhotos indicates an expected call of AddDonationPhotos.
+func (mr *MockDonationRepoMockRecorder) AddDonationPhotos(photos interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddDonationPhotos", reflect.TypeOf((*MockDonationRepo)(nil).AddDonationPhotos), photos)
+}
+
 // CreateDonation mocks base method.
 func (m *MockDonationRepo) CreateDonation(donation entity.Donation) error {
 	m.ctrl.T.Helper()
@@ -66,6 +77,21 @@ func (mr *MockDonationRepoMockRecorder) DeleteDonation(id interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteDonation", reflect.TypeOf((*MockDonationRepo)(nil).DeleteDonation), id)
 }
 
+// DeleteDonationPhoto mocks base method.
+func (m *MockDonationRepo) DeleteDonationPhoto(donationID, photoID uint) (entity.DonationPhoto, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteDonationPhoto", donationID, photoID)
+	ret0, _ := ret[0].(entity.DonationPhoto)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteDonationPhoto indicates an expected call of DeleteDonationPhoto.
+func (mr *MockDonationRepoMockRecorder) DeleteDonationPhoto(donationID, photoID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteDonationPhoto", reflect.TypeOf((*MockDonationRepo)(nil).DeleteDonationPhoto), donationID, photoID)
+}
+
 // GetAllDonations mocks base method.
 func (m *MockDonationRepo) GetAllDonations(page, limit int) ([]entity.Donation, int64, error) {
 	m.ctrl.T.Helper()
@@ -113,83 +139,60 @@ func (mr *MockDonationRepoMockRecorder) GetDonationsByUserID(userID, page, limit
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDonationsByUserID", reflect.TypeOf((*MockDonationRepo)(nil).GetDonationsByUserID), userID, page, limit)
 }
 
-// PatchDonation mocks base method.
-func (m *MockDonationRepo) PatchDonation(donation entity.Donation) error {
+// GetDonationHistory mocks base method.
+func (m *MockDonationRepo) GetDonationHistory(donationID uint) ([]entity.DonationStatusHistory, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "PatchDonation", donation)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "GetDonationHistory", donationID)
+	ret0, _ := ret[0].([]entity.DonationStatusHistory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// PatchDonation indicates an expected call of PatchDonation.
-func (mr *MockDonationRepoMockRecorder) PatchDonation(donation interface{}) *gomock.Call {
+// GetDonationHistory indicates an expected call of GetDonationHistory.
+func (mr *MockDonationRepoMockRecorder) GetDonationHistory(donationID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PatchDonation", reflect.TypeOf((*MockDonationRepo)(nil).PatchDonation), donation)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDonationHistory", reflect.TypeOf((*MockDonationRepo)(nil).GetDonationHistory), donationID)
 }
 
-// UpdateDonation mocks base method.
-func (m *MockDonationRepo) UpdateDonation(donation entity.Donation) error {
+// IsObjectReferenced mocks base method.
+func (m *MockDonationRepo) IsObjectReferenced(objectName string) (bool, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateDonation", donation)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "IsObjectReferenced", objectName)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// UpdateDonation indicates an expected call of UpdateDonation.
-func (mr *MockDonationRepoMockRecorder) UpdateDonation(donation interface{}) *gomock.Call {
+// IsObjectReferenced indicates an expected call of IsObjectReferenced.
+func (mr *MockDonationRepoMockRecorder) IsObjectReferenced(objectName interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateDonation", reflect.TypeOf((*MockDonationRepo)(nil).UpdateDonation), donation)
-}
-
-// MockGCPStorageRepo is a mock of GCPStorageRepo interface.
-type MockGCPStorageRepo struct {
-	ctrl     *gomock.Controller
-	recorder *MockGCPStorageRepoMockRecorder
-}
-
-// MockGCPStorageRepoMockRecorder is the mock recorder for MockGCPStorageRepo.
-type MockGCPStorageRepoMockRecorder struct {
-	mock *MockGCPStorageRepo
-}
-
-// NewMockGCPStorageRepo creates a new mock instance.
-func NewMockGCPStorageRepo(ctrl *gomock.Controller) *MockGCPStorageRepo {
-	mock := &MockGCPStorageRepo{ctrl: ctrl}
-	mock.recorder = &MockGCPStorageRepoMockRecorder{mock}
-	return mock
-}
-
-// EXPECT returns an object that allows the caller to indicate expected use.
-func (m *MockGCPStorageRepo) EXPECT() *MockGCPStorageRepoMockRecorder {
-	return m.recorder
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsObjectReferenced", reflect.TypeOf((*MockDonationRepo)(nil).IsObjectReferenced), objectName)
 }
 
-// GenerateSignedURL mocks base method.
-func (m *MockGCPStorageRepo) GenerateSignedURL(ctx context.Context, objectName string, expiration time.Duration) (string, error) {
+// TransitionDonation mocks base method.
+func (m *MockDonationRepo) TransitionDonation(history entity.DonationStatusHistory, draft *entity.AuctionItem) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GenerateSignedURL", ctx, objectName, expiration)
-	ret0, _ := ret[0].(string)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret := m.ctrl.Call(m, "TransitionDonation", history, draft)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// GenerateSignedURL indicates an expected call of GenerateSignedURL.
-func (mr *MockGCPStorageRepoMockRecorder) GenerateSignedURL(ctx, objectName, expiration interface{}) *gomock.Call {
+// TransitionDonation indicates an expected call of TransitionDonation.
+func (mr *MockDonationRepoMockRecorder) TransitionDonation(history, draft interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateSignedURL", reflect.TypeOf((*MockGCPStorageRepo)(nil).GenerateSignedURL), ctx, objectName, expiration)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransitionDonation", reflect.TypeOf((*MockDonationRepo)(nil).TransitionDonation), history, draft)
 }
 
-// UploadFile mocks base method.
-func (m *MockGCPStorageRepo) UploadFile(ctx context.Context, file io.Reader, fileName string) (string, error) {
+// UpdateDonation mocks base method.
+func (m *MockDonationRepo) UpdateDonation(donation entity.Donation) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UploadFile", ctx, file, fileName)
-	ret0, _ := ret[0].(string)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret := m.ctrl.Call(m, "UpdateDonation", donation)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// UploadFile indicates an expected call of UploadFile.
-func (mr *MockGCPStorageRepoMockRecorder) UploadFile(ctx, file, fileName interface{}) *gomock.Call {
+// UpdateDonation indicates an expected call of UpdateDonation.
+func (mr *MockDonationRepoMockRecorder) UpdateDonation(donation interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UploadFile", reflect.TypeOf((*MockGCPStorageRepo)(nil).UploadFile), ctx, file, fileName)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateDonation", reflect.TypeOf((*MockDonationRepo)(nil).UpdateDonation), donation)
 }
@@ -0,0 +1,194 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/service/donation_service.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	dto "milestone3/be/internal/dto"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockDonationService is a mock of DonationService interface.
+type MockDonationService struct {
+	ctrl     *gomock.Controller
+	recorder *MockDonationServiceMockRecorder
+}
+
+// MockDonationServiceMockRecorder is the mock recorder for MockDonationService.
+type MockDonationServiceMockRecorder struct {
+	mock *MockDonationService
+}
+
+// NewMockDonationService creates a new mock instance.
+func NewMockDonationService(ctrl *gomock.Controller) *MockDonationService {
+	mock := &MockDonationService{ctrl: ctrl}
+	mock.recorder = &MockDonationServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDonationService) EXPECT() *MockDonationServiceMockRecorder {
+	return m.recorder
+}
+
+// AddDonationPhotos mocks base method.
+func (m *MockDonationService) AddDonationPhotos(id uint, photos []dto.DonationPhotoDTO, userID uint, isAdmin bool) (dto.DonationDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddDonationPhotos", id, photos, userID, isAdmin)
+	ret0, _ := ret[0].(dto.DonationDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddDonationPhotos indicates an expected call of AddDonationPhotos.
+func (mr *MockDonationServiceMockRecorder) AddDonationPhotos(id, photos, userID, isAdmin interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddDonationPhotos", reflect.TypeOf((*MockDonationService)(nil).AddDonationPhotos), id, photos, userID, isAdmin)
+}
+
+// CanManageDonations mocks base method.
+func (m *MockDonationService) CanManageDonations(userID, ownerID uint, isAdmin bool) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CanManageDonations", userID, ownerID, isAdmin)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// CanManageDonations indicates an expected call of CanManageDonations.
+func (mr *MockDonationServiceMockRecorder) CanManageDonations(userID, ownerID, isAdmin interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CanManageDonations", reflect.TypeOf((*MockDonationService)(nil).CanManageDonations), userID, ownerID, isAdmin)
+}
+
+// CheckDonationEditable mocks base method.
+func (m *MockDonationService) CheckDonationEditable(id, userID uint, isAdmin bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckDonationEditable", id, userID, isAdmin)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CheckDonationEditable indicates an expected call of CheckDonationEditable.
+func (mr *MockDonationServiceMockRecorder) CheckDonationEditable(id, userID, isAdmin interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckDonationEditable", reflect.TypeOf((*MockDonationService)(nil).CheckDonationEditable), id, userID, isAdmin)
+}
+
+// CreateDonation mocks base method.
+func (m *MockDonationService) CreateDonation(donationDTO dto.DonationDTO) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateDonation", donationDTO)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateDonation indicates an expected call of CreateDonation.
+func (mr *MockDonationServiceMockRecorder) CreateDonation(donationDTO interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateDonation", reflect.TypeOf((*MockDonationService)(nil).CreateDonation), donationDTO)
+}
+
+// DeleteDonation mocks base method.
+func (m *MockDonationService) DeleteDonation(id, userID uint, isAdmin bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteDonation", id, userID, isAdmin)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteDonation indicates an expected call of DeleteDonation.
+func (mr *MockDonationServiceMockRecorder) DeleteDonation(id, userID, isAdmin interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteDonation", reflect.TypeOf((*MockDonationService)(nil).DeleteDonation), id, userID, isAdmin)
+}
+
+// GetAllDonations mocks base method.
+func (m *MockDonationService) GetAllDonations(userID uint, isAdmin bool, page, limit int) ([]dto.DonationDTO, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllDonations", userID, isAdmin, page, limit)
+	ret0, _ := ret[0].([]dto.DonationDTO)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetAllDonations indicates an expected call of GetAllDonations.
+func (mr *MockDonationServiceMockRecorder) GetAllDonations(userID, isAdmin, page, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllDonations", reflect.TypeOf((*MockDonationService)(nil).GetAllDonations), userID, isAdmin, page, limit)
+}
+
+// GetDonationByID mocks base method.
+func (m *MockDonationService) GetDonationByID(id uint) (dto.DonationDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDonationByID", id)
+	ret0, _ := ret[0].(dto.DonationDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDonationByID indicates an expected call of GetDonationByID.
+func (mr *MockDonationServiceMockRecorder) GetDonationByID(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDonationByID", reflect.TypeOf((*MockDonationService)(nil).GetDonationByID), id)
+}
+
+// GetDonationHistory mocks base method.
+func (m *MockDonationService) GetDonationHistory(id, userID uint, isAdmin bool) ([]dto.DonationStatusHistoryDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDonationHistory", id, userID, isAdmin)
+	ret0, _ := ret[0].([]dto.DonationStatusHistoryDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDonationHistory indicates an expected call of GetDonationHistory.
+func (mr *MockDonationServiceMockRecorder) GetDonationHistory(id, userID, isAdmin interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDonationHistory", reflect.TypeOf((*MockDonationService)(nil).GetDonationHistory), id, userID, isAdmin)
+}
+
+// RemoveDonationPhoto mocks base method.
+func (m *MockDonationService) RemoveDonationPhoto(id, photoID, userID uint, isAdmin bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveDonationPhoto", id, photoID, userID, isAdmin)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveDonationPhoto indicates an expected call of RemoveDonationPhoto.
+func (mr *MockDonationServiceMockRecorder) RemoveDonationPhoto(id, photoID, userID, isAdmin interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveDonationPhoto", reflect.TypeOf((*MockDonationService)(nil).RemoveDonationPhoto), id, photoID, userID, isAdmin)
+}
+
+// TransitionDonation mocks base method.
+func (m *MockDonationService) TransitionDonation(id uint, req dto.DonationApprovalDTO, userID uint, role string, permissions []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TransitionDonation", id, req, userID, role, permissions)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// TransitionDonation indicates an expected call of TransitionDonation.
+func (mr *MockDonationServiceMockRecorder) TransitionDonation(id, req, userID, role, permissions interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransitionDonation", reflect.TypeOf((*MockDonationService)(nil).TransitionDonation), id, req, userID, role, permissions)
+}
+
+// UpdateDonation mocks base method.
+func (m *MockDonationService) UpdateDonation(donationDTO dto.DonationDTO, userID uint, isAdmin bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateDonation", donationDTO, userID, isAdmin)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateDonation indicates an expected call of UpdateDonation.
+func (mr *MockDonationServiceMockRecorder) UpdateDonation(donationDTO, userID, isAdmin interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateDonation", reflect.TypeOf((*MockDonationService)(nil).UpdateDonation), donationDTO, userID, isAdmin)
+}
@@ -0,0 +1,95 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repository/payment_deadline_repo.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	entity "milestone3/be/internal/entity"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockPaymentDeadlineRepository is a mock of PaymentDeadlineRepository interface.
+type MockPaymentDeadlineRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockPaymentDeadlineRepositoryMockRecorder
+}
+
+// MockPaymentDeadlineRepositoryMockRecorder is the mock recorder for MockPaymentDeadlineRepository.
+type MockPaymentDeadlineRepositoryMockRecorder struct {
+	mock *MockPaymentDeadlineRepository
+}
+
+// NewMockPaymentDeadlineRepository creates a new mock instance.
+func NewMockPaymentDeadlineRepository(ctrl *gomock.Controller) *MockPaymentDeadlineRepository {
+	mock := &MockPaymentDeadlineRepository{ctrl: ctrl}
+	mock.recorder = &MockPaymentDeadlineRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPaymentDeadlineRepository) EXPECT() *MockPaymentDeadlineRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Default mocks base method.
+func (m *MockPaymentDeadlineRepository) Default(paymentID int64, relistItemID *int64, event *entity.OutboxEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Default", paymentID, relistItemID, event)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Default indicates an expected call of Default.
+func (mr *MockPaymentDeadlineRepositoryMockRecorder) Default(paymentID, relistItemID, event interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Default", reflect.TypeOf((*MockPaymentDeadlineRepository)(nil).Default), paymentID, relistItemID, event)
+}
+
+// GetDefaultedUserIDs mocks base method.
+func (m *MockPaymentDeadlineRepository) GetDefaultedUserIDs(itemID int64) ([]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDefaultedUserIDs", itemID)
+	ret0, _ := ret[0].([]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDefaultedUserIDs indicates an expected call of GetDefaultedUserIDs.
+func (mr *MockPaymentDeadlineRepositoryMockRecorder) GetDefaultedUserIDs(itemID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDefaultedUserIDs", reflect.TypeOf((*MockPaymentDeadlineRepository)(nil).GetDefaultedUserIDs), itemID)
+}
+
+// GetNextBidder mocks base method.
+func (m *MockPaymentDeadlineRepository) GetNextBidder(itemID int64, excludeUserIDs []int64) (*entity.Bid, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNextBidder", itemID, excludeUserIDs)
+	ret0, _ := ret[0].(*entity.Bid)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNextBidder indicates an expected call of GetNextBidder.
+func (mr *MockPaymentDeadlineRepositoryMockRecorder) GetNextBidder(itemID, excludeUserIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNextBidder", reflect.TypeOf((*MockPaymentDeadlineRepository)(nil).GetNextBidder), itemID, excludeUserIDs)
+}
+
+// GetOverdue mocks base method.
+func (m *MockPaymentDeadlineRepository) GetOverdue(now time.Time, limit int) ([]entity.Payment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOverdue", now, limit)
+	ret0, _ := ret[0].([]entity.Payment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOverdue indicates an expected call of GetOverdue.
+func (mr *MockPaymentDeadlineRepositoryMockRecorder) GetOverdue(now, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOverdue", reflect.TypeOf((*MockPaymentDeadlineRepository)(nil).GetOverdue), now, limit)
+}
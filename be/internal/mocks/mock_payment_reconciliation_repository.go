@@ -0,0 +1,95 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repository/payment_reconciliation_repo.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	entity "milestone3/be/internal/entity"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockPaymentReconciliationRepository is a mock of PaymentReconciliationRepository interface.
+type MockPaymentReconciliationRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockPaymentReconciliationRepositoryMockRecorder
+}
+
+// MockPaymentReconciliationRepositoryMockRecorder is the mock recorder for MockPaymentReconciliationRepository.
+type MockPaymentReconciliationRepositoryMockRecorder struct {
+	mock *MockPaymentReconciliationRepository
+}
+
+// NewMockPaymentReconciliationRepository creates a new mock instance.
+func NewMockPaymentReconciliationRepository(ctrl *gomock.Controller) *MockPaymentReconciliationRepository {
+	mock := &MockPaymentReconciliationRepository{ctrl: ctrl}
+	mock.recorder = &MockPaymentReconciliationRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPaymentReconciliationRepository) EXPECT() *MockPaymentReconciliationRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ApplyStatus mocks base method.
+func (m *MockPaymentReconciliationRepository) ApplyStatus(orderID, status string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApplyStatus", orderID, status)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ApplyStatus indicates an expected call of ApplyStatus.
+func (mr *MockPaymentReconciliationRepositoryMockRecorder) ApplyStatus(orderID, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApplyStatus", reflect.TypeOf((*MockPaymentReconciliationRepository)(nil).ApplyStatus), orderID, status)
+}
+
+// GetAttemptsToReconcile mocks base method.
+func (m *MockPaymentReconciliationRepository) GetAttemptsToReconcile(afterID int, createdBefore, failedSince time.Time, limit int) ([]entity.Payment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAttemptsToReconcile", afterID, createdBefore, failedSince, limit)
+	ret0, _ := ret[0].([]entity.Payment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAttemptsToReconcile indicates an expected call of GetAttemptsToReconcile.
+func (mr *MockPaymentReconciliationRepositoryMockRecorder) GetAttemptsToReconcile(afterID, createdBefore, failedSince, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAttemptsToReconcile", reflect.TypeOf((*MockPaymentReconciliationRepository)(nil).GetAttemptsToReconcile), afterID, createdBefore, failedSince, limit)
+}
+
+// GetFindings mocks base method.
+func (m *MockPaymentReconciliationRepository) GetFindings(from, to time.Time) ([]entity.PaymentReconciliation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFindings", from, to)
+	ret0, _ := ret[0].([]entity.PaymentReconciliation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFindings indicates an expected call of GetFindings.
+func (mr *MockPaymentReconciliationRepositoryMockRecorder) GetFindings(from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFindings", reflect.TypeOf((*MockPaymentReconciliationRepository)(nil).GetFindings), from, to)
+}
+
+// Record mocks base method.
+func (m *MockPaymentReconciliationRepository) Record(finding *entity.PaymentReconciliation) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Record", finding)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Record indicates an expected call of Record.
+func (mr *MockPaymentReconciliationRepositoryMockRecorder) Record(finding interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Record", reflect.TypeOf((*MockPaymentReconciliationRepository)(nil).Record), finding)
+}
@@ -35,48 +35,90 @@ func (m *MockPaymentRepository) EXPECT() *MockPaymentRepositoryMockRecorder {
 	return m.recorder
 }
 
-// CheckPaymentStatusMidtrans mocks base method.
-func (m *MockPaymentRepository) CheckPaymentStatusMidtrans(orderId string) (dto.CheckPaymentStatusResponse, error) {
+// ApplyStatus mocks base method.
+func (m *MockPaymentRepository) ApplyStatus(orderId, status string) (bool, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CheckPaymentStatusMidtrans", orderId)
-	ret0, _ := ret[0].(dto.CheckPaymentStatusResponse)
+	ret := m.ctrl.Call(m, "ApplyStatus", orderId, status)
+	ret0, _ := ret[0].(bool)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CheckPaymentStatusMidtrans indicates an expected call of CheckPaymentStatusMidtrans.
-func (mr *MockPaymentRepositoryMockRecorder) CheckPaymentStatusMidtrans(orderId interface{}) *gomock.Call {
+// ApplyStatus indicates an expected call of ApplyStatus.
+func (mr *MockPaymentRepositoryMockRecorder) ApplyStatus(orderId, status interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckPaymentStatusMidtrans", reflect.TypeOf((*MockPaymentRepository)(nil).CheckPaymentStatusMidtrans), orderId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApplyStatus", reflect.TypeOf((*MockPaymentRepository)(nil).ApplyStatus), orderId, status)
 }
 
-// Create mocks base method.
-func (m *MockPaymentRepository) Create(payment *entity.Payment, orderId string) error {
+// BeginRefund mocks base method.
+func (m *MockPaymentRepository) BeginRefund(refund *entity.PaymentRefund) (entity.Payment, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", payment, orderId)
+	ret := m.ctrl.Call(m, "BeginRefund", refund)
+	ret0, _ := ret[0].(entity.Payment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BeginRefund indicates an expected call of BeginRefund.
+func (mr *MockPaymentRepositoryMockRecorder) BeginRefund(refund interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BeginRefund", reflect.TypeOf((*MockPaymentRepository)(nil).BeginRefund), refund)
+}
+
+// CompleteRefund mocks base method.
+func (m *MockPaymentRepository) CompleteRefund(refund *entity.PaymentRefund, auctionItemId int, gatewayStatus string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteRefund", refund, auctionItemId, gatewayStatus)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// Create indicates an expected call of Create.
-func (mr *MockPaymentRepositoryMockRecorder) Create(payment, orderId interface{}) *gomock.Call {
+// CompleteRefund indicates an expected call of CompleteRefund.
+func (mr *MockPaymentRepositoryMockRecorder) CompleteRefund(refund, auctionItemId, gatewayStatus interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockPaymentRepository)(nil).Create), payment, orderId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteRefund", reflect.TypeOf((*MockPaymentRepository)(nil).CompleteRefund), refund, auctionItemId, gatewayStatus)
 }
 
-// CreateMidtrans mocks base method.
-func (m *MockPaymentRepository) CreateMidtrans(payment entity.Payment, orderId string) (dto.PaymentResponse, error) {
+// CreateAttempt mocks base method.
+func (m *MockPaymentRepository) CreateAttempt(payment *entity.Payment, winnerPaymentId int) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateMidtrans", payment, orderId)
-	ret0, _ := ret[0].(dto.PaymentResponse)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret := m.ctrl.Call(m, "CreateAttempt", payment, winnerPaymentId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateAttempt indicates an expected call of CreateAttempt.
+func (mr *MockPaymentRepositoryMockRecorder) CreateAttempt(payment, winnerPaymentId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAttempt", reflect.TypeOf((*MockPaymentRepository)(nil).CreateAttempt), payment, winnerPaymentId)
+}
+
+// CreatePending mocks base method.
+func (m *MockPaymentRepository) CreatePending(payment *entity.Payment) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePending", payment)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreatePending indicates an expected call of CreatePending.
+func (mr *MockPaymentRepositoryMockRecorder) CreatePending(payment interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePending", reflect.TypeOf((*MockPaymentRepository)(nil).CreatePending), payment)
+}
+
+// FailRefund mocks base method.
+func (m *MockPaymentRepository) FailRefund(refund entity.PaymentRefund, reason string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FailRefund", refund, reason)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// CreateMidtrans indicates an expected call of CreateMidtrans.
-func (mr *MockPaymentRepositoryMockRecorder) CreateMidtrans(payment, orderId interface{}) *gomock.Call {
+// FailRefund indicates an expected call of FailRefund.
+func (mr *MockPaymentRepositoryMockRecorder) FailRefund(refund, reason interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateMidtrans", reflect.TypeOf((*MockPaymentRepository)(nil).CreateMidtrans), payment, orderId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FailRefund", reflect.TypeOf((*MockPaymentRepository)(nil).FailRefund), refund, reason)
 }
 
 // GetAll mocks base method.
@@ -94,6 +136,21 @@ func (mr *MockPaymentRepositoryMockRecorder) GetAll() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockPaymentRepository)(nil).GetAll))
 }
 
+// GetAuctionItem mocks base method.
+func (m *MockPaymentRepository) GetAuctionItem(auctionItemId int) (entity.AuctionItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAuctionItem", auctionItemId)
+	ret0, _ := ret[0].(entity.AuctionItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAuctionItem indicates an expected call of GetAuctionItem.
+func (mr *MockPaymentRepositoryMockRecorder) GetAuctionItem(auctionItemId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuctionItem", reflect.TypeOf((*MockPaymentRepository)(nil).GetAuctionItem), auctionItemId)
+}
+
 // GetById mocks base method.
 func (m *MockPaymentRepository) GetById(id int) (entity.Payment, error) {
 	m.ctrl.T.Helper()
@@ -107,4 +164,146 @@ func (m *MockPaymentRepository) GetById(id int) (entity.Payment, error) {
 func (mr *MockPaymentRepositoryMockRecorder) GetById(id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetById", reflect.TypeOf((*MockPaymentRepository)(nil).GetById), id)
-}
\ No newline at end of file
+}
+
+// GetByOrderId mocks base method.
+func (m *MockPaymentRepository) GetByOrderId(orderId string) (entity.Payment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByOrderId", orderId)
+	ret0, _ := ret[0].(entity.Payment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByOrderId indicates an expected call of GetByOrderId.
+func (mr *MockPaymentRepositoryMockRecorder) GetByOrderId(orderId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByOrderId", reflect.TypeOf((*MockPaymentRepository)(nil).GetByOrderId), orderId)
+}
+
+// GetOpenWinnerPayment mocks base method.
+func (m *MockPaymentRepository) GetOpenWinnerPayment(auctionItemId int) (entity.Payment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOpenWinnerPayment", auctionItemId)
+	ret0, _ := ret[0].(entity.Payment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOpenWinnerPayment indicates an expected call of GetOpenWinnerPayment.
+func (mr *MockPaymentRepositoryMockRecorder) GetOpenWinnerPayment(auctionItemId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOpenWinnerPayment", reflect.TypeOf((*MockPaymentRepository)(nil).GetOpenWinnerPayment), auctionItemId)
+}
+
+// GetRefunds mocks base method.
+func (m *MockPaymentRepository) GetRefunds(paymentId int) ([]entity.PaymentRefund, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRefunds", paymentId)
+	ret0, _ := ret[0].([]entity.PaymentRefund)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRefunds indicates an expected call of GetRefunds.
+func (mr *MockPaymentRepositoryMockRecorder) GetRefunds(paymentId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRefunds", reflect.TypeOf((*MockPaymentRepository)(nil).GetRefunds), paymentId)
+}
+
+// RecordOrphan mocks base method.
+func (m *MockPaymentRepository) RecordOrphan(finding *entity.PaymentReconciliation) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordOrphan", finding)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordOrphan indicates an expected call of RecordOrphan.
+func (mr *MockPaymentRepositoryMockRecorder) RecordOrphan(finding interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordOrphan", reflect.TypeOf((*MockPaymentRepository)(nil).RecordOrphan), finding)
+}
+
+// MockPaymentGateway is a mock of PaymentGateway interface.
+type MockPaymentGateway struct {
+	ctrl     *gomock.Controller
+	recorder *MockPaymentGatewayMockRecorder
+}
+
+// MockPaymentGatewayMockRecorder is the mock recorder for MockPaymentGateway.
+type MockPaymentGatewayMockRecorder struct {
+	mock *MockPaymentGateway
+}
+
+// NewMockPaymentGateway creates a new mock instance.
+func NewMockPaymentGateway(ctrl *gomock.Controller) *MockPaymentGateway {
+	mock := &MockPaymentGateway{ctrl: ctrl}
+	mock.recorder = &MockPaymentGatewayMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPaymentGateway) EXPECT() *MockPaymentGatewayMockRecorder {
+	return m.recorder
+}
+
+// Charge mocks base method.
+func (m *MockPaymentGateway) Charge(req dto.ChargeRequest) (dto.PaymentResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Charge", req)
+	ret0, _ := ret[0].(dto.PaymentResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Charge indicates an expected call of Charge.
+func (mr *MockPaymentGatewayMockRecorder) Charge(req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Charge", reflect.TypeOf((*MockPaymentGateway)(nil).Charge), req)
+}
+
+// ParseNotification mocks base method.
+func (m *MockPaymentGateway) ParseNotification(body []byte) (dto.GatewayStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ParseNotification", body)
+	ret0, _ := ret[0].(dto.GatewayStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ParseNotification indicates an expected call of ParseNotification.
+func (mr *MockPaymentGatewayMockRecorder) ParseNotification(body interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ParseNotification", reflect.TypeOf((*MockPaymentGateway)(nil).ParseNotification), body)
+}
+
+// Refund mocks base method.
+func (m *MockPaymentGateway) Refund(req dto.RefundRequest) (dto.RefundResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Refund", req)
+	ret0, _ := ret[0].(dto.RefundResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Refund indicates an expected call of Refund.
+func (mr *MockPaymentGatewayMockRecorder) Refund(req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Refund", reflect.TypeOf((*MockPaymentGateway)(nil).Refund), req)
+}
+
+// Status mocks base method.
+func (m *MockPaymentGateway) Status(orderId string) (dto.GatewayStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Status", orderId)
+	ret0, _ := ret[0].(dto.GatewayStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Status indicates an expected call of Status.
+func (mr *MockPaymentGatewayMockRecorder) Status(orderId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Status", reflect.TypeOf((*MockPaymentGateway)(nil).Status), orderId)
+}
@@ -34,19 +34,19 @@ func (m *MockPaymentService) EXPECT() *MockPaymentServiceMockRecorder {
 	return m.recorder
 }
 
-// CheckPaymentStatusMidtrans mocks base method.
-func (m *MockPaymentService) CheckPaymentStatusMidtrans(orderId string) (dto.CheckPaymentStatusResponse, error) {
+// CheckPaymentStatus mocks base method.
+func (m *MockPaymentService) CheckPaymentStatus(orderId string) (dto.CheckPaymentStatusResponse, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CheckPaymentStatusMidtrans", orderId)
+	ret := m.ctrl.Call(m, "CheckPaymentStatus", orderId)
 	ret0, _ := ret[0].(dto.CheckPaymentStatusResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CheckPaymentStatusMidtrans indicates an expected call of CheckPaymentStatusMidtrans.
-func (mr *MockPaymentServiceMockRecorder) CheckPaymentStatusMidtrans(orderId interface{}) *gomock.Call {
+// CheckPaymentStatus indicates an expected call of CheckPaymentStatus.
+func (mr *MockPaymentServiceMockRecorder) CheckPaymentStatus(orderId interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckPaymentStatusMidtrans", reflect.TypeOf((*MockPaymentService)(nil).CheckPaymentStatusMidtrans), orderId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckPaymentStatus", reflect.TypeOf((*MockPaymentService)(nil).CheckPaymentStatus), orderId)
 }
 
 // CreatePayment mocks base method.
@@ -93,3 +93,47 @@ func (mr *MockPaymentServiceMockRecorder) GetPaymentById(id interface{}) *gomock
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPaymentById", reflect.TypeOf((*MockPaymentService)(nil).GetPaymentById), id)
 }
+
+// GetRefunds mocks base method.
+func (m *MockPaymentService) GetRefunds(paymentId int) ([]dto.PaymentRefundResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRefunds", paymentId)
+	ret0, _ := ret[0].([]dto.PaymentRefundResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRefunds indicates an expected call of GetRefunds.
+func (mr *MockPaymentServiceMockRecorder) GetRefunds(paymentId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRefunds", reflect.TypeOf((*MockPaymentService)(nil).GetRefunds), paymentId)
+}
+
+// HandleNotification mocks base method.
+func (m *MockPaymentService) HandleNotification(body []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HandleNotification", body)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// HandleNotification indicates an expected call of HandleNotification.
+func (mr *MockPaymentServiceMockRecorder) HandleNotification(body interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleNotification", reflect.TypeOf((*MockPaymentService)(nil).HandleNotification), body)
+}
+
+// RefundPayment mocks base method.
+func (m *MockPaymentService) RefundPayment(paymentId, actorId int, req dto.PaymentRefundRequest) (dto.PaymentRefundResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefundPayment", paymentId, actorId, req)
+	ret0, _ := ret[0].(dto.PaymentRefundResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RefundPayment indicates an expected call of RefundPayment.
+func (mr *MockPaymentServiceMockRecorder) RefundPayment(paymentId, actorId, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefundPayment", reflect.TypeOf((*MockPaymentService)(nil).RefundPayment), paymentId, actorId, req)
+}
@@ -0,0 +1,102 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/service/role_service.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	entity "milestone3/be/internal/entity"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockRoleRepository is a mock of RoleRepository interface.
+type MockRoleRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRoleRepositoryMockRecorder
+}
+
+// MockRoleRepositoryMockRecorder is the mock recorder for MockRoleRepository.
+type MockRoleRepositoryMockRecorder struct {
+	mock *MockRoleRepository
+}
+
+// NewMockRoleRepository creates a new mock instance.
+func NewMockRoleRepository(ctrl *gomock.Controller) *MockRoleRepository {
+	mock := &MockRoleRepository{ctrl: ctrl}
+	mock.recorder = &MockRoleRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRoleRepository) EXPECT() *MockRoleRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AssignRole mocks base method.
+func (m *MockRoleRepository) AssignRole(userID int, role string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AssignRole", userID, role)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AssignRole indicates an expected call of AssignRole.
+func (mr *MockRoleRepositoryMockRecorder) AssignRole(userID, role interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssignRole", reflect.TypeOf((*MockRoleRepository)(nil).AssignRole), userID, role)
+}
+
+// ListRoles mocks base method.
+func (m *MockRoleRepository) ListRoles() ([]entity.Role, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRoles")
+	ret0, _ := ret[0].([]entity.Role)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRoles indicates an expected call of ListRoles.
+func (mr *MockRoleRepositoryMockRecorder) ListRoles() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRoles", reflect.TypeOf((*MockRoleRepository)(nil).ListRoles))
+}
+
+// MockLiveAccessTokenRepository is a mock of LiveAccessTokenRepository interface.
+type MockLiveAccessTokenRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockLiveAccessTokenRepositoryMockRecorder
+}
+
+// MockLiveAccessTokenRepositoryMockRecorder is the mock recorder for MockLiveAccessTokenRepository.
+type MockLiveAccessTokenRepositoryMockRecorder struct {
+	mock *MockLiveAccessTokenRepository
+}
+
+// NewMockLiveAccessTokenRepository creates a new mock instance.
+func NewMockLiveAccessTokenRepository(ctrl *gomock.Controller) *MockLiveAccessTokenRepository {
+	mock := &MockLiveAccessTokenRepository{ctrl: ctrl}
+	mock.recorder = &MockLiveAccessTokenRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLiveAccessTokenRepository) EXPECT() *MockLiveAccessTokenRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ListLiveAccessTokens mocks base method.
+func (m *MockLiveAccessTokenRepository) ListLiveAccessTokens(userID int) ([]entity.RefreshToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListLiveAccessTokens", userID)
+	ret0, _ := ret[0].([]entity.RefreshToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListLiveAccessTokens indicates an expected call of ListLiveAccessTokens.
+func (mr *MockLiveAccessTokenRepositoryMockRecorder) ListLiveAccessTokens(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLiveAccessTokens", reflect.TypeOf((*MockLiveAccessTokenRepository)(nil).ListLiveAccessTokens), userID)
+}
@@ -8,7 +8,7 @@ import (
 
 type AuctionItemRepository interface {
 	Create(item *entity.AuctionItem) error
-	GetAll() ([]entity.AuctionItem, error)
+	GetAll(includeDrafts bool) ([]entity.AuctionItem, error)
 	GetByID(id int64) (*entity.AuctionItem, error)
 	ReadBySession(sessionID int64) ([]entity.AuctionItem, error)
 	GetScheduledItems() ([]entity.AuctionItem, error)
@@ -28,21 +28,26 @@ func (r *auctionItemRepository) Create(item *entity.AuctionItem) error {
 	return r.db.Create(item).Error
 }
 
-func (r *auctionItemRepository) GetAll() ([]entity.AuctionItem, error) {
+// GetAll lists the auction items, drafts not reviewed yet only when includeDrafts is set
+func (r *auctionItemRepository) GetAll(includeDrafts bool) ([]entity.AuctionItem, error) {
 	var items []entity.AuctionItem
-	err := r.db.Preload("Session").Find(&items).Error
+	query := r.db.Preload("Session").Preload("Photos")
+	if !includeDrafts {
+		query = query.Where("status <> ?", "draft")
+	}
+	err := query.Find(&items).Error
 	return items, err
 }
 
 func (r *auctionItemRepository) GetByID(id int64) (*entity.AuctionItem, error) {
 	var item entity.AuctionItem
-	err := r.db.Preload("Session").First(&item, id).Error
+	err := r.db.Preload("Session").Preload("Photos").First(&item, id).Error
 	return &item, err
 }
 
 func (r *auctionItemRepository) ReadBySession(sessionID int64) ([]entity.AuctionItem, error) {
 	var items []entity.AuctionItem
-	err := r.db.Preload("Session").Where("session_id = ?", sessionID).Find(&items).Error
+	err := r.db.Preload("Session").Preload("Photos").Where("session_id = ? AND status <> ?", sessionID, "draft").Find(&items).Error
 	return items, err
 }
 
@@ -13,6 +13,7 @@ type AuctionSessionRepository interface {
 	GetAll() ([]*entity.AuctionSession, error)
 	GetActiveSessions() ([]*entity.AuctionSession, error)
 	Update(session *entity.AuctionSession) error
+	ExtendEndTime(id int64, endTime time.Time) error
 	Delete(id int64) error
 }
 
@@ -51,6 +52,13 @@ func (r *auctionSessionRepository) Update(session *entity.AuctionSession) error
 	return r.db.Save(session).Error
 }
 
+// ExtendEndTime only ever moves the end time forward, so concurrent extensions cannot shorten a session
+func (r *auctionSessionRepository) ExtendEndTime(id int64, endTime time.Time) error {
+	return r.db.Model(&entity.AuctionSession{}).
+		Where("id = ? AND end_time < ?", id, endTime).
+		Update("end_time", endTime).Error
+}
+
 func (r *auctionSessionRepository) Delete(id int64) error {
 	return r.db.Delete(&entity.AuctionSession{}, id).Error
 }
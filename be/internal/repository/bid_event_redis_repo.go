@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"milestone3/be/internal/dto"
+
+	"github.com/redis/go-redis/v9"
+)
+
+type BidEventRepository interface {
+	Publish(event dto.BidEventDTO) error
+	Subscribe(ctx context.Context, sessionID, itemID int64) (<-chan dto.BidEventDTO, error)
+}
+
+type bidEventRepository struct {
+	client *redis.Client
+	ctx    context.Context
+}
+
+func NewBidEventRepository(client *redis.Client, ctx context.Context) BidEventRepository {
+	return &bidEventRepository{client: client, ctx: ctx}
+}
+
+func bidEventChannel(sessionID, itemID int64) string {
+	return fmt.Sprintf("auction:%d:item:%d:events", sessionID, itemID)
+}
+
+// Publish fans the event out to every instance subscribed to the item channel
+func (r *bidEventRepository) Publish(event dto.BidEventDTO) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	return r.client.Publish(r.ctx, bidEventChannel(event.SessionID, event.ItemID), payload).Err()
+}
+
+// Subscribe listens to the item channel until ctx is cancelled, then closes the returned channel
+func (r *bidEventRepository) Subscribe(ctx context.Context, sessionID, itemID int64) (<-chan dto.BidEventDTO, error) {
+	pubsub := r.client.Subscribe(ctx, bidEventChannel(sessionID, itemID))
+
+	// wait for subscription confirmation so no event is lost after returning
+	if _, err := pubsub.Receive(ctx); err != nil {
+		_ = pubsub.Close()
+		return nil, err
+	}
+
+	events := make(chan dto.BidEventDTO)
+	go func() {
+		defer close(events)
+		defer pubsub.Close()
+
+		msgs := pubsub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg, ok := <-msgs:
+				if !ok {
+					return
+				}
+				var event dto.BidEventDTO
+				if err := json.Unmarshal([]byte(msg.Payload), &event); err != nil {
+					continue
+				}
+				select {
+				case events <- event:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return events, nil
+}
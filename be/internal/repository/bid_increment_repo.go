@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"milestone3/be/internal/entity"
+
+	"gorm.io/gorm"
+)
+
+type BidIncrementRepository interface {
+	GetLadder(sessionID, itemID int64) ([]entity.BidIncrementTier, error)
+	GetScopeLadder(sessionID, itemID *int64) ([]entity.BidIncrementTier, error)
+	ReplaceLadder(sessionID, itemID *int64, tiers []entity.BidIncrementTier) error
+}
+
+type bidIncrementRepository struct {
+	db *gorm.DB
+}
+
+func NewBidIncrementRepository(db *gorm.DB) BidIncrementRepository {
+	return &bidIncrementRepository{db: db}
+}
+
+// GetLadder returns the most specific ladder for the item: its own, else its session's, else the global one
+func (r *bidIncrementRepository) GetLadder(sessionID, itemID int64) ([]entity.BidIncrementTier, error) {
+	var tiers []entity.BidIncrementTier
+	err := r.db.
+		Where("auction_item_id = ?", itemID).
+		Or("session_id = ? AND auction_item_id IS NULL", sessionID).
+		Or("session_id IS NULL AND auction_item_id IS NULL").
+		Order("min_price ASC").
+		Find(&tiers).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var itemTiers, sessionTiers, globalTiers []entity.BidIncrementTier
+	for _, tier := range tiers {
+		switch {
+		case tier.ItemID != nil:
+			itemTiers = append(itemTiers, tier)
+		case tier.SessionID != nil:
+			sessionTiers = append(sessionTiers, tier)
+		default:
+			globalTiers = append(globalTiers, tier)
+		}
+	}
+
+	if len(itemTiers) > 0 {
+		return itemTiers, nil
+	}
+	if len(sessionTiers) > 0 {
+		return sessionTiers, nil
+	}
+	return globalTiers, nil
+}
+
+func scopeQuery(db *gorm.DB, sessionID, itemID *int64) *gorm.DB {
+	if itemID != nil {
+		return db.Where("auction_item_id = ?", *itemID)
+	}
+	if sessionID != nil {
+		return db.Where("session_id = ? AND auction_item_id IS NULL", *sessionID)
+	}
+	return db.Where("session_id IS NULL AND auction_item_id IS NULL")
+}
+
+// GetScopeLadder returns only the tiers defined on the scope, without falling back
+func (r *bidIncrementRepository) GetScopeLadder(sessionID, itemID *int64) ([]entity.BidIncrementTier, error) {
+	var tiers []entity.BidIncrementTier
+	err := scopeQuery(r.db, sessionID, itemID).Order("min_price ASC").Find(&tiers).Error
+	return tiers, err
+}
+
+// ReplaceLadder swaps the tiers of the scope in one transaction, an empty ladder removes the override
+func (r *bidIncrementRepository) ReplaceLadder(sessionID, itemID *int64, tiers []entity.BidIncrementTier) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := scopeQuery(tx, sessionID, itemID).Delete(&entity.BidIncrementTier{}).Error; err != nil {
+			return err
+		}
+		if len(tiers) == 0 {
+			return nil
+		}
+		for i := range tiers {
+			tiers[i].ID = 0
+			tiers[i].SessionID = sessionID
+			tiers[i].ItemID = itemID
+		}
+		return tx.Create(&tiers).Error
+	})
+}
@@ -5,21 +5,32 @@ import (
 	"errors"
 	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
+	"milestone3/be/internal/entity"
+
 	"github.com/redis/go-redis/v9"
 )
 
 type BidRedisRepository interface {
-	SetHighestBid(sessionID, itemID int64, amount float64, userID int64, sessionEndTime time.Time) error
+	CompareAndSetHighestBid(sessionID, itemID int64, amount float64, userID int64, rules BidRules) (BidResult, error)
+	RegisterProxyBid(sessionID, itemID int64, userID int64, maxAmount float64, rules BidRules) (BidResult, error)
+	GetProxyBid(sessionID, itemID, userID int64) (float64, error)
 	GetHighestBid(sessionID, itemID int64) (float64, int64, error)
 	GetEndTime(key string) (time.Time, error)
+	ExtendSessionEndTime(sessionID int64, endTime time.Time) error
 
 	ScanKeys(pattern string) ([]string, error)
 	GetBidByKey(key string) (BidEntry, error)
 	DeleteKey(key string) error
 
 	CheckDuplicateBid(userID, itemID int64, amount float64, ttl time.Duration) error
+
+	// unsaved bids were accepted but not written to the bid ledger, they are flushed when the item closes
+	QueueUnsavedBids(sessionID, itemID int64, bids []BidEntry) error
+	GetUnsavedBids(sessionID, itemID int64) ([]BidEntry, error)
+	DeleteUnsavedBids(sessionID, itemID int64) error
 }
 
 type bidRedisRepository struct {
@@ -31,41 +42,417 @@ type BidEntry struct {
 	UserID int64
 	ItemID int64
 	Amount float64
+	// Closed is set on the active hash once buy it now was met
+	Closed bool
+}
+
+const (
+	BidAccepted             = "accepted"
+	BidRejectedTooLow       = "too_low"
+	BidRejectedAlreadyOwner = "already_highest"
+	BidRejectedClosed       = "closed"
+)
+
+// BidRules are the item rules a bid script validates against
+type BidRules struct {
+	MinAmount float64
+	// MinIncrement is the step below the first tier of Ladder, or everywhere without a ladder
+	MinIncrement float64
+	// Ladder is sorted by MinPrice, the step depends on the current highest bid
+	Ladder []entity.BidIncrementTier
+	// EndTime is the scheduled end, an end time already extended in redis is kept if later
+	EndTime time.Time
+	// a bid within SoftCloseWindow of the end pushes the end back by SoftCloseExtension
+	SoftCloseWindow    time.Duration
+	SoftCloseExtension time.Duration
+	// BuyNowPrice ends the item as soon as the highest bid reaches it, 0 disables it
+	BuyNowPrice float64
 }
 
+// BidResult is the state of the item after a bid script ran, whether the bid was accepted or not.
+// Placed lists every bid stored by the script in order, including automatic proxy bids.
+// EndTime is the effective end of the item, Extended reports whether this bid pushed it back
+// and Closed whether the buy it now price was met, after which every bid is refused.
+type BidResult struct {
+	Status        string
+	HighestAmount float64
+	HighestBidder int64
+	EndTime       time.Time
+	Extended      bool
+	Closed        bool
+	Placed        []BidEntry
+}
+
+// resolveProxyBidsLua is shared by the bid scripts. After the active hash changed it lets the
+// strongest proxy ceiling (ties go to the earliest registration) outbid the current highest
+// bid in increment steps, without ever bidding more than needed to win.
+//
+// KEYS[1] active hash, KEYS[2] history sorted set, KEYS[3] proxy ceilings hash, KEYS[4] proxy order sorted set
+const resolveProxyBidsLua = `
+local placed = {}
+
+-- incrementFor walks the ladder passed as ARGV[10] (tier count) followed by minPrice, increment,
+-- percent triples sorted by minPrice, ARGV[4] is the step below the first tier
+local function incrementFor(price)
+	local step = tonumber(ARGV[4])
+	local count = tonumber(ARGV[10]) or 0
+	for i = 0, count - 1 do
+		local base = 11 + i * 3
+		if price >= tonumber(ARGV[base]) then
+			step = tonumber(ARGV[base + 1]) + math.ceil(price * tonumber(ARGV[base + 2]) / 100)
+		end
+	end
+	return step
+end
+
+local function placeBid(user, amount)
+	redis.call('HSET', KEYS[1], 'highest_amount', tostring(amount), 'highest_bidder', user)
+	redis.call('ZADD', KEYS[2], amount, user)
+	table.insert(placed, user)
+	table.insert(placed, tostring(amount))
+end
+
+local function currentBid()
+	local amount = tonumber(redis.call('HGET', KEYS[1], 'highest_amount') or '0') or 0
+	local bidder = redis.call('HGET', KEYS[1], 'highest_bidder') or '0'
+	return amount, bidder
+end
+
+local function proxyLeaders()
+	local entries = redis.call('HGETALL', KEYS[3])
+	local best, second
+	for i = 1, #entries, 2 do
+		local seq = tonumber(redis.call('ZSCORE', KEYS[4], entries[i])) or 0
+		local cand = {user = entries[i], max = tonumber(entries[i + 1]), seq = seq}
+		if not best or cand.max > best.max or (cand.max == best.max and cand.seq < best.seq) then
+			second = best
+			best = cand
+		elseif not second or cand.max > second.max or (cand.max == second.max and cand.seq < second.seq) then
+			second = cand
+		end
+	end
+	return best, second
+end
+
+local function resolveProxyBids(minAmount)
+	local current, bidder = currentBid()
+	local best, second = proxyLeaders()
+	if not best then
+		return
+	end
+
+	local price
+	if best.user == bidder then
+		if not second or second.max <= current then
+			return
+		end
+		price = math.min(best.max, second.max + incrementFor(second.max))
+	else
+		if best.max < current then
+			return
+		end
+		local competitor = current
+		if second and second.max > competitor then
+			competitor = second.max
+		end
+		if competitor == 0 then
+			if best.max < minAmount then
+				return
+			end
+			price = minAmount
+		else
+			price = math.min(best.max, competitor + incrementFor(competitor))
+		end
+	end
+
+	-- never bid past buy it now, reaching it ends the item
+	local buyNow = tonumber(ARGV[9])
+	if buyNow > 0 and price > buyNow then
+		price = buyNow
+	end
+
+	-- the runner up proxy bids its full ceiling before losing
+	if second and second.max > current and second.user ~= best.user then
+		placeBid(second.user, second.max)
+	end
+	placeBid(best.user, price)
+end
+
+local function effectiveEnd()
+	local stored = tonumber(redis.call('HGET', KEYS[1], 'end_time') or '0') or 0
+	return math.max(stored, tonumber(ARGV[6]))
+end
+
+local function isClosed()
+	return redis.call('HGET', KEYS[1], 'closed') == '1' or tonumber(ARGV[5]) > effectiveEnd()
+end
+
+-- finish closes the item once buy it now is met, otherwise applies the soft close when bids
+-- were placed, then refreshes the ttl.
+-- ARGV[5] now, ARGV[6] scheduled end, ARGV[7] soft close window, ARGV[8] soft close extension, ARGV[9] buy it now
+local function finish(status)
+	local now = tonumber(ARGV[5])
+	local endTime = effectiveEnd()
+	local extended = '0'
+	if #placed > 0 then
+		local current = currentBid()
+		local buyNow = tonumber(ARGV[9])
+		if buyNow > 0 and current >= buyNow then
+			redis.call('HSET', KEYS[1], 'closed', '1')
+		else
+			local window = tonumber(ARGV[7])
+			if window > 0 and now >= endTime - window then
+				endTime = endTime + tonumber(ARGV[8])
+				extended = '1'
+			end
+		end
+		redis.call('HSET', KEYS[1], 'updated_at', ARGV[5], 'end_time', tostring(endTime))
+	end
+
+	-- buffer with ttl to delete key after session end
+	local ttl = endTime - now + 300
+	if ttl > 0 then
+		for i = 1, 4 do
+			if redis.call('EXISTS', KEYS[i]) == 1 then
+				redis.call('EXPIRE', KEYS[i], ttl)
+			end
+		end
+	end
+
+	local amount, bidder = currentBid()
+	local closed = '0'
+	if redis.call('HGET', KEYS[1], 'closed') == '1' then
+		closed = '1'
+	end
+	local res = {status, tostring(amount), bidder, tostring(endTime), extended, closed}
+	for _, v in ipairs(placed) do
+		table.insert(res, v)
+	end
+	return res
+end
+`
+
+// compareAndSetBidScript validates and stores an explicit bid in one atomic step so
+// concurrent bids on several instances cannot overwrite each other, then lets proxy
+// ceilings respond to it.
+//
+// ARGV: amount, userID, minAmount, minIncrement, now, endTime, softCloseWindow, softCloseExtension, buyNow, ladder...
+var compareAndSetBidScript = redis.NewScript(resolveProxyBidsLua + `
+local amount = tonumber(ARGV[1])
+local current, bidder = currentBid()
+local increment = incrementFor(current)
+
+if isClosed() then
+	return finish('closed')
+end
+
+-- a bid meeting buy it now pays exactly that price and ends the item without proxies answering
+local buyNow = tonumber(ARGV[9])
+local buyingNow = buyNow > 0 and amount >= buyNow
+if buyingNow then
+	amount = buyNow
+	increment = 0
+end
+
+if current == 0 then
+	if amount < tonumber(ARGV[3]) then
+		return finish('too_low')
+	end
+else
+	if amount <= current then
+		return finish('too_low')
+	end
+	if bidder == ARGV[2] then
+		return finish('already_highest')
+	end
+	if amount < current + increment then
+		return finish('too_low')
+	end
+end
+
+placeBid(ARGV[2], amount)
+if not buyingNow then
+	resolveProxyBids(tonumber(ARGV[3]))
+end
+
+return finish('accepted')
+`)
+
+// registerProxyBidScript stores or raises a proxy ceiling and lets it bid right away.
+// A ceiling must be able to outbid the current highest bid, unless its owner already leads.
+//
+// ARGV: maxAmount, userID, minAmount, minIncrement, now, endTime, softCloseWindow, softCloseExtension, buyNow, ladder...
+var registerProxyBidScript = redis.NewScript(resolveProxyBidsLua + `
+local maxAmount = tonumber(ARGV[1])
+local current, bidder = currentBid()
+
+if isClosed() then
+	return finish('closed')
+end
+
+if bidder == ARGV[2] and current > 0 then
+	if maxAmount <= current then
+		return finish('too_low')
+	end
+elseif current == 0 then
+	if maxAmount < tonumber(ARGV[3]) then
+		return finish('too_low')
+	end
+elseif maxAmount < current + incrementFor(current) then
+	return finish('too_low')
+end
+
+local existing = tonumber(redis.call('HGET', KEYS[3], ARGV[2]) or '0') or 0
+if maxAmount <= existing then
+	return finish('too_low')
+end
+
+-- raising a ceiling counts as a new registration for tie breaking
+local last = redis.call('ZREVRANGE', KEYS[4], 0, 0, 'WITHSCORES')
+local seq = 1
+if #last == 2 then
+	seq = tonumber(last[2]) + 1
+end
+redis.call('HSET', KEYS[3], ARGV[2], ARGV[1])
+redis.call('ZADD', KEYS[4], seq, ARGV[2])
+
+resolveProxyBids(tonumber(ARGV[3]))
+
+return finish('accepted')
+`)
+
+// extendEndTimeScript moves the end time of an item forward, never backwards, and refreshes the ttl.
+//
+// KEYS same as the bid scripts, ARGV: now, endTime
+var extendEndTimeScript = redis.NewScript(`
+local stored = tonumber(redis.call('HGET', KEYS[1], 'end_time') or '0') or 0
+local endTime = tonumber(ARGV[2])
+if stored >= endTime then
+	return 0
+end
+
+redis.call('HSET', KEYS[1], 'end_time', ARGV[2])
+local ttl = endTime - tonumber(ARGV[1]) + 300
+for i = 1, 4 do
+	if redis.call('EXISTS', KEYS[i]) == 1 then
+		redis.call('EXPIRE', KEYS[i], ttl)
+	end
+end
+return 1
+`)
+
 func NewBidRedisRepository(client *redis.Client, ctx context.Context) BidRedisRepository {
 	return &bidRedisRepository{client: client, ctx: ctx}
 }
 
-func (r *bidRedisRepository) SetHighestBid(sessionID, itemID int64, amount float64, userID int64, sessionEndTime time.Time) error {
-	key := fmt.Sprintf("active:auction:%d:item:%d", sessionID, itemID)
+func bidScriptKeys(sessionID, itemID int64) []string {
+	return []string{
+		fmt.Sprintf("active:auction:%d:item:%d", sessionID, itemID),
+		fmt.Sprintf("auction:%d:item:%d:history", sessionID, itemID),
+		fmt.Sprintf("auction:%d:item:%d:proxies", sessionID, itemID),
+		fmt.Sprintf("auction:%d:item:%d:proxy_order", sessionID, itemID),
+	}
+}
 
-	if err := r.client.HSet(r.ctx, key, map[string]interface{}{
-		"highest_amount": amount,
-		"highest_bidder": userID,
-		"updated_at":     time.Now().Unix(),
-		"end_time":       sessionEndTime.Unix(),
-	}).Err(); err != nil {
+func (r *bidRedisRepository) runBidScript(script *redis.Script, sessionID, itemID int64, userID int64, amount float64, rules BidRules) (BidResult, error) {
+	args := []interface{}{
+		strconv.FormatFloat(amount, 'f', -1, 64),
+		userID,
+		strconv.FormatFloat(rules.MinAmount, 'f', -1, 64),
+		strconv.FormatFloat(rules.MinIncrement, 'f', -1, 64),
+		time.Now().Unix(),
+		rules.EndTime.Unix(),
+		int64(rules.SoftCloseWindow.Seconds()),
+		int64(rules.SoftCloseExtension.Seconds()),
+		strconv.FormatFloat(rules.BuyNowPrice, 'f', -1, 64),
+		len(rules.Ladder),
+	}
+	for _, tier := range rules.Ladder {
+		args = append(args,
+			strconv.FormatFloat(tier.MinPrice, 'f', -1, 64),
+			strconv.FormatFloat(tier.Increment, 'f', -1, 64),
+			strconv.FormatFloat(tier.Percent, 'f', -1, 64),
+		)
+	}
+
+	res, err := script.Run(r.ctx, r.client, bidScriptKeys(sessionID, itemID), args...).StringSlice()
+	if err != nil {
+		return BidResult{}, err
+	}
+	if len(res) < 6 || len(res)%2 != 0 {
+		return BidResult{}, fmt.Errorf("unexpected bid script result: %v", res)
+	}
+
+	highest, _ := strconv.ParseFloat(res[1], 64)
+	bidder, _ := strconv.ParseInt(res[2], 10, 64)
+	endTime, _ := strconv.ParseInt(res[3], 10, 64)
+
+	result := BidResult{
+		Status:        res[0],
+		HighestAmount: highest,
+		HighestBidder: bidder,
+		EndTime:       time.Unix(endTime, 0),
+		Extended:      res[4] == "1",
+		Closed:        res[5] == "1",
+	}
+	for i := 6; i < len(res); i += 2 {
+		user, _ := strconv.ParseInt(res[i], 10, 64)
+		placedAmount, _ := strconv.ParseFloat(res[i+1], 64)
+		result.Placed = append(result.Placed, BidEntry{
+			UserID: user,
+			ItemID: itemID,
+			Amount: placedAmount,
+		})
+	}
+
+	return result, nil
+}
+
+func (r *bidRedisRepository) CompareAndSetHighestBid(sessionID, itemID int64, amount float64, userID int64, rules BidRules) (BidResult, error) {
+	return r.runBidScript(compareAndSetBidScript, sessionID, itemID, userID, amount, rules)
+}
+
+func (r *bidRedisRepository) RegisterProxyBid(sessionID, itemID int64, userID int64, maxAmount float64, rules BidRules) (BidResult, error) {
+	return r.runBidScript(registerProxyBidScript, sessionID, itemID, userID, maxAmount, rules)
+}
+
+// ExtendSessionEndTime pushes the end time of every active item in the session back to endTime
+func (r *bidRedisRepository) ExtendSessionEndTime(sessionID int64, endTime time.Time) error {
+	keys, err := r.ScanKeys(fmt.Sprintf("active:auction:%d:item:*", sessionID))
+	if err != nil {
 		return err
 	}
 
-	// buffer with ttl to delete key after session end
-	ttl := time.Until(sessionEndTime) + (5 * time.Minute)
-	if ttl > 0 {
-		if err := r.client.Expire(r.ctx, key, ttl).Err(); err != nil {
+	for _, key := range keys {
+		var keySessionID, itemID int64
+		if _, err := fmt.Sscanf(key, "active:auction:%d:item:%d", &keySessionID, &itemID); err != nil {
+			continue
+		}
+
+		err := extendEndTimeScript.Run(r.ctx, r.client, bidScriptKeys(sessionID, itemID),
+			time.Now().Unix(),
+			endTime.Unix(),
+		).Err()
+		if err != nil {
 			return err
 		}
 	}
 
-	historyKey := fmt.Sprintf("auction:%d:item:%d:history", sessionID, itemID)
-	if err := r.client.ZAdd(r.ctx, historyKey, redis.Z{
-		Score:  amount,
-		Member: userID,
-	}).Err(); err != nil {
-		return err
+	return nil
+}
+
+func (r *bidRedisRepository) GetProxyBid(sessionID, itemID, userID int64) (float64, error) {
+	key := fmt.Sprintf("auction:%d:item:%d:proxies", sessionID, itemID)
+
+	maxAmount, err := r.client.HGet(r.ctx, key, strconv.FormatInt(userID, 10)).Float64()
+	if errors.Is(err, redis.Nil) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
 	}
 
-	return nil
+	return maxAmount, nil
 }
 
 func (r *bidRedisRepository) GetHighestBid(sessionID, itemID int64) (float64, int64, error) {
@@ -152,6 +539,7 @@ func (r *bidRedisRepository) GetBidByKey(key string) (BidEntry, error) {
 		UserID: userID,
 		ItemID: itemID,
 		Amount: amount,
+		Closed: data["closed"] == "1",
 	}, nil
 }
 
@@ -159,6 +547,52 @@ func (r *bidRedisRepository) DeleteKey(key string) error {
 	return r.client.Del(r.ctx, key).Err()
 }
 
+func unsavedBidsKey(sessionID, itemID int64) string {
+	return fmt.Sprintf("auction:%d:item:%d:unsaved", sessionID, itemID)
+}
+
+// QueueUnsavedBids keeps bids the ledger write failed for in order, as user:amount entries
+func (r *bidRedisRepository) QueueUnsavedBids(sessionID, itemID int64, bids []BidEntry) error {
+	if len(bids) == 0 {
+		return nil
+	}
+
+	entries := make([]interface{}, 0, len(bids))
+	for _, bid := range bids {
+		entries = append(entries, fmt.Sprintf("%d:%s", bid.UserID, strconv.FormatFloat(bid.Amount, 'f', -1, 64)))
+	}
+	return r.client.RPush(r.ctx, unsavedBidsKey(sessionID, itemID), entries...).Err()
+}
+
+func (r *bidRedisRepository) GetUnsavedBids(sessionID, itemID int64) ([]BidEntry, error) {
+	entries, err := r.client.LRange(r.ctx, unsavedBidsKey(sessionID, itemID), 0, -1).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	bids := make([]BidEntry, 0, len(entries))
+	for _, entry := range entries {
+		user, amount, ok := strings.Cut(entry, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid unsaved bid entry: %q", entry)
+		}
+		userID, err := strconv.ParseInt(user, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid unsaved bid entry: %q", entry)
+		}
+		bidAmount, err := strconv.ParseFloat(amount, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid unsaved bid entry: %q", entry)
+		}
+		bids = append(bids, BidEntry{UserID: userID, ItemID: itemID, Amount: bidAmount})
+	}
+	return bids, nil
+}
+
+func (r *bidRedisRepository) DeleteUnsavedBids(sessionID, itemID int64) error {
+	return r.client.Del(r.ctx, unsavedBidsKey(sessionID, itemID)).Err()
+}
+
 func (r *bidRedisRepository) CheckDuplicateBid(userID, itemID int64, amount float64, ttl time.Duration) error {
 	key := fmt.Sprintf("bidder:%d:item:%d:amount:%.2f", userID, itemID, amount)
 	result, err := r.client.SetNX(r.ctx, key, "exists", ttl).Result()
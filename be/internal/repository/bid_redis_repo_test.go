@@ -0,0 +1,516 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"milestone3/be/internal/entity"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestBidRedisRepository(t *testing.T) (BidRedisRepository, *miniredis.Miniredis) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+
+	return NewBidRedisRepository(client, context.Background()), mr
+}
+
+func TestBidRedisRepository_CompareAndSetHighestBid(t *testing.T) {
+	repo, mr := newTestBidRedisRepository(t)
+	endTime := time.Now().Add(time.Hour)
+
+	tests := []struct {
+		name       string
+		userID     int64
+		amount     float64
+		wantStatus string
+		wantAmount float64
+		wantBidder int64
+	}{
+		{
+			name:       "first bid below starting price",
+			userID:     1,
+			amount:     40000,
+			wantStatus: BidRejectedTooLow,
+		},
+		{
+			name:       "first bid at starting price",
+			userID:     1,
+			amount:     50000,
+			wantStatus: BidAccepted,
+			wantAmount: 50000,
+			wantBidder: 1,
+		},
+		{
+			name:       "highest bidder cannot outbid themselves",
+			userID:     1,
+			amount:     70000,
+			wantStatus: BidRejectedAlreadyOwner,
+			wantAmount: 50000,
+			wantBidder: 1,
+		},
+		{
+			name:       "bid below minimum increment",
+			userID:     2,
+			amount:     55000,
+			wantStatus: BidRejectedTooLow,
+			wantAmount: 50000,
+			wantBidder: 1,
+		},
+		{
+			name:       "bid with valid increment",
+			userID:     2,
+			amount:     60000,
+			wantStatus: BidAccepted,
+			wantAmount: 60000,
+			wantBidder: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := repo.CompareAndSetHighestBid(1, 1, tt.amount, tt.userID, BidRules{MinAmount: 50000, MinIncrement: 10000, EndTime: endTime})
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, result.Status)
+			assert.Equal(t, tt.wantAmount, result.HighestAmount)
+			assert.Equal(t, tt.wantBidder, result.HighestBidder)
+
+			amount, bidder, err := repo.GetHighestBid(1, 1)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantAmount, amount)
+			assert.Equal(t, tt.wantBidder, bidder)
+		})
+	}
+
+	assert.True(t, mr.TTL("active:auction:1:item:1") > time.Hour)
+
+	storedEnd, err := repo.GetEndTime("active:auction:1:item:1")
+	require.NoError(t, err)
+	assert.Equal(t, endTime.Unix(), storedEnd.Unix())
+}
+
+func TestBidRedisRepository_CompareAndSetHighestBid_Concurrent(t *testing.T) {
+	repo, _ := newTestBidRedisRepository(t)
+	endTime := time.Now().Add(time.Hour)
+
+	_, err := repo.CompareAndSetHighestBid(1, 1, 10000, 100, BidRules{MinAmount: 10000, MinIncrement: 10000, EndTime: endTime})
+	require.NoError(t, err)
+
+	// every bidder reads the same highest bid and races with the same amount,
+	// only one of them may win
+	const bidders = 50
+	var wg sync.WaitGroup
+	results := make([]BidResult, bidders)
+	errs := make([]error, bidders)
+
+	for i := 0; i < bidders; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], errs[i] = repo.CompareAndSetHighestBid(1, 1, 20000, int64(i+1), BidRules{MinAmount: 10000, MinIncrement: 10000, EndTime: endTime})
+		}(i)
+	}
+	wg.Wait()
+
+	accepted := 0
+	var winner int64
+	for i := 0; i < bidders; i++ {
+		require.NoError(t, errs[i])
+		if results[i].Status == BidAccepted {
+			accepted++
+			winner = int64(i + 1)
+		}
+	}
+
+	assert.Equal(t, 1, accepted)
+
+	amount, bidder, err := repo.GetHighestBid(1, 1)
+	require.NoError(t, err)
+	assert.Equal(t, float64(20000), amount)
+	assert.Equal(t, winner, bidder)
+}
+
+func TestBidRedisRepository_CompareAndSetHighestBid_ConcurrentIncreasing(t *testing.T) {
+	repo, _ := newTestBidRedisRepository(t)
+	endTime := time.Now().Add(time.Hour)
+
+	// bidders race with different amounts, the stored bid must end up as the
+	// highest amount regardless of arrival order
+	const bidders = 50
+	var wg sync.WaitGroup
+	for i := 1; i <= bidders; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, err := repo.CompareAndSetHighestBid(1, 2, float64(i*10000), int64(i), BidRules{MinAmount: 10000, MinIncrement: 10000, EndTime: endTime})
+			assert.NoError(t, err)
+		}(i)
+	}
+	wg.Wait()
+
+	amount, bidder, err := repo.GetHighestBid(1, 2)
+	require.NoError(t, err)
+	assert.Equal(t, float64(bidders*10000), amount)
+	assert.Equal(t, int64(bidders), bidder)
+}
+
+func TestBidRedisRepository_RegisterProxyBid(t *testing.T) {
+	repo, _ := newTestBidRedisRepository(t)
+	endTime := time.Now().Add(time.Hour)
+
+	tests := []struct {
+		name       string
+		explicit   bool
+		userID     int64
+		amount     float64
+		wantStatus string
+		wantAmount float64
+		wantBidder int64
+		wantPlaced []BidEntry
+	}{
+		{
+			name:       "ceiling below starting price",
+			userID:     1,
+			amount:     40000,
+			wantStatus: BidRejectedTooLow,
+		},
+		{
+			name:       "first ceiling bids the starting price",
+			userID:     1,
+			amount:     150000,
+			wantStatus: BidAccepted,
+			wantAmount: 50000,
+			wantBidder: 1,
+			wantPlaced: []BidEntry{{UserID: 1, ItemID: 1, Amount: 50000}},
+		},
+		{
+			name:       "explicit bid is answered by the ceiling",
+			explicit:   true,
+			userID:     2,
+			amount:     70000,
+			wantStatus: BidAccepted,
+			wantAmount: 80000,
+			wantBidder: 1,
+			wantPlaced: []BidEntry{
+				{UserID: 2, ItemID: 1, Amount: 70000},
+				{UserID: 1, ItemID: 1, Amount: 80000},
+			},
+		},
+		{
+			name:       "lower competing ceiling loses at its maximum",
+			userID:     3,
+			amount:     100000,
+			wantStatus: BidAccepted,
+			wantAmount: 110000,
+			wantBidder: 1,
+			wantPlaced: []BidEntry{
+				{UserID: 3, ItemID: 1, Amount: 100000},
+				{UserID: 1, ItemID: 1, Amount: 110000},
+			},
+		},
+		{
+			name:       "ceiling below the next minimum bid",
+			userID:     4,
+			amount:     115000,
+			wantStatus: BidRejectedTooLow,
+			wantAmount: 110000,
+			wantBidder: 1,
+		},
+		{
+			name:       "equal ceiling loses to the earlier registration",
+			userID:     4,
+			amount:     150000,
+			wantStatus: BidAccepted,
+			wantAmount: 150000,
+			wantBidder: 1,
+			wantPlaced: []BidEntry{
+				{UserID: 4, ItemID: 1, Amount: 150000},
+				{UserID: 1, ItemID: 1, Amount: 150000},
+			},
+		},
+		{
+			name:       "explicit bid from the proxy leader is rejected",
+			explicit:   true,
+			userID:     1,
+			amount:     160000,
+			wantStatus: BidRejectedAlreadyOwner,
+			wantAmount: 150000,
+			wantBidder: 1,
+		},
+		{
+			name:       "higher ceiling takes the lead one increment above",
+			userID:     5,
+			amount:     200000,
+			wantStatus: BidAccepted,
+			wantAmount: 160000,
+			wantBidder: 5,
+			wantPlaced: []BidEntry{{UserID: 5, ItemID: 1, Amount: 160000}},
+		},
+		{
+			name:       "explicit bid above every ceiling takes the lead",
+			explicit:   true,
+			userID:     6,
+			amount:     210000,
+			wantStatus: BidAccepted,
+			wantAmount: 210000,
+			wantBidder: 6,
+			wantPlaced: []BidEntry{{UserID: 6, ItemID: 1, Amount: 210000}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var result BidResult
+			var err error
+			if tt.explicit {
+				result, err = repo.CompareAndSetHighestBid(1, 1, tt.amount, tt.userID, BidRules{MinAmount: 50000, MinIncrement: 10000, EndTime: endTime})
+			} else {
+				result, err = repo.RegisterProxyBid(1, 1, tt.userID, tt.amount, BidRules{MinAmount: 50000, MinIncrement: 10000, EndTime: endTime})
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, result.Status)
+			assert.Equal(t, tt.wantAmount, result.HighestAmount)
+			assert.Equal(t, tt.wantBidder, result.HighestBidder)
+			assert.Equal(t, tt.wantPlaced, result.Placed)
+		})
+	}
+
+	// ceilings are only readable per user
+	maxAmount, err := repo.GetProxyBid(1, 1, 5)
+	require.NoError(t, err)
+	assert.Equal(t, float64(200000), maxAmount)
+
+	maxAmount, err = repo.GetProxyBid(1, 1, 99)
+	require.NoError(t, err)
+	assert.Equal(t, float64(0), maxAmount)
+}
+
+func TestBidRedisRepository_RegisterProxyBid_EqualCeilingTie(t *testing.T) {
+	repo, _ := newTestBidRedisRepository(t)
+	endTime := time.Now().Add(time.Hour)
+
+	_, err := repo.CompareAndSetHighestBid(1, 1, 50000, 9, BidRules{MinAmount: 50000, MinIncrement: 10000, EndTime: endTime})
+	require.NoError(t, err)
+
+	result, err := repo.RegisterProxyBid(1, 1, 1, 100000, BidRules{MinAmount: 50000, MinIncrement: 10000, EndTime: endTime})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), result.HighestBidder)
+	assert.Equal(t, float64(60000), result.HighestAmount)
+
+	// an explicit bid matching the ceiling loses to the earlier registration
+	result, err = repo.CompareAndSetHighestBid(1, 1, 100000, 2, BidRules{MinAmount: 50000, MinIncrement: 10000, EndTime: endTime})
+	require.NoError(t, err)
+	assert.Equal(t, BidAccepted, result.Status)
+	assert.Equal(t, int64(1), result.HighestBidder)
+	assert.Equal(t, float64(100000), result.HighestAmount)
+}
+
+func TestBidRedisRepository_IncrementLadder(t *testing.T) {
+	repo, _ := newTestBidRedisRepository(t)
+	rules := BidRules{
+		MinAmount:    100000,
+		MinIncrement: 10000,
+		EndTime:      time.Now().Add(time.Hour),
+		Ladder: []entity.BidIncrementTier{
+			{MinPrice: 0, Percent: 10},
+			{MinPrice: 200000, Increment: 50000},
+		},
+	}
+
+	steps := []struct {
+		name       string
+		userID     int64
+		amount     float64
+		wantStatus string
+	}{
+		{name: "opening bid", userID: 9, amount: 100000, wantStatus: BidAccepted},
+		{name: "below the percent step", userID: 2, amount: 105000, wantStatus: BidRejectedTooLow},
+		{name: "exactly the percent step", userID: 2, amount: 110000, wantStatus: BidAccepted},
+		{name: "jump into the fixed tier", userID: 3, amount: 200000, wantStatus: BidAccepted},
+		{name: "below the fixed step", userID: 4, amount: 240000, wantStatus: BidRejectedTooLow},
+	}
+
+	for _, step := range steps {
+		result, err := repo.CompareAndSetHighestBid(1, 1, step.amount, step.userID, rules)
+		require.NoError(t, err, step.name)
+		assert.Equal(t, step.wantStatus, result.Status, step.name)
+	}
+
+	// the proxy answers with the tier step, not the fallback increment
+	result, err := repo.RegisterProxyBid(1, 1, 5, 400000, rules)
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), result.HighestBidder)
+	assert.Equal(t, float64(250000), result.HighestAmount)
+}
+
+func TestBidRedisRepository_SoftClose(t *testing.T) {
+	repo, mr := newTestBidRedisRepository(t)
+	closingEnd := time.Now().Add(time.Minute).Truncate(time.Second)
+
+	rules := BidRules{
+		MinAmount:          10000,
+		MinIncrement:       10000,
+		EndTime:            closingEnd,
+		SoftCloseWindow:    2 * time.Minute,
+		SoftCloseExtension: 2 * time.Minute,
+	}
+
+	// outside the window the end time is kept
+	farRules := rules
+	farRules.EndTime = time.Now().Add(time.Hour).Truncate(time.Second)
+	result, err := repo.CompareAndSetHighestBid(1, 2, 10000, 1, farRules)
+	require.NoError(t, err)
+	assert.False(t, result.Extended)
+	assert.Equal(t, farRules.EndTime.Unix(), result.EndTime.Unix())
+
+	// inside the window the end time moves back by the extension
+	result, err = repo.CompareAndSetHighestBid(1, 1, 10000, 1, rules)
+	require.NoError(t, err)
+	assert.Equal(t, BidAccepted, result.Status)
+	assert.True(t, result.Extended)
+	assert.Equal(t, closingEnd.Add(2*time.Minute).Unix(), result.EndTime.Unix())
+
+	// the extended end time wins over the scheduled one passed by the caller,
+	// and is now outside the window
+	result, err = repo.CompareAndSetHighestBid(1, 1, 20000, 2, rules)
+	require.NoError(t, err)
+	assert.False(t, result.Extended)
+	assert.Equal(t, closingEnd.Add(2*time.Minute).Unix(), result.EndTime.Unix())
+
+	storedEnd, err := repo.GetEndTime("active:auction:1:item:1")
+	require.NoError(t, err)
+	assert.Equal(t, closingEnd.Add(2*time.Minute).Unix(), storedEnd.Unix())
+	assert.True(t, mr.TTL("active:auction:1:item:1") > 2*time.Minute)
+
+	// rejected bids do not extend
+	result, err = repo.CompareAndSetHighestBid(1, 1, 25000, 3, rules)
+	require.NoError(t, err)
+	assert.Equal(t, BidRejectedTooLow, result.Status)
+	assert.False(t, result.Extended)
+
+	// bids after the effective end are refused
+	endedRules := rules
+	endedRules.EndTime = time.Now().Add(-time.Minute)
+	result, err = repo.CompareAndSetHighestBid(1, 3, 10000, 1, endedRules)
+	require.NoError(t, err)
+	assert.Equal(t, BidRejectedClosed, result.Status)
+}
+
+func TestBidRedisRepository_ExtendSessionEndTime(t *testing.T) {
+	repo, mr := newTestBidRedisRepository(t)
+	endTime := time.Now().Add(time.Minute).Truncate(time.Second)
+	rules := BidRules{MinAmount: 10000, MinIncrement: 10000, EndTime: endTime}
+
+	for itemID := int64(1); itemID <= 2; itemID++ {
+		_, err := repo.CompareAndSetHighestBid(1, itemID, 10000, 1, rules)
+		require.NoError(t, err)
+	}
+	_, err := repo.CompareAndSetHighestBid(2, 3, 10000, 1, rules)
+	require.NoError(t, err)
+
+	extended := endTime.Add(5 * time.Minute)
+	require.NoError(t, repo.ExtendSessionEndTime(1, extended))
+
+	for _, key := range []string{"active:auction:1:item:1", "active:auction:1:item:2"} {
+		storedEnd, err := repo.GetEndTime(key)
+		require.NoError(t, err)
+		assert.Equal(t, extended.Unix(), storedEnd.Unix())
+		assert.True(t, mr.TTL(key) > 5*time.Minute)
+	}
+
+	// other sessions are untouched
+	storedEnd, err := repo.GetEndTime("active:auction:2:item:3")
+	require.NoError(t, err)
+	assert.Equal(t, endTime.Unix(), storedEnd.Unix())
+
+	// end times never move backwards
+	require.NoError(t, repo.ExtendSessionEndTime(1, endTime))
+	storedEnd, err = repo.GetEndTime("active:auction:1:item:1")
+	require.NoError(t, err)
+	assert.Equal(t, extended.Unix(), storedEnd.Unix())
+}
+
+func TestBidRedisRepository_BuyNow(t *testing.T) {
+	repo, _ := newTestBidRedisRepository(t)
+	rules := BidRules{
+		MinAmount:    10000,
+		MinIncrement: 10000,
+		EndTime:      time.Now().Add(time.Hour),
+		BuyNowPrice:  100000,
+	}
+
+	result, err := repo.RegisterProxyBid(1, 1, 1, 200000, rules)
+	require.NoError(t, err)
+	assert.False(t, result.Closed)
+	assert.Equal(t, float64(10000), result.HighestAmount)
+
+	// a bid above buy it now pays the buy it now price and proxies do not answer it
+	result, err = repo.CompareAndSetHighestBid(1, 1, 150000, 2, rules)
+	require.NoError(t, err)
+	assert.Equal(t, BidAccepted, result.Status)
+	assert.True(t, result.Closed)
+	assert.Equal(t, float64(100000), result.HighestAmount)
+	assert.Equal(t, int64(2), result.HighestBidder)
+	assert.Equal(t, []BidEntry{{UserID: 2, ItemID: 1, Amount: 100000}}, result.Placed)
+
+	// every later bid is refused
+	result, err = repo.CompareAndSetHighestBid(1, 1, 300000, 3, rules)
+	require.NoError(t, err)
+	assert.Equal(t, BidRejectedClosed, result.Status)
+
+	result, err = repo.RegisterProxyBid(1, 1, 3, 300000, rules)
+	require.NoError(t, err)
+	assert.Equal(t, BidRejectedClosed, result.Status)
+
+	bid, err := repo.GetBidByKey("active:auction:1:item:1")
+	require.NoError(t, err)
+	assert.True(t, bid.Closed)
+	assert.Equal(t, int64(2), bid.UserID)
+
+	// a proxy ceiling above buy it now stops at it and ends the item
+	_, err = repo.CompareAndSetHighestBid(1, 2, 90000, 1, rules)
+	require.NoError(t, err)
+
+	result, err = repo.RegisterProxyBid(1, 2, 2, 200000, rules)
+	require.NoError(t, err)
+	assert.True(t, result.Closed)
+	assert.Equal(t, float64(100000), result.HighestAmount)
+	assert.Equal(t, int64(2), result.HighestBidder)
+}
+
+func TestBidRedisRepository_UnsavedBids(t *testing.T) {
+	repo, _ := newTestBidRedisRepository(t)
+
+	bids, err := repo.GetUnsavedBids(1, 1)
+	assert.NoError(t, err)
+	assert.Empty(t, bids)
+
+	assert.NoError(t, repo.QueueUnsavedBids(1, 1, []BidEntry{{UserID: 2, Amount: 15000}}))
+	assert.NoError(t, repo.QueueUnsavedBids(1, 1, []BidEntry{{UserID: 3, Amount: 17500.5}}))
+	assert.NoError(t, repo.QueueUnsavedBids(1, 2, []BidEntry{{UserID: 4, Amount: 9000}}))
+
+	bids, err = repo.GetUnsavedBids(1, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []BidEntry{
+		{UserID: 2, ItemID: 1, Amount: 15000},
+		{UserID: 3, ItemID: 1, Amount: 17500.5},
+	}, bids)
+
+	assert.NoError(t, repo.DeleteUnsavedBids(1, 1))
+	bids, err = repo.GetUnsavedBids(1, 1)
+	assert.NoError(t, err)
+	assert.Empty(t, bids)
+
+	bids, err = repo.GetUnsavedBids(1, 2)
+	assert.NoError(t, err)
+	assert.Len(t, bids, 1)
+}
@@ -4,10 +4,13 @@ import (
 	"milestone3/be/internal/entity"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type BidRepository interface {
-	SaveFinalBid(bid *entity.Bid) error
+	SaveBid(bid *entity.Bid) error
+	FinalizeItem(itemID int64, status string, unsavedBids []entity.Bid, winningBid *entity.Bid, event *entity.OutboxEvent) error
+	GetByItemID(itemID int64, page, limit int) ([]entity.Bid, int64, error)
 }
 
 type bidRepository struct {
@@ -18,6 +21,53 @@ func NewBidRepository(db *gorm.DB) BidRepository {
 	return &bidRepository{db: db}
 }
 
-func (r *bidRepository) SaveFinalBid(bid *entity.Bid) error {
+// SaveBid records an accepted bid in the bid ledger
+func (r *bidRepository) SaveBid(bid *entity.Bid) error {
 	return r.db.Create(bid).Error
 }
+
+// FinalizeItem settles an item in one transaction: the bids whose write-through failed, the
+// winning bid (nil when there is none), the item status and the outbox event announcing the
+// outcome. A bidder never bids the same amount on an item twice, so a bid already in the ledger
+// is found instead of inserted again, and the event dedup key makes running it again after a
+// commit harmless.
+func (r *bidRepository) FinalizeItem(itemID int64, status string, unsavedBids []entity.Bid, winningBid *entity.Bid, event *entity.OutboxEvent) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		bids := append([]entity.Bid{}, unsavedBids...)
+		if winningBid != nil {
+			bids = append(bids, *winningBid)
+		}
+		for i := range bids {
+			err := tx.
+				Where("auction_item_id = ? AND user_id = ? AND amount = ?", bids[i].ItemID, bids[i].UserID, bids[i].Amount).
+				FirstOrCreate(&bids[i]).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Model(&entity.AuctionItem{}).Where("id = ?", itemID).Update("status", status).Error; err != nil {
+			return err
+		}
+
+		return tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "dedup_key"}},
+			DoNothing: true,
+		}).Create(event).Error
+	})
+}
+
+func (r *bidRepository) GetByItemID(itemID int64, page, limit int) ([]entity.Bid, int64, error) {
+	var bids []entity.Bid
+	var total int64
+
+	// Count total records for item
+	if err := r.db.Model(&entity.Bid{}).Where("auction_item_id = ?", itemID).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Get paginated records, newest bid first
+	offset := (page - 1) * limit
+	err := r.db.Where("auction_item_id = ?", itemID).Offset(offset).Limit(limit).Order("created_at DESC, id DESC").Find(&bids).Error
+	return bids, total, err
+}
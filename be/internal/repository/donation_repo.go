@@ -1,11 +1,25 @@
 package repository
 
 import (
+	"errors"
+	"slices"
+
 	"milestone3/be/internal/entity"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// ErrDonationStatusChanged is returned when the donation left the expected status before the transition
+var ErrDonationStatusChanged = errors.New("donation status changed")
+
+// ErrDonationItemInAuction is returned when the auction item of a donation verified for auction is
+// scheduled, running or sold, so the donation can no longer be given away instead
+var ErrDonationItemInAuction = errors.New("auction item of the donation is in an auction")
+
+// donationReleasableItemStatuses are the auction item statuses that let its donation be given away instead
+var donationReleasableItemStatuses = []string{"draft", "unsold"}
+
 type DonationRepo interface {
 	CreateDonation(donation entity.Donation) error
 	GetDonationByID(id uint) (entity.Donation, error)
@@ -16,7 +30,16 @@ type DonationRepo interface {
 	GetAllDonations(page, limit int) ([]entity.Donation, int64, error)
 	GetDonationsByUserID(userID uint, page, limit int) ([]entity.Donation, int64, error)
 
-	PatchDonation(donation entity.Donation) error
+	AddDonationPhotos(photos []entity.DonationPhoto) error
+	// DeleteDonationPhoto deletes a photo of the donation and returns it, gorm.ErrRecordNotFound when the donation has no such photo
+	DeleteDonationPhoto(donationID uint, photoID uint) (entity.DonationPhoto, error)
+	// IsObjectReferenced tells whether a donation or auction item photo still points at the stored object
+	IsObjectReferenced(objectName string) (bool, error)
+
+	// TransitionDonation moves the donation from history.FromStatus to history.ToStatus and records the history.
+	// draft is the auction item created along when the donation is verified for auction, nil otherwise
+	TransitionDonation(history entity.DonationStatusHistory, draft *entity.AuctionItem) error
+	GetDonationHistory(donationID uint) ([]entity.DonationStatusHistory, error)
 	CreateFinalDonation(donationID uint) error
 }
 
@@ -91,24 +114,82 @@ func (r *donationRepo) UpdateDonation(donation entity.Donation) error {
 }
 
 func (r *donationRepo) DeleteDonation(id uint) error {
-	return r.db.Delete(&entity.Donation{}, id).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// the photos reference the donation without a cascade
+		if err := tx.Where("donation_id = ?", id).Delete(&entity.DonationPhoto{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&entity.Donation{}, id).Error
+	})
+}
+
+func (r *donationRepo) AddDonationPhotos(photos []entity.DonationPhoto) error {
+	return r.db.Create(&photos).Error
+}
+
+func (r *donationRepo) DeleteDonationPhoto(donationID uint, photoID uint) (entity.DonationPhoto, error) {
+	var photo entity.DonationPhoto
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("id = ? AND donation_id = ?", photoID, donationID).First(&photo).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&photo).Error
+	})
+	return photo, err
+}
+
+func (r *donationRepo) IsObjectReferenced(objectName string) (bool, error) {
+	var count int64
+	if err := r.db.Model(&entity.DonationPhoto{}).
+		Where("url = ? OR display_url = ? OR thumbnail_url = ?", objectName, objectName, objectName).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	if count > 0 {
+		return true, nil
+	}
+
+	// drafted auction items share the photos of their donation
+	if err := r.db.Model(&entity.AuctionItemPhoto{}).
+		Where("private AND (url = ? OR thumbnail_url = ?)", objectName, objectName).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
 }
 
-func (r *donationRepo) PatchDonation(donation entity.Donation) error {
+func (r *donationRepo) TransitionDonation(history entity.DonationStatusHistory, draft *entity.AuctionItem) error {
 	return r.db.Transaction(func(tx *gorm.DB) error {
-		// Update donation status
-		if err := tx.Model(&entity.Donation{}).Where("id = ?", donation.ID).Updates(donation).Error; err != nil {
+		updates := map[string]interface{}{"status": history.ToStatus}
+		if history.ToStatus == entity.StatusRejected {
+			updates["rejection_reason"] = history.Reason
+		}
+
+		// only move from the status the caller checked, a concurrent transition wins
+		res := tx.Model(&entity.Donation{}).
+			Where("id = ? AND status = ?", history.DonationID, history.FromStatus).
+			Updates(updates)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrDonationStatusChanged
+		}
+
+		if err := tx.Create(&history).Error; err != nil {
 			return err
 		}
 
 		// If status is verified_for_donation, create final_donation entry
-		if donation.Status == entity.StatusVerifiedForDonation {
+		if history.ToStatus == entity.StatusVerifiedForDonation {
 			// Check if already exists
 			var count int64
-			tx.Model(&entity.FinalDonation{}).Where("donation_id = ?", donation.ID).Count(&count)
+			if err := tx.Model(&entity.FinalDonation{}).Where("donation_id = ?", history.DonationID).Count(&count).Error; err != nil {
+				return err
+			}
 			if count == 0 {
 				finalDonation := entity.FinalDonation{
-					DonationID: donation.ID,
+					DonationID: history.DonationID,
 				}
 				if err := tx.Create(&finalDonation).Error; err != nil {
 					return err
@@ -116,10 +197,48 @@ func (r *donationRepo) PatchDonation(donation entity.Donation) error {
 			}
 		}
 
+		// If a draft is given, create the auction item of the donation
+		if draft != nil {
+			var count int64
+			if err := tx.Model(&entity.AuctionItem{}).Where("donation_id = ?", history.DonationID).Count(&count).Error; err != nil {
+				return err
+			}
+			if count == 0 {
+				if err := tx.Create(draft).Error; err != nil {
+					return err
+				}
+			}
+		}
+
+		// the donation is given away instead only while its item is a draft nobody scheduled yet, which
+		// goes away, or ended unsold, which stays with its bids. the lock keeps it from being scheduled meanwhile
+		if history.FromStatus == entity.StatusVerifiedForAuction && history.ToStatus == entity.StatusVerifiedForDonation {
+			var items []entity.AuctionItem
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("donation_id = ?", history.DonationID).Find(&items).Error; err != nil {
+				return err
+			}
+			for _, item := range items {
+				if !slices.Contains(donationReleasableItemStatuses, item.Status) {
+					return ErrDonationItemInAuction
+				}
+				if item.Status == "draft" {
+					if err := tx.Delete(&entity.AuctionItem{}, item.ID).Error; err != nil {
+						return err
+					}
+				}
+			}
+		}
+
 		return nil
 	})
 }
 
+func (r *donationRepo) GetDonationHistory(donationID uint) ([]entity.DonationStatusHistory, error) {
+	var history []entity.DonationStatusHistory
+	err := r.db.Where("donation_id = ?", donationID).Order("created_at ASC, id ASC").Find(&history).Error
+	return history, err
+}
+
 func (r *donationRepo) CreateFinalDonation(donationID uint) error {
 	return r.db.Create(&entity.FinalDonation{DonationID: donationID}).Error
 }
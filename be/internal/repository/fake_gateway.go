@@ -0,0 +1,197 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"milestone3/be/internal/dto"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var ErrFakeRefundExceedsCharge = errors.New("fake gateway: refund exceeds the charged amount")
+
+// FakeOutcome scripts how the fake gateway treats one charge. Statuses are the midtrans
+// transaction statuses reported by Status in turn, the last one repeats
+type FakeOutcome struct {
+	ChargeErr error
+	Statuses  []string
+	RefundErr error
+}
+
+// FakeGateway is an in-process payment gateway for offline runs and integration tests. every
+// charge takes the next scripted outcome, without one the charge settles on the first status check.
+// its notifications have the midtrans shape and signature, so the webhook runs unchanged
+type FakeGateway struct {
+	mu        sync.Mutex
+	serverKey string
+	script    []FakeOutcome
+	orders    map[string]*fakeOrder
+}
+
+type fakeOrder struct {
+	charge   dto.ChargeRequest
+	outcome  FakeOutcome
+	checks   int
+	refunded float64
+}
+
+func NewFakeGateway(serverKey string) *FakeGateway {
+	return &FakeGateway{serverKey: serverKey, orders: map[string]*fakeOrder{}}
+}
+
+// Script queues outcomes for the next charges, one outcome per charge
+func (g *FakeGateway) Script(outcomes ...FakeOutcome) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.script = append(g.script, outcomes...)
+}
+
+func (g *FakeGateway) Charge(req dto.ChargeRequest) (res dto.PaymentResponse, err error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	outcome := FakeOutcome{Statuses: []string{"settlement"}}
+	if len(g.script) > 0 {
+		outcome = g.script[0]
+		g.script = g.script[1:]
+	}
+	if outcome.ChargeErr != nil {
+		return dto.PaymentResponse{}, outcome.ChargeErr
+	}
+	if len(outcome.Statuses) == 0 {
+		outcome.Statuses = []string{"pending"}
+	}
+
+	res = dto.PaymentResponse{
+		OrderId:       req.OrderId,
+		TransactionId: "FAKE-" + req.OrderId,
+		PaymentMethod: req.PaymentMethod,
+		ExpiryTime:    time.Now().Add(15 * time.Minute).Format("2006-01-02 15:04:05"),
+	}
+	switch req.PaymentMethod {
+	case dto.PaymentMethodBcaVa, dto.PaymentMethodBniVa, dto.PaymentMethodBriVa, dto.PaymentMethodPermataVa:
+		res.VirtualAccount = &dto.VirtualAccountPayment{
+			Bank:     strings.TrimSuffix(req.PaymentMethod, "_va"),
+			VaNumber: fmt.Sprintf("8808%08d", len(g.orders)+1),
+		}
+	case dto.PaymentMethodGopay, dto.PaymentMethodShopeePay:
+		res.EWallet = &dto.EWalletPayment{DeeplinkUrl: "fake://" + req.PaymentMethod + "/" + req.OrderId}
+	case dto.PaymentMethodQris:
+		res.Qris = &dto.QrisPayment{QrString: "FAKE-QRIS-" + req.OrderId}
+	case dto.PaymentMethodCard:
+		res.TransactionId = ""
+		res.Redirect = &dto.RedirectPayment{Token: "FAKE-SNAP-" + req.OrderId, RedirectUrl: "fake://snap/" + req.OrderId}
+	default:
+		return dto.PaymentResponse{}, ErrUnsupportedPaymentMethod
+	}
+
+	g.orders[req.OrderId] = &fakeOrder{charge: req, outcome: outcome}
+	return res, nil
+}
+
+// Status reports the next scripted status of the order
+func (g *FakeGateway) Status(orderId string) (res dto.GatewayStatus, err error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	order, ok := g.orders[orderId]
+	if !ok {
+		return dto.GatewayStatus{}, ErrTransactionNotFound
+	}
+
+	res = order.status()
+	if order.checks < len(order.outcome.Statuses)-1 {
+		order.checks++
+	}
+	return res, nil
+}
+
+func (g *FakeGateway) Refund(req dto.RefundRequest) (res dto.RefundResponse, err error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	order, ok := g.orders[req.OrderId]
+	if !ok {
+		return dto.RefundResponse{}, ErrTransactionNotFound
+	}
+	if order.outcome.RefundErr != nil {
+		return dto.RefundResponse{}, order.outcome.RefundErr
+	}
+	if order.refunded+req.Amount > order.charge.Amount {
+		return dto.RefundResponse{}, ErrFakeRefundExceedsCharge
+	}
+
+	order.refunded += req.Amount
+	status := "partial_refund"
+	if order.refunded == order.charge.Amount {
+		status = "refund"
+	}
+	return dto.RefundResponse{OrderId: req.OrderId, RefundKey: req.RefundKey, Amount: req.Amount, Status: status}, nil
+}
+
+func (g *FakeGateway) ParseNotification(body []byte) (res dto.GatewayStatus, err error) {
+	return parseMidtransNotification(body, g.serverKey)
+}
+
+// Notification builds the signed notification midtrans would send for the status Status reports next
+func (g *FakeGateway) Notification(orderId string) (dto.MidtransNotification, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	order, ok := g.orders[orderId]
+	if !ok {
+		return dto.MidtransNotification{}, ErrTransactionNotFound
+	}
+
+	status := order.status()
+	notif := dto.MidtransNotification{
+		TransactionTime:   time.Now().Format("2006-01-02 15:04:05"),
+		TransactionStatus: status.TransactionStatus,
+		TransactionId:     status.TransactionId,
+		StatusCode:        fakeStatusCode(status.TransactionStatus),
+		PaymentType:       fakePaymentType(order.charge.PaymentMethod),
+		OrderId:           orderId,
+		GrossAmount:       strconv.FormatFloat(order.charge.Amount, 'f', 2, 64),
+		Currency:          "IDR",
+	}
+	notif.SignatureKey = midtransSignature(notif.OrderId, notif.StatusCode, notif.GrossAmount, g.serverKey)
+	return notif, nil
+}
+
+func (o *fakeOrder) status() dto.GatewayStatus {
+	transactionStatus := o.outcome.Statuses[o.checks]
+	return dto.GatewayStatus{
+		OrderId:           o.charge.OrderId,
+		TransactionId:     "FAKE-" + o.charge.OrderId,
+		TransactionStatus: transactionStatus,
+		Amount:            o.charge.Amount,
+		PaymentStatus:     midtransPaymentStatus(transactionStatus, ""),
+	}
+}
+
+func fakeStatusCode(transactionStatus string) string {
+	switch transactionStatus {
+	case "settlement", "capture", "cancel":
+		return "200"
+	case "pending":
+		return "201"
+	case "deny", "failure":
+		return "202"
+	case "expire":
+		return "407"
+	}
+	return "200"
+}
+
+// fakePaymentType is the midtrans payment_type of a method
+func fakePaymentType(method string) string {
+	switch method {
+	case dto.PaymentMethodBcaVa, dto.PaymentMethodBniVa, dto.PaymentMethodBriVa, dto.PaymentMethodPermataVa:
+		return "bank_transfer"
+	case dto.PaymentMethodCard:
+		return "credit_card"
+	}
+	return method
+}
@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"time"
+
+	"cloud.google.com/go/storage"
+)
+
+type gcsObjectStore struct {
+	client     *storage.Client
+	bucketName string
+	isPublic   bool
+}
+
+func NewGCSObjectStore(client *storage.Client, bucketName string, isPublic bool) ObjectStore {
+	return &gcsObjectStore{
+		client:     client,
+		bucketName: bucketName,
+		isPublic:   isPublic,
+	}
+}
+
+// Upload — handle file upload to GCS
+func (r *gcsObjectStore) Upload(ctx context.Context, file io.Reader, objectName string, contentType string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, 50*time.Second)
+	defer cancel()
+
+	obj := r.client.Bucket(r.bucketName).Object(objectName)
+	writer := obj.NewWriter(ctx)
+	writer.ContentType = contentType
+
+	// Copy file
+	if _, err := io.Copy(writer, file); err != nil {
+		log.Printf("GCS upload failed: %v", err)
+		return "", err
+	}
+
+	// Close writer
+	if err := writer.Close(); err != nil {
+		log.Printf("GCS writer close failed: %v", err)
+		return "", err
+	}
+
+	// PUBLIC bucket → return URL
+	if r.isPublic {
+		url := r.publicURL(objectName)
+		log.Printf("Public file uploaded to: %s", url)
+		return url, nil
+	}
+
+	// PRIVATE bucket → return objectName
+	log.Printf("Private file uploaded as object: %s", objectName)
+	return objectName, nil
+}
+
+// SignedURL — generate signed URL for private objects
+func (r *gcsObjectStore) SignedURL(ctx context.Context, objectName string, expire time.Duration) (string, error) {
+	if r.isPublic {
+		// Public bucket doesn't need signed URL
+		return r.publicURL(objectName), nil
+	}
+
+	// PRIVATE → generate signed URL. the bucket handle finds the signer itself: the key of the
+	// service account credentials, or the IAM signBlob API of the default account on Cloud Run
+	url, err := r.client.Bucket(r.bucketName).SignedURL(objectName, &storage.SignedURLOptions{
+		Scheme:  storage.SigningSchemeV4,
+		Method:  "GET",
+		Expires: time.Now().Add(expire),
+	})
+
+	if err != nil {
+		log.Printf("Failed generating signed URL: %v", err)
+		return "", err
+	}
+
+	return url, nil
+}
+
+// Delete — delete an object, an object already gone is not an error
+func (r *gcsObjectStore) Delete(ctx context.Context, objectName string) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	err := r.client.Bucket(r.bucketName).Object(objectName).Delete(ctx)
+	if err != nil && !errors.Is(err, storage.ErrObjectNotExist) {
+		log.Printf("GCS delete failed: %v", err)
+		return err
+	}
+	return nil
+}
+
+// Stat — read the size and type of an object
+func (r *gcsObjectStore) Stat(ctx context.Context, objectName string) (ObjectInfo, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	attrs, err := r.client.Bucket(r.bucketName).Object(objectName).Attrs(ctx)
+	if err != nil {
+		if errors.Is(err, storage.ErrObjectNotExist) {
+			return ObjectInfo{}, ErrObjectNotFound
+		}
+		return ObjectInfo{}, err
+	}
+
+	return ObjectInfo{
+		Name:        attrs.Name,
+		Size:        attrs.Size,
+		ContentType: attrs.ContentType,
+		UpdatedAt:   attrs.Updated,
+	}, nil
+}
+
+func (r *gcsObjectStore) publicURL(objectName string) string {
+	return "https://storage.googleapis.com/" + r.bucketName + "/" + objectName
+}
@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/storage"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/api/option"
+)
+
+func TestGCSObjectStore_SignedURL(t *testing.T) {
+	ctx := context.Background()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	assert.NoError(t, err)
+	credentials, err := json.Marshal(map[string]string{
+		"type":         "service_account",
+		"client_email": "uploader@project.iam.gserviceaccount.com",
+		"private_key":  string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})),
+	})
+	assert.NoError(t, err)
+
+	client, err := storage.NewClient(ctx, option.WithCredentialsJSON(credentials))
+	assert.NoError(t, err)
+	defer client.Close()
+
+	private := NewGCSObjectStore(client, "private-bucket", false)
+	signed, err := private.SignedURL(ctx, "donations/private/1_a.jpg", 10*time.Minute)
+	assert.NoError(t, err)
+
+	u, err := url.Parse(signed)
+	assert.NoError(t, err)
+	assert.Equal(t, "/private-bucket/donations/private/1_a.jpg", u.Path)
+	query := u.Query()
+	assert.Equal(t, "GOOG4-RSA-SHA256", query.Get("X-Goog-Algorithm"))
+	assert.True(t, strings.HasPrefix(query.Get("X-Goog-Credential"), "uploader@project.iam.gserviceaccount.com/"))
+	expires, err := strconv.Atoi(query.Get("X-Goog-Expires"))
+	assert.NoError(t, err)
+	assert.InDelta(t, 600, expires, 2)
+	assert.NotEmpty(t, query.Get("X-Goog-Signature"))
+
+	public := NewGCSObjectStore(client, "public-bucket", true)
+	plain, err := public.SignedURL(ctx, "articles/1_a.png", 10*time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, "https://storage.googleapis.com/public-bucket/articles/1_a.png", plain)
+}
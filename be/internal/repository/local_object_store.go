@@ -0,0 +1,218 @@
+package repository
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log"
+	"mime"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrInvalidSignature is returned for a download URL that was not signed by the store
+	ErrInvalidSignature = errors.New("invalid signature")
+	// ErrSignatureExpired is returned for a signed download URL past its expiry
+	ErrSignatureExpired = errors.New("signature expired")
+)
+
+// LocalObjectStore keeps objects on the local filesystem, the app serves them itself under
+// /files/<bucket>/<object>. Objects of a private bucket need the signature query of SignedURL
+type LocalObjectStore interface {
+	ObjectStore
+	Bucket() string
+	// Authorize checks the expires and signature query of a download, a public bucket allows any
+	Authorize(objectName string, expires string, signature string) error
+	Open(objectName string) (*os.File, ObjectInfo, error)
+}
+
+type localObjectStore struct {
+	dir        string
+	bucketName string
+	baseURL    string
+	signingKey []byte
+	isPublic   bool
+	now        func() time.Time
+}
+
+// NewLocalObjectStore stores the bucket in dir/bucketName, baseURL is the address the app is reached on
+func NewLocalObjectStore(dir string, bucketName string, baseURL string, signingKey []byte, isPublic bool) LocalObjectStore {
+	return &localObjectStore{
+		dir:        filepath.Join(dir, bucketName),
+		bucketName: bucketName,
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		signingKey: signingKey,
+		isPublic:   isPublic,
+		now:        time.Now,
+	}
+}
+
+func (r *localObjectStore) Bucket() string {
+	return r.bucketName
+}
+
+func (r *localObjectStore) Upload(ctx context.Context, file io.Reader, objectName string, contentType string) (string, error) {
+	fullPath, err := r.resolve(objectName)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(fullPath), 0o755); err != nil {
+		return "", err
+	}
+
+	// write next to the target and rename, a reader never sees half a file
+	tmp, err := os.CreateTemp(filepath.Dir(fullPath), ".upload-*")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, file); err != nil {
+		tmp.Close()
+		log.Printf("local upload failed: %v", err)
+		return "", err
+	}
+	if err := tmp.Close(); err != nil {
+		return "", err
+	}
+	if err := os.Rename(tmp.Name(), fullPath); err != nil {
+		return "", err
+	}
+
+	if r.isPublic {
+		return r.objectURL(objectName), nil
+	}
+	return objectName, nil
+}
+
+func (r *localObjectStore) Delete(ctx context.Context, objectName string) error {
+	fullPath, err := r.resolve(objectName)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(fullPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		log.Printf("local delete failed: %v", err)
+		return err
+	}
+	return nil
+}
+
+func (r *localObjectStore) SignedURL(ctx context.Context, objectName string, expire time.Duration) (string, error) {
+	if _, err := r.resolve(objectName); err != nil {
+		return "", err
+	}
+	if r.isPublic {
+		return r.objectURL(objectName), nil
+	}
+
+	expires := strconv.FormatInt(r.now().Add(expire).Unix(), 10)
+	query := url.Values{}
+	query.Set("expires", expires)
+	query.Set("signature", r.sign(objectName, expires))
+	return r.objectURL(objectName) + "?" + query.Encode(), nil
+}
+
+func (r *localObjectStore) Stat(ctx context.Context, objectName string) (ObjectInfo, error) {
+	fullPath, err := r.resolve(objectName)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	fi, err := os.Stat(fullPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return ObjectInfo{}, ErrObjectNotFound
+		}
+		return ObjectInfo{}, err
+	}
+	if fi.IsDir() {
+		return ObjectInfo{}, ErrObjectNotFound
+	}
+	return r.info(objectName, fi), nil
+}
+
+func (r *localObjectStore) Authorize(objectName string, expires string, signature string) error {
+	if r.isPublic {
+		return nil
+	}
+
+	expiresAt, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	if !hmac.Equal([]byte(signature), []byte(r.sign(objectName, expires))) {
+		return ErrInvalidSignature
+	}
+	if r.now().Unix() > expiresAt {
+		return ErrSignatureExpired
+	}
+	return nil
+}
+
+func (r *localObjectStore) Open(objectName string) (*os.File, ObjectInfo, error) {
+	fullPath, err := r.resolve(objectName)
+	if err != nil {
+		return nil, ObjectInfo{}, err
+	}
+	f, err := os.Open(fullPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, ObjectInfo{}, ErrObjectNotFound
+		}
+		return nil, ObjectInfo{}, err
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, ObjectInfo{}, err
+	}
+	if fi.IsDir() {
+		f.Close()
+		return nil, ObjectInfo{}, ErrObjectNotFound
+	}
+	return f, r.info(objectName, fi), nil
+}
+
+// resolve maps the object name to its file, names reaching outside the bucket directory are refused
+func (r *localObjectStore) resolve(objectName string) (string, error) {
+	cleaned := path.Clean("/" + objectName)
+	if objectName == "" || cleaned == "/" || cleaned != "/"+objectName || strings.Contains(objectName, "\\") {
+		return "", ErrInvalidObjectName
+	}
+	return filepath.Join(r.dir, filepath.FromSlash(cleaned)), nil
+}
+
+func (r *localObjectStore) sign(objectName string, expires string) string {
+	mac := hmac.New(sha256.New, r.signingKey)
+	mac.Write([]byte(r.bucketName + "/" + objectName + "\n" + expires))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (r *localObjectStore) objectURL(objectName string) string {
+	segments := strings.Split(objectName, "/")
+	for i, s := range segments {
+		segments[i] = url.PathEscape(s)
+	}
+	return r.baseURL + "/files/" + r.bucketName + "/" + strings.Join(segments, "/")
+}
+
+func (r *localObjectStore) info(objectName string, fi os.FileInfo) ObjectInfo {
+	contentType := mime.TypeByExtension(path.Ext(objectName))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	return ObjectInfo{
+		Name:        objectName,
+		Size:        fi.Size(),
+		ContentType: contentType,
+		UpdatedAt:   fi.ModTime(),
+	}
+}
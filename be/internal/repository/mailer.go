@@ -0,0 +1,200 @@
+package repository
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"milestone3/be/internal/dto"
+)
+
+// ErrInvalidMailHeader is returned for a recipient or subject that would break out of its header
+var ErrInvalidMailHeader = errors.New("invalid mail header")
+
+// SMTPMailer sends mail through an SMTP server. Port 465 speaks TLS from the start, on any other
+// port the connection is upgraded with STARTTLS when the server offers it. Credentials are only
+// sent over TLS, or to a server on localhost
+type SMTPMailer struct {
+	host     string
+	port     int
+	username string
+	password string
+	from     string
+	timeout  time.Duration
+}
+
+func NewSMTPMailer(host string, port int, username string, password string, from string) *SMTPMailer {
+	return &SMTPMailer{
+		host:     host,
+		port:     port,
+		username: username,
+		password: password,
+		from:     from,
+		timeout:  30 * time.Second,
+	}
+}
+
+func (m *SMTPMailer) Send(msg dto.MailMessage) error {
+	data, err := buildMailMessage(m.from, msg, time.Now())
+	if err != nil {
+		return err
+	}
+
+	addr := net.JoinHostPort(m.host, strconv.Itoa(m.port))
+	dialer := &net.Dialer{Timeout: m.timeout}
+	var conn net.Conn
+	if m.port == 465 {
+		conn, err = tls.DialWithDialer(dialer, "tcp", addr, &tls.Config{ServerName: m.host})
+	} else {
+		conn, err = dialer.Dial("tcp", addr)
+	}
+	if err != nil {
+		log.Printf("SMTP dial failed: %v", err)
+		return err
+	}
+	conn.SetDeadline(time.Now().Add(m.timeout))
+
+	client, err := smtp.NewClient(conn, m.host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok && m.port != 465 {
+		if err := client.StartTLS(&tls.Config{ServerName: m.host}); err != nil {
+			return err
+		}
+	}
+	if m.username != "" {
+		// PlainAuth refuses to send the password over a connection without TLS, unless to localhost
+		if err := client.Auth(smtp.PlainAuth("", m.username, m.password, m.host)); err != nil {
+			log.Printf("SMTP auth failed: %v", err)
+			return err
+		}
+	}
+
+	if err := client.Mail(mailAddress(m.from)); err != nil {
+		return err
+	}
+	if err := client.Rcpt(mailAddress(msg.To)); err != nil {
+		return err
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(data); err != nil {
+		w.Close()
+		return err
+	}
+	if err := w.Close(); err != nil {
+		log.Printf("SMTP send failed: %v", err)
+		return err
+	}
+	return client.Quit()
+}
+
+// LogMailer is the mail sink for development, every email is written to dir as an .eml file
+// and to the log rather than sent
+type LogMailer struct {
+	dir  string
+	from string
+}
+
+func NewLogMailer(dir string, from string) *LogMailer {
+	return &LogMailer{dir: dir, from: from}
+}
+
+func (m *LogMailer) Send(msg dto.MailMessage) error {
+	now := time.Now()
+	data, err := buildMailMessage(m.from, msg, now)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(m.dir, 0o755); err != nil {
+		return err
+	}
+	name := filepath.Join(m.dir, fmt.Sprintf("%d_%s.eml", now.UnixNano(), sanitizeMailFilename(msg.To)))
+	if err := os.WriteFile(name, data, 0o644); err != nil {
+		return err
+	}
+
+	log.Printf("mail to %s %q written to %s\n%s", msg.To, msg.Subject, name, msg.Body)
+	return nil
+}
+
+// buildMailMessage renders msg as an RFC 5322 message, the body is quoted-printable UTF-8
+func buildMailMessage(from string, msg dto.MailMessage, now time.Time) ([]byte, error) {
+	for _, v := range []string{from, msg.To, msg.Subject} {
+		if strings.ContainsAny(v, "\r\n") {
+			return nil, ErrInvalidMailHeader
+		}
+	}
+	if _, err := mail.ParseAddress(from); err != nil {
+		return nil, fmt.Errorf("%w: from %q", ErrInvalidMailHeader, from)
+	}
+	if _, err := mail.ParseAddress(msg.To); err != nil {
+		return nil, fmt.Errorf("%w: to %q", ErrInvalidMailHeader, msg.To)
+	}
+
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return nil, err
+	}
+	domain := "localhost"
+	if at := strings.LastIndex(mailAddress(from), "@"); at >= 0 {
+		domain = mailAddress(from)[at+1:]
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", from)
+	fmt.Fprintf(&buf, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", now.Format(time.RFC1123Z))
+	fmt.Fprintf(&buf, "Message-ID: <%s@%s>\r\n", hex.EncodeToString(id), domain)
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+
+	qp := quotedprintable.NewWriter(&buf)
+	if _, err := qp.Write([]byte(strings.ReplaceAll(strings.ReplaceAll(msg.Body, "\r\n", "\n"), "\n", "\r\n"))); err != nil {
+		return nil, err
+	}
+	if err := qp.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// mailAddress is the bare address of a header value like "Name <user@example.com>"
+func mailAddress(v string) string {
+	if addr, err := mail.ParseAddress(v); err == nil {
+		return addr.Address
+	}
+	return v
+}
+
+func sanitizeMailFilename(v string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '.' || r == '-' || r == '@' {
+			return r
+		}
+		return '_'
+	}, mailAddress(v))
+}
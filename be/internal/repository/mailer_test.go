@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"milestone3/be/internal/dto"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogMailer(t *testing.T) {
+	dir := t.TempDir()
+	mailer := NewLogMailer(dir, "Your Donate Rise <no-reply@example.com>")
+
+	err := mailer.Send(dto.MailMessage{
+		To:      "test@example.com",
+		Subject: "Verify your email",
+		Body:    "Hi,\n\nhttp://localhost:8080/auth/verify-email?token=abc\n",
+	})
+	assert.NoError(t, err)
+
+	files, err := filepath.Glob(filepath.Join(dir, "*_test@example.com.eml"))
+	assert.NoError(t, err)
+	if assert.Len(t, files, 1) {
+		f, err := os.Open(files[0])
+		assert.NoError(t, err)
+		defer f.Close()
+
+		msg, err := mail.ReadMessage(f)
+		assert.NoError(t, err)
+		assert.Equal(t, "test@example.com", msg.Header.Get("To"))
+		assert.Equal(t, "Verify your email", msg.Header.Get("Subject"))
+		assert.Contains(t, msg.Header.Get("Message-ID"), "@example.com>")
+
+		body, err := io.ReadAll(msg.Body)
+		assert.NoError(t, err)
+		// the = of the query is quoted-printable encoded
+		assert.Contains(t, string(body), "verify-email?token=3Dabc")
+	}
+}
+
+func TestBuildMailMessage_RejectsHeaderInjection(t *testing.T) {
+	for _, msg := range []dto.MailMessage{
+		{To: "test@example.com\r\nBcc: victim@example.com", Subject: "hi"},
+		{To: "test@example.com", Subject: "hi\nBcc: victim@example.com"},
+		{To: "not an address", Subject: "hi"},
+	} {
+		_, err := buildMailMessage("no-reply@example.com", msg, time.Now())
+		assert.ErrorIs(t, err, ErrInvalidMailHeader)
+	}
+}
+
+func TestSMTPMailer(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer ln.Close()
+
+	received := make(chan []string, 1)
+	go serveFakeSMTP(ln, received)
+
+	mailer := NewSMTPMailer("127.0.0.1", ln.Addr().(*net.TCPAddr).Port, "", "", "no-reply@example.com")
+
+	err = mailer.Send(dto.MailMessage{To: "test@example.com", Subject: "Reset your password", Body: "reset link"})
+	assert.NoError(t, err)
+
+	commands := <-received
+	assert.Contains(t, commands, "MAIL FROM:<no-reply@example.com>")
+	assert.Contains(t, commands, "RCPT TO:<test@example.com>")
+	assert.Contains(t, commands, "Subject: Reset your password")
+	assert.Contains(t, commands, "reset link")
+}
+
+// serveFakeSMTP answers one SMTP session without extensions and sends back every line it read
+func serveFakeSMTP(ln net.Listener, received chan<- []string) {
+	conn, err := ln.Accept()
+	if err != nil {
+		received <- nil
+		return
+	}
+	defer conn.Close()
+
+	var lines []string
+	r := bufio.NewReader(conn)
+	reply := func(s string) { io.WriteString(conn, s+"\r\n") }
+	reply("220 localhost ESMTP")
+
+	inData := false
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			break
+		}
+		line = strings.TrimRight(line, "\r\n")
+		lines = append(lines, line)
+
+		if inData {
+			if line == "." {
+				inData = false
+				reply("250 queued")
+			}
+			continue
+		}
+		switch {
+		case strings.HasPrefix(line, "EHLO"), strings.HasPrefix(line, "HELO"):
+			reply("250 localhost")
+		case strings.HasPrefix(line, "DATA"):
+			inData = true
+			reply("354 end with .")
+		case strings.HasPrefix(line, "QUIT"):
+			reply("221 bye")
+			received <- lines
+			return
+		default:
+			reply("250 ok")
+		}
+	}
+	received <- lines
+}
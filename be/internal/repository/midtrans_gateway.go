@@ -0,0 +1,259 @@
+package repository
+
+import (
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"milestone3/be/internal/dto"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/midtrans/midtrans-go"
+	"github.com/midtrans/midtrans-go/coreapi"
+	"github.com/midtrans/midtrans-go/snap"
+)
+
+var (
+	ErrInvalidNotification          = errors.New("invalid notification payload")
+	ErrInvalidNotificationSignature = errors.New("invalid notification signature")
+	ErrUnsupportedPaymentMethod     = errors.New("unsupported payment method")
+	// ErrTransactionNotFound is returned when the gateway never received a charge for the order
+	ErrTransactionNotFound = errors.New("transaction not found at the payment gateway")
+)
+
+var midtransVaBanks = map[string]midtrans.Bank{
+	dto.PaymentMethodBcaVa:     midtrans.BankBca,
+	dto.PaymentMethodBniVa:     midtrans.BankBni,
+	dto.PaymentMethodBriVa:     midtrans.BankBri,
+	dto.PaymentMethodPermataVa: midtrans.BankPermata,
+}
+
+// MidtransGateway charges, checks and refunds through the Midtrans Core API, cards go through
+// a Snap redirect so card data never reaches us
+type MidtransGateway struct {
+	client    coreapi.Client
+	snap      snap.Client
+	serverKey string
+}
+
+func NewMidtransGateway(serverKey string, production bool) *MidtransGateway {
+	env := midtrans.Sandbox
+	if production {
+		env = midtrans.Production
+	}
+
+	c := coreapi.Client{}
+	c.New(serverKey, env)
+	s := snap.Client{}
+	s.New(serverKey, env)
+	return &MidtransGateway{client: c, snap: s, serverKey: serverKey}
+}
+
+func (g *MidtransGateway) Charge(req dto.ChargeRequest) (res dto.PaymentResponse, err error) {
+	details := midtrans.TransactionDetails{
+		OrderID:  req.OrderId,
+		GrossAmt: int64(req.Amount),
+	}
+	customer := &midtrans.CustomerDetails{
+		FName: req.CustomerName,
+		Email: req.CustomerEmail,
+	}
+
+	if req.PaymentMethod == dto.PaymentMethodCard {
+		resp, midtransErr := g.snap.CreateTransaction(&snap.Request{
+			TransactionDetails: details,
+			CustomerDetail:     customer,
+			EnabledPayments:    []snap.SnapPaymentType{snap.PaymentTypeCreditCard},
+			CreditCard:         &snap.CreditCardDetails{Secure: true},
+		})
+		if midtransErr != nil {
+			return dto.PaymentResponse{}, midtransErr
+		}
+
+		return dto.PaymentResponse{
+			OrderId:       req.OrderId,
+			PaymentMethod: req.PaymentMethod,
+			Redirect:      &dto.RedirectPayment{Token: resp.Token, RedirectUrl: resp.RedirectURL},
+		}, nil
+	}
+
+	chargeReq := &coreapi.ChargeReq{
+		TransactionDetails: details,
+		CustomerDetails:    customer,
+	}
+	switch req.PaymentMethod {
+	case dto.PaymentMethodBcaVa, dto.PaymentMethodBniVa, dto.PaymentMethodBriVa, dto.PaymentMethodPermataVa:
+		chargeReq.PaymentType = coreapi.PaymentTypeBankTransfer
+		chargeReq.BankTransfer = &coreapi.BankTransferDetails{Bank: midtransVaBanks[req.PaymentMethod]}
+	case dto.PaymentMethodGopay:
+		chargeReq.PaymentType = coreapi.PaymentTypeGopay
+		chargeReq.Gopay = &coreapi.GopayDetails{}
+	case dto.PaymentMethodShopeePay:
+		chargeReq.PaymentType = coreapi.PaymentTypeShopeepay
+		chargeReq.ShopeePay = &coreapi.ShopeePayDetails{}
+	case dto.PaymentMethodQris:
+		chargeReq.PaymentType = coreapi.PaymentTypeQris
+	default:
+		return dto.PaymentResponse{}, ErrUnsupportedPaymentMethod
+	}
+
+	resp, midtransErr := g.client.ChargeTransaction(chargeReq)
+	if midtransErr != nil {
+		return dto.PaymentResponse{}, midtransErr
+	}
+
+	return midtransChargeResponse(req.PaymentMethod, resp), nil
+}
+
+// midtransChargeResponse picks the instructions of the method out of the charge response, actions
+// are looked up by name since their order differs per payment type
+func midtransChargeResponse(method string, resp *coreapi.ChargeResponse) dto.PaymentResponse {
+	res := dto.PaymentResponse{
+		OrderId:       resp.OrderID,
+		TransactionId: resp.TransactionID,
+		PaymentMethod: method,
+		ExpiryTime:    resp.ExpiryTime,
+	}
+
+	switch method {
+	case dto.PaymentMethodBcaVa, dto.PaymentMethodBniVa, dto.PaymentMethodBriVa, dto.PaymentMethodPermataVa:
+		va := &dto.VirtualAccountPayment{Bank: string(midtransVaBanks[method]), VaNumber: resp.PermataVaNumber}
+		if len(resp.VaNumbers) > 0 {
+			va.Bank = resp.VaNumbers[0].Bank
+			va.VaNumber = resp.VaNumbers[0].VANumber
+		}
+		res.VirtualAccount = va
+	case dto.PaymentMethodGopay, dto.PaymentMethodShopeePay:
+		res.EWallet = &dto.EWalletPayment{
+			DeeplinkUrl: midtransActionURL(resp.Actions, "deeplink-redirect"),
+			QrCodeUrl:   midtransActionURL(resp.Actions, "generate-qr-code"),
+		}
+	case dto.PaymentMethodQris:
+		res.Qris = &dto.QrisPayment{
+			QrString:  resp.QRString,
+			QrCodeUrl: midtransActionURL(resp.Actions, "generate-qr-code"),
+		}
+	}
+	return res
+}
+
+func midtransActionURL(actions []coreapi.Action, name string) string {
+	for _, action := range actions {
+		if action.Name == name {
+			return action.URL
+		}
+	}
+	return ""
+}
+
+func (g *MidtransGateway) Status(orderId string) (res dto.GatewayStatus, err error) {
+	resp, midtransErr := g.client.CheckTransaction(orderId)
+	if midtransErr != nil {
+		if midtransErr.StatusCode == http.StatusNotFound {
+			return dto.GatewayStatus{}, ErrTransactionNotFound
+		}
+		return dto.GatewayStatus{}, midtransErr
+	}
+
+	amount, err := strconv.ParseFloat(resp.GrossAmount, 64)
+	if err != nil {
+		return dto.GatewayStatus{}, err
+	}
+
+	return dto.GatewayStatus{
+		OrderId:           resp.OrderID,
+		TransactionId:     resp.TransactionID,
+		TransactionStatus: resp.TransactionStatus,
+		FraudStatus:       resp.FraudStatus,
+		Amount:            amount,
+		PaymentStatus:     midtransPaymentStatus(resp.TransactionStatus, resp.FraudStatus),
+	}, nil
+}
+
+func (g *MidtransGateway) Refund(req dto.RefundRequest) (res dto.RefundResponse, err error) {
+	resp, midtransErr := g.client.RefundTransaction(req.OrderId, &coreapi.RefundReq{
+		RefundKey: req.RefundKey,
+		Amount:    int64(req.Amount),
+		Reason:    req.Reason,
+	})
+	if midtransErr != nil {
+		return dto.RefundResponse{}, midtransErr
+	}
+
+	amount, err := strconv.ParseFloat(resp.RefundAmount, 64)
+	if err != nil {
+		return dto.RefundResponse{}, err
+	}
+
+	return dto.RefundResponse{
+		OrderId:   resp.OrderID,
+		RefundKey: resp.RefundKey,
+		Amount:    amount,
+		Status:    resp.TransactionStatus,
+	}, nil
+}
+
+// ParseNotification decodes a Midtrans HTTP notification, the signature proves it comes from midtrans
+func (g *MidtransGateway) ParseNotification(body []byte) (res dto.GatewayStatus, err error) {
+	return parseMidtransNotification(body, g.serverKey)
+}
+
+func parseMidtransNotification(body []byte, serverKey string) (dto.GatewayStatus, error) {
+	var notif dto.MidtransNotification
+	if err := json.Unmarshal(body, &notif); err != nil || notif.OrderId == "" {
+		return dto.GatewayStatus{}, ErrInvalidNotification
+	}
+
+	if !verifyMidtransSignature(notif, serverKey) {
+		return dto.GatewayStatus{}, ErrInvalidNotificationSignature
+	}
+
+	amount, err := strconv.ParseFloat(notif.GrossAmount, 64)
+	if err != nil {
+		return dto.GatewayStatus{}, ErrInvalidNotification
+	}
+
+	return dto.GatewayStatus{
+		OrderId:           notif.OrderId,
+		TransactionId:     notif.TransactionId,
+		TransactionStatus: notif.TransactionStatus,
+		FraudStatus:       notif.FraudStatus,
+		Amount:            amount,
+		PaymentStatus:     midtransPaymentStatus(notif.TransactionStatus, notif.FraudStatus),
+	}, nil
+}
+
+// midtransSignature is sha512(order_id + status_code + gross_amount + server key)
+func midtransSignature(orderId, statusCode, grossAmount, serverKey string) string {
+	sum := sha512.Sum512([]byte(orderId + statusCode + grossAmount + serverKey))
+	return hex.EncodeToString(sum[:])
+}
+
+func verifyMidtransSignature(notif dto.MidtransNotification, serverKey string) bool {
+	if serverKey == "" || notif.SignatureKey == "" {
+		return false
+	}
+
+	expected := midtransSignature(notif.OrderId, notif.StatusCode, notif.GrossAmount, serverKey)
+	return subtle.ConstantTimeCompare([]byte(expected), []byte(strings.ToLower(notif.SignatureKey))) == 1
+}
+
+// midtransPaymentStatus maps a midtrans transaction status to ours, "" when nothing changes yet
+func midtransPaymentStatus(transactionStatus, fraudStatus string) string {
+	switch transactionStatus {
+	case "capture":
+		// a challenged card payment waits for the merchant review
+		if fraudStatus == "accept" || fraudStatus == "" {
+			return "paid"
+		}
+		return ""
+	case "settlement":
+		return "paid"
+	case "deny", "cancel", "expire", "failure":
+		return "failed"
+	}
+	return ""
+}
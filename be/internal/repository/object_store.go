@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+)
+
+// ErrObjectNotFound is returned by Stat when the bucket has no such object
+var ErrObjectNotFound = errors.New("object not found")
+
+// ErrInvalidObjectName is returned for object names that would escape the bucket
+var ErrInvalidObjectName = errors.New("invalid object name")
+
+// ObjectStore keeps uploaded files in one bucket. Upload returns the URL of the object for a public
+// bucket and the object name for a private one, a private object is only readable through SignedURL
+type ObjectStore interface {
+	Upload(ctx context.Context, file io.Reader, objectName string, contentType string) (string, error)
+	// Delete removes the object, an object already gone is not an error
+	Delete(ctx context.Context, objectName string) error
+	SignedURL(ctx context.Context, objectName string, expire time.Duration) (string, error)
+	Stat(ctx context.Context, objectName string) (ObjectInfo, error)
+}
+
+type ObjectInfo struct {
+	Name        string
+	Size        int64
+	ContentType string
+	UpdatedAt   time.Time
+}
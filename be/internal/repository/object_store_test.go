@@ -0,0 +1,196 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocalObjectStore(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	key := []byte("signing-key")
+
+	public := NewLocalObjectStore(dir, "public", "http://localhost:8080/", key, true)
+	private := NewLocalObjectStore(dir, "private", "http://localhost:8080", key, false)
+
+	t.Run("public upload returns the served url", func(t *testing.T) {
+		url, err := public.Upload(ctx, strings.NewReader("img"), "articles/1_a b.png", "image/png")
+		assert.NoError(t, err)
+		assert.Equal(t, "http://localhost:8080/files/public/articles/1_a%20b.png", url)
+		assert.NoError(t, public.Authorize("articles/1_a b.png", "", ""))
+	})
+
+	t.Run("private object needs a valid signature", func(t *testing.T) {
+		name, err := private.Upload(ctx, strings.NewReader("photo"), "donations/private/1_a.jpg", "image/jpeg")
+		assert.NoError(t, err)
+		assert.Equal(t, "donations/private/1_a.jpg", name)
+
+		signed, err := private.SignedURL(ctx, name, 10*time.Minute)
+		assert.NoError(t, err)
+		u, err := url.Parse(signed)
+		assert.NoError(t, err)
+		assert.Equal(t, "/files/private/donations/private/1_a.jpg", u.Path)
+
+		expires, signature := u.Query().Get("expires"), u.Query().Get("signature")
+		assert.NoError(t, private.Authorize(name, expires, signature))
+		assert.ErrorIs(t, private.Authorize("donations/private/2_b.jpg", expires, signature), ErrInvalidSignature)
+		assert.ErrorIs(t, private.Authorize(name, "9999999999", signature), ErrInvalidSignature)
+		assert.ErrorIs(t, private.Authorize(name, "", ""), ErrInvalidSignature)
+
+		// the public bucket uses the same key, its signature must not open private objects
+		assert.ErrorIs(t, NewLocalObjectStore(dir, "public", "", key, false).Authorize(name, expires, signature), ErrInvalidSignature)
+
+		f, info, err := private.Open(name)
+		assert.NoError(t, err)
+		defer f.Close()
+		content, _ := io.ReadAll(f)
+		assert.Equal(t, "photo", string(content))
+		assert.Equal(t, "image/jpeg", info.ContentType)
+		assert.Equal(t, int64(5), info.Size)
+	})
+
+	t.Run("expired signature", func(t *testing.T) {
+		store := private.(*localObjectStore)
+		signed, err := store.SignedURL(ctx, "donations/private/1_a.jpg", time.Minute)
+		assert.NoError(t, err)
+		u, _ := url.Parse(signed)
+
+		store.now = func() time.Time { return time.Now().Add(2 * time.Minute) }
+		defer func() { store.now = time.Now }()
+		assert.ErrorIs(t, store.Authorize("donations/private/1_a.jpg", u.Query().Get("expires"), u.Query().Get("signature")), ErrSignatureExpired)
+	})
+
+	t.Run("stat and delete", func(t *testing.T) {
+		info, err := private.Stat(ctx, "donations/private/1_a.jpg")
+		assert.NoError(t, err)
+		assert.Equal(t, int64(5), info.Size)
+
+		assert.NoError(t, private.Delete(ctx, "donations/private/1_a.jpg"))
+		assert.NoError(t, private.Delete(ctx, "donations/private/1_a.jpg"))
+		_, err = private.Stat(ctx, "donations/private/1_a.jpg")
+		assert.ErrorIs(t, err, ErrObjectNotFound)
+	})
+
+	t.Run("names outside the bucket are refused", func(t *testing.T) {
+		for _, name := range []string{"", "../public/articles/1_a b.png", "donations/../../x", "/abs", "a\\b", "donations/"} {
+			_, err := private.Upload(ctx, strings.NewReader("x"), name, "")
+			assert.ErrorIs(t, err, ErrInvalidObjectName, name)
+			_, _, err = private.Open(name)
+			assert.ErrorIs(t, err, ErrInvalidObjectName, name)
+		}
+	})
+}
+
+func TestS3ObjectStore_SignedURL(t *testing.T) {
+	ctx := context.Background()
+	cfg := S3Config{Endpoint: "http://localhost:9000", Region: "us-east-1", AccessKey: "minio", SecretKey: "minio-secret"}
+
+	private, err := NewS3ObjectStore(cfg, "private", false)
+	assert.NoError(t, err)
+	signed, err := private.SignedURL(ctx, "donations/private/1_a b.jpg", 10*time.Minute)
+	assert.NoError(t, err)
+
+	u, err := url.Parse(signed)
+	assert.NoError(t, err)
+	assert.Equal(t, "private.localhost:9000", u.Host)
+	assert.Equal(t, "/donations/private/1_a%20b.jpg", u.EscapedPath())
+	query := u.Query()
+	assert.Equal(t, "AWS4-HMAC-SHA256", query.Get("X-Amz-Algorithm"))
+	assert.True(t, strings.HasPrefix(query.Get("X-Amz-Credential"), "minio/"))
+	assert.True(t, strings.HasSuffix(query.Get("X-Amz-Credential"), "/us-east-1/s3/aws4_request"))
+	assert.Equal(t, "600", query.Get("X-Amz-Expires"))
+	assert.NotEmpty(t, query.Get("X-Amz-Signature"))
+
+	// a public object is read without a signature
+	cfg.PathStyle = true
+	public, err := NewS3ObjectStore(cfg, "public", true)
+	assert.NoError(t, err)
+	plain, err := public.SignedURL(ctx, "articles/1_a b.png", 10*time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, "http://localhost:9000/public/articles/1_a%20b.png", plain)
+
+	_, err = NewS3ObjectStore(S3Config{Endpoint: "http://localhost:9000/prefix"}, "public", true)
+	assert.Error(t, err)
+}
+
+func TestS3ObjectStore_Requests(t *testing.T) {
+	objects := map[string][]byte{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.True(t, strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=minio/"))
+		assert.NotEmpty(t, r.Header.Get("X-Amz-Content-Sha256"))
+
+		switch r.Method {
+		case http.MethodPut:
+			body, _ := io.ReadAll(r.Body)
+			if r.Header.Get("X-Amz-Content-Sha256") == "STREAMING-AWS4-HMAC-SHA256-PAYLOAD" {
+				body = decodeAWSChunked(t, body)
+			}
+			assert.Equal(t, "image/png", r.Header.Get("Content-Type"))
+			objects[r.URL.Path] = body
+			w.Header().Set("ETag", `"etag"`)
+		case http.MethodHead:
+			if _, ok := objects[r.URL.Path]; !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Header().Set("Content-Type", "image/png")
+			w.Header().Set("Content-Length", "3")
+			w.Header().Set("ETag", `"etag"`)
+			w.Header().Set("Last-Modified", "Wed, 15 Oct 2026 07:28:00 GMT")
+		case http.MethodDelete:
+			delete(objects, r.URL.Path)
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	store, err := NewS3ObjectStore(S3Config{Endpoint: server.URL, Region: "us-east-1", AccessKey: "minio", SecretKey: "minio-secret", PathStyle: true}, "public", true)
+	assert.NoError(t, err)
+
+	url, err := store.Upload(ctx, bytes.NewReader([]byte("img")), "articles/1_a b.png", "image/png")
+	assert.NoError(t, err)
+	assert.Equal(t, server.URL+"/public/articles/1_a%20b.png", url)
+	assert.Equal(t, []byte("img"), objects["/public/articles/1_a b.png"])
+
+	info, err := store.Stat(ctx, "articles/1_a b.png")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), info.Size)
+	assert.Equal(t, "image/png", info.ContentType)
+	assert.Equal(t, 2026, info.UpdatedAt.Year())
+
+	assert.NoError(t, store.Delete(ctx, "articles/1_a b.png"))
+	_, err = store.Stat(ctx, "articles/1_a b.png")
+	assert.ErrorIs(t, err, ErrObjectNotFound)
+}
+
+// decodeAWSChunked joins the data of a streaming signed body, chunks of "<hex size>;chunk-signature=<sig>\r\n<data>\r\n"
+func decodeAWSChunked(t *testing.T, body []byte) []byte {
+	var data []byte
+	for {
+		header, rest, ok := bytes.Cut(body, []byte("\r\n"))
+		if !ok {
+			t.Fatalf("invalid aws-chunked body %q", body)
+		}
+		sizeHex, _, _ := bytes.Cut(header, []byte(";"))
+		size, err := strconv.ParseInt(string(sizeHex), 16, 64)
+		if err != nil {
+			t.Fatalf("invalid aws-chunked size %q", sizeHex)
+		}
+		if size == 0 {
+			return data
+		}
+		data = append(data, rest[:size]...)
+		body = rest[size+2:]
+	}
+}
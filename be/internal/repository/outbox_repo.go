@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"time"
+
+	"milestone3/be/internal/entity"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type OutboxRepository interface {
+	ClaimPending(limit int, lease time.Duration) ([]entity.OutboxEvent, error)
+	MarkProcessed(id int64) error
+	MarkRetry(id int64, reason string, availableAt time.Time) error
+	MarkFailed(id int64, reason string) error
+}
+
+type outboxRepository struct {
+	db *gorm.DB
+}
+
+func NewOutboxRepository(db *gorm.DB) OutboxRepository {
+	return &outboxRepository{db: db}
+}
+
+// ClaimPending locks due events for the lease so concurrent workers skip them, an event whose
+// worker died before marking it becomes claimable again once the lease runs out
+func (r *outboxRepository) ClaimPending(limit int, lease time.Duration) ([]entity.OutboxEvent, error) {
+	var events []entity.OutboxEvent
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now().UTC()
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND available_at <= ?", entity.OutboxStatusPending, now).
+			Where("locked_until IS NULL OR locked_until < ?", now).
+			Order("id ASC").
+			Limit(limit).
+			Find(&events).Error
+		if err != nil || len(events) == 0 {
+			return err
+		}
+
+		ids := make([]int64, 0, len(events))
+		for i := range events {
+			ids = append(ids, events[i].ID)
+			events[i].Attempts++
+		}
+
+		return tx.Model(&entity.OutboxEvent{}).
+			Where("id IN ?", ids).
+			Updates(map[string]interface{}{
+				"locked_until": now.Add(lease),
+				"attempts":     gorm.Expr("attempts + 1"),
+			}).Error
+	})
+	return events, err
+}
+
+func (r *outboxRepository) MarkProcessed(id int64) error {
+	return r.db.Model(&entity.OutboxEvent{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":       entity.OutboxStatusProcessed,
+			"processed_at": time.Now().UTC(),
+			"locked_until": nil,
+			"last_error":   "",
+		}).Error
+}
+
+// MarkRetry releases the event and makes it due again at availableAt
+func (r *outboxRepository) MarkRetry(id int64, reason string, availableAt time.Time) error {
+	return r.db.Model(&entity.OutboxEvent{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"available_at": availableAt.UTC(),
+			"locked_until": nil,
+			"last_error":   reason,
+		}).Error
+}
+
+// MarkFailed parks the event for manual inspection, it is not retried anymore
+func (r *outboxRepository) MarkFailed(id int64, reason string) error {
+	return r.db.Model(&entity.OutboxEvent{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":       entity.OutboxStatusFailed,
+			"locked_until": nil,
+			"last_error":   reason,
+		}).Error
+}
@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"milestone3/be/internal/entity"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrPaymentNotPending is returned when a payment was settled between reading and defaulting it
+var ErrPaymentNotPending = errors.New("payment is no longer pending")
+
+type PaymentDeadlineRepository interface {
+	GetOverdue(now time.Time, limit int) ([]entity.Payment, error)
+	GetDefaultedUserIDs(itemID int64) ([]int64, error)
+	GetNextBidder(itemID int64, excludeUserIDs []int64) (*entity.Bid, error)
+	Default(paymentID int64, relistItemID *int64, event *entity.OutboxEvent) error
+}
+
+type paymentDeadlineRepository struct {
+	db *gorm.DB
+}
+
+func NewPaymentDeadlineRepository(db *gorm.DB) PaymentDeadlineRepository {
+	return &paymentDeadlineRepository{db: db}
+}
+
+// overdueBlockingStatuses are the statuses of a charge attempt that keep its winner payment from
+// defaulting: paid through it, even once it is being refunded, or still waiting on the gateway
+var overdueBlockingStatuses = append([]string{"pending"}, winnerSettledStatuses...)
+
+// GetOverdue returns pending winner payments past their deadline. a winner who paid the item through
+// a later attempt is not overdue, nor one whose attempt is still pending at the gateway: it settles
+// or fails through the notification or the reconciliation, a failure defaults it on the next run
+func (r *paymentDeadlineRepository) GetOverdue(now time.Time, limit int) ([]entity.Payment, error) {
+	var payments []entity.Payment
+	err := r.db.
+		Where("status = ? AND due_at IS NOT NULL AND due_at < ?", "pending", now.UTC()).
+		Where("NOT EXISTS (?)", r.db.Table("payments AS attempt").
+			Select("1").
+			Where("attempt.auction_item_id = payments.auction_item_id AND attempt.user_id = payments.user_id AND attempt.id > payments.id").
+			Where("attempt.due_at IS NULL AND attempt.status IN ?", overdueBlockingStatuses)).
+		Order("due_at ASC").
+		Limit(limit).
+		Find(&payments).Error
+	return payments, err
+}
+
+// roundStart selects when the item's current auction round began, the start of the session it is
+// listed in. bids and payments from before it belong to a round that ended in a relist
+func (r *paymentDeadlineRepository) roundStart(itemID int64) *gorm.DB {
+	return r.db.Table("auction_items").
+		Select("auction_sessions.start_time").
+		Joins("JOIN auction_sessions ON auction_sessions.id = auction_items.session_id").
+		Where("auction_items.id = ?", itemID)
+}
+
+// GetDefaultedUserIDs returns who defaulted on the item in its current round. a winner payment is
+// due after the round began, those of earlier rounds fell due before the relist
+func (r *paymentDeadlineRepository) GetDefaultedUserIDs(itemID int64) ([]int64, error) {
+	var userIDs []int64
+	err := r.db.Model(&entity.Payment{}).
+		Where("auction_item_id = ? AND status = ?", itemID, "defaulted").
+		Where("due_at >= (?)", r.roundStart(itemID)).
+		Distinct().
+		Pluck("user_id", &userIDs).Error
+	return userIDs, err
+}
+
+// GetNextBidder returns the highest bid of the current round by a bidder not excluded, nil when
+// nobody is left
+func (r *paymentDeadlineRepository) GetNextBidder(itemID int64, excludeUserIDs []int64) (*entity.Bid, error) {
+	var bid entity.Bid
+	query := r.db.Where("auction_item_id = ? AND created_at >= (?)", itemID, r.roundStart(itemID))
+	if len(excludeUserIDs) > 0 {
+		query = query.Where("user_id NOT IN ?", excludeUserIDs)
+	}
+
+	err := query.Order("amount DESC, id ASC").First(&bid).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &bid, nil
+}
+
+// Default marks the payment defaulted, optionally puts the item back to scheduled and writes
+// the outbox event announcing what happens next, all in one transaction. only a finished item
+// is relisted, one sold or already reopened by a refund is left alone
+func (r *paymentDeadlineRepository) Default(paymentID int64, relistItemID *int64, event *entity.OutboxEvent) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&entity.Payment{}).
+			Where("id = ? AND status = ?", paymentID, "pending").
+			Update("status", "defaulted")
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrPaymentNotPending
+		}
+
+		if relistItemID != nil {
+			err := tx.Model(&entity.AuctionItem{}).
+				Where("id = ? AND status = ?", *relistItemID, "finished").
+				Update("status", "scheduled").Error
+			if err != nil {
+				return err
+			}
+		}
+
+		return tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "dedup_key"}},
+			DoNothing: true,
+		}).Create(event).Error
+	})
+}
@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// dryRunDB builds the statements without a database and hands each query's SQL to capture
+func dryRunDB(t *testing.T, capture *string) *gorm.DB {
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=127.0.0.1 dbname=dry sslmode=disable"}), &gorm.Config{
+		DryRun:               true,
+		DisableAutomaticPing: true,
+	})
+	assert.NoError(t, err)
+	err = db.Callback().Query().After("gorm:query").Register("test:capture", func(tx *gorm.DB) {
+		*capture = tx.Statement.SQL.String()
+	})
+	assert.NoError(t, err)
+	return db
+}
+
+func TestPaymentDeadlineRepository_ScopesToCurrentRound(t *testing.T) {
+	var sql string
+	repo := NewPaymentDeadlineRepository(dryRunDB(t, &sql))
+	roundStart := `(SELECT auction_sessions.start_time FROM "auction_items" JOIN auction_sessions ON auction_sessions.id = auction_items.session_id WHERE auction_items.id = $`
+
+	// a bid placed before the relist is no second chance offer
+	_, err := repo.GetNextBidder(5, []int64{2})
+	assert.NoError(t, err)
+	assert.Contains(t, sql, "created_at >= "+roundStart)
+
+	// a default of an earlier round does not count against this one
+	_, err = repo.GetDefaultedUserIDs(5)
+	assert.NoError(t, err)
+	assert.Contains(t, sql, "due_at >= "+roundStart)
+}
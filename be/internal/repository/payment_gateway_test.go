@@ -0,0 +1,164 @@
+package repository
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"milestone3/be/internal/dto"
+
+	"github.com/midtrans/midtrans-go/coreapi"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMidtransChargeResponse(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		resp   coreapi.ChargeResponse
+		want   dto.PaymentResponse
+	}{
+		{
+			name:   "bca virtual account",
+			method: dto.PaymentMethodBcaVa,
+			resp:   coreapi.ChargeResponse{OrderID: "YDR-1", TransactionID: "trx", VaNumbers: []coreapi.VANumber{{Bank: "bca", VANumber: "12345678901"}}},
+			want: dto.PaymentResponse{OrderId: "YDR-1", TransactionId: "trx", PaymentMethod: dto.PaymentMethodBcaVa,
+				VirtualAccount: &dto.VirtualAccountPayment{Bank: "bca", VaNumber: "12345678901"}},
+		},
+		{
+			name:   "permata returns its own va field",
+			method: dto.PaymentMethodPermataVa,
+			resp:   coreapi.ChargeResponse{OrderID: "YDR-1", PermataVaNumber: "8562000000001"},
+			want: dto.PaymentResponse{OrderId: "YDR-1", PaymentMethod: dto.PaymentMethodPermataVa,
+				VirtualAccount: &dto.VirtualAccountPayment{Bank: "permata", VaNumber: "8562000000001"}},
+		},
+		{
+			name:   "gopay actions are found by name",
+			method: dto.PaymentMethodGopay,
+			resp: coreapi.ChargeResponse{OrderID: "YDR-1", Actions: []coreapi.Action{
+				{Name: "deeplink-redirect", URL: "gojek://gopay/merchanttransfer"},
+				{Name: "generate-qr-code", URL: "https://api.midtrans.com/qr"},
+			}},
+			want: dto.PaymentResponse{OrderId: "YDR-1", PaymentMethod: dto.PaymentMethodGopay,
+				EWallet: &dto.EWalletPayment{DeeplinkUrl: "gojek://gopay/merchanttransfer", QrCodeUrl: "https://api.midtrans.com/qr"}},
+		},
+		{
+			name:   "qris with a single action",
+			method: dto.PaymentMethodQris,
+			resp: coreapi.ChargeResponse{OrderID: "YDR-1", QRString: "00020101021226", Actions: []coreapi.Action{
+				{Name: "generate-qr-code", URL: "https://api.midtrans.com/qr"},
+			}},
+			want: dto.PaymentResponse{OrderId: "YDR-1", PaymentMethod: dto.PaymentMethodQris,
+				Qris: &dto.QrisPayment{QrString: "00020101021226", QrCodeUrl: "https://api.midtrans.com/qr"}},
+		},
+		{
+			name:   "shopeepay without actions",
+			method: dto.PaymentMethodShopeePay,
+			resp:   coreapi.ChargeResponse{OrderID: "YDR-1"},
+			want:   dto.PaymentResponse{OrderId: "YDR-1", PaymentMethod: dto.PaymentMethodShopeePay, EWallet: &dto.EWalletPayment{}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, midtransChargeResponse(tt.method, &tt.resp))
+		})
+	}
+}
+
+func TestMidtransPaymentStatus(t *testing.T) {
+	tests := []struct {
+		transactionStatus string
+		fraudStatus       string
+		want              string
+	}{
+		{"settlement", "", "paid"},
+		{"capture", "accept", "paid"},
+		{"capture", "challenge", ""},
+		{"pending", "", ""},
+		{"expire", "", "failed"},
+		{"deny", "", "failed"},
+		{"refund", "", ""},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, midtransPaymentStatus(tt.transactionStatus, tt.fraudStatus), tt.transactionStatus+"/"+tt.fraudStatus)
+	}
+}
+
+func TestFakeGateway_ScriptedStatuses(t *testing.T) {
+	gateway := NewFakeGateway("key")
+	gateway.Script(FakeOutcome{Statuses: []string{"pending", "expire"}})
+
+	_, err := gateway.Charge(dto.ChargeRequest{OrderId: "YDR-1", PaymentMethod: dto.PaymentMethodQris, Amount: 50000})
+	assert.NoError(t, err)
+	// without a scripted outcome the charge settles
+	_, err = gateway.Charge(dto.ChargeRequest{OrderId: "YDR-2", PaymentMethod: dto.PaymentMethodBniVa, Amount: 50000})
+	assert.NoError(t, err)
+
+	var got []string
+	for i := 0; i < 3; i++ {
+		status, err := gateway.Status("YDR-1")
+		assert.NoError(t, err)
+		got = append(got, status.PaymentStatus)
+	}
+	assert.Equal(t, []string{"", "failed", "failed"}, got)
+
+	status, err := gateway.Status("YDR-2")
+	assert.NoError(t, err)
+	assert.Equal(t, "paid", status.PaymentStatus)
+
+	_, err = gateway.Status("YDR-3")
+	assert.Equal(t, ErrTransactionNotFound, err)
+}
+
+func TestFakeGateway_ChargeError(t *testing.T) {
+	gateway := NewFakeGateway("key")
+	gateway.Script(FakeOutcome{ChargeErr: errors.New("declined")})
+
+	_, err := gateway.Charge(dto.ChargeRequest{OrderId: "YDR-1", PaymentMethod: dto.PaymentMethodQris, Amount: 50000})
+	assert.EqualError(t, err, "declined")
+
+	_, err = gateway.Status("YDR-1")
+	assert.Equal(t, ErrTransactionNotFound, err)
+}
+
+func TestFakeGateway_Refund(t *testing.T) {
+	gateway := NewFakeGateway("key")
+	_, err := gateway.Charge(dto.ChargeRequest{OrderId: "YDR-1", PaymentMethod: dto.PaymentMethodQris, Amount: 50000})
+	assert.NoError(t, err)
+
+	res, err := gateway.Refund(dto.RefundRequest{OrderId: "YDR-1", RefundKey: "r1", Amount: 20000})
+	assert.NoError(t, err)
+	assert.Equal(t, "partial_refund", res.Status)
+
+	_, err = gateway.Refund(dto.RefundRequest{OrderId: "YDR-1", RefundKey: "r2", Amount: 40000})
+	assert.Equal(t, ErrFakeRefundExceedsCharge, err)
+
+	res, err = gateway.Refund(dto.RefundRequest{OrderId: "YDR-1", RefundKey: "r3", Amount: 30000})
+	assert.NoError(t, err)
+	assert.Equal(t, "refund", res.Status)
+}
+
+func TestFakeGateway_NotificationRoundTrip(t *testing.T) {
+	gateway := NewFakeGateway("key")
+	_, err := gateway.Charge(dto.ChargeRequest{OrderId: "YDR-1", PaymentMethod: dto.PaymentMethodQris, Amount: 50000})
+	assert.NoError(t, err)
+
+	notif, err := gateway.Notification("YDR-1")
+	assert.NoError(t, err)
+	body, err := json.Marshal(notif)
+	assert.NoError(t, err)
+
+	status, err := gateway.ParseNotification(body)
+	assert.NoError(t, err)
+	assert.Equal(t, "paid", status.PaymentStatus)
+	assert.Equal(t, float64(50000), status.Amount)
+
+	// a notification signed with another key is rejected
+	_, err = NewFakeGateway("other").ParseNotification(body)
+	assert.Equal(t, ErrInvalidNotificationSignature, err)
+
+	_, err = gateway.ParseNotification([]byte("not json"))
+	assert.Equal(t, ErrInvalidNotification, err)
+}
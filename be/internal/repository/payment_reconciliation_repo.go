@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"time"
+
+	"milestone3/be/internal/entity"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PaymentReconciliationRepository interface {
+	GetAttemptsToReconcile(afterID int, createdBefore time.Time, failedSince time.Time, limit int) ([]entity.Payment, error)
+	ApplyStatus(orderID string, status string) (orphaned bool, err error)
+	Record(finding *entity.PaymentReconciliation) error
+	GetFindings(from, to time.Time) ([]entity.PaymentReconciliation, error)
+}
+
+type paymentReconciliationRepository struct {
+	db *gorm.DB
+}
+
+func NewPaymentReconciliationRepository(db *gorm.DB) PaymentReconciliationRepository {
+	return &paymentReconciliationRepository{db: db}
+}
+
+// GetAttemptsToReconcile pages by id through the charge attempts older than createdBefore that are
+// pending, or failed or expired since failedSince, the gateway may still settle those late. winner
+// payments (with a due_at) are obligations the deadline job handles, the gateway never sees them
+func (r *paymentReconciliationRepository) GetAttemptsToReconcile(afterID int, createdBefore time.Time, failedSince time.Time, limit int) ([]entity.Payment, error) {
+	var payments []entity.Payment
+	err := r.db.
+		Where("due_at IS NULL AND order_id IS NOT NULL AND order_id <> ''").
+		Where("status = ? OR (status = ? AND created_at >= ?)", "pending", "failed", failedSince.UTC()).
+		Where("id > ? AND created_at < ?", afterID, createdBefore.UTC()).
+		Order("id ASC").
+		Limit(limit).
+		Find(&payments).Error
+	return payments, err
+}
+
+func (r *paymentReconciliationRepository) ApplyStatus(orderID string, status string) (orphaned bool, err error) {
+	return applyPaymentStatus(r.db, orderID, status)
+}
+
+func (r *paymentReconciliationRepository) Record(finding *entity.PaymentReconciliation) error {
+	return recordReconciliation(r.db, finding)
+}
+
+func (r *paymentReconciliationRepository) GetFindings(from, to time.Time) ([]entity.PaymentReconciliation, error) {
+	var findings []entity.PaymentReconciliation
+	err := r.db.
+		Where("created_at >= ? AND created_at < ?", from.UTC(), to.UTC()).
+		Order("created_at ASC, id ASC").
+		Find(&findings).Error
+	return findings, err
+}
+
+// recordReconciliation keeps a finding once, seeing the same one again on the next run adds nothing
+func recordReconciliation(db *gorm.DB, finding *entity.PaymentReconciliation) error {
+	return db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "order_id"}, {Name: "outcome"}, {Name: "gateway_status"}},
+		DoNothing: true,
+	}).Create(finding).Error
+}
@@ -2,13 +2,18 @@ package repository
 
 import (
 	"context"
-	"milestone3/be/internal/dto"
+	"errors"
 	"milestone3/be/internal/entity"
-	"os"
+	"time"
 
-	"github.com/midtrans/midtrans-go"
-	"github.com/midtrans/midtrans-go/coreapi"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrPaymentNotRefundable = errors.New("payment is not paid or a refund is in progress")
+	ErrRefundExceedsPayment = errors.New("refund exceeds the amount left on the payment")
+	ErrDuplicatePayment     = errors.New("a payment for this item is already in progress")
 )
 
 type PaymentRepo struct {
@@ -20,9 +25,9 @@ func NewPaymentRepository(db *gorm.DB, ctx context.Context) *PaymentRepo {
 	return &PaymentRepo{db: db, ctx: ctx}
 }
 
-func (pr *PaymentRepo) Create(payment *entity.Payment, orderId string) (error) {
-	payment.OrderId = orderId
-	if err := pr.db.WithContext(pr.ctx).Omit("Status").Preload("User").Create(payment).Error; err != nil {
+// CreatePending is idempotent on the order id, a second call finds the payment created by the first
+func (pr *PaymentRepo) CreatePending(payment *entity.Payment) (error) {
+	if err := pr.db.WithContext(pr.ctx).Where("order_id = ?", payment.OrderId).FirstOrCreate(payment).Error; err != nil {
 		return err
 	}
 
@@ -45,88 +50,300 @@ func (pr *PaymentRepo) GetAll() (payment []entity.Payment, err error) {
 	return payment, err
 }
 
-func (pr *PaymentRepo) GetBidByAuctionId(auctionItemId int) (bid entity.Bid, err error) {
-	if err := pr.db.WithContext(context.Background()).First(&bid, "auction_item_id = ?", auctionItemId).Error; err != nil {
-		return entity.Bid{}, err
+func (pr *PaymentRepo) GetAuctionItem(auctionItemId int) (item entity.AuctionItem, err error) {
+	if err := pr.db.WithContext(pr.ctx).First(&item, auctionItemId).Error; err != nil {
+		return entity.AuctionItem{}, err
 	}
 
-	return bid, nil
+	return item, nil
 }
 
-func (pr *PaymentRepo) CreateMidtrans(payment entity.Payment, orderId string) (res dto.PaymentResponse, err error) {
-	serverKey := os.Getenv("MIDTRANS_SERVER_KEY")
-	c := coreapi.Client{}
-	c.New(serverKey, midtrans.Sandbox)
-	chargeReq := &coreapi.ChargeReq{
-		PaymentType: coreapi.PaymentTypeQris,
-		TransactionDetails: midtrans.TransactionDetails{
-			OrderID: orderId,
-			GrossAmt: int64(payment.Amount),
-		},
-		CustomerDetails: &midtrans.CustomerDetails{
-			FName: payment.User.Name,
-			Email: payment.User.Email,
-		},
+// GetOpenWinnerPayment returns the pending payment the current winner owes, opened from the finalized
+// winning bid or from a second chance offer, the amount is the bid of that bidder
+func (pr *PaymentRepo) GetOpenWinnerPayment(auctionItemId int) (payment entity.Payment, err error) {
+	if err := pr.db.WithContext(pr.ctx).Preload("User").
+		Where("auction_item_id = ? AND status = ? AND due_at IS NOT NULL", auctionItemId, "pending").
+		Order("id DESC").
+		First(&payment).Error; err != nil {
+		return entity.Payment{}, err
 	}
-	coreApiResp, _ := c.ChargeTransaction(chargeReq)
 
-	var paymentURL string
-    if len(coreApiResp.Actions) > 0 {
-        paymentURL = coreApiResp.Actions[1].URL
-    }
+	return payment, nil
+}
+
+// CreateAttempt stores a charge attempt of the winner unless one of theirs is still pending or went
+// through. the winner payment row is locked so two attempts cannot both pass the check
+func (pr *PaymentRepo) CreateAttempt(payment *entity.Payment, winnerPaymentId int) (error) {
+	return pr.db.WithContext(pr.ctx).Transaction(func(tx *gorm.DB) error {
+		var winnerPayment entity.Payment
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&winnerPayment, winnerPaymentId).Error; err != nil {
+			return err
+		}
+
+		var active int64
+		if err := tx.Model(&entity.Payment{}).
+			Where("auction_item_id = ? AND user_id = ? AND due_at IS NULL", payment.AuctionItemId, payment.UserId).
+			Where("status IN ?", []string{"pending", "paid", "refund_pending", "partially_refunded"}).
+			Count(&active).Error; err != nil {
+			return err
+		}
+		if active > 0 {
+			return ErrDuplicatePayment
+		}
+
+		payment.Status = "pending"
+		return tx.Create(payment).Error
+	})
+}
 
-	resp := dto.PaymentResponse{
-		PaymentLinkUrl: paymentURL,
-		TransactionId: coreApiResp.TransactionID,
-		ExpiryTime: coreApiResp.ExpiryTime,
-		OrderId: coreApiResp.OrderID,
+func (pr *PaymentRepo) GetByOrderId(orderId string) (payment entity.Payment, err error) {
+	if err := pr.db.WithContext(pr.ctx).Where("order_id = ?", orderId).First(&payment).Error; err != nil {
+		return entity.Payment{}, err
 	}
-	return resp, nil
+
+	return payment, nil
 }
 
-func (pr *PaymentRepo) CheckPaymentStatusMidtrans(orderId string) (res dto.CheckPaymentStatusResponse, err error) {
-	var payment entity.Payment
-	var auction entity.AuctionItem
+// winnerSettledStatuses are the statuses of a charge attempt by which the winner paid the item,
+// a refund later on does not make the winner owe it again
+var winnerSettledStatuses = []string{"paid", "refund_pending", "partially_refunded", "refunded"}
 
-	serverKey := os.Getenv("MIDTRANS_SERVER_KEY")
-	c := coreapi.Client{}
-	c.New(serverKey, midtrans.Sandbox)
+// settlesWinnerPayment is true when a charge attempt moving to status means the winner paid, the
+// winner payment it was made against is then settled
+func settlesWinnerPayment(payment entity.Payment, status string) bool {
+	if payment.DueAt != nil {
+		return false
+	}
+	for _, settled := range winnerSettledStatuses {
+		if status == settled {
+			return true
+		}
+	}
+	return false
+}
 
-	resp, _ := c.CheckTransaction(orderId)
-	// if err != nil {
-	// 	return res, err
-	// }
+// shouldApplyPaymentStatus is false for the same notification delivered again and for a late
+// failure after the payment settled. the item is not relisted on a failed attempt either, the
+// winner can still pay until the deadline of their winner payment, after it the deadline job
+// offers or relists the item. a winner payment itself stays pending for the deadline job to see
+func shouldApplyPaymentStatus(payment entity.Payment, status string) bool {
+	if payment.Status == status || payment.Status == "paid" || payment.Status == "settled" {
+		return false
+	}
+	// a settlement delivered again must not undo a refund
+	if payment.Status == "refund_pending" || payment.Status == "partially_refunded" || payment.Status == "refunded" {
+		return false
+	}
+	if status == "failed" && payment.DueAt != nil {
+		return false
+	}
+	return true
+}
+
+// ApplyStatus moves the payment, and the auction item once paid, to the gateway status in one
+// transaction. a notification delivered again finds the payment already in that status and changes nothing.
+// orphaned is true for a charge attempt settled after the winner payment it was made against closed, see applyPaymentStatus
+func (pr *PaymentRepo) ApplyStatus(orderId string, status string) (orphaned bool, err error) {
+	return applyPaymentStatus(pr.db.WithContext(pr.ctx), orderId, status)
+}
+
+// applyPaymentStatus is shared by the notification, the status check and the reconciliation. a
+// charge attempt that settles is only worth the item while the winner payment it was made against
+// is still pending: once that one defaulted, and the item went to a second chance offer or back
+// up for auction, or was settled by another attempt, the attempt is kept paid so finance can
+// refund it, but it neither settles nor sells anything and orphaned is returned
+func applyPaymentStatus(db *gorm.DB, orderId string, status string) (orphaned bool, err error) {
+	err = db.Transaction(func(tx *gorm.DB) error {
+		var payment entity.Payment
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("order_id = ?", orderId).First(&payment).Error; err != nil {
+			return err
+		}
+
+		if !shouldApplyPaymentStatus(payment, status) {
+			return nil
+		}
+
+		if err := tx.Model(&entity.Payment{}).Where("id = ?", payment.Id).Update("status", status).Error; err != nil {
+			return err
+		}
 
-	// assign into named variable res
-	res = dto.CheckPaymentStatusResponse{
-		OrderId:        resp.OrderID,
-		TransactionId:  resp.TransactionID,
-		PaymentStatus:  resp.TransactionStatus,
+		if !settlesWinnerPayment(payment, status) {
+			return nil
+		}
+
+		// the deadline job locks the winner payment to default it, whichever comes first wins
+		var winnerPayment entity.Payment
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("auction_item_id = ? AND user_id = ? AND due_at IS NOT NULL AND id < ?", payment.AuctionItemId, payment.UserId, payment.Id).
+			Order("id DESC").
+			First(&winnerPayment).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		if errors.Is(err, gorm.ErrRecordNotFound) || winnerPayment.Status != "pending" {
+			orphaned = true
+			return nil
+		}
+
+		if err := tx.Model(&entity.Payment{}).Where("id = ?", winnerPayment.Id).Update("status", "settled").Error; err != nil {
+			return err
+		}
+
+		if status == "paid" {
+			return tx.Model(&entity.AuctionItem{}).
+				Where("id = ? AND status = ?", payment.AuctionItemId, "finished").
+				Update("status", "sold").Error
+		}
+
+		return nil
+	})
+	if err != nil {
+		return false, err
 	}
 
-	switch resp.TransactionStatus {
-	case "settlement":
-		pr.db.Model(&payment).
-			WithContext(pr.ctx).
-			Where("order_id = ?", orderId).
-			Update("status", "paid")
+	return orphaned, nil
+}
+
+// RecordOrphan keeps a gateway transaction we have no payment for, for the reconciliation report
+func (pr *PaymentRepo) RecordOrphan(finding *entity.PaymentReconciliation) (error) {
+	return recordReconciliation(pr.db.WithContext(pr.ctx), finding)
+}
 
-	case "cancel", "expire":
-		// update auction to scheduled
-		pr.db.Model(&auction).
-			Where("id = (?)",
-				pr.db.Model(&payment).
-					Select("auction_item_id").
-					Where("order_id = ?", orderId),
-			).Update("status", "scheduled")
+// BeginRefund records a pending refund and moves the payment to refund_pending. the refund has to fit
+// in what the earlier refunds left, an amount of 0 refunds all of it. only one refund runs at a time
+func (pr *PaymentRepo) BeginRefund(refund *entity.PaymentRefund) (payment entity.Payment, err error) {
+	err = pr.db.WithContext(pr.ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&payment, refund.PaymentId).Error; err != nil {
+			return err
+		}
 
-		// update payment to failed
-		pr.db.Model(&payment).
-			WithContext(pr.ctx).
-			Where("order_id = ?", orderId).
-			Update("status", "failed")
+		if payment.Status != "paid" && payment.Status != "partially_refunded" {
+			return ErrPaymentNotRefundable
+		}
+
+		refunded, err := sumRefunded(tx, payment.Id)
+		if err != nil {
+			return err
+		}
+
+		if refund.Amount == 0 {
+			refund.Amount = payment.Amount - refunded
+		}
+		if refund.Amount <= 0 || refunded+refund.Amount > payment.Amount {
+			return ErrRefundExceedsPayment
+		}
+
+		refund.Status = "pending"
+		if err := tx.Create(refund).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&entity.Payment{}).Where("id = ?", payment.Id).Update("status", "refund_pending").Error
+	})
+	if err != nil {
+		return entity.Payment{}, err
+	}
+
+	return payment, nil
+}
+
+// CompleteRefund marks the refund refunded and the payment refunded, or partially_refunded while
+// part of it is left, and puts the item back up for auction when the refund asks for it
+func (pr *PaymentRepo) CompleteRefund(refund *entity.PaymentRefund, auctionItemId int, gatewayStatus string) (error) {
+	now := time.Now().UTC()
+	err := pr.db.WithContext(pr.ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&entity.PaymentRefund{}).Where("id = ?", refund.Id).Updates(map[string]interface{}{
+			"status": "refunded",
+			"gateway_status": gatewayStatus,
+			"refunded_at": now,
+		}).Error; err != nil {
+			return err
+		}
+
+		var payment entity.Payment
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&payment, refund.PaymentId).Error; err != nil {
+			return err
+		}
+
+		refunded, err := sumRefunded(tx, payment.Id)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Model(&entity.Payment{}).Where("id = ?", payment.Id).Update("status", refundedPaymentStatus(payment.Amount, refunded)).Error; err != nil {
+			return err
+		}
+
+		if refund.ReopenItem {
+			return tx.Model(&entity.AuctionItem{}).
+				Where("id = ? AND status IN ?", auctionItemId, []string{"sold", "finished"}).
+				Update("status", "scheduled").Error
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
-	return res, nil
+	refund.Status = "refunded"
+	refund.GatewayStatus = gatewayStatus
+	refund.RefundedAt = &now
+	return nil
+}
+
+// FailRefund marks a refund the gateway refused, the payment goes back to what it was before it
+func (pr *PaymentRepo) FailRefund(refund entity.PaymentRefund, reason string) (error) {
+	return pr.db.WithContext(pr.ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&entity.PaymentRefund{}).Where("id = ?", refund.Id).Updates(map[string]interface{}{
+			"status": "failed",
+			"last_error": reason,
+		}).Error; err != nil {
+			return err
+		}
+
+		var payment entity.Payment
+		if err := tx.First(&payment, refund.PaymentId).Error; err != nil {
+			return err
+		}
+
+		refunded, err := sumRefunded(tx, payment.Id)
+		if err != nil {
+			return err
+		}
+
+		return tx.Model(&entity.Payment{}).
+			Where("id = ? AND status = ?", payment.Id, "refund_pending").
+			Update("status", refundedPaymentStatus(payment.Amount, refunded)).Error
+	})
 }
 
+// sumRefunded is what the completed refunds of the payment returned so far
+func sumRefunded(tx *gorm.DB, paymentId int) (refunded float64, err error) {
+	err = tx.Model(&entity.PaymentRefund{}).
+		Where("payment_id = ? AND status = ?", paymentId, "refunded").
+		Select("COALESCE(SUM(amount), 0)").
+		Scan(&refunded).Error
+	return refunded, err
+}
+
+// refundedPaymentStatus is paid while nothing was returned, refunded once all of it was and
+// partially_refunded in between
+func refundedPaymentStatus(amount float64, refunded float64) string {
+	switch {
+	case refunded <= 0:
+		return "paid"
+	case refunded >= amount:
+		return "refunded"
+	default:
+		return "partially_refunded"
+	}
+}
+
+func (pr *PaymentRepo) GetRefunds(paymentId int) (refunds []entity.PaymentRefund, err error) {
+	if err := pr.db.WithContext(pr.ctx).Where("payment_id = ?", paymentId).Order("id ASC").Find(&refunds).Error; err != nil {
+		return []entity.PaymentRefund{}, err
+	}
+
+	return refunds, nil
+}
@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"milestone3/be/internal/entity"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShouldApplyPaymentStatus(t *testing.T) {
+	dueAt := time.Now().Add(48 * time.Hour)
+
+	tests := []struct {
+		name    string
+		payment entity.Payment
+		status  string
+		want    bool
+	}{
+		{name: "pending to paid", payment: entity.Payment{Status: "pending"}, status: "paid", want: true},
+		{name: "same notification delivered again", payment: entity.Payment{Status: "paid"}, status: "paid", want: false},
+		{name: "late expire after settlement", payment: entity.Payment{Status: "paid"}, status: "failed", want: false},
+		{name: "failed attempt on a plain payment", payment: entity.Payment{Status: "pending"}, status: "failed", want: true},
+		{name: "failed attempt on a winner payment", payment: entity.Payment{Status: "pending", DueAt: &dueAt}, status: "failed", want: false},
+		{name: "winner pays after a failed attempt", payment: entity.Payment{Status: "pending", DueAt: &dueAt}, status: "paid", want: true},
+		{name: "settlement delivered again after a refund", payment: entity.Payment{Status: "refunded"}, status: "paid", want: false},
+		{name: "late failure while refunding", payment: entity.Payment{Status: "refund_pending"}, status: "failed", want: false},
+		{name: "settlement delivered again after a partial refund", payment: entity.Payment{Status: "partially_refunded"}, status: "paid", want: false},
+		{name: "winner payment settled by an attempt", payment: entity.Payment{Status: "settled", DueAt: &dueAt}, status: "paid", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, shouldApplyPaymentStatus(tt.payment, tt.status))
+		})
+	}
+}
+
+func TestSettlesWinnerPayment(t *testing.T) {
+	dueAt := time.Now().Add(48 * time.Hour)
+
+	tests := []struct {
+		name    string
+		payment entity.Payment
+		status  string
+		want    bool
+	}{
+		{name: "attempt paid", payment: entity.Payment{Status: "pending"}, status: "paid", want: true},
+		{name: "attempt refunded", payment: entity.Payment{Status: "refund_pending"}, status: "refunded", want: true},
+		{name: "attempt being refunded", payment: entity.Payment{Status: "paid"}, status: "refund_pending", want: true},
+		{name: "attempt failed", payment: entity.Payment{Status: "pending"}, status: "failed", want: false},
+		{name: "winner payment paid itself", payment: entity.Payment{Status: "pending", DueAt: &dueAt}, status: "paid", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, settlesWinnerPayment(tt.payment, tt.status))
+		})
+	}
+}
+
+func TestRefundedPaymentStatus(t *testing.T) {
+	tests := []struct {
+		name     string
+		refunded float64
+		want     string
+	}{
+		{name: "nothing refunded", refunded: 0, want: "paid"},
+		{name: "part refunded", refunded: 40000, want: "partially_refunded"},
+		{name: "all refunded", refunded: 150000, want: "refunded"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, refundedPaymentStatus(150000, tt.refunded))
+		})
+	}
+}
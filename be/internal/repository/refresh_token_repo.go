@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"milestone3/be/internal/entity"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrRefreshTokenRevoked is returned by Rotate when the token was revoked, or rotated by a concurrent request
+var ErrRefreshTokenRevoked = errors.New("refresh token revoked")
+
+type RefreshTokenRepo struct {
+	ctx context.Context
+	db  *gorm.DB
+}
+
+func NewRefreshTokenRepo(db *gorm.DB, ctx context.Context) *RefreshTokenRepo {
+	return &RefreshTokenRepo{db: db, ctx: ctx}
+}
+
+func (rr *RefreshTokenRepo) Create(token *entity.RefreshToken) error {
+	return rr.db.WithContext(rr.ctx).Create(token).Error
+}
+
+func (rr *RefreshTokenRepo) GetByHash(tokenHash string) (token entity.RefreshToken, err error) {
+	if err := rr.db.WithContext(rr.ctx).First(&token, "token_hash = ?", tokenHash).Error; err != nil {
+		return entity.RefreshToken{}, err
+	}
+
+	return token, nil
+}
+
+// Rotate revokes the token used and stores the next one of its family, only one request can rotate a token
+func (rr *RefreshTokenRepo) Rotate(usedID uint, next *entity.RefreshToken) error {
+	return rr.db.WithContext(rr.ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&entity.RefreshToken{}).
+			Where("id = ? AND revoked_at IS NULL", usedID).
+			Update("revoked_at", time.Now())
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrRefreshTokenRevoked
+		}
+
+		return tx.Create(next).Error
+	})
+}
+
+// RevokeFamily revokes every token of the family still active and returns the whole family
+func (rr *RefreshTokenRepo) RevokeFamily(familyID string) (tokens []entity.RefreshToken, err error) {
+	err = rr.db.WithContext(rr.ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&entity.RefreshToken{}).
+			Where("family_id = ? AND revoked_at IS NULL", familyID).
+			Update("revoked_at", time.Now()).Error; err != nil {
+			return err
+		}
+
+		return tx.Where("family_id = ?", familyID).Find(&tokens).Error
+	})
+	return tokens, err
+}
+
+// RevokeUser revokes every active token of the user and returns the ones it revoked
+func (rr *RefreshTokenRepo) RevokeUser(userID int) (tokens []entity.RefreshToken, err error) {
+	err = rr.db.WithContext(rr.ctx).Model(&tokens).
+		Clauses(clause.Returning{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now()).Error
+	return tokens, err
+}
+
+// ListLiveAccessTokens returns the tokens of the user whose access token has not expired yet
+func (rr *RefreshTokenRepo) ListLiveAccessTokens(userID int) (tokens []entity.RefreshToken, err error) {
+	err = rr.db.WithContext(rr.ctx).
+		Where("user_id = ? AND access_expires_at > ?", userID, time.Now()).
+		Find(&tokens).Error
+	return tokens, err
+}
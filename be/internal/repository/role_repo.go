@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"milestone3/be/internal/entity"
+
+	"gorm.io/gorm"
+)
+
+// ErrRoleNotFound is returned by AssignRole for a role that does not exist
+var ErrRoleNotFound = errors.New("role not found")
+
+type RoleRepo struct {
+	ctx context.Context
+	db  *gorm.DB
+}
+
+func NewRoleRepo(db *gorm.DB, ctx context.Context) *RoleRepo {
+	return &RoleRepo{db: db, ctx: ctx}
+}
+
+func (rr *RoleRepo) ListRoles() (roles []entity.Role, err error) {
+	err = rr.db.WithContext(rr.ctx).
+		Preload("Permissions", func(db *gorm.DB) *gorm.DB { return db.Order("permissions.name") }).
+		Order("id").
+		Find(&roles).Error
+	return roles, err
+}
+
+// AssignRole sets the role of the user, gorm.ErrRecordNotFound when there is no such user or the
+// account was deleted
+func (rr *RoleRepo) AssignRole(userID int, role string) error {
+	return rr.db.WithContext(rr.ctx).Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&entity.Role{}).Where("name = ?", role).Count(&count).Error; err != nil {
+			return err
+		}
+		if count == 0 {
+			return ErrRoleNotFound
+		}
+
+		res := tx.Model(&entity.Users{}).Where("id = ? AND deleted_at IS NULL", userID).Update("role", role)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+}
@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/minio/minio-go/v7/pkg/s3utils"
+)
+
+// S3Config addresses an S3-compatible service, MinIO and most self-hosted services need PathStyle
+type S3Config struct {
+	Endpoint  string
+	Region    string
+	AccessKey string
+	SecretKey string
+	PathStyle bool
+}
+
+// s3ObjectStore keeps the objects of one bucket of an S3-compatible service through the minio client
+type s3ObjectStore struct {
+	client     *minio.Client
+	endpoint   *url.URL
+	pathStyle  bool
+	bucketName string
+	isPublic   bool
+}
+
+func NewS3ObjectStore(cfg S3Config, bucketName string, isPublic bool) (ObjectStore, error) {
+	endpoint, err := url.Parse(strings.TrimRight(cfg.Endpoint, "/"))
+	if err != nil {
+		return nil, err
+	}
+	if endpoint.Scheme == "" || endpoint.Host == "" || endpoint.Path != "" {
+		return nil, fmt.Errorf("invalid s3 endpoint %q", cfg.Endpoint)
+	}
+
+	lookup := minio.BucketLookupDNS
+	if cfg.PathStyle {
+		lookup = minio.BucketLookupPath
+	}
+	client, err := minio.New(endpoint.Host, &minio.Options{
+		Creds:        credentials.NewStaticV4(cfg.AccessKey, cfg.SecretKey, ""),
+		Secure:       endpoint.Scheme == "https",
+		Region:       cfg.Region,
+		BucketLookup: lookup,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &s3ObjectStore{
+		client:     client,
+		endpoint:   endpoint,
+		pathStyle:  cfg.PathStyle,
+		bucketName: bucketName,
+		isPublic:   isPublic,
+	}, nil
+}
+
+func (r *s3ObjectStore) Upload(ctx context.Context, file io.Reader, objectName string, contentType string) (string, error) {
+	// with the size known up front the object goes in a single PUT, uploads are small enough to buffer
+	body, err := io.ReadAll(file)
+	if err != nil {
+		return "", err
+	}
+
+	_, err = r.client.PutObject(ctx, r.bucketName, objectName, bytes.NewReader(body), int64(len(body)), minio.PutObjectOptions{
+		ContentType: contentType,
+	})
+	if err != nil {
+		log.Printf("S3 upload failed: %v", err)
+		return "", err
+	}
+
+	if r.isPublic {
+		return r.objectURL(objectName), nil
+	}
+	return objectName, nil
+}
+
+func (r *s3ObjectStore) Delete(ctx context.Context, objectName string) error {
+	err := r.client.RemoveObject(ctx, r.bucketName, objectName, minio.RemoveObjectOptions{})
+	// S3 answers a missing key like any other delete, some compatible services with 404
+	if err != nil && minio.ToErrorResponse(err).StatusCode != http.StatusNotFound {
+		log.Printf("S3 delete failed: %v", err)
+		return err
+	}
+	return nil
+}
+
+func (r *s3ObjectStore) SignedURL(ctx context.Context, objectName string, expire time.Duration) (string, error) {
+	if r.isPublic {
+		return r.objectURL(objectName), nil
+	}
+
+	u, err := r.client.PresignedGetObject(ctx, r.bucketName, objectName, expire, nil)
+	if err != nil {
+		return "", err
+	}
+	return u.String(), nil
+}
+
+func (r *s3ObjectStore) Stat(ctx context.Context, objectName string) (ObjectInfo, error) {
+	info, err := r.client.StatObject(ctx, r.bucketName, objectName, minio.StatObjectOptions{})
+	if err != nil {
+		if minio.ToErrorResponse(err).StatusCode == http.StatusNotFound {
+			return ObjectInfo{}, ErrObjectNotFound
+		}
+		return ObjectInfo{}, err
+	}
+
+	return ObjectInfo{
+		Name:        objectName,
+		Size:        info.Size,
+		ContentType: info.ContentType,
+		UpdatedAt:   info.LastModified,
+	}, nil
+}
+
+// objectURL is the public address of the object, the bucket goes in the host or, path style, in the path
+func (r *s3ObjectStore) objectURL(objectName string) string {
+	u := *r.endpoint
+	objectPath := "/" + objectName
+	if r.pathStyle {
+		objectPath = "/" + r.bucketName + objectPath
+	} else {
+		u.Host = r.bucketName + "." + u.Host
+	}
+	u.Path = objectPath
+	u.RawPath = s3utils.EncodePath(objectPath)
+	return u.String()
+}
@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// TokenDenyListRepo keeps the ids (jti) of access tokens revoked before they expire. an entry
+// lives as long as the token would have, after that the signature check rejects it anyway
+type TokenDenyListRepo struct {
+	client *redis.Client
+	ctx    context.Context
+}
+
+func NewTokenDenyListRepo(client *redis.Client, ctx context.Context) *TokenDenyListRepo {
+	return &TokenDenyListRepo{client: client, ctx: ctx}
+}
+
+func deniedTokenKey(jti string) string {
+	return "auth:denied:" + jti
+}
+
+func (r *TokenDenyListRepo) Deny(jti string, ttl time.Duration) error {
+	if ttl <= 0 {
+		return nil
+	}
+	return r.client.Set(r.ctx, deniedTokenKey(jti), 1, ttl).Err()
+}
+
+func (r *TokenDenyListRepo) IsDenied(jti string) (bool, error) {
+	n, err := r.client.Exists(r.ctx, deniedTokenKey(jti)).Result()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
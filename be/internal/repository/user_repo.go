@@ -2,6 +2,10 @@ package repository
 
 import (
 	"context"
+	"fmt"
+	"strings"
+	"time"
+
 	"milestone3/be/internal/entity"
 
 	"gorm.io/gorm"
@@ -38,5 +42,203 @@ func (ur *UserRepo) GetById(id int) (user entity.Users, err error) {
 
 	return user, nil
 }
-// reset password?
-// validation?
\ No newline at end of file
+
+func (ur *UserRepo) MarkEmailVerified(id int) error {
+	return ur.db.WithContext(ur.ctx).Model(&entity.Users{}).
+		Where("id = ? AND email_verified_at IS NULL", id).
+		Update("email_verified_at", time.Now()).Error
+}
+
+func (ur *UserRepo) UpdatePassword(id int, passwordHash string) error {
+	return ur.db.WithContext(ur.ctx).Model(&entity.Users{}).
+		Where("id = ?", id).
+		Update("password", passwordHash).Error
+}
+
+// GetPermissions lists the permissions granted to the role
+func (ur *UserRepo) GetPermissions(role string) (permissions []string, err error) {
+	err = ur.db.WithContext(ur.ctx).Model(&entity.Permission{}).
+		Joins("JOIN role_permissions ON role_permissions.permission_id = permissions.id").
+		Joins("JOIN roles ON roles.id = role_permissions.role_id").
+		Where("roles.name = ?", role).
+		Order("permissions.name").
+		Pluck("permissions.name", &permissions).Error
+	return permissions, err
+}
+
+// UserFilter narrows ListUsers, Search matches the name or email and Status is active, suspended or deleted
+type UserFilter struct {
+	Search string
+	Role   string
+	Status string
+}
+
+// likeEscaper escapes the wildcards of ILIKE in a search typed by a user
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// ListUsers returns a page of users matching filter, newest first, with the total count
+func (ur *UserRepo) ListUsers(filter UserFilter, page, limit int) (users []entity.Users, total int64, err error) {
+	q := ur.db.WithContext(ur.ctx).Model(&entity.Users{})
+	if filter.Search != "" {
+		pattern := "%" + likeEscaper.Replace(filter.Search) + "%"
+		q = q.Where("name ILIKE ? OR email ILIKE ?", pattern, pattern)
+	}
+	if filter.Role != "" {
+		q = q.Where("role = ?", filter.Role)
+	}
+	switch filter.Status {
+	case "active":
+		q = q.Where("suspended_at IS NULL AND deleted_at IS NULL")
+	case "suspended":
+		q = q.Where("suspended_at IS NOT NULL AND deleted_at IS NULL")
+	case "deleted":
+		q = q.Where("deleted_at IS NOT NULL")
+	}
+
+	if err = q.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	err = q.Order("id DESC").Offset((page - 1) * limit).Limit(limit).Find(&users).Error
+	return users, total, err
+}
+
+// UpdateProfile sets the name, phone and address of the user
+func (ur *UserRepo) UpdateProfile(id int, name, phone, address string) error {
+	return ur.db.WithContext(ur.ctx).Model(&entity.Users{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{"name": name, "phone": phone, "address": address}).Error
+}
+
+// Suspend keeps the user out with reason, gorm.ErrRecordNotFound when there is no such user or the
+// account was deleted. suspending again only updates the reason
+func (ur *UserRepo) Suspend(id int, reason string) error {
+	res := ur.db.WithContext(ur.ctx).Model(&entity.Users{}).
+		Where("id = ? AND deleted_at IS NULL", id).
+		Updates(map[string]interface{}{
+			"suspended_at":      gorm.Expr("COALESCE(suspended_at, ?)", time.Now()),
+			"suspension_reason": reason,
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// Unsuspend lets the user back in, gorm.ErrRecordNotFound when there is no such user or the account was deleted
+func (ur *UserRepo) Unsuspend(id int) error {
+	res := ur.db.WithContext(ur.ctx).Model(&entity.Users{}).
+		Where("id = ? AND deleted_at IS NULL", id).
+		Updates(map[string]interface{}{"suspended_at": nil, "suspension_reason": ""})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// HasOpenActivity tells whether the user still has something running that needs the account: a bid on
+// an ongoing auction, a payment to settle or a donation waiting to be picked up
+func (ur *UserRepo) HasOpenActivity(id int) (open bool, err error) {
+	err = ur.db.WithContext(ur.ctx).Raw(`SELECT
+		EXISTS (SELECT 1 FROM bids JOIN auction_items ON auction_items.id = bids.auction_item_id
+			WHERE bids.user_id = ? AND auction_items.status = 'ongoing')
+		OR EXISTS (SELECT 1 FROM payments WHERE user_id = ? AND status = 'pending')
+		OR EXISTS (SELECT 1 FROM donations WHERE user_id = ? AND status IN ('pending', 'awaiting_pickup'))`,
+		id, id, id).Scan(&open).Error
+	return open, err
+}
+
+// Anonymize wipes everything personal from the user and voids its mailed tokens. the row stays so
+// the bids, donations and payments of the user keep pointing at it, without telling who it was. the
+// donations lose what the donor wrote and their photos, objectNames are the stored photos no auction
+// item shows anymore, for the caller to delete once this committed
+func (ur *UserRepo) Anonymize(id int) (objectNames []string, err error) {
+	err = ur.db.WithContext(ur.ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&entity.Users{}).Where("id = ? AND deleted_at IS NULL", id).
+			Updates(map[string]interface{}{
+				"name":              "Deleted user",
+				"email":             fmt.Sprintf("deleted-%d@deleted.invalid", id),
+				"password":          "",
+				"phone":             "",
+				"address":           "",
+				"email_verified_at": nil,
+				"suspended_at":      nil,
+				"suspension_reason": "",
+				"deleted_at":        time.Now(),
+			})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		if err := tx.Where("user_id = ?", id).Delete(&entity.UserToken{}).Error; err != nil {
+			return err
+		}
+
+		var photos []entity.DonationPhoto
+		if err := tx.Where("donation_id IN (SELECT id FROM donations WHERE user_id = ?)", id).Find(&photos).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("donation_id IN (SELECT id FROM donations WHERE user_id = ?)", id).Delete(&entity.DonationPhoto{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&entity.Donation{}).Where("user_id = ?", id).Updates(map[string]interface{}{
+			"title":       "Deleted donation",
+			"description": "",
+		}).Error; err != nil {
+			return err
+		}
+
+		// drafted auction items show the photos of their donation, those stay with the item
+		names := donationPhotoObjects(photos)
+		var shown []entity.AuctionItemPhoto
+		if len(names) > 0 {
+			if err := tx.Where("private AND (url IN ? OR thumbnail_url IN ?)", names, names).Find(&shown).Error; err != nil {
+				return err
+			}
+		}
+		objectNames = unshownObjects(names, shown)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return objectNames, nil
+}
+
+// donationPhotoObjects lists the stored objects of every variant of the photos
+func donationPhotoObjects(photos []entity.DonationPhoto) []string {
+	var names []string
+	for _, p := range photos {
+		for _, name := range []string{p.URL, p.DisplayURL, p.ThumbnailURL} {
+			if name != "" {
+				names = append(names, name)
+			}
+		}
+	}
+	return names
+}
+
+// unshownObjects drops the objects an auction item photo still shows from names
+func unshownObjects(names []string, shown []entity.AuctionItemPhoto) []string {
+	inUse := make(map[string]bool, 2*len(shown))
+	for _, p := range shown {
+		inUse[p.URL] = true
+		inUse[p.ThumbnailURL] = true
+	}
+
+	var unshown []string
+	for _, name := range names {
+		if !inUse[name] {
+			unshown = append(unshown, name)
+		}
+	}
+	return unshown
+}
@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"testing"
+
+	"milestone3/be/internal/entity"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnshownObjects(t *testing.T) {
+	photos := []entity.DonationPhoto{
+		{URL: "donations/private/1_a.jpg", DisplayURL: "donations/private/1_a_display.jpg", ThumbnailURL: "donations/private/1_a_thumb.jpg"},
+		{URL: "donations/private/2_b.jpg"},
+	}
+	names := donationPhotoObjects(photos)
+	assert.Equal(t, []string{
+		"donations/private/1_a.jpg",
+		"donations/private/1_a_display.jpg",
+		"donations/private/1_a_thumb.jpg",
+		"donations/private/2_b.jpg",
+	}, names)
+
+	// the drafted auction item shows the display and thumbnail variants of the first photo
+	shown := []entity.AuctionItemPhoto{
+		{URL: "donations/private/1_a_display.jpg", ThumbnailURL: "donations/private/1_a_thumb.jpg", Private: true},
+	}
+	assert.Equal(t, []string{"donations/private/1_a.jpg", "donations/private/2_b.jpg"}, unshownObjects(names, shown))
+	assert.Equal(t, names, unshownObjects(names, nil))
+}
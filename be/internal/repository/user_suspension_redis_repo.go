@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// UserSuspensionRepo marks suspended users so every request can be checked without a database
+// lookup. users.suspended_at stays the record, login and refresh check it there
+type UserSuspensionRepo struct {
+	client *redis.Client
+	ctx    context.Context
+}
+
+func NewUserSuspensionRepo(client *redis.Client, ctx context.Context) *UserSuspensionRepo {
+	return &UserSuspensionRepo{client: client, ctx: ctx}
+}
+
+func suspendedUserKey(userID int64) string {
+	return "auth:suspended:" + strconv.FormatInt(userID, 10)
+}
+
+func (r *UserSuspensionRepo) Suspend(userID int) error {
+	return r.client.Set(r.ctx, suspendedUserKey(int64(userID)), 1, 0).Err()
+}
+
+func (r *UserSuspensionRepo) Lift(userID int) error {
+	return r.client.Del(r.ctx, suspendedUserKey(int64(userID))).Err()
+}
+
+func (r *UserSuspensionRepo) IsSuspended(userID uint) (bool, error) {
+	n, err := r.client.Exists(r.ctx, suspendedUserKey(int64(userID))).Result()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
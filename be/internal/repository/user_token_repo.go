@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"milestone3/be/internal/entity"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrUserTokenInvalid is returned by Consume for a token that is unknown, used, voided or expired
+var ErrUserTokenInvalid = errors.New("invalid or expired token")
+
+type UserTokenRepo struct {
+	ctx context.Context
+	db  *gorm.DB
+}
+
+func NewUserTokenRepo(db *gorm.DB, ctx context.Context) *UserTokenRepo {
+	return &UserTokenRepo{db: db, ctx: ctx}
+}
+
+// Create stores the token and voids the unused tokens of the same user and purpose, only the
+// latest email sent works
+func (tr *UserTokenRepo) Create(token *entity.UserToken) error {
+	return tr.db.WithContext(tr.ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&entity.UserToken{}).
+			Where("user_id = ? AND purpose = ? AND used_at IS NULL", token.UserID, token.Purpose).
+			Update("used_at", time.Now()).Error; err != nil {
+			return err
+		}
+
+		return tx.Create(token).Error
+	})
+}
+
+// Consume marks the token used and returns it, only one request can consume a token
+func (tr *UserTokenRepo) Consume(tokenHash string, purpose string) (token entity.UserToken, err error) {
+	res := tr.db.WithContext(tr.ctx).Model(&token).
+		Clauses(clause.Returning{}).
+		Where("token_hash = ? AND purpose = ? AND used_at IS NULL AND expires_at > ?", tokenHash, purpose, time.Now()).
+		Update("used_at", time.Now())
+	if res.Error != nil {
+		return entity.UserToken{}, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return entity.UserToken{}, ErrUserTokenInvalid
+	}
+
+	return token, nil
+}
@@ -1,33 +1,55 @@
 package service
 
 import (
+	"context"
 	"log/slog"
 	"milestone3/be/internal/dto"
+	"milestone3/be/internal/entity"
 	"milestone3/be/internal/repository"
 	"time"
 )
 
 type itemsService struct {
-	repo   repository.AuctionItemRepository
-	logger *slog.Logger
-	ai     repository.AIRepository
+	repo         repository.AuctionItemRepository
+	logger       *slog.Logger
+	ai           repository.AIRepository
+	eventRepo    repository.BidEventRepository
+	privateStore repository.ObjectStore
 }
 
 type AuctionItemService interface {
 	Create(item *dto.AuctionItemDTO) (dto.AuctionItemDTO, error)
-	GetAll() ([]dto.AuctionItemDTO, error)
-	GetByID(id int64) (dto.AuctionItemDTO, error)
+	// GetAll and GetByID leave out drafts unless includeDrafts is set, for callers with auction:manage
+	GetAll(includeDrafts bool) ([]dto.AuctionItemDTO, error)
+	GetByID(id int64, includeDrafts bool) (dto.AuctionItemDTO, error)
 	Update(id int64, item *dto.AuctionItemUpdateDTO) (dto.AuctionItemDTO, error)
 	Delete(id int64) error
 	CheckAndStartScheduledItems() error
 }
 
-func NewAuctionItemService(r repository.AuctionItemRepository, aiRepo repository.AIRepository, logger *slog.Logger) AuctionItemService {
-	return &itemsService{repo: r, logger: logger, ai: aiRepo}
+func NewAuctionItemService(r repository.AuctionItemRepository, aiRepo repository.AIRepository, eventRepo repository.BidEventRepository, privateStore repository.ObjectStore, logger *slog.Logger) AuctionItemService {
+	return &itemsService{repo: r, logger: logger, ai: aiRepo, eventRepo: eventRepo, privateStore: privateStore}
 }
 
 const DefaultStartingPrice = 10000
 
+// AuctionPhotoURLExpiry is how long the signed photo URLs of a drafted item stay valid
+const AuctionPhotoURLExpiry = time.Hour
+
+// validatePriceRules checks the optional buy it now price against the starting and reserve price
+func validatePriceRules(item *entity.AuctionItem) error {
+	if item.BuyNowPrice == nil {
+		return nil
+	}
+	if *item.BuyNowPrice <= item.StartingPrice {
+		return ErrInvalidPriceRules
+	}
+	if item.ReservePrice != nil && *item.BuyNowPrice < *item.ReservePrice {
+		return ErrInvalidPriceRules
+	}
+	return nil
+}
+
 func (s *itemsService) Create(itemDTO *dto.AuctionItemDTO) (dto.AuctionItemDTO, error) {
 	item, err := dto.AuctionItemRequest(*itemDTO)
 	if err != nil {
@@ -52,6 +74,11 @@ func (s *itemsService) Create(itemDTO *dto.AuctionItemDTO) (dto.AuctionItemDTO,
 
 	item.StartingPrice = estimatedPrice
 
+	if err := validatePriceRules(&item); err != nil {
+		s.logger.Warn("Invalid auction item prices", "startingPrice", item.StartingPrice, "error", err)
+		return dto.AuctionItemDTO{}, err
+	}
+
 	if item.Status == "" {
 		item.Status = "scheduled"
 	}
@@ -62,11 +89,11 @@ func (s *itemsService) Create(itemDTO *dto.AuctionItemDTO) (dto.AuctionItemDTO,
 		return dto.AuctionItemDTO{}, ErrInvalidAuction
 	}
 
-	return dto.AuctionItemResponse(item), nil
+	return s.signedResponse(item), nil
 }
 
-func (s *itemsService) GetAll() ([]dto.AuctionItemDTO, error) {
-	items, err := s.repo.GetAll()
+func (s *itemsService) GetAll(includeDrafts bool) ([]dto.AuctionItemDTO, error) {
+	items, err := s.repo.GetAll(includeDrafts)
 	if err != nil {
 		s.logger.Error("Failed to get all auction items", "error", err)
 		return nil, ErrAuctionNotFound
@@ -74,18 +101,22 @@ func (s *itemsService) GetAll() ([]dto.AuctionItemDTO, error) {
 
 	var itemDTOs []dto.AuctionItemDTO
 	for _, item := range items {
-		itemDTOs = append(itemDTOs, dto.AuctionItemResponse(item))
+		itemDTOs = append(itemDTOs, s.signedResponse(item))
 	}
 
 	return itemDTOs, nil
 }
 
-func (s *itemsService) GetByID(id int64) (dto.AuctionItemDTO, error) {
+func (s *itemsService) GetByID(id int64, includeDrafts bool) (dto.AuctionItemDTO, error) {
 	item, err := s.repo.GetByID(id)
 	if err != nil {
 		return dto.AuctionItemDTO{}, ErrAuctionNotFoundID
 	}
-	return dto.AuctionItemResponse(*item), nil
+	// a draft is not reviewed yet, to everyone else it does not exist
+	if item.Status == "draft" && !includeDrafts {
+		return dto.AuctionItemDTO{}, ErrAuctionNotFoundID
+	}
+	return s.signedResponse(*item), nil
 }
 
 func (s *itemsService) Update(id int64, updateDTO *dto.AuctionItemUpdateDTO) (dto.AuctionItemDTO, error) {
@@ -95,7 +126,8 @@ func (s *itemsService) Update(id int64, updateDTO *dto.AuctionItemUpdateDTO) (dt
 		return dto.AuctionItemDTO{}, ErrAuctionNotFoundID
 	}
 
-	if existingItem.Status == "finished" {
+	// a sold item is a paid finished item
+	if existingItem.Status == "finished" || existingItem.Status == "sold" {
 		s.logger.Warn("Cannot update finished auction item", "itemID", id, "status", existingItem.Status)
 		return dto.AuctionItemDTO{}, ErrAuctionFinished
 	}
@@ -117,10 +149,26 @@ func (s *itemsService) Update(id int64, updateDTO *dto.AuctionItemUpdateDTO) (dt
 		}
 		existingItem.StartingPrice = *updateDTO.StartingPrice
 	}
+	if updateDTO.ReservePrice != nil {
+		existingItem.ReservePrice = updateDTO.ReservePrice
+	}
+	if updateDTO.BuyNowPrice != nil {
+		existingItem.BuyNowPrice = updateDTO.BuyNowPrice
+	}
+	if err := validatePriceRules(existingItem); err != nil {
+		s.logger.Warn("Invalid auction item prices", "itemID", id, "error", err)
+		return dto.AuctionItemDTO{}, err
+	}
 	if updateDTO.Status != nil {
 		newStatus := *updateDTO.Status
 		// status transition rules
 		switch existingItem.Status {
+		case "draft":
+			// drafted from a verified donation, an admin schedules it once reviewed
+			if newStatus != "scheduled" && newStatus != "draft" {
+				s.logger.Warn("Invalid status transition", "from", existingItem.Status, "to", newStatus)
+				return dto.AuctionItemDTO{}, ErrInvalidAuction
+			}
 		case "scheduled":
 			// to ongoing
 			if newStatus != "ongoing" && newStatus != "scheduled" {
@@ -128,8 +176,14 @@ func (s *itemsService) Update(id int64, updateDTO *dto.AuctionItemUpdateDTO) (dt
 				return dto.AuctionItemDTO{}, ErrInvalidAuction
 			}
 		case "ongoing":
-			// to finished
-			if newStatus != "finished" && newStatus != "ongoing" {
+			// to finished, or unsold when the reserve was not met
+			if newStatus != "finished" && newStatus != "unsold" && newStatus != "ongoing" {
+				s.logger.Warn("Invalid status transition", "from", existingItem.Status, "to", newStatus)
+				return dto.AuctionItemDTO{}, ErrInvalidAuction
+			}
+		case "unsold":
+			// relist
+			if newStatus != "scheduled" && newStatus != "unsold" {
 				s.logger.Warn("Invalid status transition", "from", existingItem.Status, "to", newStatus)
 				return dto.AuctionItemDTO{}, ErrInvalidAuction
 			}
@@ -158,7 +212,36 @@ func (s *itemsService) Update(id int64, updateDTO *dto.AuctionItemUpdateDTO) (dt
 		return dto.AuctionItemDTO{}, ErrInvalidAuction
 	}
 
-	return dto.AuctionItemResponse(*existingItem), nil
+	return s.signedResponse(*existingItem), nil
+}
+
+// signedResponse converts the item with signed URLs in place of the private object names of the
+// photos copied from its donation, photos given as URLs are returned as they are
+func (s *itemsService) signedResponse(item entity.AuctionItem) dto.AuctionItemDTO {
+	res := dto.AuctionItemResponse(item)
+
+	ctx := context.Background()
+	sign := func(objectName string) string {
+		if objectName == "" || s.privateStore == nil {
+			return ""
+		}
+		url, err := s.privateStore.SignedURL(ctx, objectName, AuctionPhotoURLExpiry)
+		if err != nil {
+			// a private object name is no use to the client, an empty URL is shown as a missing photo
+			s.logger.Warn("Failed to sign auction item photo URL", "itemID", item.ID, "error", err)
+			return ""
+		}
+		return url
+	}
+	for i, p := range item.Photos {
+		if !p.Private {
+			continue
+		}
+		res.PhotoItems[i].URL = sign(p.URL)
+		res.PhotoItems[i].ThumbnailURL = sign(p.ThumbnailURL)
+		res.Photos[i] = res.PhotoItems[i].URL
+	}
+	return res
 }
 
 func (s *itemsService) Delete(id int64) error {
@@ -203,6 +286,12 @@ func (s *itemsService) CheckAndStartScheduledItems() error {
 				s.logger.Error("Failed to start auction item", "itemID", item.ID, "error", err)
 				continue
 			}
+			publishBidEvent(s.eventRepo, s.logger, dto.BidEventDTO{
+				Type:      dto.BidEventStatusChanged,
+				SessionID: item.Session.ID,
+				ItemID:    item.ID,
+				Status:    item.Status,
+			})
 			updatedCount++
 		}
 	}
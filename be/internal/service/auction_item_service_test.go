@@ -20,9 +20,11 @@ func TestAuctionItemService_Create(t *testing.T) {
 
 	mockRepo := mocks.NewMockAuctionItemRepository(ctrl)
 	mockAI := mocks.NewMockAIRepository(ctrl)
+	mockEventRepo := mocks.NewMockBidEventRepository(ctrl)
+	mockStorage := mocks.NewMockObjectStore(ctrl)
 	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
 
-	auctionService := NewAuctionItemService(mockRepo, mockAI, logger)
+	auctionService := NewAuctionItemService(mockRepo, mockAI, mockEventRepo, mockStorage, logger)
 
 	tests := []struct {
 		name    string
@@ -69,6 +71,52 @@ func TestAuctionItemService_Create(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "reserve and buy it now prices",
+			req: dto.AuctionItemDTO{
+				Title:        "Test Item",
+				Category:     "Electronics",
+				Description:  "Test description",
+				ReservePrice: floatPtr(500),
+				BuyNowPrice:  floatPtr(1000),
+			},
+			setup: func() {
+				mockAI.EXPECT().EstimateStartingPrice(gomock.Any()).Return(float64(100), nil)
+				mockRepo.EXPECT().Create(gomock.Any()).DoAndReturn(func(item *entity.AuctionItem) error {
+					assert.Equal(t, 500.0, *item.ReservePrice)
+					assert.Equal(t, 1000.0, *item.BuyNowPrice)
+					return nil
+				})
+			},
+			wantErr: false,
+		},
+		{
+			name: "buy it now below estimated starting price",
+			req: dto.AuctionItemDTO{
+				Title:       "Test Item",
+				Category:    "Electronics",
+				Description: "Test description",
+				BuyNowPrice: floatPtr(50),
+			},
+			setup: func() {
+				mockAI.EXPECT().EstimateStartingPrice(gomock.Any()).Return(float64(100), nil)
+			},
+			wantErr: true,
+		},
+		{
+			name: "buy it now below reserve price",
+			req: dto.AuctionItemDTO{
+				Title:        "Test Item",
+				Category:     "Electronics",
+				Description:  "Test description",
+				ReservePrice: floatPtr(2000),
+				BuyNowPrice:  floatPtr(1000),
+			},
+			setup: func() {
+				mockAI.EXPECT().EstimateStartingPrice(gomock.Any()).Return(float64(100), nil)
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -94,9 +142,11 @@ func TestAuctionItemService_GetAll(t *testing.T) {
 
 	mockRepo := mocks.NewMockAuctionItemRepository(ctrl)
 	mockAI := mocks.NewMockAIRepository(ctrl)
+	mockEventRepo := mocks.NewMockBidEventRepository(ctrl)
+	mockStorage := mocks.NewMockObjectStore(ctrl)
 	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
 
-	auctionService := NewAuctionItemService(mockRepo, mockAI, logger)
+	auctionService := NewAuctionItemService(mockRepo, mockAI, mockEventRepo, mockStorage, logger)
 
 	tests := []struct {
 		name    string
@@ -110,14 +160,14 @@ func TestAuctionItemService_GetAll(t *testing.T) {
 					{ID: 1, Title: "Item 1", StartingPrice: 100},
 					{ID: 2, Title: "Item 2", StartingPrice: 200},
 				}
-				mockRepo.EXPECT().GetAll().Return(items, nil)
+				mockRepo.EXPECT().GetAll(false).Return(items, nil)
 			},
 			wantErr: false,
 		},
 		{
 			name: "repository error",
 			setup: func() {
-				mockRepo.EXPECT().GetAll().Return(nil, errors.New("db error"))
+				mockRepo.EXPECT().GetAll(false).Return(nil, errors.New("db error"))
 			},
 			wantErr: true,
 		},
@@ -127,7 +177,7 @@ func TestAuctionItemService_GetAll(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.setup()
 
-			result, err := auctionService.GetAll()
+			result, err := auctionService.GetAll(false)
 
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -146,15 +196,18 @@ func TestAuctionItemService_GetByID(t *testing.T) {
 
 	mockRepo := mocks.NewMockAuctionItemRepository(ctrl)
 	mockAI := mocks.NewMockAIRepository(ctrl)
+	mockEventRepo := mocks.NewMockBidEventRepository(ctrl)
+	mockStorage := mocks.NewMockObjectStore(ctrl)
 	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
 
-	auctionService := NewAuctionItemService(mockRepo, mockAI, logger)
+	auctionService := NewAuctionItemService(mockRepo, mockAI, mockEventRepo, mockStorage, logger)
 
 	tests := []struct {
-		name    string
-		id      int64
-		setup   func()
-		wantErr bool
+		name       string
+		id         int64
+		setup      func()
+		wantErr    bool
+		wantPhotos []dto.AuctionItemPhotoDTO
 	}{
 		{
 			name: "successful get by id",
@@ -165,6 +218,31 @@ func TestAuctionItemService_GetByID(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "photos copied from the donation are signed",
+			id:   1,
+			setup: func() {
+				item := &entity.AuctionItem{ID: 1, Title: "Test Item", Status: "scheduled", Photos: []entity.AuctionItemPhoto{
+					{URL: "donations/private/1_display.jpg", ThumbnailURL: "donations/private/1_thumb.jpg", Private: true},
+					{URL: "https://cdn.example.com/2.jpg"},
+				}}
+				mockRepo.EXPECT().GetByID(int64(1)).Return(item, nil)
+				mockStorage.EXPECT().SignedURL(gomock.Any(), "donations/private/1_display.jpg", AuctionPhotoURLExpiry).Return("https://signed/display", nil)
+				mockStorage.EXPECT().SignedURL(gomock.Any(), "donations/private/1_thumb.jpg", AuctionPhotoURLExpiry).Return("", errors.New("storage down"))
+			},
+			wantPhotos: []dto.AuctionItemPhotoDTO{
+				{URL: "https://signed/display"},
+				{URL: "https://cdn.example.com/2.jpg"},
+			},
+		},
+		{
+			name: "draft hidden from bidders",
+			id:   3,
+			setup: func() {
+				mockRepo.EXPECT().GetByID(int64(3)).Return(&entity.AuctionItem{ID: 3, Status: "draft"}, nil)
+			},
+			wantErr: true,
+		},
 		{
 			name: "item not found",
 			id:   999,
@@ -179,7 +257,7 @@ func TestAuctionItemService_GetByID(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.setup()
 
-			result, err := auctionService.GetByID(tt.id)
+			result, err := auctionService.GetByID(tt.id, false)
 
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -187,6 +265,9 @@ func TestAuctionItemService_GetByID(t *testing.T) {
 			} else {
 				assert.NoError(t, err)
 				assert.Equal(t, int64(1), result.ID)
+				if tt.wantPhotos != nil {
+					assert.Equal(t, tt.wantPhotos, result.PhotoItems)
+				}
 			}
 		})
 	}
@@ -198,8 +279,10 @@ func TestAuctionItemService_Update(t *testing.T) {
 
 	mockRepo := mocks.NewMockAuctionItemRepository(ctrl)
 	mockAI := mocks.NewMockAIRepository(ctrl)
+	mockEventRepo := mocks.NewMockBidEventRepository(ctrl)
+	mockStorage := mocks.NewMockObjectStore(ctrl)
 	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
-	auctionService := NewAuctionItemService(mockRepo, mockAI, logger)
+	auctionService := NewAuctionItemService(mockRepo, mockAI, mockEventRepo, mockStorage, logger)
 
 	tests := []struct {
 		name    string
@@ -222,6 +305,31 @@ func TestAuctionItemService_Update(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "draft is scheduled",
+			id:   2,
+			req: func() *dto.AuctionItemUpdateDTO {
+				status := "scheduled"
+				return &dto.AuctionItemUpdateDTO{Status: &status}
+			}(),
+			setup: func() {
+				mockRepo.EXPECT().GetByID(int64(2)).Return(&entity.AuctionItem{ID: 2, Status: "draft"}, nil)
+				mockRepo.EXPECT().Update(gomock.Any()).Return(nil)
+			},
+			wantErr: false,
+		},
+		{
+			name: "draft cannot skip scheduling",
+			id:   2,
+			req: func() *dto.AuctionItemUpdateDTO {
+				status := "ongoing"
+				return &dto.AuctionItemUpdateDTO{Status: &status}
+			}(),
+			setup: func() {
+				mockRepo.EXPECT().GetByID(int64(2)).Return(&entity.AuctionItem{ID: 2, Status: "draft"}, nil)
+			},
+			wantErr: true,
+		},
 		{
 			name: "item not found",
 			id:   999,
@@ -252,8 +360,10 @@ func TestAuctionItemService_Delete(t *testing.T) {
 
 	mockRepo := mocks.NewMockAuctionItemRepository(ctrl)
 	mockAI := mocks.NewMockAIRepository(ctrl)
+	mockEventRepo := mocks.NewMockBidEventRepository(ctrl)
+	mockStorage := mocks.NewMockObjectStore(ctrl)
 	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
-	auctionService := NewAuctionItemService(mockRepo, mockAI, logger)
+	auctionService := NewAuctionItemService(mockRepo, mockAI, mockEventRepo, mockStorage, logger)
 
 	tests := []struct {
 		name    string
@@ -293,3 +403,7 @@ func TestAuctionItemService_Delete(t *testing.T) {
 		})
 	}
 }
+
+func floatPtr(f float64) *float64 {
+	return &f
+}
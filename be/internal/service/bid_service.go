@@ -1,17 +1,19 @@
 package service
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"log/slog"
+	"math"
+	"milestone3/be/config"
+	"milestone3/be/internal/dto"
 	"milestone3/be/internal/entity"
 	"milestone3/be/internal/repository"
+	"sort"
 	"strconv"
 	"strings"
-	"sync"
 	"time"
-
-	"github.com/redis/go-redis/v9"
 )
 
 const (
@@ -19,10 +21,7 @@ const (
 	MaxRetries      = 3
 )
 
-var (
-	mutex       sync.Map
-	wibLocation *time.Location
-)
+var wibLocation *time.Location
 
 func init() {
 	var err error
@@ -33,60 +32,129 @@ func init() {
 	}
 }
 
-func getMutex(itemID int64) *sync.Mutex {
-	m, _ := mutex.LoadOrStore(itemID, &sync.Mutex{})
-	return m.(*sync.Mutex)
-}
-
 type bidService struct {
 	redisRepo          repository.BidRedisRepository
 	bidRepo            repository.BidRepository
 	itemRepo           repository.AuctionItemRepository
 	auctionSessionRepo repository.AuctionSessionRepository
+	eventRepo          repository.BidEventRepository
+	incrementRepo      repository.BidIncrementRepository
+	softClose          config.SoftCloseConfig
 	logger             *slog.Logger
 }
 
 type BidService interface {
 	PlaceBid(sessionID, itemID, userID int64, amount float64, sessionEndTime time.Time) error
-	GetHighestBid(sessionID, itemID int64) (float64, int64, error)
+	RegisterProxyBid(sessionID, itemID, userID int64, maxAmount float64, sessionEndTime time.Time) error
+	GetProxyBid(sessionID, itemID, userID int64) (float64, error)
+	GetHighestBid(sessionID, itemID int64) (dto.HighestBidDTO, error)
+	SubscribeBidEvents(ctx context.Context, sessionID, itemID int64) (<-chan dto.BidEventDTO, error)
+	GetBidHistory(itemID int64, page, limit int) ([]dto.BidHistoryDTO, int64, error)
+	GetIncrementLadder(sessionID, itemID *int64) ([]dto.BidIncrementTierDTO, error)
+	SetIncrementLadder(sessionID, itemID *int64, tiers []dto.BidIncrementTierDTO) ([]dto.BidIncrementTierDTO, error)
 
 	SaveKeyToDB() error
 	DeleteKeyValue() error
 	CloseExpiredItemsWithoutBids() error
 }
 
-func NewBidService(r repository.BidRedisRepository, b repository.BidRepository, itemRepo repository.AuctionItemRepository, sessionRepo repository.AuctionSessionRepository, logger *slog.Logger) BidService {
+func NewBidService(r repository.BidRedisRepository, b repository.BidRepository, itemRepo repository.AuctionItemRepository, sessionRepo repository.AuctionSessionRepository, eventRepo repository.BidEventRepository, incrementRepo repository.BidIncrementRepository, softClose config.SoftCloseConfig, logger *slog.Logger) BidService {
 	return &bidService{
 		redisRepo:          r,
 		bidRepo:            b,
 		itemRepo:           itemRepo,
 		auctionSessionRepo: sessionRepo,
+		eventRepo:          eventRepo,
+		incrementRepo:      incrementRepo,
+		softClose:          softClose,
 		logger:             logger,
 	}
 }
 
-func (s *bidService) PlaceBid(sessionID, itemID, userID int64, amount float64, sessionEndTime time.Time) error {
-	if amount <= 0 {
-		return ErrInvalidBidding
+func activeBidKey(sessionID, itemID int64) string {
+	return fmt.Sprintf("active:auction:%d:item:%d", sessionID, itemID)
+}
+
+// effectiveEndTime is the session end, or the later end time a soft close stored for the item
+func (s *bidService) effectiveEndTime(sessionID, itemID int64, sessionEnd time.Time) time.Time {
+	extendedEnd, err := s.redisRepo.GetEndTime(activeBidKey(sessionID, itemID))
+	if err == nil && extendedEnd.After(sessionEnd) {
+		return extendedEnd
+	}
+	return sessionEnd
+}
+
+// incrementFor returns the step of the last tier starting at or below price, MinBidIncrement below the ladder
+func incrementFor(ladder []entity.BidIncrementTier, price float64) float64 {
+	step := float64(MinBidIncrement)
+	for _, tier := range ladder {
+		if price >= tier.MinPrice {
+			step = tier.Increment + math.Ceil(price*tier.Percent/100)
+		}
+	}
+	return step
+}
+
+// minNextBid is the lowest amount the next bid must reach, the starting price while nobody has bid yet
+func minNextBid(ladder []entity.BidIncrementTier, current, startingPrice float64) float64 {
+	if current <= 0 {
+		return startingPrice
+	}
+	return current + incrementFor(ladder, current)
+}
+
+// ladder falls back to MinBidIncrement steps when the tiers cannot be loaded, bidding must not stop for it
+func (s *bidService) ladder(sessionID, itemID int64) []entity.BidIncrementTier {
+	tiers, err := s.incrementRepo.GetLadder(sessionID, itemID)
+	if err != nil {
+		s.logger.Warn("failed to load bid increment ladder", "sessionID", sessionID, "itemID", itemID, "error", err)
+		return nil
+	}
+	return tiers
+}
+
+func (s *bidService) bidRules(item *entity.AuctionItem, ladder []entity.BidIncrementTier, sessionEndTime time.Time) repository.BidRules {
+	rules := repository.BidRules{
+		MinAmount:          item.StartingPrice,
+		MinIncrement:       MinBidIncrement,
+		Ladder:             ladder,
+		EndTime:            sessionEndTime,
+		SoftCloseWindow:    s.softClose.Window,
+		SoftCloseExtension: s.softClose.Extension,
+	}
+	if item.BuyNowPrice != nil {
+		rules.BuyNowPrice = *item.BuyNowPrice
+	}
+	return rules
+}
+
+// publishBidEvent is best effort, a failed publish must not fail the bid or the cron job
+func publishBidEvent(eventRepo repository.BidEventRepository, logger *slog.Logger, event dto.BidEventDTO) {
+	event.Timestamp = time.Now().In(wibLocation)
+	if err := eventRepo.Publish(event); err != nil {
+		logger.Warn("failed to publish bid event", "type", event.Type, "sessionID", event.SessionID, "itemID", event.ItemID, "error", err)
 	}
+}
 
+// validateBiddableItem checks the item is ongoing in an active session
+func (s *bidService) validateBiddableItem(sessionID, itemID int64) (*entity.AuctionItem, error) {
 	item, err := s.itemRepo.GetByID(itemID)
 	if err != nil {
-		return ErrAuctionNotFound
+		return nil, ErrAuctionNotFound
 	}
 
 	if item.SessionID == nil || *item.SessionID != sessionID {
-		return ErrInvalidAuction
+		return nil, ErrInvalidAuction
 	}
 
 	if item.Status != "ongoing" {
-		return ErrInvalidAuction
+		return nil, ErrInvalidAuction
 	}
 
 	// validate session has started
 	session, err := s.auctionSessionRepo.GetByID(sessionID)
 	if err != nil {
-		return ErrSessionNotFoundID
+		return nil, ErrSessionNotFoundID
 	}
 
 	// Convert both to same timezone for comparison
@@ -97,62 +165,330 @@ func (s *bidService) PlaceBid(sessionID, itemID, userID int64, amount float64, s
 	sessionEnd := session.EndTime.In(wibLocation)
 
 	if now.Before(sessionStart) {
-		return ErrInvalidAuction
+		return nil, ErrInvalidAuction
 	}
 
-	if now.After(sessionEnd) {
-		return ErrInvalidAuction
+	// a soft close may have extended the item past the session end
+	if now.After(sessionEnd) && now.After(s.effectiveEndTime(sessionID, itemID, sessionEnd).In(wibLocation)) {
+		return nil, ErrInvalidAuction
 	}
 
-	if err = s.redisRepo.CheckDuplicateBid(userID, itemID, amount, 10*time.Second); err != nil {
-		return ErrDuplicateBid
+	return item, nil
+}
+
+// recordPlacedBids writes the bids stored by a bid script through to the bid ledger and
+// announces the new highest bid. a bid the ledger write failed for is queued in redis and
+// written when the item closes, ErrBidNotRecorded is returned when it cannot be queued either
+func (s *bidService) recordPlacedBids(sessionID, itemID int64, result repository.BidResult) error {
+	if len(result.Placed) == 0 {
+		return nil
 	}
 
-	// lock per item
-	mu := getMutex(itemID)
-	mu.Lock()
-	defer mu.Unlock()
+	var unsaved []repository.BidEntry
+	for _, placed := range result.Placed {
+		if err := s.bidRepo.SaveBid(&entity.Bid{
+			ItemID: itemID,
+			UserID: placed.UserID,
+			Amount: placed.Amount,
+		}); err != nil {
+			s.logger.Warn("failed to persist bid, queued until the item closes", "sessionID", sessionID, "itemID", itemID, "userID", placed.UserID, "amount", placed.Amount, "error", err)
+			unsaved = append(unsaved, placed)
+		}
+	}
 
-	currentHighest, currentBid, err := s.redisRepo.GetHighestBid(sessionID, itemID)
-	if err != nil && !errors.Is(err, redis.Nil) {
-		return err
+	var recordErr error
+	if len(unsaved) > 0 {
+		if err := s.redisRepo.QueueUnsavedBids(sessionID, itemID, unsaved); err != nil {
+			s.logger.Error("failed to queue unsaved bids", "sessionID", sessionID, "itemID", itemID, "count", len(unsaved), "error", err)
+			recordErr = ErrBidNotRecorded
+		}
+	}
+
+	publishBidEvent(s.eventRepo, s.logger, dto.BidEventDTO{
+		Type:      dto.BidEventPlaced,
+		SessionID: sessionID,
+		ItemID:    itemID,
+		Amount:    result.HighestAmount,
+		BidderID:  result.HighestBidder,
+	})
+
+	if result.Extended {
+		s.extendEndTime(sessionID, itemID, result.EndTime)
+	}
+
+	return recordErr
+}
+
+// closeIfBoughtNow ends the item right away when buy it now was met, redis already refuses
+// further bids so a failure here is picked up by the next SaveKeyToDB run
+func (s *bidService) closeIfBoughtNow(sessionID int64, item *entity.AuctionItem, result repository.BidResult) {
+	if !result.Closed {
+		return
+	}
+
+	s.logger.Info("buy it now price met", "sessionID", sessionID, "itemID", item.ID, "winner", result.HighestBidder)
+	_ = s.closeItem(sessionID, item, activeBidKey(sessionID, item.ID), repository.BidEntry{
+		UserID: result.HighestBidder,
+		ItemID: item.ID,
+		Amount: result.HighestAmount,
+		Closed: true,
+	})
+}
+
+// extendEndTime propagates a soft close, redis already holds the new end time of the bid item
+func (s *bidService) extendEndTime(sessionID, itemID int64, endTime time.Time) {
+	s.logger.Info("auction end time extended", "sessionID", sessionID, "itemID", itemID, "scope", s.softClose.Scope, "endTime", endTime)
+
+	if s.softClose.Scope == config.SoftCloseScopeSession {
+		if err := s.auctionSessionRepo.ExtendEndTime(sessionID, endTime); err != nil {
+			s.logger.Error("failed to extend session end time", "sessionID", sessionID, "error", err)
+		}
+		if err := s.redisRepo.ExtendSessionEndTime(sessionID, endTime); err != nil {
+			s.logger.Error("failed to extend session items end time", "sessionID", sessionID, "error", err)
+		}
 	}
 
-	// validate amount > starting price
-	if currentHighest == 0 {
-		if amount < item.StartingPrice {
-			return ErrBidTooLow
+	itemIDs := []int64{itemID}
+	if s.softClose.Scope == config.SoftCloseScopeSession {
+		// every item of the session moved, tell all of their subscribers
+		items, err := s.itemRepo.ReadBySession(sessionID)
+		if err != nil {
+			s.logger.Warn("failed to get session items for end time event", "sessionID", sessionID, "error", err)
+		} else {
+			itemIDs = itemIDs[:0]
+			for _, item := range items {
+				itemIDs = append(itemIDs, item.ID)
+			}
 		}
 	}
 
-	if currentHighest > 0 && amount <= currentHighest {
-		return ErrBidTooLow
+	extendedEnd := endTime.In(wibLocation)
+	for _, id := range itemIDs {
+		publishBidEvent(s.eventRepo, s.logger, dto.BidEventDTO{
+			Type:      dto.BidEventExtended,
+			SessionID: sessionID,
+			ItemID:    id,
+			EndTime:   &extendedEnd,
+		})
+	}
+}
+
+func (s *bidService) PlaceBid(sessionID, itemID, userID int64, amount float64, sessionEndTime time.Time) error {
+	if amount <= 0 {
+		return ErrInvalidBidding
 	}
 
-	if currentBid == userID {
-		return ErrAlreadyHighestBidder
+	item, err := s.validateBiddableItem(sessionID, itemID)
+	if err != nil {
+		return err
 	}
 
-	if currentHighest > 0 && amount < currentHighest+MinBidIncrement {
-		return ErrBidTooLow
+	if err = s.redisRepo.CheckDuplicateBid(userID, itemID, amount, 10*time.Second); err != nil {
+		return ErrDuplicateBid
 	}
 
-	if err := s.redisRepo.SetHighestBid(sessionID, itemID, amount, userID, sessionEndTime); err != nil {
+	// validate against the current highest bid and store it in one atomic redis step,
+	// so concurrent bids across instances cannot overwrite each other
+	ladder := s.ladder(sessionID, itemID)
+	result, err := s.redisRepo.CompareAndSetHighestBid(sessionID, itemID, amount, userID, s.bidRules(item, ladder, sessionEndTime))
+	if err != nil {
 		s.logger.Error("failed to set highest bid", "error", err)
 		return err
 	}
 
+	rejected := func(err error) error {
+		return &BidRejectedError{Err: err, MinNextBid: minNextBid(ladder, result.HighestAmount, item.StartingPrice)}
+	}
+
+	switch result.Status {
+	case repository.BidRejectedTooLow:
+		return rejected(ErrBidTooLow)
+	case repository.BidRejectedAlreadyOwner:
+		return rejected(ErrAlreadyHighestBidder)
+	case repository.BidRejectedClosed:
+		return ErrInvalidAuction
+	}
+
+	recordErr := s.recordPlacedBids(sessionID, itemID, result)
+	s.closeIfBoughtNow(sessionID, item, result)
+	if recordErr != nil {
+		return recordErr
+	}
+
 	s.logger.Info("bid placed", "sessionID", sessionID, "itemID", itemID, "userID", userID, "amount", amount)
 
+	// the bid is kept in the history but a proxy ceiling answered it right away
+	if result.HighestBidder != userID {
+		return rejected(ErrOutbidByProxy)
+	}
+
+	return nil
+}
+
+// RegisterProxyBid stores a hidden maximum for the user, the system then bids on their
+// behalf in increment ladder steps up to that ceiling
+func (s *bidService) RegisterProxyBid(sessionID, itemID, userID int64, maxAmount float64, sessionEndTime time.Time) error {
+	if maxAmount <= 0 {
+		return ErrInvalidBidding
+	}
+
+	item, err := s.validateBiddableItem(sessionID, itemID)
+	if err != nil {
+		return err
+	}
+
+	ladder := s.ladder(sessionID, itemID)
+	result, err := s.redisRepo.RegisterProxyBid(sessionID, itemID, userID, maxAmount, s.bidRules(item, ladder, sessionEndTime))
+	if err != nil {
+		s.logger.Error("failed to register proxy bid", "error", err)
+		return err
+	}
+
+	rejected := func(err error) error {
+		return &BidRejectedError{Err: err, MinNextBid: minNextBid(ladder, result.HighestAmount, item.StartingPrice)}
+	}
+
+	switch result.Status {
+	case repository.BidRejectedTooLow:
+		return rejected(ErrBidTooLow)
+	case repository.BidRejectedClosed:
+		return ErrInvalidAuction
+	}
+
+	recordErr := s.recordPlacedBids(sessionID, itemID, result)
+	s.closeIfBoughtNow(sessionID, item, result)
+	if recordErr != nil {
+		return recordErr
+	}
+
+	// never log the ceiling itself next to the user, only that one exists
+	s.logger.Info("proxy bid registered", "sessionID", sessionID, "itemID", itemID, "userID", userID)
+
+	if result.HighestBidder != userID {
+		return rejected(ErrOutbidByProxy)
+	}
+
+	return nil
+}
+
+// GetProxyBid returns the ceiling registered by the user, 0 if none
+func (s *bidService) GetProxyBid(sessionID, itemID, userID int64) (float64, error) {
+	item, err := s.itemRepo.GetByID(itemID)
+	if err != nil {
+		return 0, ErrAuctionNotFound
+	}
+
+	if item.SessionID == nil || *item.SessionID != sessionID {
+		return 0, ErrInvalidAuction
+	}
+
+	return s.redisRepo.GetProxyBid(sessionID, itemID, userID)
+}
+
+func (s *bidService) GetHighestBid(sessionID, itemID int64) (dto.HighestBidDTO, error) {
+	item, err := s.itemRepo.GetByID(itemID)
+	if err != nil {
+		return dto.HighestBidDTO{}, ErrAuctionNotFound
+	}
+
+	highest, bidder, err := s.redisRepo.GetHighestBid(sessionID, itemID)
+	if err != nil {
+		return dto.HighestBidDTO{}, err
+	}
+
+	return dto.HighestBidDTO{
+		SessionID:  sessionID,
+		ItemID:     itemID,
+		HighestBid: highest,
+		BidderID:   bidder,
+		MinNextBid: minNextBid(s.ladder(sessionID, itemID), highest, item.StartingPrice),
+	}, nil
+}
+
+// checkLadderScope makes sure the session or item a ladder is attached to exists
+func (s *bidService) checkLadderScope(sessionID, itemID *int64) error {
+	if itemID != nil {
+		if _, err := s.itemRepo.GetByID(*itemID); err != nil {
+			return ErrAuctionNotFound
+		}
+	}
+	if sessionID != nil {
+		if _, err := s.auctionSessionRepo.GetByID(*sessionID); err != nil {
+			return ErrSessionNotFoundID
+		}
+	}
 	return nil
 }
 
-func (s *bidService) GetHighestBid(sessionID, itemID int64) (float64, int64, error) {
+// GetIncrementLadder returns the tiers defined on the scope itself, nil ids mean the global ladder
+func (s *bidService) GetIncrementLadder(sessionID, itemID *int64) ([]dto.BidIncrementTierDTO, error) {
+	if err := s.checkLadderScope(sessionID, itemID); err != nil {
+		return nil, err
+	}
+
+	tiers, err := s.incrementRepo.GetScopeLadder(sessionID, itemID)
+	if err != nil {
+		s.logger.Error("failed to get bid increment ladder", "error", err)
+		return nil, err
+	}
+
+	return dto.BidIncrementTierResponses(tiers), nil
+}
+
+// SetIncrementLadder replaces the tiers of the scope, an empty list removes a session or item override
+func (s *bidService) SetIncrementLadder(sessionID, itemID *int64, tiers []dto.BidIncrementTierDTO) ([]dto.BidIncrementTierDTO, error) {
+	if err := s.checkLadderScope(sessionID, itemID); err != nil {
+		return nil, err
+	}
+
+	seen := make(map[float64]bool, len(tiers))
+	for _, tier := range tiers {
+		if tier.MinPrice < 0 || tier.Increment < 0 || tier.Percent < 0 || tier.Percent > 100 {
+			return nil, ErrInvalidIncrementLadder
+		}
+		if tier.Increment+tier.Percent <= 0 || seen[tier.MinPrice] {
+			return nil, ErrInvalidIncrementLadder
+		}
+		seen[tier.MinPrice] = true
+	}
+
+	models := dto.BidIncrementTierRequests(tiers)
+	sort.Slice(models, func(i, j int) bool { return models[i].MinPrice < models[j].MinPrice })
+
+	if err := s.incrementRepo.ReplaceLadder(sessionID, itemID, models); err != nil {
+		s.logger.Error("failed to replace bid increment ladder", "error", err)
+		return nil, err
+	}
+
+	return dto.BidIncrementTierResponses(models), nil
+}
+
+func (s *bidService) GetBidHistory(itemID int64, page, limit int) ([]dto.BidHistoryDTO, int64, error) {
 	_, err := s.itemRepo.GetByID(itemID)
 	if err != nil {
-		return 0, 0, ErrAuctionNotFound
+		return nil, 0, ErrAuctionNotFound
+	}
+
+	bids, total, err := s.bidRepo.GetByItemID(itemID, page, limit)
+	if err != nil {
+		s.logger.Error("failed to get bid history", "itemID", itemID, "error", err)
+		return nil, 0, err
+	}
+
+	return dto.BidHistoryResponses(bids), total, nil
+}
+
+func (s *bidService) SubscribeBidEvents(ctx context.Context, sessionID, itemID int64) (<-chan dto.BidEventDTO, error) {
+	item, err := s.itemRepo.GetByID(itemID)
+	if err != nil {
+		return nil, ErrAuctionNotFound
 	}
-	return s.redisRepo.GetHighestBid(sessionID, itemID)
+
+	if item.SessionID == nil || *item.SessionID != sessionID {
+		return nil, ErrInvalidAuction
+	}
+
+	return s.eventRepo.Subscribe(ctx, sessionID, itemID)
 }
 
 func parseKey(key string) (sessionID, itemID int64, err error) {
@@ -199,61 +535,40 @@ func (s *bidService) SaveKeyToDB() error {
 			continue
 		}
 
-		// fetch end_time from database for accuracy
-		session, err := s.auctionSessionRepo.GetByID(parsedSessionID)
+		// get bid data from redis
+		bid, err := s.redisRepo.GetBidByKey(key)
 		if err != nil {
 			continue
 		}
 
-		// Convert both to same timezone for comparison
-		now := time.Now().In(wibLocation)
-
-		// DB stores UTC, convert to WIB
-		endTimeLocal := session.EndTime.In(wibLocation)
+		// an item closed by buy it now is final right away
+		if !bid.Closed {
+			// fetch end_time from database for accuracy
+			session, err := s.auctionSessionRepo.GetByID(parsedSessionID)
+			if err != nil {
+				continue
+			}
 
-		if now.Before(endTimeLocal) {
-			continue
-		}
+			// Convert both to same timezone for comparison
+			now := time.Now().In(wibLocation)
 
-		// get bid data from redis
-		bid, err := s.redisRepo.GetBidByKey(key)
-		if err != nil {
-			continue
-		}
+			// DB stores UTC, convert to WIB, a soft close may have pushed the item end back
+			endTimeLocal := s.effectiveEndTime(parsedSessionID, itemID, session.EndTime).In(wibLocation)
 
-		// save final bid to DB
-		err = s.bidRepo.SaveFinalBid(&entity.Bid{
-			ItemID: itemID,
-			UserID: bid.UserID,
-			Amount: bid.Amount,
-		})
-		if err != nil {
-			s.logger.Error("failed to save final bid", "sessionID", parsedSessionID, "itemID", itemID, "error", err)
-			continue
+			if now.Before(endTimeLocal) {
+				continue
+			}
 		}
 
-		// set status to finished
 		item, err := s.itemRepo.GetByID(itemID)
 		if err != nil {
-			s.logger.Warn("failed to get item for status update", "itemID", itemID, "error", err)
-		} else {
-			item.Status = "finished"
-			if err := s.itemRepo.Update(item); err != nil {
-				s.logger.Error("failed to update item status", "itemID", itemID, "error", err)
-			}
+			s.logger.Warn("failed to get item for finalization", "itemID", itemID, "error", err)
+			continue
 		}
 
-		// delete redis key after saved to table Bid
-		if err := s.redisRepo.DeleteKey(key); err != nil {
-			s.logger.Warn("failed to delete Redis key", "key", key, "error", err)
+		if err := s.closeItem(parsedSessionID, item, key, bid); err != nil {
+			continue
 		}
-
-		s.logger.Info("final bid saved",
-			"sessionID", parsedSessionID,
-			"itemID", itemID,
-			"amount", bid.Amount,
-			"winner", bid.UserID,
-		)
 		totalSavedAuctionItemToDB++
 	}
 
@@ -269,9 +584,99 @@ func (s *bidService) SaveKeyToDB() error {
 	return nil
 }
 
+// closeItem settles an item once bidding is over, below the hidden reserve it ends unsold
+// without a winner, otherwise the highest bid wins. The winning bid, the item status and the
+// outbox event are written in one transaction, the redis key is only deleted after the commit
+// and kept on failure so the next run retries.
+func (s *bidService) closeItem(sessionID int64, item *entity.AuctionItem, key string, bid repository.BidEntry) error {
+	event := dto.BidEventDTO{
+		Type:      dto.BidEventStatusChanged,
+		SessionID: sessionID,
+		ItemID:    item.ID,
+	}
+	outcome := entity.AuctionOutcome{
+		SessionID: sessionID,
+		ItemID:    item.ID,
+		Amount:    bid.Amount,
+	}
+
+	var winningBid *entity.Bid
+	eventType := entity.OutboxItemUnsold
+	if item.ReservePrice != nil && bid.Amount < *item.ReservePrice {
+		item.Status = "unsold"
+	} else {
+		item.Status = "finished"
+		eventType = entity.OutboxItemWon
+		winningBid = &entity.Bid{
+			ItemID: item.ID,
+			UserID: bid.UserID,
+			Amount: bid.Amount,
+		}
+		outcome.WinnerID = bid.UserID
+		event.Amount = bid.Amount
+		event.BidderID = bid.UserID
+	}
+
+	outboxEvent, err := newOutboxEvent(eventType, item.ID, outcomeDedupKey(eventType, outcome), outcome)
+	if err != nil {
+		return err
+	}
+
+	// bids the write-through missed go to the ledger with the outcome, closing without them would lose them
+	queued, err := s.redisRepo.GetUnsavedBids(sessionID, item.ID)
+	if err != nil {
+		s.logger.Error("failed to get unsaved bids", "sessionID", sessionID, "itemID", item.ID, "error", err)
+		return err
+	}
+	unsavedBids := make([]entity.Bid, 0, len(queued))
+	for _, queuedBid := range queued {
+		unsavedBids = append(unsavedBids, entity.Bid{ItemID: item.ID, UserID: queuedBid.UserID, Amount: queuedBid.Amount})
+	}
+
+	if err := s.bidRepo.FinalizeItem(item.ID, item.Status, unsavedBids, winningBid, outboxEvent); err != nil {
+		s.logger.Error("failed to finalize item", "sessionID", sessionID, "itemID", item.ID, "status", item.Status, "error", err)
+		return err
+	}
+
+	event.Status = item.Status
+	publishBidEvent(s.eventRepo, s.logger, event)
+
+	// the outcome is committed, a key left behind only makes the next run finalize it again harmlessly
+	if err := s.redisRepo.DeleteKey(key); err != nil {
+		s.logger.Warn("failed to delete Redis key", "key", key, "error", err)
+	}
+	if len(queued) > 0 {
+		if err := s.redisRepo.DeleteUnsavedBids(sessionID, item.ID); err != nil {
+			s.logger.Warn("failed to delete unsaved bids", "sessionID", sessionID, "itemID", item.ID, "error", err)
+		}
+	}
+
+	if item.Status == "unsold" {
+		// the reserve itself is never logged next to bids
+		s.logger.Info("item closed below reserve", "sessionID", sessionID, "itemID", item.ID, "highestBid", bid.Amount)
+	} else {
+		s.logger.Info("final bid saved",
+			"sessionID", sessionID,
+			"itemID", item.ID,
+			"amount", bid.Amount,
+			"winner", bid.UserID,
+		)
+	}
+
+	return nil
+}
+
+// outcomeDedupKey identifies one auction round of an item, a relisted item that closes
+// again with another result gets a new key
+func outcomeDedupKey(eventType string, outcome entity.AuctionOutcome) string {
+	return fmt.Sprintf("%s:session:%d:item:%d:user:%d:amount:%s",
+		eventType, outcome.SessionID, outcome.ItemID, outcome.WinnerID,
+		strconv.FormatFloat(outcome.Amount, 'f', -1, 64))
+}
+
 // CloseExpiredItemsWithoutBids to get the redis key with no bids
 func (s *bidService) CloseExpiredItemsWithoutBids() error {
-	items, err := s.itemRepo.GetAll()
+	items, err := s.itemRepo.GetAll(false)
 	if err != nil {
 		return err
 	}
@@ -309,6 +714,12 @@ func (s *bidService) CloseExpiredItemsWithoutBids() error {
 				if err := s.itemRepo.Update(&item); err != nil {
 					s.logger.Error("failed to revert item to scheduled", "itemID", item.ID, "error", err)
 				} else {
+					publishBidEvent(s.eventRepo, s.logger, dto.BidEventDTO{
+						Type:      dto.BidEventStatusChanged,
+						SessionID: *item.SessionID,
+						ItemID:    item.ID,
+						Status:    item.Status,
+					})
 					closedCount++
 				}
 			}
@@ -1,14 +1,18 @@
 package service
 
 import (
+	"context"
 	"errors"
 	"log/slog"
 	"os"
 	"testing"
 	"time"
 
+	"milestone3/be/config"
+	"milestone3/be/internal/dto"
 	"milestone3/be/internal/entity"
 	"milestone3/be/internal/mocks"
+	"milestone3/be/internal/repository"
 
 	"github.com/golang/mock/gomock"
 
@@ -23,9 +27,19 @@ func TestBidService_PlaceBid(t *testing.T) {
 	mockBidRepo := mocks.NewMockBidRepository(ctrl)
 	mockItemRepo := mocks.NewMockAuctionItemRepository(ctrl)
 	mockSessionRepo := mocks.NewMockAuctionSessionRepository(ctrl)
+	mockEventRepo := mocks.NewMockBidEventRepository(ctrl)
+	mockIncrementRepo := mocks.NewMockBidIncrementRepository(ctrl)
+	mockIncrementRepo.EXPECT().GetLadder(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
 	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
 
-	bidService := NewBidService(mockRedisRepo, mockBidRepo, mockItemRepo, mockSessionRepo, logger)
+	bidService := NewBidService(mockRedisRepo, mockBidRepo, mockItemRepo, mockSessionRepo, mockEventRepo, mockIncrementRepo, config.SoftCloseConfig{}, logger)
+
+	sessionID := int64(1)
+	activeSession := &entity.AuctionSession{
+		ID:        sessionID,
+		StartTime: time.Now().Add(-time.Hour),
+		EndTime:   time.Now().Add(time.Hour),
+	}
 
 	tests := []struct {
 		name           string
@@ -35,25 +49,47 @@ func TestBidService_PlaceBid(t *testing.T) {
 		amount         float64
 		sessionEndTime time.Time
 		setup          func()
-		wantErr        bool
+		wantErr        error
 	}{
 		{
 			name:           "successful bid placement",
 			sessionID:      1,
 			itemID:         1,
 			userID:         1,
-			amount:         150.0,
+			amount:         20000.0,
 			sessionEndTime: time.Now().Add(time.Hour),
 			setup: func() {
 				item := &entity.AuctionItem{
-					ID:     1,
-					Status: "ongoing",
+					ID:        1,
+					SessionID: &sessionID,
+					Status:    "ongoing",
 				}
 				mockItemRepo.EXPECT().GetByID(int64(1)).Return(item, nil)
-				mockRedisRepo.EXPECT().GetHighestBid(int64(1), int64(1)).Return(100.0, int64(2), nil)
-				mockRedisRepo.EXPECT().SetHighestBid(int64(1), int64(1), 150.0, int64(1), gomock.Any()).Return(nil)
+				mockSessionRepo.EXPECT().GetByID(int64(1)).Return(activeSession, nil)
+				mockRedisRepo.EXPECT().CheckDuplicateBid(int64(1), int64(1), 20000.0, gomock.Any()).Return(nil)
+				mockRedisRepo.EXPECT().CompareAndSetHighestBid(int64(1), int64(1), 20000.0, int64(1), gomock.Any()).
+					Do(func(_, _ int64, _ float64, _ int64, rules repository.BidRules) {
+						assert.Equal(t, float64(MinBidIncrement), rules.MinIncrement)
+					}).
+					Return(repository.BidResult{
+						Status:        repository.BidAccepted,
+						HighestAmount: 20000.0,
+						HighestBidder: 1,
+						Placed:        []repository.BidEntry{{UserID: 1, ItemID: 1, Amount: 20000.0}},
+					}, nil)
+				mockBidRepo.EXPECT().SaveBid(gomock.Any()).DoAndReturn(func(bid *entity.Bid) error {
+					assert.Equal(t, int64(1), bid.ItemID)
+					assert.Equal(t, int64(1), bid.UserID)
+					assert.Equal(t, 20000.0, bid.Amount)
+					return nil
+				})
+				mockEventRepo.EXPECT().Publish(gomock.Any()).DoAndReturn(func(event dto.BidEventDTO) error {
+					assert.Equal(t, dto.BidEventPlaced, event.Type)
+					assert.Equal(t, 20000.0, event.Amount)
+					assert.Equal(t, int64(1), event.BidderID)
+					return nil
+				})
 			},
-			wantErr: false,
 		},
 		{
 			name:           "invalid bid amount - zero",
@@ -63,7 +99,7 @@ func TestBidService_PlaceBid(t *testing.T) {
 			amount:         0,
 			sessionEndTime: time.Now().Add(time.Hour),
 			setup:          func() {},
-			wantErr:        true,
+			wantErr:        ErrInvalidBidding,
 		},
 		{
 			name:           "item not found",
@@ -75,7 +111,7 @@ func TestBidService_PlaceBid(t *testing.T) {
 			setup: func() {
 				mockItemRepo.EXPECT().GetByID(int64(999)).Return(nil, errors.New("not found"))
 			},
-			wantErr: true,
+			wantErr: ErrAuctionNotFound,
 		},
 		{
 			name:           "item not ongoing",
@@ -91,7 +127,7 @@ func TestBidService_PlaceBid(t *testing.T) {
 				}
 				mockItemRepo.EXPECT().GetByID(int64(1)).Return(item, nil)
 			},
-			wantErr: true,
+			wantErr: ErrInvalidAuction,
 		},
 		{
 			name:           "bid too low",
@@ -102,13 +138,73 @@ func TestBidService_PlaceBid(t *testing.T) {
 			sessionEndTime: time.Now().Add(time.Hour),
 			setup: func() {
 				item := &entity.AuctionItem{
-					ID:     1,
-					Status: "ongoing",
+					ID:        1,
+					SessionID: &sessionID,
+					Status:    "ongoing",
 				}
 				mockItemRepo.EXPECT().GetByID(int64(1)).Return(item, nil)
-				mockRedisRepo.EXPECT().GetHighestBid(int64(1), int64(1)).Return(100.0, int64(2), nil)
+				mockSessionRepo.EXPECT().GetByID(int64(1)).Return(activeSession, nil)
+				mockRedisRepo.EXPECT().CheckDuplicateBid(int64(1), int64(1), 50.0, gomock.Any()).Return(nil)
+				mockRedisRepo.EXPECT().CompareAndSetHighestBid(int64(1), int64(1), 50.0, int64(1), gomock.Any()).
+					Return(repository.BidResult{Status: repository.BidRejectedTooLow, HighestAmount: 100.0, HighestBidder: 2}, nil)
 			},
-			wantErr: true,
+			wantErr: ErrBidTooLow,
+		},
+		{
+			name:           "already highest bidder",
+			sessionID:      1,
+			itemID:         1,
+			userID:         2,
+			amount:         30000.0,
+			sessionEndTime: time.Now().Add(time.Hour),
+			setup: func() {
+				item := &entity.AuctionItem{
+					ID:        1,
+					SessionID: &sessionID,
+					Status:    "ongoing",
+				}
+				mockItemRepo.EXPECT().GetByID(int64(1)).Return(item, nil)
+				mockSessionRepo.EXPECT().GetByID(int64(1)).Return(activeSession, nil)
+				mockRedisRepo.EXPECT().CheckDuplicateBid(int64(2), int64(1), 30000.0, gomock.Any()).Return(nil)
+				mockRedisRepo.EXPECT().CompareAndSetHighestBid(int64(1), int64(1), 30000.0, int64(2), gomock.Any()).
+					Return(repository.BidResult{Status: repository.BidRejectedAlreadyOwner, HighestAmount: 20000.0, HighestBidder: 2}, nil)
+			},
+			wantErr: ErrAlreadyHighestBidder,
+		},
+		{
+			name:           "outbid by proxy bid",
+			sessionID:      1,
+			itemID:         1,
+			userID:         3,
+			amount:         40000.0,
+			sessionEndTime: time.Now().Add(time.Hour),
+			setup: func() {
+				item := &entity.AuctionItem{
+					ID:        1,
+					SessionID: &sessionID,
+					Status:    "ongoing",
+				}
+				mockItemRepo.EXPECT().GetByID(int64(1)).Return(item, nil)
+				mockSessionRepo.EXPECT().GetByID(int64(1)).Return(activeSession, nil)
+				mockRedisRepo.EXPECT().CheckDuplicateBid(int64(3), int64(1), 40000.0, gomock.Any()).Return(nil)
+				mockRedisRepo.EXPECT().CompareAndSetHighestBid(int64(1), int64(1), 40000.0, int64(3), gomock.Any()).
+					Return(repository.BidResult{
+						Status:        repository.BidAccepted,
+						HighestAmount: 50000.0,
+						HighestBidder: 2,
+						Placed: []repository.BidEntry{
+							{UserID: 3, ItemID: 1, Amount: 40000.0},
+							{UserID: 2, ItemID: 1, Amount: 50000.0},
+						},
+					}, nil)
+				mockBidRepo.EXPECT().SaveBid(gomock.Any()).Return(nil).Times(2)
+				mockEventRepo.EXPECT().Publish(gomock.Any()).DoAndReturn(func(event dto.BidEventDTO) error {
+					assert.Equal(t, 50000.0, event.Amount)
+					assert.Equal(t, int64(2), event.BidderID)
+					return nil
+				})
+			},
+			wantErr: ErrOutbidByProxy,
 		},
 	}
 
@@ -118,8 +214,146 @@ func TestBidService_PlaceBid(t *testing.T) {
 
 			err := bidService.PlaceBid(tt.sessionID, tt.itemID, tt.userID, tt.amount, tt.sessionEndTime)
 
-			if tt.wantErr {
-				assert.Error(t, err)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestBidService_RegisterProxyBid(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRedisRepo := mocks.NewMockBidRedisRepository(ctrl)
+	mockBidRepo := mocks.NewMockBidRepository(ctrl)
+	mockItemRepo := mocks.NewMockAuctionItemRepository(ctrl)
+	mockSessionRepo := mocks.NewMockAuctionSessionRepository(ctrl)
+	mockEventRepo := mocks.NewMockBidEventRepository(ctrl)
+	mockIncrementRepo := mocks.NewMockBidIncrementRepository(ctrl)
+	mockIncrementRepo.EXPECT().GetLadder(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	bidService := NewBidService(mockRedisRepo, mockBidRepo, mockItemRepo, mockSessionRepo, mockEventRepo, mockIncrementRepo, config.SoftCloseConfig{}, logger)
+
+	sessionID := int64(1)
+	activeSession := &entity.AuctionSession{
+		ID:        sessionID,
+		StartTime: time.Now().Add(-time.Hour),
+		EndTime:   time.Now().Add(time.Hour),
+	}
+	item := &entity.AuctionItem{
+		ID:            1,
+		SessionID:     &sessionID,
+		Status:        "ongoing",
+		StartingPrice: 50000,
+	}
+
+	tests := []struct {
+		name      string
+		userID    int64
+		maxAmount float64
+		setup     func()
+		wantErr   error
+	}{
+		{
+			name:      "ceiling takes the lead",
+			userID:    1,
+			maxAmount: 100000,
+			setup: func() {
+				mockItemRepo.EXPECT().GetByID(int64(1)).Return(item, nil)
+				mockSessionRepo.EXPECT().GetByID(int64(1)).Return(activeSession, nil)
+				mockRedisRepo.EXPECT().RegisterProxyBid(int64(1), int64(1), int64(1), 100000.0, repository.BidRules{MinAmount: 50000.0, MinIncrement: MinBidIncrement, EndTime: activeSession.EndTime}).
+					Return(repository.BidResult{
+						Status:        repository.BidAccepted,
+						HighestAmount: 50000.0,
+						HighestBidder: 1,
+						Placed:        []repository.BidEntry{{UserID: 1, ItemID: 1, Amount: 50000.0}},
+					}, nil)
+				mockBidRepo.EXPECT().SaveBid(gomock.Any()).DoAndReturn(func(bid *entity.Bid) error {
+					// only the bid amount is stored, never the ceiling
+					assert.Equal(t, 50000.0, bid.Amount)
+					return nil
+				})
+				mockEventRepo.EXPECT().Publish(gomock.Any()).DoAndReturn(func(event dto.BidEventDTO) error {
+					assert.Equal(t, 50000.0, event.Amount)
+					return nil
+				})
+			},
+		},
+		{
+			name:      "ceiling already leading without new bids",
+			userID:    1,
+			maxAmount: 150000,
+			setup: func() {
+				mockItemRepo.EXPECT().GetByID(int64(1)).Return(item, nil)
+				mockSessionRepo.EXPECT().GetByID(int64(1)).Return(activeSession, nil)
+				mockRedisRepo.EXPECT().RegisterProxyBid(int64(1), int64(1), int64(1), 150000.0, repository.BidRules{MinAmount: 50000.0, MinIncrement: MinBidIncrement, EndTime: activeSession.EndTime}).
+					Return(repository.BidResult{Status: repository.BidAccepted, HighestAmount: 50000.0, HighestBidder: 1}, nil)
+			},
+		},
+		{
+			name:      "ceiling too low",
+			userID:    2,
+			maxAmount: 55000,
+			setup: func() {
+				mockItemRepo.EXPECT().GetByID(int64(1)).Return(item, nil)
+				mockSessionRepo.EXPECT().GetByID(int64(1)).Return(activeSession, nil)
+				mockRedisRepo.EXPECT().RegisterProxyBid(int64(1), int64(1), int64(2), 55000.0, repository.BidRules{MinAmount: 50000.0, MinIncrement: MinBidIncrement, EndTime: activeSession.EndTime}).
+					Return(repository.BidResult{Status: repository.BidRejectedTooLow, HighestAmount: 50000.0, HighestBidder: 1}, nil)
+			},
+			wantErr: ErrBidTooLow,
+		},
+		{
+			name:      "equal ceiling registered later loses",
+			userID:    2,
+			maxAmount: 150000,
+			setup: func() {
+				mockItemRepo.EXPECT().GetByID(int64(1)).Return(item, nil)
+				mockSessionRepo.EXPECT().GetByID(int64(1)).Return(activeSession, nil)
+				mockRedisRepo.EXPECT().RegisterProxyBid(int64(1), int64(1), int64(2), 150000.0, repository.BidRules{MinAmount: 50000.0, MinIncrement: MinBidIncrement, EndTime: activeSession.EndTime}).
+					Return(repository.BidResult{
+						Status:        repository.BidAccepted,
+						HighestAmount: 150000.0,
+						HighestBidder: 1,
+						Placed: []repository.BidEntry{
+							{UserID: 2, ItemID: 1, Amount: 150000.0},
+							{UserID: 1, ItemID: 1, Amount: 150000.0},
+						},
+					}, nil)
+				mockBidRepo.EXPECT().SaveBid(gomock.Any()).Return(nil).Times(2)
+				mockEventRepo.EXPECT().Publish(gomock.Any()).Return(nil)
+			},
+			wantErr: ErrOutbidByProxy,
+		},
+		{
+			name:      "invalid ceiling",
+			userID:    1,
+			maxAmount: 0,
+			setup:     func() {},
+			wantErr:   ErrInvalidBidding,
+		},
+		{
+			name:      "item not ongoing",
+			userID:    1,
+			maxAmount: 100000,
+			setup: func() {
+				mockItemRepo.EXPECT().GetByID(int64(1)).Return(&entity.AuctionItem{ID: 1, SessionID: &sessionID, Status: "scheduled"}, nil)
+			},
+			wantErr: ErrInvalidAuction,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+
+			err := bidService.RegisterProxyBid(sessionID, 1, tt.userID, tt.maxAmount, activeSession.EndTime)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
 			} else {
 				assert.NoError(t, err)
 			}
@@ -135,9 +369,12 @@ func TestBidService_GetHighestBid(t *testing.T) {
 	mockBidRepo := mocks.NewMockBidRepository(ctrl)
 	mockItemRepo := mocks.NewMockAuctionItemRepository(ctrl)
 	mockSessionRepo := mocks.NewMockAuctionSessionRepository(ctrl)
+	mockEventRepo := mocks.NewMockBidEventRepository(ctrl)
+	mockIncrementRepo := mocks.NewMockBidIncrementRepository(ctrl)
+	mockIncrementRepo.EXPECT().GetLadder(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
 	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
 
-	bidService := NewBidService(mockRedisRepo, mockBidRepo, mockItemRepo, mockSessionRepo, logger)
+	bidService := NewBidService(mockRedisRepo, mockBidRepo, mockItemRepo, mockSessionRepo, mockEventRepo, mockIncrementRepo, config.SoftCloseConfig{}, logger)
 
 	tests := []struct {
 		name      string
@@ -172,17 +409,723 @@ func TestBidService_GetHighestBid(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.setup()
 
-			amount, bidder, err := bidService.GetHighestBid(tt.sessionID, tt.itemID)
+			resp, err := bidService.GetHighestBid(tt.sessionID, tt.itemID)
 
 			if tt.wantErr {
 				assert.Error(t, err)
-				assert.Equal(t, float64(0), amount)
-				assert.Equal(t, int64(0), bidder)
+				assert.Equal(t, dto.HighestBidDTO{}, resp)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, 150.0, resp.HighestBid)
+				assert.Equal(t, int64(1), resp.BidderID)
+				assert.Equal(t, 150.0+MinBidIncrement, resp.MinNextBid)
+			}
+		})
+	}
+}
+
+func TestBidService_SubscribeBidEvents(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRedisRepo := mocks.NewMockBidRedisRepository(ctrl)
+	mockBidRepo := mocks.NewMockBidRepository(ctrl)
+	mockItemRepo := mocks.NewMockAuctionItemRepository(ctrl)
+	mockSessionRepo := mocks.NewMockAuctionSessionRepository(ctrl)
+	mockEventRepo := mocks.NewMockBidEventRepository(ctrl)
+	mockIncrementRepo := mocks.NewMockBidIncrementRepository(ctrl)
+	mockIncrementRepo.EXPECT().GetLadder(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	bidService := NewBidService(mockRedisRepo, mockBidRepo, mockItemRepo, mockSessionRepo, mockEventRepo, mockIncrementRepo, config.SoftCloseConfig{}, logger)
+
+	sessionID := int64(1)
+	otherSessionID := int64(2)
+
+	tests := []struct {
+		name    string
+		itemID  int64
+		setup   func()
+		wantErr error
+	}{
+		{
+			name:   "successful subscribe",
+			itemID: 1,
+			setup: func() {
+				mockItemRepo.EXPECT().GetByID(int64(1)).Return(&entity.AuctionItem{ID: 1, SessionID: &sessionID}, nil)
+				mockEventRepo.EXPECT().Subscribe(gomock.Any(), int64(1), int64(1)).Return(make(chan dto.BidEventDTO), nil)
+			},
+		},
+		{
+			name:   "item not found",
+			itemID: 999,
+			setup: func() {
+				mockItemRepo.EXPECT().GetByID(int64(999)).Return(nil, errors.New("not found"))
+			},
+			wantErr: ErrAuctionNotFound,
+		},
+		{
+			name:   "item belongs to another session",
+			itemID: 2,
+			setup: func() {
+				mockItemRepo.EXPECT().GetByID(int64(2)).Return(&entity.AuctionItem{ID: 2, SessionID: &otherSessionID}, nil)
+			},
+			wantErr: ErrInvalidAuction,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+
+			events, err := bidService.SubscribeBidEvents(context.Background(), sessionID, tt.itemID)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, events)
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, events)
+			}
+		})
+	}
+}
+
+func TestBidService_GetBidHistory(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRedisRepo := mocks.NewMockBidRedisRepository(ctrl)
+	mockBidRepo := mocks.NewMockBidRepository(ctrl)
+	mockItemRepo := mocks.NewMockAuctionItemRepository(ctrl)
+	mockSessionRepo := mocks.NewMockAuctionSessionRepository(ctrl)
+	mockEventRepo := mocks.NewMockBidEventRepository(ctrl)
+	mockIncrementRepo := mocks.NewMockBidIncrementRepository(ctrl)
+	mockIncrementRepo.EXPECT().GetLadder(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	bidService := NewBidService(mockRedisRepo, mockBidRepo, mockItemRepo, mockSessionRepo, mockEventRepo, mockIncrementRepo, config.SoftCloseConfig{}, logger)
+
+	bids := []entity.Bid{
+		{ID: 2, ItemID: 1, UserID: 2, Amount: 30000, CreatedAt: time.Now()},
+		{ID: 1, ItemID: 1, UserID: 1, Amount: 20000, CreatedAt: time.Now().Add(-time.Minute)},
+	}
+
+	tests := []struct {
+		name      string
+		itemID    int64
+		setup     func()
+		wantCount int
+		wantTotal int64
+		wantErr   error
+	}{
+		{
+			name:   "successful get bid history",
+			itemID: 1,
+			setup: func() {
+				mockItemRepo.EXPECT().GetByID(int64(1)).Return(&entity.AuctionItem{ID: 1}, nil)
+				mockBidRepo.EXPECT().GetByItemID(int64(1), 1, 10).Return(bids, int64(2), nil)
+			},
+			wantCount: 2,
+			wantTotal: 2,
+		},
+		{
+			name:   "item not found",
+			itemID: 999,
+			setup: func() {
+				mockItemRepo.EXPECT().GetByID(int64(999)).Return(nil, errors.New("not found"))
+			},
+			wantErr: ErrAuctionNotFound,
+		},
+		{
+			name:   "repository error",
+			itemID: 1,
+			setup: func() {
+				mockItemRepo.EXPECT().GetByID(int64(1)).Return(&entity.AuctionItem{ID: 1}, nil)
+				mockBidRepo.EXPECT().GetByItemID(int64(1), 1, 10).Return(nil, int64(0), errors.New("db error"))
+			},
+			wantErr: errors.New("db error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+
+			history, total, err := bidService.GetBidHistory(tt.itemID, 1, 10)
+
+			if tt.wantErr != nil {
+				assert.Error(t, err)
+				assert.Equal(t, tt.wantErr.Error(), err.Error())
 			} else {
 				assert.NoError(t, err)
-				assert.Equal(t, 150.0, amount)
-				assert.Equal(t, int64(1), bidder)
+				assert.Len(t, history, tt.wantCount)
+				assert.Equal(t, tt.wantTotal, total)
+				assert.Equal(t, int64(2), history[0].ID)
+				assert.Equal(t, 30000.0, history[0].Amount)
+			}
+		})
+	}
+}
+
+func TestBidService_PlaceBid_SoftClose(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRedisRepo := mocks.NewMockBidRedisRepository(ctrl)
+	mockBidRepo := mocks.NewMockBidRepository(ctrl)
+	mockItemRepo := mocks.NewMockAuctionItemRepository(ctrl)
+	mockSessionRepo := mocks.NewMockAuctionSessionRepository(ctrl)
+	mockEventRepo := mocks.NewMockBidEventRepository(ctrl)
+	mockIncrementRepo := mocks.NewMockBidIncrementRepository(ctrl)
+	mockIncrementRepo.EXPECT().GetLadder(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	softClose := config.SoftCloseConfig{
+		Window:    2 * time.Minute,
+		Extension: 2 * time.Minute,
+		Scope:     config.SoftCloseScopeSession,
+	}
+	bidService := NewBidService(mockRedisRepo, mockBidRepo, mockItemRepo, mockSessionRepo, mockEventRepo, mockIncrementRepo, softClose, logger)
+
+	sessionID := int64(1)
+	item := &entity.AuctionItem{ID: 1, SessionID: &sessionID, Status: "ongoing", StartingPrice: 10000}
+
+	t.Run("bid in the window extends the whole session", func(t *testing.T) {
+		session := &entity.AuctionSession{
+			ID:        sessionID,
+			StartTime: time.Now().Add(-time.Hour),
+			EndTime:   time.Now().Add(time.Minute),
+		}
+		extendedEnd := session.EndTime.Add(2 * time.Minute)
+
+		mockItemRepo.EXPECT().GetByID(int64(1)).Return(item, nil)
+		mockSessionRepo.EXPECT().GetByID(sessionID).Return(session, nil)
+		mockRedisRepo.EXPECT().CheckDuplicateBid(int64(1), int64(1), 20000.0, gomock.Any()).Return(nil)
+		mockRedisRepo.EXPECT().CompareAndSetHighestBid(sessionID, int64(1), 20000.0, int64(1), gomock.Any()).
+			Do(func(_, _ int64, _ float64, _ int64, rules repository.BidRules) {
+				assert.Equal(t, 2*time.Minute, rules.SoftCloseWindow)
+				assert.Equal(t, 2*time.Minute, rules.SoftCloseExtension)
+			}).
+			Return(repository.BidResult{
+				Status:        repository.BidAccepted,
+				HighestAmount: 20000.0,
+				HighestBidder: 1,
+				EndTime:       extendedEnd,
+				Extended:      true,
+				Placed:        []repository.BidEntry{{UserID: 1, ItemID: 1, Amount: 20000.0}},
+			}, nil)
+		mockBidRepo.EXPECT().SaveBid(gomock.Any()).Return(nil)
+		mockSessionRepo.EXPECT().ExtendEndTime(sessionID, extendedEnd).Return(nil)
+		mockRedisRepo.EXPECT().ExtendSessionEndTime(sessionID, extendedEnd).Return(nil)
+		mockItemRepo.EXPECT().ReadBySession(sessionID).Return([]entity.AuctionItem{{ID: 1}, {ID: 2}}, nil)
+
+		var extendedItems []int64
+		mockEventRepo.EXPECT().Publish(gomock.Any()).DoAndReturn(func(event dto.BidEventDTO) error {
+			if event.Type == dto.BidEventExtended {
+				assert.Equal(t, extendedEnd.Unix(), event.EndTime.Unix())
+				extendedItems = append(extendedItems, event.ItemID)
+			}
+			return nil
+		}).Times(3)
+
+		err := bidService.PlaceBid(sessionID, 1, 1, 20000.0, session.EndTime)
+		assert.NoError(t, err)
+		assert.Equal(t, []int64{1, 2}, extendedItems)
+	})
+
+	t.Run("bid after session end but before extended end is accepted", func(t *testing.T) {
+		session := &entity.AuctionSession{
+			ID:        sessionID,
+			StartTime: time.Now().Add(-time.Hour),
+			EndTime:   time.Now().Add(-time.Minute),
+		}
+
+		mockItemRepo.EXPECT().GetByID(int64(1)).Return(item, nil)
+		mockSessionRepo.EXPECT().GetByID(sessionID).Return(session, nil)
+		mockRedisRepo.EXPECT().GetEndTime("active:auction:1:item:1").Return(time.Now().Add(time.Minute), nil)
+		mockRedisRepo.EXPECT().CheckDuplicateBid(int64(2), int64(1), 30000.0, gomock.Any()).Return(nil)
+		mockRedisRepo.EXPECT().CompareAndSetHighestBid(sessionID, int64(1), 30000.0, int64(2), gomock.Any()).
+			Return(repository.BidResult{
+				Status:        repository.BidAccepted,
+				HighestAmount: 30000.0,
+				HighestBidder: 2,
+				Placed:        []repository.BidEntry{{UserID: 2, ItemID: 1, Amount: 30000.0}},
+			}, nil)
+		mockBidRepo.EXPECT().SaveBid(gomock.Any()).Return(nil)
+		mockEventRepo.EXPECT().Publish(gomock.Any()).Return(nil)
+
+		err := bidService.PlaceBid(sessionID, 1, 2, 30000.0, session.EndTime)
+		assert.NoError(t, err)
+	})
+
+	t.Run("bid after extended end is rejected", func(t *testing.T) {
+		session := &entity.AuctionSession{
+			ID:        sessionID,
+			StartTime: time.Now().Add(-time.Hour),
+			EndTime:   time.Now().Add(-2 * time.Minute),
+		}
+
+		mockItemRepo.EXPECT().GetByID(int64(1)).Return(item, nil)
+		mockSessionRepo.EXPECT().GetByID(sessionID).Return(session, nil)
+		mockRedisRepo.EXPECT().GetEndTime("active:auction:1:item:1").Return(time.Now().Add(-time.Minute), nil)
+
+		err := bidService.PlaceBid(sessionID, 1, 2, 40000.0, session.EndTime)
+		assert.ErrorIs(t, err, ErrInvalidAuction)
+	})
+}
+
+func TestBidService_SaveKeyToDB_RespectsSoftClose(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRedisRepo := mocks.NewMockBidRedisRepository(ctrl)
+	mockBidRepo := mocks.NewMockBidRepository(ctrl)
+	mockItemRepo := mocks.NewMockAuctionItemRepository(ctrl)
+	mockSessionRepo := mocks.NewMockAuctionSessionRepository(ctrl)
+	mockEventRepo := mocks.NewMockBidEventRepository(ctrl)
+	mockIncrementRepo := mocks.NewMockBidIncrementRepository(ctrl)
+	mockIncrementRepo.EXPECT().GetLadder(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	bidService := NewBidService(mockRedisRepo, mockBidRepo, mockItemRepo, mockSessionRepo, mockEventRepo, mockIncrementRepo, config.SoftCloseConfig{}, logger)
+
+	endedSession := &entity.AuctionSession{
+		ID:        1,
+		StartTime: time.Now().Add(-time.Hour),
+		EndTime:   time.Now().Add(-time.Minute),
+	}
+
+	// item 1 was extended past the session end, item 2 was not
+	mockRedisRepo.EXPECT().ScanKeys("active:auction:*:item:*").Return([]string{"active:auction:1:item:1", "active:auction:1:item:2"}, nil)
+	mockSessionRepo.EXPECT().GetByID(int64(1)).Return(endedSession, nil).Times(2)
+	mockRedisRepo.EXPECT().GetBidByKey("active:auction:1:item:1").Return(repository.BidEntry{UserID: 2, ItemID: 1, Amount: 40000}, nil)
+	mockRedisRepo.EXPECT().GetEndTime("active:auction:1:item:1").Return(time.Now().Add(time.Minute), nil)
+	mockRedisRepo.EXPECT().GetEndTime("active:auction:1:item:2").Return(endedSession.EndTime, nil)
+
+	mockRedisRepo.EXPECT().GetBidByKey("active:auction:1:item:2").Return(repository.BidEntry{UserID: 3, ItemID: 2, Amount: 50000}, nil)
+	mockItemRepo.EXPECT().GetByID(int64(2)).Return(&entity.AuctionItem{ID: 2, Status: "ongoing"}, nil)
+	mockRedisRepo.EXPECT().GetUnsavedBids(int64(1), int64(2)).Return(nil, nil)
+	mockBidRepo.EXPECT().FinalizeItem(int64(2), "finished", []entity.Bid{}, &entity.Bid{ItemID: 2, UserID: 3, Amount: 50000}, gomock.Any()).Return(nil)
+	mockEventRepo.EXPECT().Publish(gomock.Any()).Return(nil)
+	mockRedisRepo.EXPECT().DeleteKey("active:auction:1:item:2").Return(nil)
+
+	mockItemRepo.EXPECT().GetAll(false).Return(nil, nil)
+
+	err := bidService.SaveKeyToDB()
+	assert.NoError(t, err)
+}
+
+func TestBidService_SaveKeyToDB_Reserve(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRedisRepo := mocks.NewMockBidRedisRepository(ctrl)
+	mockBidRepo := mocks.NewMockBidRepository(ctrl)
+	mockItemRepo := mocks.NewMockAuctionItemRepository(ctrl)
+	mockSessionRepo := mocks.NewMockAuctionSessionRepository(ctrl)
+	mockEventRepo := mocks.NewMockBidEventRepository(ctrl)
+	mockIncrementRepo := mocks.NewMockBidIncrementRepository(ctrl)
+	mockIncrementRepo.EXPECT().GetLadder(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	bidService := NewBidService(mockRedisRepo, mockBidRepo, mockItemRepo, mockSessionRepo, mockEventRepo, mockIncrementRepo, config.SoftCloseConfig{}, logger)
+
+	endedSession := &entity.AuctionSession{
+		ID:        1,
+		StartTime: time.Now().Add(-time.Hour),
+		EndTime:   time.Now().Add(-time.Minute),
+	}
+	reserve := 100000.0
+
+	tests := []struct {
+		name       string
+		bid        repository.BidEntry
+		wantStatus string
+		wantEvent  string
+		wantBid    *entity.Bid
+		setup      func()
+	}{
+		{
+			name:       "highest bid below reserve ends unsold without a winner",
+			bid:        repository.BidEntry{UserID: 2, ItemID: 1, Amount: 90000},
+			wantStatus: "unsold",
+			wantEvent:  entity.OutboxItemUnsold,
+			setup: func() {
+				mockEventRepo.EXPECT().Publish(gomock.Any()).DoAndReturn(func(event dto.BidEventDTO) error {
+					assert.Equal(t, "unsold", event.Status)
+					assert.Zero(t, event.BidderID)
+					assert.Zero(t, event.Amount)
+					return nil
+				})
+			},
+		},
+		{
+			name:       "highest bid meeting reserve wins",
+			bid:        repository.BidEntry{UserID: 2, ItemID: 1, Amount: 100000},
+			wantStatus: "finished",
+			wantEvent:  entity.OutboxItemWon,
+			wantBid:    &entity.Bid{ItemID: 1, UserID: 2, Amount: 100000},
+			setup: func() {
+				mockEventRepo.EXPECT().Publish(gomock.Any()).DoAndReturn(func(event dto.BidEventDTO) error {
+					assert.Equal(t, "finished", event.Status)
+					assert.Equal(t, int64(2), event.BidderID)
+					return nil
+				})
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRedisRepo.EXPECT().ScanKeys("active:auction:*:item:*").Return([]string{"active:auction:1:item:1"}, nil)
+			mockRedisRepo.EXPECT().GetBidByKey("active:auction:1:item:1").Return(tt.bid, nil)
+			mockSessionRepo.EXPECT().GetByID(int64(1)).Return(endedSession, nil)
+			mockRedisRepo.EXPECT().GetEndTime("active:auction:1:item:1").Return(endedSession.EndTime, nil)
+			mockItemRepo.EXPECT().GetByID(int64(1)).Return(&entity.AuctionItem{ID: 1, Status: "ongoing", ReservePrice: &reserve}, nil)
+			mockRedisRepo.EXPECT().GetUnsavedBids(int64(1), int64(1)).Return(nil, nil)
+			mockBidRepo.EXPECT().FinalizeItem(int64(1), tt.wantStatus, []entity.Bid{}, tt.wantBid, gomock.Any()).
+				DoAndReturn(func(_ int64, _ string, _ []entity.Bid, _ *entity.Bid, event *entity.OutboxEvent) error {
+					assert.Equal(t, tt.wantEvent, event.EventType)
+					assert.Equal(t, entity.OutboxStatusPending, event.Status)
+					return nil
+				})
+			mockRedisRepo.EXPECT().DeleteKey("active:auction:1:item:1").Return(nil)
+			mockItemRepo.EXPECT().GetAll(false).Return(nil, nil)
+			tt.setup()
+
+			err := bidService.SaveKeyToDB()
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestBidService_SaveKeyToDB_FinalizeFailureKeepsKey(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRedisRepo := mocks.NewMockBidRedisRepository(ctrl)
+	mockBidRepo := mocks.NewMockBidRepository(ctrl)
+	mockItemRepo := mocks.NewMockAuctionItemRepository(ctrl)
+	mockSessionRepo := mocks.NewMockAuctionSessionRepository(ctrl)
+	mockEventRepo := mocks.NewMockBidEventRepository(ctrl)
+	mockIncrementRepo := mocks.NewMockBidIncrementRepository(ctrl)
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	bidService := NewBidService(mockRedisRepo, mockBidRepo, mockItemRepo, mockSessionRepo, mockEventRepo, mockIncrementRepo, config.SoftCloseConfig{}, logger)
+
+	endedSession := &entity.AuctionSession{
+		ID:        1,
+		StartTime: time.Now().Add(-time.Hour),
+		EndTime:   time.Now().Add(-time.Minute),
+	}
+
+	mockRedisRepo.EXPECT().ScanKeys("active:auction:*:item:*").Return([]string{"active:auction:1:item:1"}, nil)
+	mockRedisRepo.EXPECT().GetBidByKey("active:auction:1:item:1").Return(repository.BidEntry{UserID: 2, ItemID: 1, Amount: 50000}, nil)
+	mockSessionRepo.EXPECT().GetByID(int64(1)).Return(endedSession, nil)
+	mockRedisRepo.EXPECT().GetEndTime("active:auction:1:item:1").Return(endedSession.EndTime, nil)
+	mockItemRepo.EXPECT().GetByID(int64(1)).Return(&entity.AuctionItem{ID: 1, Status: "ongoing"}, nil)
+	mockRedisRepo.EXPECT().GetUnsavedBids(int64(1), int64(1)).Return([]repository.BidEntry{{UserID: 3, Amount: 40000}}, nil)
+	mockBidRepo.EXPECT().FinalizeItem(int64(1), "finished", gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New("tx rolled back"))
+	// no status event, no DeleteKey and the unsaved bids stay queued, the next run retries the whole transaction
+	mockItemRepo.EXPECT().GetAll(false).Return(nil, nil)
+
+	err := bidService.SaveKeyToDB()
+	assert.NoError(t, err)
+}
+
+func TestBidService_PlaceBid_UnsavedBid(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRedisRepo := mocks.NewMockBidRedisRepository(ctrl)
+	mockBidRepo := mocks.NewMockBidRepository(ctrl)
+	mockItemRepo := mocks.NewMockAuctionItemRepository(ctrl)
+	mockSessionRepo := mocks.NewMockAuctionSessionRepository(ctrl)
+	mockEventRepo := mocks.NewMockBidEventRepository(ctrl)
+	mockIncrementRepo := mocks.NewMockBidIncrementRepository(ctrl)
+	mockIncrementRepo.EXPECT().GetLadder(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	bidService := NewBidService(mockRedisRepo, mockBidRepo, mockItemRepo, mockSessionRepo, mockEventRepo, mockIncrementRepo, config.SoftCloseConfig{}, logger)
+
+	sessionID := int64(1)
+	session := &entity.AuctionSession{
+		ID:        sessionID,
+		StartTime: time.Now().Add(-time.Hour),
+		EndTime:   time.Now().Add(time.Hour),
+	}
+	item := &entity.AuctionItem{ID: 1, SessionID: &sessionID, Status: "ongoing", StartingPrice: 10000}
+	placed := repository.BidEntry{UserID: 2, ItemID: 1, Amount: 20000.0}
+
+	tests := []struct {
+		name     string
+		queueErr error
+		wantErr  error
+	}{
+		{name: "failed ledger write is queued for the close", queueErr: nil, wantErr: nil},
+		{name: "bid neither written nor queued is an error", queueErr: errors.New("redis down"), wantErr: ErrBidNotRecorded},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockItemRepo.EXPECT().GetByID(int64(1)).Return(item, nil)
+			mockSessionRepo.EXPECT().GetByID(sessionID).Return(session, nil)
+			mockRedisRepo.EXPECT().CheckDuplicateBid(int64(2), int64(1), 20000.0, gomock.Any()).Return(nil)
+			mockRedisRepo.EXPECT().CompareAndSetHighestBid(sessionID, int64(1), 20000.0, int64(2), gomock.Any()).
+				Return(repository.BidResult{
+					Status:        repository.BidAccepted,
+					HighestAmount: 20000.0,
+					HighestBidder: 2,
+					Placed:        []repository.BidEntry{placed},
+				}, nil)
+			mockBidRepo.EXPECT().SaveBid(gomock.Any()).Return(errors.New("connection reset"))
+			mockRedisRepo.EXPECT().QueueUnsavedBids(sessionID, int64(1), []repository.BidEntry{placed}).Return(tt.queueErr)
+			// the bid stands in redis either way, so it is still announced
+			mockEventRepo.EXPECT().Publish(gomock.Any()).Return(nil)
+
+			err := bidService.PlaceBid(sessionID, 1, 2, 20000.0, session.EndTime)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestBidService_SaveKeyToDB_FlushesUnsavedBids(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRedisRepo := mocks.NewMockBidRedisRepository(ctrl)
+	mockBidRepo := mocks.NewMockBidRepository(ctrl)
+	mockItemRepo := mocks.NewMockAuctionItemRepository(ctrl)
+	mockSessionRepo := mocks.NewMockAuctionSessionRepository(ctrl)
+	mockEventRepo := mocks.NewMockBidEventRepository(ctrl)
+	mockIncrementRepo := mocks.NewMockBidIncrementRepository(ctrl)
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	bidService := NewBidService(mockRedisRepo, mockBidRepo, mockItemRepo, mockSessionRepo, mockEventRepo, mockIncrementRepo, config.SoftCloseConfig{}, logger)
+
+	endedSession := &entity.AuctionSession{
+		ID:        1,
+		StartTime: time.Now().Add(-time.Hour),
+		EndTime:   time.Now().Add(-time.Minute),
+	}
+
+	mockRedisRepo.EXPECT().ScanKeys("active:auction:*:item:*").Return([]string{"active:auction:1:item:1"}, nil)
+	mockRedisRepo.EXPECT().GetBidByKey("active:auction:1:item:1").Return(repository.BidEntry{UserID: 2, ItemID: 1, Amount: 50000}, nil)
+	mockSessionRepo.EXPECT().GetByID(int64(1)).Return(endedSession, nil)
+	mockRedisRepo.EXPECT().GetEndTime("active:auction:1:item:1").Return(endedSession.EndTime, nil)
+	mockItemRepo.EXPECT().GetByID(int64(1)).Return(&entity.AuctionItem{ID: 1, Status: "ongoing"}, nil)
+	mockRedisRepo.EXPECT().GetUnsavedBids(int64(1), int64(1)).Return([]repository.BidEntry{
+		{UserID: 3, Amount: 40000},
+		{UserID: 2, Amount: 50000},
+	}, nil)
+	mockBidRepo.EXPECT().FinalizeItem(int64(1), "finished", []entity.Bid{
+		{ItemID: 1, UserID: 3, Amount: 40000},
+		{ItemID: 1, UserID: 2, Amount: 50000},
+	}, &entity.Bid{ItemID: 1, UserID: 2, Amount: 50000}, gomock.Any()).Return(nil)
+	mockEventRepo.EXPECT().Publish(gomock.Any()).Return(nil)
+	mockRedisRepo.EXPECT().DeleteKey("active:auction:1:item:1").Return(nil)
+	mockRedisRepo.EXPECT().DeleteUnsavedBids(int64(1), int64(1)).Return(nil)
+	mockItemRepo.EXPECT().GetAll(false).Return(nil, nil)
+
+	err := bidService.SaveKeyToDB()
+	assert.NoError(t, err)
+}
+
+func TestBidService_PlaceBid_BuyNow(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRedisRepo := mocks.NewMockBidRedisRepository(ctrl)
+	mockBidRepo := mocks.NewMockBidRepository(ctrl)
+	mockItemRepo := mocks.NewMockAuctionItemRepository(ctrl)
+	mockSessionRepo := mocks.NewMockAuctionSessionRepository(ctrl)
+	mockEventRepo := mocks.NewMockBidEventRepository(ctrl)
+	mockIncrementRepo := mocks.NewMockBidIncrementRepository(ctrl)
+	mockIncrementRepo.EXPECT().GetLadder(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	bidService := NewBidService(mockRedisRepo, mockBidRepo, mockItemRepo, mockSessionRepo, mockEventRepo, mockIncrementRepo, config.SoftCloseConfig{}, logger)
+
+	sessionID := int64(1)
+	buyNow := 100000.0
+	session := &entity.AuctionSession{
+		ID:        sessionID,
+		StartTime: time.Now().Add(-time.Hour),
+		EndTime:   time.Now().Add(time.Hour),
+	}
+	item := &entity.AuctionItem{ID: 1, SessionID: &sessionID, Status: "ongoing", StartingPrice: 10000, BuyNowPrice: &buyNow}
+
+	mockItemRepo.EXPECT().GetByID(int64(1)).Return(item, nil)
+	mockSessionRepo.EXPECT().GetByID(sessionID).Return(session, nil)
+	mockRedisRepo.EXPECT().CheckDuplicateBid(int64(2), int64(1), 120000.0, gomock.Any()).Return(nil)
+	mockRedisRepo.EXPECT().CompareAndSetHighestBid(sessionID, int64(1), 120000.0, int64(2), gomock.Any()).
+		Do(func(_, _ int64, _ float64, _ int64, rules repository.BidRules) {
+			assert.Equal(t, buyNow, rules.BuyNowPrice)
+		}).
+		Return(repository.BidResult{
+			Status:        repository.BidAccepted,
+			HighestAmount: buyNow,
+			HighestBidder: 2,
+			Closed:        true,
+			Placed:        []repository.BidEntry{{UserID: 2, ItemID: 1, Amount: buyNow}},
+		}, nil)
+	mockBidRepo.EXPECT().SaveBid(gomock.Any()).Return(nil)
+	mockRedisRepo.EXPECT().GetUnsavedBids(sessionID, int64(1)).Return(nil, nil)
+	mockBidRepo.EXPECT().FinalizeItem(int64(1), "finished", []entity.Bid{}, &entity.Bid{ItemID: 1, UserID: 2, Amount: buyNow}, gomock.Any()).Return(nil)
+	mockRedisRepo.EXPECT().DeleteKey("active:auction:1:item:1").Return(nil)
+	mockEventRepo.EXPECT().Publish(gomock.Any()).Return(nil).Times(2)
+
+	err := bidService.PlaceBid(sessionID, 1, 2, 120000.0, session.EndTime)
+	assert.NoError(t, err)
+}
+
+func TestBidService_IncrementLadder(t *testing.T) {
+	ladder := []entity.BidIncrementTier{
+		{MinPrice: 0, Percent: 5},
+		{MinPrice: 1000000, Increment: 50000},
+	}
+
+	tests := []struct {
+		name    string
+		ladder  []entity.BidIncrementTier
+		current float64
+		want    float64
+	}{
+		{name: "no bid yet starts at the starting price", ladder: ladder, current: 0, want: 10000},
+		{name: "percent tier rounds the step up", ladder: ladder, current: 123457, want: 123457 + 6173},
+		{name: "fixed tier from its min price", ladder: ladder, current: 1000000, want: 1050000},
+		{name: "no ladder falls back to the default step", ladder: nil, current: 20000, want: 20000 + MinBidIncrement},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, minNextBid(tt.ladder, tt.current, 10000))
+		})
+	}
+}
+
+func TestBidService_PlaceBid_ReturnsMinNextBid(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRedisRepo := mocks.NewMockBidRedisRepository(ctrl)
+	mockBidRepo := mocks.NewMockBidRepository(ctrl)
+	mockItemRepo := mocks.NewMockAuctionItemRepository(ctrl)
+	mockSessionRepo := mocks.NewMockAuctionSessionRepository(ctrl)
+	mockEventRepo := mocks.NewMockBidEventRepository(ctrl)
+	mockIncrementRepo := mocks.NewMockBidIncrementRepository(ctrl)
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	bidService := NewBidService(mockRedisRepo, mockBidRepo, mockItemRepo, mockSessionRepo, mockEventRepo, mockIncrementRepo, config.SoftCloseConfig{}, logger)
+
+	sessionID := int64(1)
+	session := &entity.AuctionSession{
+		ID:        sessionID,
+		StartTime: time.Now().Add(-time.Hour),
+		EndTime:   time.Now().Add(time.Hour),
+	}
+	item := &entity.AuctionItem{ID: 1, SessionID: &sessionID, Status: "ongoing", StartingPrice: 10000}
+	ladder := []entity.BidIncrementTier{{MinPrice: 0, Percent: 10}}
+
+	mockItemRepo.EXPECT().GetByID(int64(1)).Return(item, nil)
+	mockSessionRepo.EXPECT().GetByID(sessionID).Return(session, nil)
+	mockRedisRepo.EXPECT().CheckDuplicateBid(int64(2), int64(1), 205000.0, gomock.Any()).Return(nil)
+	mockIncrementRepo.EXPECT().GetLadder(sessionID, int64(1)).Return(ladder, nil)
+	mockRedisRepo.EXPECT().CompareAndSetHighestBid(sessionID, int64(1), 205000.0, int64(2), gomock.Any()).
+		Do(func(_, _ int64, _ float64, _ int64, rules repository.BidRules) {
+			assert.Equal(t, ladder, rules.Ladder)
+		}).
+		Return(repository.BidResult{Status: repository.BidRejectedTooLow, HighestAmount: 200000, HighestBidder: 3}, nil)
+
+	err := bidService.PlaceBid(sessionID, 1, 2, 205000.0, session.EndTime)
+
+	assert.ErrorIs(t, err, ErrBidTooLow)
+	var rejected *BidRejectedError
+	assert.True(t, errors.As(err, &rejected))
+	assert.Equal(t, 220000.0, rejected.MinNextBid)
+}
+
+func TestBidService_SetIncrementLadder(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRedisRepo := mocks.NewMockBidRedisRepository(ctrl)
+	mockBidRepo := mocks.NewMockBidRepository(ctrl)
+	mockItemRepo := mocks.NewMockAuctionItemRepository(ctrl)
+	mockSessionRepo := mocks.NewMockAuctionSessionRepository(ctrl)
+	mockEventRepo := mocks.NewMockBidEventRepository(ctrl)
+	mockIncrementRepo := mocks.NewMockBidIncrementRepository(ctrl)
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	bidService := NewBidService(mockRedisRepo, mockBidRepo, mockItemRepo, mockSessionRepo, mockEventRepo, mockIncrementRepo, config.SoftCloseConfig{}, logger)
+
+	sessionID := int64(1)
+
+	tests := []struct {
+		name      string
+		sessionID *int64
+		tiers     []dto.BidIncrementTierDTO
+		setup     func()
+		wantErr   error
+	}{
+		{
+			name:      "session override sorted by min price",
+			sessionID: &sessionID,
+			tiers: []dto.BidIncrementTierDTO{
+				{MinPrice: 500000, Increment: 25000},
+				{MinPrice: 0, Increment: 5000},
+			},
+			setup: func() {
+				mockSessionRepo.EXPECT().GetByID(sessionID).Return(&entity.AuctionSession{ID: sessionID}, nil)
+				mockIncrementRepo.EXPECT().ReplaceLadder(&sessionID, nil, []entity.BidIncrementTier{
+					{MinPrice: 0, Increment: 5000},
+					{MinPrice: 500000, Increment: 25000},
+				}).Return(nil)
+			},
+		},
+		{
+			name:    "tier without a step",
+			tiers:   []dto.BidIncrementTierDTO{{MinPrice: 0}},
+			setup:   func() {},
+			wantErr: ErrInvalidIncrementLadder,
+		},
+		{
+			name: "duplicate min price",
+			tiers: []dto.BidIncrementTierDTO{
+				{MinPrice: 0, Increment: 5000},
+				{MinPrice: 0, Percent: 5},
+			},
+			setup:   func() {},
+			wantErr: ErrInvalidIncrementLadder,
+		},
+		{
+			name:      "session not found",
+			sessionID: &sessionID,
+			tiers:     []dto.BidIncrementTierDTO{{MinPrice: 0, Increment: 5000}},
+			setup: func() {
+				mockSessionRepo.EXPECT().GetByID(sessionID).Return(nil, errors.New("not found"))
+			},
+			wantErr: ErrSessionNotFoundID,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+
+			tiers, err := bidService.SetIncrementLadder(tt.sessionID, nil, tt.tiers)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
 			}
+			assert.NoError(t, err)
+			assert.Equal(t, 0.0, tiers[0].MinPrice)
 		})
 	}
 }
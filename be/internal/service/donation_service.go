@@ -3,10 +3,12 @@ package service
 import (
 	"context"
 	"errors"
-	"io"
+	"slices"
+	"strings"
 	"time"
 
 	"milestone3/be/internal/dto"
+	"milestone3/be/internal/entity"
 	"milestone3/be/internal/repository"
 
 	"github.com/sirupsen/logrus"
@@ -18,20 +20,66 @@ type DonationService interface {
 	GetAllDonations(userID uint, isAdmin bool, page, limit int) ([]dto.DonationDTO, int64, error)
 	GetDonationByID(id uint) (dto.DonationDTO, error)
 	UpdateDonation(donationDTO dto.DonationDTO, userID uint, isAdmin bool) error
+	CheckDonationEditable(id uint, userID uint, isAdmin bool) error
 	DeleteDonation(id uint, userID uint, isAdmin bool) error
-	PatchDonation(donationDTO dto.DonationDTO, userID uint, isAdmin bool) error
+	TransitionDonation(id uint, req dto.DonationApprovalDTO, userID uint, role string, permissions []string) error
+	GetDonationHistory(id uint, userID uint, isAdmin bool) ([]dto.DonationStatusHistoryDTO, error)
+	AddDonationPhotos(id uint, photos []dto.DonationPhotoDTO, userID uint, isAdmin bool) (dto.DonationDTO, error)
+	RemoveDonationPhoto(id uint, photoID uint, userID uint, isAdmin bool) error
 	CanManageDonations(userID uint, ownerID uint, isAdmin bool) bool
 }
 
+// DonationRoleDonor stands for the owner of the donation in donationTransitions and the status history
+const DonationRoleDonor = "donor"
+
+// donationTransitions lists, per status, the statuses a donation can move to and who may move it:
+// the donor, or a role holding the permission. the donor can withdraw until the item is received,
+// after that verifiers inspect and place it and the institution confirms what it receives.
+// rejected, auctioned, distributed and cancelled_by_donor are final
+var donationTransitions = map[entity.StatusDonation]map[entity.StatusDonation][]string{
+	entity.StatusPending: {
+		entity.StatusAwaitingPickup:   {entity.PermDonationVerify},
+		entity.StatusRejected:         {entity.PermDonationVerify},
+		entity.StatusCancelledByDonor: {DonationRoleDonor},
+	},
+	entity.StatusAwaitingPickup: {
+		entity.StatusReceived:         {entity.PermDonationVerify},
+		entity.StatusRejected:         {entity.PermDonationVerify},
+		entity.StatusCancelledByDonor: {DonationRoleDonor},
+	},
+	entity.StatusReceived: {
+		entity.StatusVerifiedForAuction:  {entity.PermDonationVerify},
+		entity.StatusVerifiedForDonation: {entity.PermDonationVerify},
+		entity.StatusRejected:            {entity.PermDonationVerify},
+	},
+	entity.StatusVerifiedForAuction: {
+		entity.StatusAuctioned: {entity.PermDonationVerify},
+		// an item that does not sell can still be donated
+		entity.StatusVerifiedForDonation: {entity.PermDonationVerify},
+	},
+	entity.StatusVerifiedForDonation: {
+		entity.StatusDistributed: {entity.PermDonationDistribute},
+	},
+}
+
+// a donation is edited and deleted only until the item is picked up, after that it belongs to the
+// audit trail and may have an auction item drafted from it. a cancelled donation can still be deleted
+var (
+	donationEditableStatuses  = []entity.StatusDonation{entity.StatusPending, entity.StatusAwaitingPickup}
+	donationDeletableStatuses = []entity.StatusDonation{entity.StatusPending, entity.StatusAwaitingPickup, entity.StatusCancelledByDonor}
+)
+
 type donationService struct {
 	repo         repository.DonationRepo
-	privateStore repository.GCPStorageRepo
+	privateStore repository.ObjectStore
+	ai           repository.AIRepository
 }
 
-func NewDonationService(repo repository.DonationRepo, privateStore repository.GCPStorageRepo) DonationService {
+func NewDonationService(repo repository.DonationRepo, privateStore repository.ObjectStore, aiRepo repository.AIRepository) DonationService {
 	return &donationService{
 		repo:         repo,
 		privateStore: privateStore,
+		ai:           aiRepo,
 	}
 }
 
@@ -41,6 +89,8 @@ func (s *donationService) CreateDonation(donationDTO dto.DonationDTO) error {
 		logrus.WithError(err).Error("Failed to convert DTO to entity")
 		return err
 	}
+	// every donation starts pending, whatever the request says
+	donation.Status = entity.StatusPending
 	if err := s.repo.CreateDonation(donation); err != nil {
 		logrus.WithError(err).WithFields(logrus.Fields{
 			"user_id": donation.UserID,
@@ -57,13 +107,13 @@ func (s *donationService) GetAllDonations(userID uint, isAdmin bool, page, limit
 		if err != nil {
 			return nil, 0, err
 		}
-		return dto.DonationResponses(donations), total, nil
+		return s.signedResponses(donations), total, nil
 	}
 	donations, total, err := s.repo.GetDonationsByUserID(userID, page, limit)
 	if err != nil {
 		return nil, 0, err
 	}
-	return dto.DonationResponses(donations), total, nil
+	return s.signedResponses(donations), total, nil
 }
 
 func (s *donationService) GetDonationByID(id uint) (dto.DonationDTO, error) {
@@ -74,7 +124,7 @@ func (s *donationService) GetDonationByID(id uint) (dto.DonationDTO, error) {
 		}
 		return dto.DonationDTO{}, err
 	}
-	return dto.DonationResponse(donation), nil
+	return s.signedResponse(donation), nil
 }
 
 func (s *donationService) UpdateDonation(donationDTO dto.DonationDTO, userID uint, isAdmin bool) error {
@@ -83,19 +133,46 @@ func (s *donationService) UpdateDonation(donationDTO dto.DonationDTO, userID uin
 		return err
 	}
 
-	existing, err := s.repo.GetDonationByID(donation.ID)
+	existing, err := s.editableDonation(donation.ID, userID, isAdmin)
+	if err != nil {
+		return err
+	}
+
+	// the status only changes through TransitionDonation
+	donation.Status = existing.Status
+	donation.RejectionReason = existing.RejectionReason
+	// photos change through AddDonationPhotos and RemoveDonationPhoto
+	donation.Photos = nil
+
+	return s.repo.UpdateDonation(donation)
+}
+
+// CheckDonationEditable fails like UpdateDonation would, without changing anything, so a caller
+// can refuse a request before doing the expensive part of it
+func (s *donationService) CheckDonationEditable(id uint, userID uint, isAdmin bool) error {
+	_, err := s.editableDonation(id, userID, isAdmin)
+	return err
+}
+
+// editableDonation returns the donation when the caller can manage it and it is not received yet
+func (s *donationService) editableDonation(id uint, userID uint, isAdmin bool) (entity.Donation, error) {
+	donation, err := s.repo.GetDonationByID(id)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return ErrDonationNotFound
+			return entity.Donation{}, ErrDonationNotFound
 		}
-		return err
+		return entity.Donation{}, err
 	}
 
-	if !s.CanManageDonations(userID, existing.UserID, isAdmin) {
-		return ErrForbidden
+	if !s.CanManageDonations(userID, donation.UserID, isAdmin) {
+		return entity.Donation{}, ErrForbidden
 	}
 
-	return s.repo.UpdateDonation(donation)
+	if !slices.Contains(donationEditableStatuses, donation.Status) {
+		return entity.Donation{}, ErrInvalidDonationTransition
+	}
+
+	return donation, nil
 }
 
 func (s *donationService) DeleteDonation(id uint, userID uint, isAdmin bool) error {
@@ -111,16 +188,27 @@ func (s *donationService) DeleteDonation(id uint, userID uint, isAdmin bool) err
 		return ErrForbidden
 	}
 
-	return s.repo.DeleteDonation(id)
-}
+	if !slices.Contains(donationDeletableStatuses, donation.Status) {
+		return ErrInvalidDonationTransition
+	}
 
-func (s *donationService) PatchDonation(donationDTO dto.DonationDTO, userID uint, isAdmin bool) error {
-	donation, err := dto.DonationRequest(donationDTO)
-	if err != nil {
+	if err := s.repo.DeleteDonation(id); err != nil {
 		return err
 	}
 
-	existing, err := s.repo.GetDonationByID(donation.ID)
+	var objectNames []string
+	for _, p := range donation.Photos {
+		objectNames = append(objectNames, photoObjects(p)...)
+	}
+	s.removeObjects(objectNames)
+	return nil
+}
+
+// TransitionDonation moves a donation to req.Status if the state machine allows it from the current
+// status and the caller is the donor or holds the permission of that move. the move is recorded in
+// the history with role, the role of the caller, or donor
+func (s *donationService) TransitionDonation(id uint, req dto.DonationApprovalDTO, userID uint, role string, permissions []string) error {
+	existing, err := s.repo.GetDonationByID(id)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return ErrDonationNotFound
@@ -128,11 +216,124 @@ func (s *donationService) PatchDonation(donationDTO dto.DonationDTO, userID uint
 		return err
 	}
 
-	if !s.CanManageDonations(userID, existing.UserID, isAdmin) {
+	// somebody who can make no move at all learns nothing about the status
+	reviewer := slices.Contains(permissions, entity.PermDonationVerify) || slices.Contains(permissions, entity.PermDonationDistribute)
+	if !reviewer && userID != existing.UserID {
+		return ErrForbidden
+	}
+
+	allowed, ok := donationTransitions[existing.Status][req.Status]
+	if !ok {
+		return ErrInvalidDonationTransition
+	}
+
+	actorRole := donationActorRole(allowed, userID, existing.UserID, role, permissions)
+	if actorRole == "" {
 		return ErrForbidden
 	}
 
-	return s.repo.PatchDonation(donation)
+	reason := strings.TrimSpace(req.Reason)
+	if req.Status == entity.StatusRejected && reason == "" {
+		return ErrRejectionReasonRequired
+	}
+
+	var draft *entity.AuctionItem
+	if req.Status == entity.StatusVerifiedForAuction {
+		draft = s.draftAuctionItem(existing)
+	}
+
+	err = s.repo.TransitionDonation(entity.DonationStatusHistory{
+		DonationID: id,
+		FromStatus: existing.Status,
+		ToStatus:   req.Status,
+		Reason:     reason,
+		ActorID:    userID,
+		ActorRole:  actorRole,
+	}, draft)
+	if err != nil {
+		if errors.Is(err, repository.ErrDonationStatusChanged) {
+			return ErrInvalidDonationTransition
+		}
+		if errors.Is(err, repository.ErrDonationItemInAuction) {
+			return ErrDonationItemInAuction
+		}
+		logrus.WithError(err).WithFields(logrus.Fields{
+			"donation_id": id,
+			"from":        existing.Status,
+			"to":          req.Status,
+		}).Error("Failed to transition donation")
+		return err
+	}
+	return nil
+}
+
+// draftAuctionItem prepares the auction item of a donation verified for auction, priced by the AI
+// estimate. the estimate is asked before the transition so no transaction waits on it
+func (s *donationService) draftAuctionItem(donation entity.Donation) *entity.AuctionItem {
+	price, err := s.ai.EstimateStartingPrice(repository.PriceEstimationRequest{
+		Name:        donation.Title,
+		Category:    donation.Category,
+		Condition:   donation.Condition,
+		Description: donation.Description,
+	})
+	if err != nil {
+		logrus.WithError(err).WithField("donation_id", donation.ID).Warn("EstimateStartingPrice failed, drafting with the default price")
+		price = DefaultStartingPrice
+	}
+
+	// bidders see the re-encoded display variant, the original upload stays with the donation
+	photos := make([]entity.AuctionItemPhoto, 0, len(donation.Photos))
+	for _, p := range donation.Photos {
+		url := p.DisplayURL
+		if url == "" {
+			url = p.URL
+		}
+		photos = append(photos, entity.AuctionItemPhoto{URL: url, ThumbnailURL: p.ThumbnailURL, Private: true})
+	}
+
+	return &entity.AuctionItem{
+		DonationID:    int64(donation.ID),
+		Title:         donation.Title,
+		Description:   donation.Description,
+		Category:      donation.Category,
+		Status:        "draft",
+		StartingPrice: price,
+		Photos:        photos,
+	}
+}
+
+// donationActorRole picks the role the caller makes the transition as, "" when none is allowed
+func donationActorRole(allowed []string, userID uint, ownerID uint, role string, permissions []string) string {
+	for _, a := range allowed {
+		switch {
+		case a == DonationRoleDonor && userID == ownerID:
+			return DonationRoleDonor
+		case a != DonationRoleDonor && slices.Contains(permissions, a):
+			return role
+		}
+	}
+	return ""
+}
+
+// GetDonationHistory lists the status transitions of a donation, for its donor or an admin
+func (s *donationService) GetDonationHistory(id uint, userID uint, isAdmin bool) ([]dto.DonationStatusHistoryDTO, error) {
+	donation, err := s.repo.GetDonationByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrDonationNotFound
+		}
+		return nil, err
+	}
+
+	if !s.CanManageDonations(userID, donation.UserID, isAdmin) {
+		return nil, ErrForbidden
+	}
+
+	history, err := s.repo.GetDonationHistory(id)
+	if err != nil {
+		return nil, err
+	}
+	return dto.DonationHistoryResponses(history), nil
 }
 
 func (s *donationService) CanManageDonations(userID uint, ownerID uint, isAdmin bool) bool {
@@ -146,18 +347,115 @@ func (s *donationService) CanManageDonations(userID uint, ownerID uint, isAdmin
 //  METHODS FOR GCS
 // ======================
 
-func (s *donationService) UploadDonationImage(ctx context.Context, file io.Reader, fileName string) (string, error) {
-	objectName, err := s.privateStore.UploadFile(ctx, file, fileName)
+// DonationPhotoURLExpiry is how long the signed photo URLs of a donation response stay valid
+const DonationPhotoURLExpiry = 10 * time.Minute
+
+// AddDonationPhotos attaches photos already uploaded to the private bucket to a donation
+func (s *donationService) AddDonationPhotos(id uint, uploaded []dto.DonationPhotoDTO, userID uint, isAdmin bool) (dto.DonationDTO, error) {
+	// the photos show the goods as handed in, they are locked with the rest of the donation
+	donation, err := s.editableDonation(id, userID, isAdmin)
 	if err != nil {
-		return ErrImageNotFound.Error(), err
+		return dto.DonationDTO{}, err
+	}
+
+	photos := dto.DonationPhotoRequests(uploaded)
+	for i := range photos {
+		photos[i].DonationID = id
+	}
+	if err := s.repo.AddDonationPhotos(photos); err != nil {
+		logrus.WithError(err).WithField("donation_id", id).Error("Failed to add donation photos")
+		return dto.DonationDTO{}, err
 	}
-	return objectName, nil
+
+	donation.Photos = append(donation.Photos, photos...)
+	return s.signedResponse(donation), nil
 }
 
-func (s *donationService) GetDonationImageURL(ctx context.Context, objectName string) (string, error) {
-	url, err := s.privateStore.GenerateSignedURL(ctx, objectName, 10*time.Minute)
+// RemoveDonationPhoto deletes a photo of a donation and its stored object
+func (s *donationService) RemoveDonationPhoto(id uint, photoID uint, userID uint, isAdmin bool) error {
+	if _, err := s.editableDonation(id, userID, isAdmin); err != nil {
+		return err
+	}
+
+	photo, err := s.repo.DeleteDonationPhoto(id, photoID)
 	if err != nil {
-		return ErrSignedURLFailed.Error(), err
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrDonationPhotoNotFound
+		}
+		return err
+	}
+
+	s.removeObjects(photoObjects(photo))
+	return nil
+}
+
+func (s *donationService) signedResponses(donations []entity.Donation) []dto.DonationDTO {
+	res := make([]dto.DonationDTO, 0, len(donations))
+	for _, d := range donations {
+		res = append(res, s.signedResponse(d))
+	}
+	return res
+}
+
+// signedResponse converts the donation with signed URLs in place of the private object names
+func (s *donationService) signedResponse(donation entity.Donation) dto.DonationDTO {
+	res := dto.DonationResponse(donation)
+	if s.privateStore == nil {
+		return res
+	}
+
+	ctx := context.Background()
+	sign := func(photoID uint, objectName string) string {
+		if objectName == "" {
+			return ""
+		}
+		url, err := s.privateStore.SignedURL(ctx, objectName, DonationPhotoURLExpiry)
+		if err != nil {
+			// the object name is no use to the client, an empty URL is shown as a missing photo
+			logrus.WithError(err).WithField("photo_id", photoID).Warn("Failed to sign donation photo URL")
+			return ""
+		}
+		return url
+	}
+	for i, p := range res.PhotoItems {
+		res.PhotoItems[i].URL = sign(p.ID, p.URL)
+		res.PhotoItems[i].ThumbnailURL = sign(p.ID, p.ThumbnailURL)
+		res.PhotoItems[i].DisplayURL = sign(p.ID, p.DisplayURL)
+		res.Photos[i] = res.PhotoItems[i].URL
+	}
+	return res
+}
+
+// photoObjects are the stored objects of a photo, photos uploaded before the variants have only one
+func photoObjects(p entity.DonationPhoto) []string {
+	var names []string
+	for _, name := range []string{p.URL, p.DisplayURL, p.ThumbnailURL} {
+		if name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// removeObjects deletes stored objects nothing points at anymore. a failure leaves an orphaned
+// object behind, which is logged but does not fail the request that already deleted the rows
+func (s *donationService) removeObjects(objectNames []string) {
+	if s.privateStore == nil {
+		return
+	}
+
+	ctx := context.Background()
+	for _, name := range objectNames {
+		referenced, err := s.repo.IsObjectReferenced(name)
+		if err != nil {
+			logrus.WithError(err).WithField("object", name).Warn("Failed to check donation photo references, object kept")
+			continue
+		}
+		if referenced {
+			continue
+		}
+		if err := s.privateStore.Delete(ctx, name); err != nil {
+			logrus.WithError(err).WithField("object", name).Warn("Failed to delete orphaned donation photo")
+		}
 	}
-	return url, nil
 }
@@ -7,6 +7,7 @@ import (
 	"milestone3/be/internal/dto"
 	"milestone3/be/internal/entity"
 	"milestone3/be/internal/mocks"
+	"milestone3/be/internal/repository"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
@@ -18,8 +19,8 @@ func TestDonationService_CreateDonation(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockDonationRepo(ctrl)
-	mockStorage := mocks.NewMockGCPStorageRepo(ctrl)
-	donationService := NewDonationService(mockRepo, mockStorage)
+	mockStorage := mocks.NewMockObjectStore(ctrl)
+	donationService := NewDonationService(mockRepo, mockStorage, mocks.NewMockAIRepository(ctrl))
 
 	tests := []struct {
 		name    string
@@ -73,8 +74,8 @@ func TestDonationService_GetAllDonations(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockDonationRepo(ctrl)
-	mockStorage := mocks.NewMockGCPStorageRepo(ctrl)
-	donationService := NewDonationService(mockRepo, mockStorage)
+	mockStorage := mocks.NewMockObjectStore(ctrl)
+	donationService := NewDonationService(mockRepo, mockStorage, mocks.NewMockAIRepository(ctrl))
 
 	tests := []struct {
 		name    string
@@ -142,14 +143,15 @@ func TestDonationService_GetDonationByID(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockDonationRepo(ctrl)
-	mockStorage := mocks.NewMockGCPStorageRepo(ctrl)
-	donationService := NewDonationService(mockRepo, mockStorage)
+	mockStorage := mocks.NewMockObjectStore(ctrl)
+	donationService := NewDonationService(mockRepo, mockStorage, mocks.NewMockAIRepository(ctrl))
 
 	tests := []struct {
-		name    string
-		id      uint
-		setup   func()
-		wantErr bool
+		name       string
+		id         uint
+		setup      func()
+		wantErr    bool
+		wantPhotos []string
 	}{
 		{
 			name: "successful get donation by id",
@@ -160,6 +162,21 @@ func TestDonationService_GetDonationByID(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "photos come back as signed URLs",
+			id:   1,
+			setup: func() {
+				donation := entity.Donation{ID: 1, Title: "Test Donation", UserID: 1, Photos: []entity.DonationPhoto{
+					{ID: 5, DonationID: 1, URL: "donations/private/1_a.jpg"},
+					{ID: 6, DonationID: 1, URL: "donations/private/2_b.jpg"},
+				}}
+				mockRepo.EXPECT().GetDonationByID(uint(1)).Return(donation, nil)
+				mockStorage.EXPECT().SignedURL(gomock.Any(), "donations/private/1_a.jpg", DonationPhotoURLExpiry).Return("https://signed/a", nil)
+				mockStorage.EXPECT().SignedURL(gomock.Any(), "donations/private/2_b.jpg", DonationPhotoURLExpiry).Return("", errors.New("sign failed"))
+			},
+			wantErr:    false,
+			wantPhotos: []string{"https://signed/a", ""},
+		},
 		{
 			name: "donation not found",
 			id:   999,
@@ -175,6 +192,11 @@ func TestDonationService_GetDonationByID(t *testing.T) {
 			tt.setup()
 
 			result, err := donationService.GetDonationByID(tt.id)
+			if tt.wantPhotos != nil {
+				assert.Equal(t, tt.wantPhotos, result.Photos)
+				assert.Equal(t, uint(5), result.PhotoItems[0].ID)
+				assert.Equal(t, "https://signed/a", result.PhotoItems[0].URL)
+			}
 
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -192,8 +214,8 @@ func TestDonationService_CanManageDonations(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockDonationRepo(ctrl)
-	mockStorage := mocks.NewMockGCPStorageRepo(ctrl)
-	donationService := NewDonationService(mockRepo, mockStorage)
+	mockStorage := mocks.NewMockObjectStore(ctrl)
+	donationService := NewDonationService(mockRepo, mockStorage, mocks.NewMockAIRepository(ctrl))
 
 	tests := []struct {
 		name     string
@@ -238,8 +260,8 @@ func TestDonationService_UpdateDonation(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockDonationRepo(ctrl)
-	mockStorage := mocks.NewMockGCPStorageRepo(ctrl)
-	donationService := NewDonationService(mockRepo, mockStorage)
+	mockStorage := mocks.NewMockObjectStore(ctrl)
+	donationService := NewDonationService(mockRepo, mockStorage, mocks.NewMockAIRepository(ctrl))
 
 	tests := []struct {
 		name    string
@@ -258,11 +280,24 @@ func TestDonationService_UpdateDonation(t *testing.T) {
 			userID:  1,
 			isAdmin: false,
 			setup: func() {
-				mockRepo.EXPECT().GetDonationByID(uint(1)).Return(entity.Donation{ID: 1, UserID: 1}, nil)
+				mockRepo.EXPECT().GetDonationByID(uint(1)).Return(entity.Donation{ID: 1, UserID: 1, Status: entity.StatusPending}, nil)
 				mockRepo.EXPECT().UpdateDonation(gomock.Any()).Return(nil)
 			},
 			wantErr: false,
 		},
+		{
+			name: "received donation cannot be edited",
+			req: dto.DonationDTO{
+				ID:    1,
+				Title: "Updated",
+			},
+			userID:  1,
+			isAdmin: true,
+			setup: func() {
+				mockRepo.EXPECT().GetDonationByID(uint(1)).Return(entity.Donation{ID: 1, UserID: 1, Status: entity.StatusReceived}, nil)
+			},
+			wantErr: true,
+		},
 		{
 			name: "forbidden - not owner",
 			req: dto.DonationDTO{
@@ -295,8 +330,8 @@ func TestDonationService_DeleteDonation(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockDonationRepo(ctrl)
-	mockStorage := mocks.NewMockGCPStorageRepo(ctrl)
-	donationService := NewDonationService(mockRepo, mockStorage)
+	mockStorage := mocks.NewMockObjectStore(ctrl)
+	donationService := NewDonationService(mockRepo, mockStorage, mocks.NewMockAIRepository(ctrl))
 
 	tests := []struct {
 		name    string
@@ -312,11 +347,49 @@ func TestDonationService_DeleteDonation(t *testing.T) {
 			userID:  1,
 			isAdmin: false,
 			setup: func() {
-				mockRepo.EXPECT().GetDonationByID(uint(1)).Return(entity.Donation{ID: 1, UserID: 1}, nil)
+				mockRepo.EXPECT().GetDonationByID(uint(1)).Return(entity.Donation{ID: 1, UserID: 1, Status: entity.StatusPending}, nil)
 				mockRepo.EXPECT().DeleteDonation(uint(1)).Return(nil)
 			},
 			wantErr: false,
 		},
+		{
+			name:    "delete removes photos nothing else references",
+			id:      2,
+			userID:  1,
+			isAdmin: false,
+			setup: func() {
+				mockRepo.EXPECT().GetDonationByID(uint(2)).Return(entity.Donation{ID: 2, UserID: 1, Status: entity.StatusCancelledByDonor, Photos: []entity.DonationPhoto{
+					{ID: 5, DonationID: 2, URL: "donations/private/1_a.jpg"},
+					{ID: 6, DonationID: 2, URL: "donations/private/2_b.jpg"},
+				}}, nil)
+				mockRepo.EXPECT().DeleteDonation(uint(2)).Return(nil)
+				mockRepo.EXPECT().IsObjectReferenced("donations/private/1_a.jpg").Return(false, nil)
+				mockStorage.EXPECT().Delete(gomock.Any(), "donations/private/1_a.jpg").Return(nil)
+				// still used by the drafted auction item
+				mockRepo.EXPECT().IsObjectReferenced("donations/private/2_b.jpg").Return(true, nil)
+			},
+			wantErr: false,
+		},
+		{
+			name:    "donation with a drafted auction item cannot be deleted",
+			id:      4,
+			userID:  1,
+			isAdmin: false,
+			setup: func() {
+				mockRepo.EXPECT().GetDonationByID(uint(4)).Return(entity.Donation{ID: 4, UserID: 1, Status: entity.StatusVerifiedForAuction}, nil)
+			},
+			wantErr: true,
+		},
+		{
+			name:    "delete by another user is forbidden",
+			id:      3,
+			userID:  2,
+			isAdmin: false,
+			setup: func() {
+				mockRepo.EXPECT().GetDonationByID(uint(3)).Return(entity.Donation{ID: 3, UserID: 1}, nil)
+			},
+			wantErr: true,
+		},
 		{
 			name:    "donation not found",
 			id:      999,
@@ -342,47 +415,460 @@ func TestDonationService_DeleteDonation(t *testing.T) {
 	}
 }
 
-func TestDonationService_PatchDonation(t *testing.T) {
+func TestDonationService_TransitionDonation(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockDonationRepo(ctrl)
-	mockStorage := mocks.NewMockGCPStorageRepo(ctrl)
-	donationService := NewDonationService(mockRepo, mockStorage)
+	mockStorage := mocks.NewMockObjectStore(ctrl)
+	mockAI := mocks.NewMockAIRepository(ctrl)
+	donationService := NewDonationService(mockRepo, mockStorage, mockAI)
+
+	adminPerms := []string{entity.PermDonationVerify, entity.PermDonationDistribute}
 
 	tests := []struct {
 		name    string
-		req     dto.DonationDTO
+		current entity.StatusDonation
+		req     dto.DonationApprovalDTO
 		userID  uint
-		isAdmin bool
+		role    string
+		perms   []string
 		setup   func()
-		wantErr bool
+		wantErr error
 	}{
 		{
-			name: "successful patch by admin",
-			req: dto.DonationDTO{
-				ID:     1,
-				Status: "verified_for_donation",
+			name:    "admin schedules the pickup",
+			current: entity.StatusPending,
+			req:     dto.DonationApprovalDTO{Status: entity.StatusAwaitingPickup},
+			userID:  1,
+			role:    "admin",
+			perms:   adminPerms,
+			setup: func() {
+				mockRepo.EXPECT().TransitionDonation(entity.DonationStatusHistory{
+					DonationID: 1,
+					FromStatus: entity.StatusPending,
+					ToStatus:   entity.StatusAwaitingPickup,
+					ActorID:    1,
+					ActorRole:  "admin",
+				}, nil).Return(nil)
+			},
+		},
+		{
+			name:    "admin verifies a received item for donation",
+			current: entity.StatusReceived,
+			req:     dto.DonationApprovalDTO{Status: entity.StatusVerifiedForDonation},
+			userID:  1,
+			role:    "admin",
+			perms:   adminPerms,
+			setup: func() {
+				mockRepo.EXPECT().TransitionDonation(gomock.Any(), nil).Return(nil)
+			},
+		},
+		{
+			name:    "verifying for auction drafts the auction item",
+			current: entity.StatusReceived,
+			req:     dto.DonationApprovalDTO{Status: entity.StatusVerifiedForAuction},
+			userID:  1,
+			role:    "admin",
+			perms:   adminPerms,
+			setup: func() {
+				mockAI.EXPECT().EstimateStartingPrice(repository.PriceEstimationRequest{Name: "Laptop", Category: "Electronics", Condition: "good", Description: "14 inch"}).Return(2500000.0, nil)
+				mockRepo.EXPECT().TransitionDonation(gomock.Any(), &entity.AuctionItem{
+					DonationID:    1,
+					Title:         "Laptop",
+					Description:   "14 inch",
+					Category:      "Electronics",
+					Status:        "draft",
+					StartingPrice: 2500000,
+					Photos: []entity.AuctionItemPhoto{{
+						URL:          "donations/private/1_laptop_display.jpg",
+						ThumbnailURL: "donations/private/1_laptop_thumb.jpg",
+						Private:      true,
+					}},
+				}).Return(nil)
+			},
+		},
+		{
+			name:    "draft falls back to the default price",
+			current: entity.StatusReceived,
+			req:     dto.DonationApprovalDTO{Status: entity.StatusVerifiedForAuction},
+			userID:  1,
+			role:    "admin",
+			perms:   adminPerms,
+			setup: func() {
+				mockAI.EXPECT().EstimateStartingPrice(gomock.Any()).Return(10000.0, errors.New("all AI models failed"))
+				mockRepo.EXPECT().TransitionDonation(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ entity.DonationStatusHistory, draft *entity.AuctionItem) error {
+						assert.Equal(t, float64(DefaultStartingPrice), draft.StartingPrice)
+						return nil
+					})
 			},
+		},
+		{
+			name:    "admin rejects with a reason",
+			current: entity.StatusReceived,
+			req:     dto.DonationApprovalDTO{Status: entity.StatusRejected, Reason: "  broken screen "},
 			userID:  1,
+			role:    "admin",
+			perms:   adminPerms,
+			setup: func() {
+				mockRepo.EXPECT().TransitionDonation(entity.DonationStatusHistory{
+					DonationID: 1,
+					FromStatus: entity.StatusReceived,
+					ToStatus:   entity.StatusRejected,
+					Reason:     "broken screen",
+					ActorID:    1,
+					ActorRole:  "admin",
+				}, nil).Return(nil)
+			},
+		},
+		{
+			name:    "rejection without a reason",
+			current: entity.StatusPending,
+			req:     dto.DonationApprovalDTO{Status: entity.StatusRejected, Reason: " "},
+			userID:  1,
+			role:    "admin",
+			perms:   adminPerms,
+			setup:   func() {},
+			wantErr: ErrRejectionReasonRequired,
+		},
+		{
+			name:    "donor cancels before the pickup",
+			current: entity.StatusAwaitingPickup,
+			req:     dto.DonationApprovalDTO{Status: entity.StatusCancelledByDonor},
+			userID:  2,
+			setup: func() {
+				mockRepo.EXPECT().TransitionDonation(entity.DonationStatusHistory{
+					DonationID: 1,
+					FromStatus: entity.StatusAwaitingPickup,
+					ToStatus:   entity.StatusCancelledByDonor,
+					ActorID:    2,
+					ActorRole:  DonationRoleDonor,
+				}, nil).Return(nil)
+			},
+		},
+		{
+			name:    "donor cannot cancel a received item",
+			current: entity.StatusReceived,
+			req:     dto.DonationApprovalDTO{Status: entity.StatusCancelledByDonor},
+			userID:  2,
+			setup:   func() {},
+			wantErr: ErrInvalidDonationTransition,
+		},
+		{
+			name:    "donor cannot verify their own donation",
+			current: entity.StatusReceived,
+			req:     dto.DonationApprovalDTO{Status: entity.StatusVerifiedForAuction},
+			userID:  2,
+			setup:   func() {},
+			wantErr: ErrForbidden,
+		},
+		{
+			name:    "admin cannot cancel for the donor",
+			current: entity.StatusPending,
+			req:     dto.DonationApprovalDTO{Status: entity.StatusCancelledByDonor},
+			userID:  1,
+			role:    "admin",
+			perms:   adminPerms,
+			setup:   func() {},
+			wantErr: ErrForbidden,
+		},
+		{
+			name:    "other user",
+			current: entity.StatusPending,
+			req:     dto.DonationApprovalDTO{Status: entity.StatusCancelledByDonor},
+			userID:  3,
+			setup:   func() {},
+			wantErr: ErrForbidden,
+		},
+		{
+			name:    "verification skips the inspection",
+			current: entity.StatusPending,
+			req:     dto.DonationApprovalDTO{Status: entity.StatusVerifiedForAuction},
+			userID:  1,
+			role:    "admin",
+			perms:   adminPerms,
+			setup:   func() {},
+			wantErr: ErrInvalidDonationTransition,
+		},
+		{
+			name:    "final status",
+			current: entity.StatusDistributed,
+			req:     dto.DonationApprovalDTO{Status: entity.StatusAuctioned},
+			userID:  1,
+			role:    "admin",
+			perms:   adminPerms,
+			setup:   func() {},
+			wantErr: ErrInvalidDonationTransition,
+		},
+		{
+			name:    "verifier schedules the pickup",
+			current: entity.StatusPending,
+			req:     dto.DonationApprovalDTO{Status: entity.StatusAwaitingPickup},
+			userID:  4,
+			role:    entity.RoleVerifier,
+			perms:   []string{entity.PermDonationReadAll, entity.PermDonationVerify},
+			setup: func() {
+				mockRepo.EXPECT().TransitionDonation(entity.DonationStatusHistory{
+					DonationID: 1,
+					FromStatus: entity.StatusPending,
+					ToStatus:   entity.StatusAwaitingPickup,
+					ActorID:    4,
+					ActorRole:  entity.RoleVerifier,
+				}, nil).Return(nil)
+			},
+		},
+		{
+			name:    "verifier cannot distribute",
+			current: entity.StatusVerifiedForDonation,
+			req:     dto.DonationApprovalDTO{Status: entity.StatusDistributed},
+			userID:  4,
+			role:    entity.RoleVerifier,
+			perms:   []string{entity.PermDonationReadAll, entity.PermDonationVerify},
+			setup:   func() {},
+			wantErr: ErrForbidden,
+		},
+		{
+			name:    "institution distributes a verified item",
+			current: entity.StatusVerifiedForDonation,
+			req:     dto.DonationApprovalDTO{Status: entity.StatusDistributed},
+			userID:  5,
+			role:    entity.RoleInstitution,
+			perms:   []string{entity.PermDonationDistribute, entity.PermFinalDonationReadAll},
+			setup: func() {
+				mockRepo.EXPECT().TransitionDonation(entity.DonationStatusHistory{
+					DonationID: 1,
+					FromStatus: entity.StatusVerifiedForDonation,
+					ToStatus:   entity.StatusDistributed,
+					ActorID:    5,
+					ActorRole:  entity.RoleInstitution,
+				}, nil).Return(nil)
+			},
+		},
+		{
+			name:    "institution cannot verify",
+			current: entity.StatusReceived,
+			req:     dto.DonationApprovalDTO{Status: entity.StatusVerifiedForDonation},
+			userID:  5,
+			role:    entity.RoleInstitution,
+			perms:   []string{entity.PermDonationDistribute, entity.PermFinalDonationReadAll},
+			setup:   func() {},
+			wantErr: ErrForbidden,
+		},
+		{
+			name:    "status changed in between",
+			current: entity.StatusAwaitingPickup,
+			req:     dto.DonationApprovalDTO{Status: entity.StatusReceived},
+			userID:  1,
+			role:    "admin",
+			perms:   adminPerms,
+			setup: func() {
+				mockRepo.EXPECT().TransitionDonation(gomock.Any(), nil).Return(repository.ErrDonationStatusChanged)
+			},
+			wantErr: ErrInvalidDonationTransition,
+		},
+		{
+			name:    "item already scheduled cannot be given away",
+			current: entity.StatusVerifiedForAuction,
+			req:     dto.DonationApprovalDTO{Status: entity.StatusVerifiedForDonation},
+			userID:  1,
+			role:    "admin",
+			perms:   adminPerms,
+			setup: func() {
+				mockRepo.EXPECT().TransitionDonation(gomock.Any(), nil).Return(repository.ErrDonationItemInAuction)
+			},
+			wantErr: ErrDonationItemInAuction,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo.EXPECT().GetDonationByID(uint(1)).Return(entity.Donation{
+				ID:          1,
+				UserID:      2,
+				Title:       "Laptop",
+				Description: "14 inch",
+				Category:    "Electronics",
+				Condition:   "good",
+				Status:      tt.current,
+				Photos: []entity.DonationPhoto{{
+					DonationID:   1,
+					URL:          "donations/private/1_laptop.jpg",
+					DisplayURL:   "donations/private/1_laptop_display.jpg",
+					ThumbnailURL: "donations/private/1_laptop_thumb.jpg",
+				}},
+			}, nil)
+			tt.setup()
+			err := donationService.TransitionDonation(1, tt.req, tt.userID, tt.role, tt.perms)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+
+	t.Run("donation not found", func(t *testing.T) {
+		mockRepo.EXPECT().GetDonationByID(uint(999)).Return(entity.Donation{}, gorm.ErrRecordNotFound)
+		err := donationService.TransitionDonation(999, dto.DonationApprovalDTO{Status: entity.StatusAwaitingPickup}, 1, "admin", adminPerms)
+		assert.ErrorIs(t, err, ErrDonationNotFound)
+	})
+}
+
+func TestDonationService_GetDonationHistory(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockDonationRepo(ctrl)
+	mockStorage := mocks.NewMockObjectStore(ctrl)
+	donationService := NewDonationService(mockRepo, mockStorage, mocks.NewMockAIRepository(ctrl))
+
+	mockRepo.EXPECT().GetDonationByID(uint(1)).Return(entity.Donation{ID: 1, UserID: 2}, nil).Times(2)
+	mockRepo.EXPECT().GetDonationHistory(uint(1)).Return([]entity.DonationStatusHistory{
+		{DonationID: 1, FromStatus: entity.StatusPending, ToStatus: entity.StatusRejected, Reason: "not accepted", ActorID: 1, ActorRole: "admin"},
+	}, nil)
+
+	history, err := donationService.GetDonationHistory(1, 2, false)
+	assert.NoError(t, err)
+	assert.Equal(t, []dto.DonationStatusHistoryDTO{
+		{FromStatus: entity.StatusPending, ToStatus: entity.StatusRejected, Reason: "not accepted", ActorRole: "admin"},
+	}, history)
+
+	_, err = donationService.GetDonationHistory(1, 3, false)
+	assert.ErrorIs(t, err, ErrForbidden)
+}
+
+func TestDonationService_RemoveDonationPhoto(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockDonationRepo(ctrl)
+	mockStorage := mocks.NewMockObjectStore(ctrl)
+	donationService := NewDonationService(mockRepo, mockStorage, mocks.NewMockAIRepository(ctrl))
+
+	tests := []struct {
+		name    string
+		userID  uint
+		isAdmin bool
+		setup   func()
+		wantErr error
+	}{
+		{
+			name:   "owner removes an unreferenced photo",
+			userID: 1,
+			setup: func() {
+				mockRepo.EXPECT().GetDonationByID(uint(1)).Return(entity.Donation{ID: 1, UserID: 1, Status: entity.StatusPending}, nil)
+				mockRepo.EXPECT().DeleteDonationPhoto(uint(1), uint(5)).Return(entity.DonationPhoto{ID: 5, DonationID: 1, URL: "donations/private/1_a.jpg"}, nil)
+				mockRepo.EXPECT().IsObjectReferenced("donations/private/1_a.jpg").Return(false, nil)
+				mockStorage.EXPECT().Delete(gomock.Any(), "donations/private/1_a.jpg").Return(nil)
+			},
+		},
+		{
+			name:   "variants are removed with the photo",
+			userID: 1,
+			setup: func() {
+				mockRepo.EXPECT().GetDonationByID(uint(1)).Return(entity.Donation{ID: 1, UserID: 1, Status: entity.StatusPending}, nil)
+				mockRepo.EXPECT().DeleteDonationPhoto(uint(1), uint(5)).Return(entity.DonationPhoto{
+					ID: 5, DonationID: 1, URL: "donations/private/1_a.jpg",
+					DisplayURL: "donations/private/1_a_display.jpg", ThumbnailURL: "donations/private/1_a_thumb.jpg",
+				}, nil)
+				for _, name := range []string{"1_a", "1_a_display", "1_a_thumb"} {
+					mockRepo.EXPECT().IsObjectReferenced("donations/private/"+name+".jpg").Return(false, nil)
+					mockStorage.EXPECT().Delete(gomock.Any(), "donations/private/"+name+".jpg").Return(nil)
+				}
+			},
+		},
+		{
+			name:    "admin removes a photo the auction item still uses",
+			userID:  9,
 			isAdmin: true,
 			setup: func() {
-				mockRepo.EXPECT().GetDonationByID(uint(1)).Return(entity.Donation{ID: 1, UserID: 2}, nil)
-				mockRepo.EXPECT().PatchDonation(gomock.Any()).Return(nil)
+				mockRepo.EXPECT().GetDonationByID(uint(1)).Return(entity.Donation{ID: 1, UserID: 1, Status: entity.StatusPending}, nil)
+				mockRepo.EXPECT().DeleteDonationPhoto(uint(1), uint(5)).Return(entity.DonationPhoto{ID: 5, DonationID: 1, URL: "donations/private/1_a.jpg"}, nil)
+				mockRepo.EXPECT().IsObjectReferenced("donations/private/1_a.jpg").Return(true, nil)
 			},
-			wantErr: false,
+		},
+		{
+			name:   "photo of another donation",
+			userID: 1,
+			setup: func() {
+				mockRepo.EXPECT().GetDonationByID(uint(1)).Return(entity.Donation{ID: 1, UserID: 1, Status: entity.StatusPending}, nil)
+				mockRepo.EXPECT().DeleteDonationPhoto(uint(1), uint(5)).Return(entity.DonationPhoto{}, gorm.ErrRecordNotFound)
+			},
+			wantErr: ErrDonationPhotoNotFound,
+		},
+		{
+			name:   "photos of a received donation are locked",
+			userID: 1,
+			setup: func() {
+				mockRepo.EXPECT().GetDonationByID(uint(1)).Return(entity.Donation{ID: 1, UserID: 1, Status: entity.StatusVerifiedForAuction}, nil)
+			},
+			wantErr: ErrInvalidDonationTransition,
+		},
+		{
+			name:   "other user is forbidden",
+			userID: 2,
+			setup: func() {
+				mockRepo.EXPECT().GetDonationByID(uint(1)).Return(entity.Donation{ID: 1, UserID: 1, Status: entity.StatusPending}, nil)
+			},
+			wantErr: ErrForbidden,
+		},
+		{
+			name:   "donation not found",
+			userID: 1,
+			setup: func() {
+				mockRepo.EXPECT().GetDonationByID(uint(1)).Return(entity.Donation{}, gorm.ErrRecordNotFound)
+			},
+			wantErr: ErrDonationNotFound,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.setup()
-			err := donationService.PatchDonation(tt.req, tt.userID, tt.isAdmin)
-			if tt.wantErr {
-				assert.Error(t, err)
+			err := donationService.RemoveDonationPhoto(1, 5, tt.userID, tt.isAdmin)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
 			} else {
 				assert.NoError(t, err)
 			}
 		})
 	}
 }
+
+func TestDonationService_AddDonationPhotos(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockDonationRepo(ctrl)
+	mockStorage := mocks.NewMockObjectStore(ctrl)
+	donationService := NewDonationService(mockRepo, mockStorage, mocks.NewMockAIRepository(ctrl))
+
+	mockRepo.EXPECT().GetDonationByID(uint(1)).Return(entity.Donation{ID: 1, UserID: 1, Status: entity.StatusPending}, nil)
+	mockRepo.EXPECT().AddDonationPhotos([]entity.DonationPhoto{{
+		DonationID:   1,
+		URL:          "donations/private/3_c.jpg",
+		DisplayURL:   "donations/private/3_c_display.jpg",
+		ThumbnailURL: "donations/private/3_c_thumb.jpg",
+	}}).Return(nil)
+	for _, name := range []string{"3_c", "3_c_display", "3_c_thumb"} {
+		mockStorage.EXPECT().SignedURL(gomock.Any(), "donations/private/"+name+".jpg", DonationPhotoURLExpiry).Return("https://signed/"+name, nil)
+	}
+
+	result, err := donationService.AddDonationPhotos(1, []dto.DonationPhotoDTO{{
+		URL:          "donations/private/3_c.jpg",
+		DisplayURL:   "donations/private/3_c_display.jpg",
+		ThumbnailURL: "donations/private/3_c_thumb.jpg",
+	}}, 1, false)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"https://signed/3_c"}, result.Photos)
+	assert.Equal(t, dto.DonationPhotoDTO{URL: "https://signed/3_c", DisplayURL: "https://signed/3_c_display", ThumbnailURL: "https://signed/3_c_thumb"}, result.PhotoItems[0])
+
+	mockRepo.EXPECT().GetDonationByID(uint(1)).Return(entity.Donation{ID: 1, UserID: 1, Status: entity.StatusPending}, nil)
+	_, err = donationService.AddDonationPhotos(1, []dto.DonationPhotoDTO{{URL: "donations/private/4_d.jpg"}}, 2, false)
+	assert.ErrorIs(t, err, ErrForbidden)
+
+	mockRepo.EXPECT().GetDonationByID(uint(1)).Return(entity.Donation{ID: 1, UserID: 1, Status: entity.StatusVerifiedForDonation}, nil)
+	_, err = donationService.AddDonationPhotos(1, []dto.DonationPhotoDTO{{URL: "donations/private/4_d.jpg"}}, 1, false)
+	assert.ErrorIs(t, err, ErrInvalidDonationTransition)
+}
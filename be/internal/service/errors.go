@@ -11,6 +11,18 @@ var (
 	ErrUserNotFoundEmail    = errors.New("user email not found")
 	ErrUserNotFoundPassword = errors.New("user password not found")
 	ErrInvalidCredential    = errors.New("invalid credentials")
+	ErrInvalidRefreshToken  = errors.New("invalid or expired refresh token")
+	// ErrRefreshTokenReused means a rotated refresh token came back, its whole family is revoked
+	ErrRefreshTokenReused       = errors.New("refresh token reused")
+	ErrInvalidVerificationToken = errors.New("invalid or expired verification token")
+	ErrInvalidResetToken        = errors.New("invalid or expired password reset token")
+	ErrRoleNotFound             = errors.New("role not found")
+	ErrCannotChangeOwnRole      = errors.New("you cannot change your own role")
+	ErrWrongPassword            = errors.New("current password is incorrect")
+	ErrAccountSuspended         = errors.New("account suspended")
+	ErrCannotSuspendSelf        = errors.New("you cannot suspend yourself")
+	// ErrAccountHasOpenActivity blocks deleting an account with a running bid, payment or donation
+	ErrAccountHasOpenActivity = errors.New("finish your running bids, payments and donations before deleting your account")
 
 	// Payment Errors
 	ErrPaymentNotFound       = errors.New("payment not found")
@@ -19,6 +31,17 @@ var (
 	ErrPaymentNotFoundAmount = errors.New("payment amount not found")
 	ErrPaymentNotFoundMethod = errors.New("payment method not found")
 	ErrPaymentNotFoundStatus = errors.New("payment status not found")
+	ErrInvalidSignature      = errors.New("invalid notification signature")
+	ErrInvalidNotification   = errors.New("invalid notification payload")
+	ErrPaymentAmountMismatch = errors.New("notification amount does not match the payment")
+	ErrItemNotFinished       = errors.New("auction item is not finished")
+	ErrItemAlreadyPaid       = errors.New("auction item is already paid")
+	ErrWinnerPaymentNotOpen  = errors.New("the winner payment is not open yet, try again shortly")
+	ErrNotAuctionWinner      = errors.New("only the winning bidder can pay for this item")
+	ErrDuplicatePayment      = errors.New("a payment for this item is already in progress")
+	ErrPaymentNotRefundable  = errors.New("payment is not paid or a refund is in progress")
+	ErrRefundExceedsPayment  = errors.New("refund exceeds the amount left on the payment")
+	ErrRefundRejected        = errors.New("the payment gateway rejected the refund")
 	// Auction Errors
 	ErrAuctionNotFound   = errors.New("auction not found")
 	ErrInvalidAuction    = errors.New("invalid auction data")
@@ -29,20 +52,28 @@ var (
 	ErrActiveSession     = errors.New("cannot modify an active auction session")
 	ErrAuctionFinished   = errors.New("cannot update auction item with status 'finished'")
 	ErrExpiredSession    = errors.New("cannot modify expired auction session")
+	ErrInvalidPriceRules = errors.New("buy it now price must be above the starting price and not below the reserve price")
 	// Donation Errors
 	ErrDonationNotFound          = errors.New("donation not found")
 	ErrInvalidDonation           = errors.New("invalid donation data")
 	ErrDonationNotFoundID        = errors.New("donation ID not found")
 	ErrDonationNotFoundAmount    = errors.New("donation amount not found")
 	ErrDonationNotFoundDonorName = errors.New("donor name not found")
+	ErrInvalidDonationTransition = errors.New("donation cannot move to this status from its current status")
+	ErrRejectionReasonRequired   = errors.New("a reason is required to reject a donation")
+	ErrDonationPhotoNotFound     = errors.New("donation photo not found")
+	ErrDonationItemInAuction     = errors.New("the auction item of the donation is scheduled, running or sold")
 	// Article Errors
 	ErrArticleNotFound = errors.New("article not found")
 	ErrInvalidArticle  = errors.New("invalid article data")
 	// Bidding Errors
-	ErrInvalidBidding       = errors.New("invalid bid amount")
-	ErrBidTooLow            = errors.New("bid too low")
-	ErrDuplicateBid         = errors.New("duplicate bid")
-	ErrAlreadyHighestBidder = errors.New("you are already the highest bidder")
+	ErrInvalidBidding         = errors.New("invalid bid amount")
+	ErrBidTooLow              = errors.New("bid too low")
+	ErrDuplicateBid           = errors.New("duplicate bid")
+	ErrAlreadyHighestBidder   = errors.New("you are already the highest bidder")
+	ErrOutbidByProxy          = errors.New("bid placed but outbid by an automatic bid")
+	ErrBidNotRecorded         = errors.New("bid placed but could not be recorded")
+	ErrInvalidIncrementLadder = errors.New("each increment tier needs a unique min price and a positive increment or percent")
 	// Final Donation Errors
 	ErrFinalDonationNotFound   = errors.New("final donation not found")
 	ErrFinalDonationNotFoundID = errors.New("final donation ID not found")
@@ -55,3 +86,17 @@ var (
 	ErrUnauthorized = errors.New("unauthorized access")
 	ErrForbidden    = errors.New("forbidden access")
 )
+
+// BidRejectedError wraps a bidding error with the lowest amount the next bid must reach
+type BidRejectedError struct {
+	Err        error
+	MinNextBid float64
+}
+
+func (e *BidRejectedError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *BidRejectedError) Unwrap() error {
+	return e.Err
+}
@@ -43,8 +43,8 @@ func (s *finalDonationService) UpdateNotes(donationID uint, userID uint, notes s
 		return ErrForbidden
 	}
 
-	// Check if status is verified_for_donation
-	if donation.Status != entity.StatusVerifiedForDonation {
+	// Check if status is verified_for_donation, notes can still be added once it is distributed
+	if donation.Status != entity.StatusVerifiedForDonation && donation.Status != entity.StatusDistributed {
 		return ErrDonationNotVerified
 	}
 
@@ -0,0 +1,120 @@
+package service
+
+import (
+	"encoding/json"
+	"log/slog"
+	"milestone3/be/internal/entity"
+	"milestone3/be/internal/repository"
+	"time"
+)
+
+const (
+	OutboxBatchSize   = 50
+	OutboxLease       = time.Minute
+	OutboxMaxAttempts = 10
+	OutboxBaseBackoff = 30 * time.Second
+	OutboxMaxBackoff  = time.Hour
+)
+
+// OutboxHandler performs the downstream action of one event type. An event can be delivered
+// again when a worker dies between the handler and marking it processed, so handlers must be
+// idempotent on the event id.
+type OutboxHandler func(event entity.OutboxEvent) error
+
+type OutboxService interface {
+	ProcessPending() error
+}
+
+type outboxService struct {
+	outboxRepo repository.OutboxRepository
+	handlers   map[string]OutboxHandler
+	logger     *slog.Logger
+}
+
+func NewOutboxService(outboxRepo repository.OutboxRepository, handlers map[string]OutboxHandler, logger *slog.Logger) OutboxService {
+	return &outboxService{
+		outboxRepo: outboxRepo,
+		handlers:   handlers,
+		logger:     logger,
+	}
+}
+
+func newOutboxEvent(eventType string, aggregateID int64, dedupKey string, payload interface{}) (*entity.OutboxEvent, error) {
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity.OutboxEvent{
+		EventType:   eventType,
+		AggregateID: aggregateID,
+		DedupKey:    dedupKey,
+		Payload:     string(raw),
+		Status:      entity.OutboxStatusPending,
+		AvailableAt: time.Now().UTC(),
+	}, nil
+}
+
+// outboxBackoff doubles the wait per attempt, capped at OutboxMaxBackoff
+func outboxBackoff(attempts int) time.Duration {
+	backoff := OutboxBaseBackoff
+	for i := 1; i < attempts && backoff < OutboxMaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > OutboxMaxBackoff {
+		return OutboxMaxBackoff
+	}
+	return backoff
+}
+
+// ProcessPending delivers one batch of due events. A failed handler is retried with backoff
+// until OutboxMaxAttempts, then the event is parked as failed.
+func (s *outboxService) ProcessPending() error {
+	events, err := s.outboxRepo.ClaimPending(OutboxBatchSize, OutboxLease)
+	if err != nil {
+		s.logger.Error("failed to claim outbox events", "error", err)
+		return err
+	}
+
+	processed := 0
+	for _, event := range events {
+		handler, ok := s.handlers[event.EventType]
+		if ok {
+			if err := handler(event); err != nil {
+				s.handleFailure(event, err)
+				continue
+			}
+		} else {
+			s.logger.Debug("no handler for outbox event", "id", event.ID, "type", event.EventType)
+		}
+
+		if err := s.outboxRepo.MarkProcessed(event.ID); err != nil {
+			// the lease runs out and the event comes back, handlers are idempotent
+			s.logger.Error("failed to mark outbox event processed", "id", event.ID, "error", err)
+			continue
+		}
+		processed++
+	}
+
+	if processed > 0 {
+		s.logger.Info("outbox events processed", "count", processed)
+	}
+
+	return nil
+}
+
+func (s *outboxService) handleFailure(event entity.OutboxEvent, handlerErr error) {
+	if event.Attempts >= OutboxMaxAttempts {
+		s.logger.Error("outbox event failed permanently", "id", event.ID, "type", event.EventType, "attempts", event.Attempts, "error", handlerErr)
+		if err := s.outboxRepo.MarkFailed(event.ID, handlerErr.Error()); err != nil {
+			s.logger.Error("failed to mark outbox event failed", "id", event.ID, "error", err)
+		}
+		return
+	}
+
+	retryAt := time.Now().Add(outboxBackoff(event.Attempts))
+	s.logger.Warn("outbox event failed, retrying", "id", event.ID, "type", event.EventType, "attempts", event.Attempts, "retryAt", retryAt, "error", handlerErr)
+	if err := s.outboxRepo.MarkRetry(event.ID, handlerErr.Error(), retryAt); err != nil {
+		s.logger.Error("failed to schedule outbox retry", "id", event.ID, "error", err)
+	}
+}
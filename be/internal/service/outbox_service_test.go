@@ -0,0 +1,133 @@
+package service
+
+import (
+	"errors"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"milestone3/be/internal/entity"
+	"milestone3/be/internal/mocks"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOutboxService_ProcessPending(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOutboxRepo := mocks.NewMockOutboxRepository(ctrl)
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	handlerErr := errors.New("payment gateway down")
+	var handled []int64
+	handlers := map[string]OutboxHandler{
+		entity.OutboxItemWon: func(event entity.OutboxEvent) error {
+			handled = append(handled, event.ID)
+			if event.AggregateID == 99 {
+				return handlerErr
+			}
+			return nil
+		},
+	}
+	outboxService := NewOutboxService(mockOutboxRepo, handlers, logger)
+
+	tests := []struct {
+		name        string
+		events      []entity.OutboxEvent
+		setup       func()
+		wantErr     bool
+		wantHandled []int64
+	}{
+		{
+			name:   "handled event is marked processed",
+			events: []entity.OutboxEvent{{ID: 1, EventType: entity.OutboxItemWon, AggregateID: 1, Attempts: 1}},
+			setup: func() {
+				mockOutboxRepo.EXPECT().MarkProcessed(int64(1)).Return(nil)
+			},
+			wantHandled: []int64{1},
+		},
+		{
+			name:   "event without handler is marked processed",
+			events: []entity.OutboxEvent{{ID: 2, EventType: entity.OutboxItemUnsold, AggregateID: 1, Attempts: 1}},
+			setup: func() {
+				mockOutboxRepo.EXPECT().MarkProcessed(int64(2)).Return(nil)
+			},
+		},
+		{
+			name:   "failed handler is retried with backoff",
+			events: []entity.OutboxEvent{{ID: 3, EventType: entity.OutboxItemWon, AggregateID: 99, Attempts: 2}},
+			setup: func() {
+				mockOutboxRepo.EXPECT().MarkRetry(int64(3), handlerErr.Error(), gomock.Any()).
+					DoAndReturn(func(_ int64, _ string, availableAt time.Time) error {
+						assert.WithinDuration(t, time.Now().Add(2*OutboxBaseBackoff), availableAt, 5*time.Second)
+						return nil
+					})
+			},
+			wantHandled: []int64{3},
+		},
+		{
+			name:   "failed handler on the last attempt is parked",
+			events: []entity.OutboxEvent{{ID: 4, EventType: entity.OutboxItemWon, AggregateID: 99, Attempts: OutboxMaxAttempts}},
+			setup: func() {
+				mockOutboxRepo.EXPECT().MarkFailed(int64(4), handlerErr.Error()).Return(nil)
+			},
+			wantHandled: []int64{4},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handled = nil
+			mockOutboxRepo.EXPECT().ClaimPending(OutboxBatchSize, OutboxLease).Return(tt.events, nil)
+			tt.setup()
+
+			err := outboxService.ProcessPending()
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantHandled, handled)
+		})
+	}
+
+	t.Run("claim error", func(t *testing.T) {
+		mockOutboxRepo.EXPECT().ClaimPending(OutboxBatchSize, OutboxLease).Return(nil, errors.New("db down"))
+
+		err := outboxService.ProcessPending()
+		assert.Error(t, err)
+	})
+}
+
+func TestOutboxBackoff(t *testing.T) {
+	assert.Equal(t, OutboxBaseBackoff, outboxBackoff(1))
+	assert.Equal(t, 4*OutboxBaseBackoff, outboxBackoff(3))
+	assert.Equal(t, OutboxMaxBackoff, outboxBackoff(OutboxMaxAttempts))
+}
+
+func TestPaymentService_OpenWinnerPayment(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockPaymentRepository(ctrl)
+	paymentService := NewPaymentService(mockRepo, nil, 48*time.Hour)
+
+	event, err := newOutboxEvent(entity.OutboxItemWon, 7, "key", entity.AuctionOutcome{SessionID: 1, ItemID: 7, WinnerID: 3, Amount: 150000})
+	assert.NoError(t, err)
+	event.ID = 42
+
+	// a redelivered event opens the same payment, the order id is derived from the event id
+	mockRepo.EXPECT().CreatePending(gomock.Any()).DoAndReturn(func(payment *entity.Payment) error {
+		assert.Equal(t, 3, payment.UserId)
+		assert.Equal(t, 7, payment.AuctionItemId)
+		assert.Equal(t, 150000.0, payment.Amount)
+		assert.Equal(t, "pending", payment.Status)
+		assert.Equal(t, "YDR-W42", payment.OrderId)
+		assert.WithinDuration(t, time.Now().Add(48*time.Hour), *payment.DueAt, time.Minute)
+		return nil
+	}).Times(2)
+
+	assert.NoError(t, paymentService.OpenWinnerPayment(*event))
+	assert.NoError(t, paymentService.OpenWinnerPayment(*event))
+}
@@ -0,0 +1,144 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"milestone3/be/config"
+	"milestone3/be/internal/entity"
+	"milestone3/be/internal/repository"
+	"time"
+)
+
+const PaymentDeadlineBatchSize = 100
+
+type PaymentDeadlineService interface {
+	EnforcePaymentDeadlines() error
+}
+
+type paymentDeadlineService struct {
+	deadlineRepo repository.PaymentDeadlineRepository
+	itemRepo     repository.AuctionItemRepository
+	cfg          config.PaymentDeadlineConfig
+	logger       *slog.Logger
+}
+
+func NewPaymentDeadlineService(deadlineRepo repository.PaymentDeadlineRepository, itemRepo repository.AuctionItemRepository, cfg config.PaymentDeadlineConfig, logger *slog.Logger) PaymentDeadlineService {
+	return &paymentDeadlineService{
+		deadlineRepo: deadlineRepo,
+		itemRepo:     itemRepo,
+		cfg:          cfg,
+		logger:       logger,
+	}
+}
+
+// EnforcePaymentDeadlines defaults winners who did not pay in time. Depending on the policy
+// the next-highest bidder gets a second chance offer or the item is relisted.
+func (s *paymentDeadlineService) EnforcePaymentDeadlines() error {
+	payments, err := s.deadlineRepo.GetOverdue(time.Now(), PaymentDeadlineBatchSize)
+	if err != nil {
+		s.logger.Error("failed to get overdue winner payments", "error", err)
+		return err
+	}
+
+	defaulted := 0
+	for _, payment := range payments {
+		if err := s.defaultWinner(payment); err != nil {
+			continue
+		}
+		defaulted++
+	}
+
+	if defaulted > 0 {
+		s.logger.Info("defaulted winners processed", "count", defaulted)
+	}
+
+	return nil
+}
+
+func (s *paymentDeadlineService) defaultWinner(payment entity.Payment) error {
+	itemID := int64(payment.AuctionItemId)
+	item, err := s.itemRepo.GetByID(itemID)
+	if err != nil {
+		s.logger.Warn("failed to get item of overdue payment", "paymentID", payment.Id, "itemID", itemID, "error", err)
+		return err
+	}
+
+	outcome := entity.AuctionOutcome{
+		ItemID:          itemID,
+		DefaultedUserID: int64(payment.UserId),
+	}
+	if item.SessionID != nil {
+		outcome.SessionID = *item.SessionID
+	}
+
+	next, err := s.nextOffer(itemID, int64(payment.UserId))
+	if err != nil {
+		s.logger.Error("failed to find next bidder", "itemID", itemID, "error", err)
+		return err
+	}
+
+	eventType := entity.OutboxItemRelisted
+	var relistItemID *int64
+	if next != nil {
+		eventType = entity.OutboxSecondChanceOffered
+		outcome.WinnerID = next.UserID
+		outcome.Amount = next.Amount
+	} else {
+		relistItemID = &itemID
+	}
+
+	// one defaulted payment leads to exactly one follow-up
+	event, err := newOutboxEvent(eventType, itemID, fmt.Sprintf("%s:payment:%d", eventType, payment.Id), outcome)
+	if err != nil {
+		return err
+	}
+
+	if err := s.deadlineRepo.Default(int64(payment.Id), relistItemID, event); err != nil {
+		if errors.Is(err, repository.ErrPaymentNotPending) {
+			s.logger.Info("winner paid before the default was recorded", "paymentID", payment.Id)
+		} else {
+			s.logger.Error("failed to default winner", "paymentID", payment.Id, "error", err)
+		}
+		return err
+	}
+
+	if next != nil {
+		s.logger.Info("winner defaulted, second chance offered",
+			"itemID", itemID,
+			"defaultedUser", payment.UserId,
+			"offeredTo", next.UserID,
+			"amount", next.Amount,
+		)
+	} else {
+		s.logger.Info("winner defaulted, item relisted", "itemID", itemID, "defaultedUser", payment.UserId)
+	}
+
+	return nil
+}
+
+// nextOffer picks the bidder for a second chance offer, nil when the item should be relisted
+func (s *paymentDeadlineService) nextOffer(itemID, defaultedUserID int64) (*entity.Bid, error) {
+	if s.cfg.Policy != config.WinnerDefaultSecondChance {
+		return nil, nil
+	}
+
+	defaulted, err := s.deadlineRepo.GetDefaultedUserIDs(itemID)
+	if err != nil {
+		return nil, err
+	}
+
+	exclude := []int64{defaultedUserID}
+	for _, userID := range defaulted {
+		if userID != defaultedUserID {
+			exclude = append(exclude, userID)
+		}
+	}
+
+	// the original winner is not an offer, everyone after them is
+	if len(exclude)-1 >= s.cfg.MaxSecondChances {
+		return nil, nil
+	}
+
+	return s.deadlineRepo.GetNextBidder(itemID, exclude)
+}
@@ -0,0 +1,120 @@
+package service
+
+import (
+	"errors"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"milestone3/be/config"
+	"milestone3/be/internal/entity"
+	"milestone3/be/internal/mocks"
+	"milestone3/be/internal/repository"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPaymentDeadlineService_EnforcePaymentDeadlines(t *testing.T) {
+	sessionID := int64(1)
+	overdue := entity.Payment{Id: 10, UserId: 2, AuctionItemId: 5, Amount: 150000, Status: "pending"}
+	item := &entity.AuctionItem{ID: 5, SessionID: &sessionID, Status: "finished"}
+	itemID := int64(5)
+
+	tests := []struct {
+		name  string
+		cfg   config.PaymentDeadlineConfig
+		setup func(deadlineRepo *mocks.MockPaymentDeadlineRepository)
+	}{
+		{
+			name: "next-highest bidder gets a second chance",
+			cfg:  config.PaymentDeadlineConfig{Policy: config.WinnerDefaultSecondChance, MaxSecondChances: 2},
+			setup: func(deadlineRepo *mocks.MockPaymentDeadlineRepository) {
+				deadlineRepo.EXPECT().GetDefaultedUserIDs(itemID).Return(nil, nil)
+				deadlineRepo.EXPECT().GetNextBidder(itemID, []int64{2}).Return(&entity.Bid{ItemID: 5, UserID: 3, Amount: 140000}, nil)
+				deadlineRepo.EXPECT().Default(int64(10), nil, gomock.Any()).
+					DoAndReturn(func(_ int64, _ *int64, event *entity.OutboxEvent) error {
+						assert.Equal(t, entity.OutboxSecondChanceOffered, event.EventType)
+						assert.JSONEq(t, `{"session_id":1,"auction_item_id":5,"winner_id":3,"amount":140000,"defaulted_user_id":2}`, event.Payload)
+						return nil
+					})
+			},
+		},
+		{
+			name: "relisted once the offers are used up",
+			cfg:  config.PaymentDeadlineConfig{Policy: config.WinnerDefaultSecondChance, MaxSecondChances: 1},
+			setup: func(deadlineRepo *mocks.MockPaymentDeadlineRepository) {
+				// user 7 won first and defaulted, user 2 was the single second chance
+				deadlineRepo.EXPECT().GetDefaultedUserIDs(itemID).Return([]int64{7}, nil)
+				deadlineRepo.EXPECT().Default(int64(10), &itemID, gomock.Any()).
+					DoAndReturn(func(_ int64, _ *int64, event *entity.OutboxEvent) error {
+						assert.Equal(t, entity.OutboxItemRelisted, event.EventType)
+						return nil
+					})
+			},
+		},
+		{
+			name: "relisted when no other bidder is left",
+			cfg:  config.PaymentDeadlineConfig{Policy: config.WinnerDefaultSecondChance, MaxSecondChances: 2},
+			setup: func(deadlineRepo *mocks.MockPaymentDeadlineRepository) {
+				deadlineRepo.EXPECT().GetDefaultedUserIDs(itemID).Return(nil, nil)
+				deadlineRepo.EXPECT().GetNextBidder(itemID, []int64{2}).Return(nil, nil)
+				deadlineRepo.EXPECT().Default(int64(10), &itemID, gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name: "relist policy skips the second chance",
+			cfg:  config.PaymentDeadlineConfig{Policy: config.WinnerDefaultRelist},
+			setup: func(deadlineRepo *mocks.MockPaymentDeadlineRepository) {
+				deadlineRepo.EXPECT().Default(int64(10), &itemID, gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name: "winner paid in between",
+			cfg:  config.PaymentDeadlineConfig{Policy: config.WinnerDefaultRelist},
+			setup: func(deadlineRepo *mocks.MockPaymentDeadlineRepository) {
+				deadlineRepo.EXPECT().Default(int64(10), &itemID, gomock.Any()).Return(repository.ErrPaymentNotPending)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockDeadlineRepo := mocks.NewMockPaymentDeadlineRepository(ctrl)
+			mockItemRepo := mocks.NewMockAuctionItemRepository(ctrl)
+			logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+			deadlineService := NewPaymentDeadlineService(mockDeadlineRepo, mockItemRepo, tt.cfg, logger)
+
+			mockDeadlineRepo.EXPECT().GetOverdue(gomock.Any(), PaymentDeadlineBatchSize).Return([]entity.Payment{overdue}, nil)
+			mockItemRepo.EXPECT().GetByID(itemID).Return(item, nil)
+			tt.setup(mockDeadlineRepo)
+
+			err := deadlineService.EnforcePaymentDeadlines()
+			assert.NoError(t, err)
+		})
+	}
+
+	t.Run("overdue lookup fails", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockDeadlineRepo := mocks.NewMockPaymentDeadlineRepository(ctrl)
+		mockItemRepo := mocks.NewMockAuctionItemRepository(ctrl)
+		logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+		deadlineService := NewPaymentDeadlineService(mockDeadlineRepo, mockItemRepo, config.PaymentDeadlineConfig{}, logger)
+
+		mockDeadlineRepo.EXPECT().GetOverdue(gomock.Any(), PaymentDeadlineBatchSize).
+			DoAndReturn(func(now time.Time, _ int) ([]entity.Payment, error) {
+				assert.WithinDuration(t, time.Now(), now, time.Second)
+				return nil, errors.New("db down")
+			})
+
+		err := deadlineService.EnforcePaymentDeadlines()
+		assert.Error(t, err)
+	})
+}
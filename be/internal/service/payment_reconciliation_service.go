@@ -0,0 +1,161 @@
+package service
+
+import (
+	"errors"
+	"log/slog"
+	"milestone3/be/internal/dto"
+	"milestone3/be/internal/entity"
+	"milestone3/be/internal/repository"
+	"time"
+)
+
+const (
+	ReconciliationBatchSize = 100
+	// attempts younger than this may still be on their way to the gateway
+	ReconciliationGracePeriod = 10 * time.Minute
+	// failed and expired attempts are checked again for this long, the gateway can still settle them
+	ReconciliationLookback = 7 * 24 * time.Hour
+)
+
+type PaymentReconciliationService interface {
+	Reconcile() error
+	Report(from, to time.Time) (dto.ReconciliationReport, error)
+}
+
+type paymentReconciliationService struct {
+	repo    repository.PaymentReconciliationRepository
+	gateway PaymentGateway
+	logger  *slog.Logger
+}
+
+func NewPaymentReconciliationService(repo repository.PaymentReconciliationRepository, gateway PaymentGateway, logger *slog.Logger) PaymentReconciliationService {
+	return &paymentReconciliationService{
+		repo:    repo,
+		gateway: gateway,
+		logger:  logger,
+	}
+}
+
+// Reconcile pages through pending charge attempts, and the failed and expired ones of the lookback
+// window, and asks the gateway about each, so a payment whose notification never arrived does not
+// stay pending forever. settled and failed attempts are applied, an amount differing from ours is
+// only recorded for finance to look at. a settlement of an attempt whose winner payment already
+// closed is recorded as orphaned, the gateway holds money we do not count for anything.
+func (s *paymentReconciliationService) Reconcile() error {
+	now := time.Now()
+	createdBefore := now.Add(-ReconciliationGracePeriod)
+	failedSince := now.Add(-ReconciliationLookback)
+	afterID := 0
+	checked := 0
+
+	for {
+		payments, err := s.repo.GetAttemptsToReconcile(afterID, createdBefore, failedSince, ReconciliationBatchSize)
+		if err != nil {
+			s.logger.Error("failed to get pending payments to reconcile", "error", err)
+			return err
+		}
+
+		for _, payment := range payments {
+			s.reconcile(payment)
+			afterID = payment.Id
+		}
+		checked += len(payments)
+
+		if len(payments) < ReconciliationBatchSize {
+			break
+		}
+	}
+
+	if checked > 0 {
+		s.logger.Info("pending payments reconciled", "count", checked)
+	}
+	return nil
+}
+
+func (s *paymentReconciliationService) reconcile(payment entity.Payment) {
+	paymentID := payment.Id
+	finding := entity.PaymentReconciliation{
+		PaymentID:   &paymentID,
+		OrderID:     payment.OrderId,
+		LocalStatus: payment.Status,
+		LocalAmount: payment.Amount,
+	}
+
+	status, err := s.gateway.Status(payment.OrderId)
+	if errors.Is(err, repository.ErrTransactionNotFound) {
+		// the charge never reached the gateway, failing it lets the winner try again
+		finding.Outcome = entity.ReconciliationMissingAtGateway
+		finding.AppliedStatus = "failed"
+		s.apply(payment, finding)
+		return
+	}
+	if err != nil {
+		s.logger.Warn("failed to get gateway status", "orderID", payment.OrderId, "error", err)
+		return
+	}
+
+	finding.GatewayStatus = status.TransactionStatus
+	finding.GatewayAmount = status.Amount
+
+	if status.Amount != payment.Amount {
+		finding.Outcome = entity.ReconciliationAmountMismatch
+		s.record(finding)
+		return
+	}
+
+	// still pending, or a failed attempt the gateway failed too
+	if status.PaymentStatus == "" || status.PaymentStatus == payment.Status {
+		return
+	}
+
+	// the same way the notification applies it, a settlement of an attempt whose winner payment
+	// closed comes back orphaned, whether the attempt was pending or had failed here
+	applied, err := applyGatewayStatus(s.repo.ApplyStatus, payment, status)
+	if err != nil {
+		s.logger.Warn("failed to apply reconciled status", "orderID", payment.OrderId, "status", status.PaymentStatus, "error", err)
+		return
+	}
+	s.record(applied)
+}
+
+func (s *paymentReconciliationService) apply(payment entity.Payment, finding entity.PaymentReconciliation) {
+	if _, err := s.repo.ApplyStatus(payment.OrderId, finding.AppliedStatus); err != nil {
+		s.logger.Warn("failed to apply reconciled status", "orderID", payment.OrderId, "status", finding.AppliedStatus, "error", err)
+		return
+	}
+	s.record(finding)
+}
+
+func (s *paymentReconciliationService) record(finding entity.PaymentReconciliation) {
+	if err := s.repo.Record(&finding); err != nil {
+		s.logger.Warn("failed to record reconciliation finding", "orderID", finding.OrderID, "outcome", finding.Outcome, "error", err)
+	}
+}
+
+func (s *paymentReconciliationService) Report(from, to time.Time) (dto.ReconciliationReport, error) {
+	if !to.After(from) {
+		return dto.ReconciliationReport{}, ErrInvalidDate
+	}
+
+	findings, err := s.repo.GetFindings(from, to)
+	if err != nil {
+		s.logger.Error("failed to get reconciliation findings", "error", err)
+		return dto.ReconciliationReport{}, err
+	}
+
+	report := dto.ReconciliationReport{From: from, To: to, Findings: findings}
+	for _, finding := range findings {
+		switch finding.Outcome {
+		case entity.ReconciliationMatched:
+			report.Summary.Matched++
+		case entity.ReconciliationAmountMismatch:
+			report.Summary.AmountMismatch++
+		case entity.ReconciliationOrphaned:
+			report.Summary.Orphaned++
+		case entity.ReconciliationMissingAtGateway:
+			report.Summary.MissingAtGateway++
+		}
+	}
+
+	return report, nil
+}
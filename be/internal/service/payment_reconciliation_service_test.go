@@ -0,0 +1,241 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"milestone3/be/internal/dto"
+	"milestone3/be/internal/entity"
+	"milestone3/be/internal/mocks"
+	"milestone3/be/internal/repository"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPaymentReconciliationService_Reconcile(t *testing.T) {
+	tests := []struct {
+		name     string
+		outcome  repository.FakeOutcome
+		charged  float64
+		local    string
+		setup    func(repo *mocks.MockPaymentReconciliationRepository)
+		noCharge bool
+	}{
+		{
+			name:    "settled at the gateway",
+			outcome: repository.FakeOutcome{Statuses: []string{"settlement"}},
+			charged: 150000,
+			setup: func(repo *mocks.MockPaymentReconciliationRepository) {
+				repo.EXPECT().ApplyStatus("YDR-W42", "paid").Return(false, nil)
+				repo.EXPECT().Record(gomock.Any()).DoAndReturn(func(finding *entity.PaymentReconciliation) error {
+					assert.Equal(t, entity.ReconciliationMatched, finding.Outcome)
+					assert.Equal(t, "settlement", finding.GatewayStatus)
+					assert.Equal(t, "paid", finding.AppliedStatus)
+					return nil
+				})
+			},
+		},
+		{
+			name:    "expired at the gateway",
+			outcome: repository.FakeOutcome{Statuses: []string{"expire"}},
+			charged: 150000,
+			setup: func(repo *mocks.MockPaymentReconciliationRepository) {
+				repo.EXPECT().ApplyStatus("YDR-W42", "failed").Return(false, nil)
+				repo.EXPECT().Record(gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name:    "still pending at the gateway",
+			outcome: repository.FakeOutcome{Statuses: []string{"pending"}},
+			charged: 150000,
+			setup:   func(repo *mocks.MockPaymentReconciliationRepository) {},
+		},
+		{
+			name:    "gateway amount differs",
+			outcome: repository.FakeOutcome{Statuses: []string{"settlement"}},
+			charged: 125000,
+			setup: func(repo *mocks.MockPaymentReconciliationRepository) {
+				repo.EXPECT().Record(gomock.Any()).DoAndReturn(func(finding *entity.PaymentReconciliation) error {
+					assert.Equal(t, entity.ReconciliationAmountMismatch, finding.Outcome)
+					assert.Equal(t, 150000.0, finding.LocalAmount)
+					assert.Equal(t, 125000.0, finding.GatewayAmount)
+					assert.Empty(t, finding.AppliedStatus)
+					return nil
+				})
+			},
+		},
+		{
+			name:     "charge never reached the gateway",
+			noCharge: true,
+			setup: func(repo *mocks.MockPaymentReconciliationRepository) {
+				repo.EXPECT().ApplyStatus("YDR-W42", "failed").Return(false, nil)
+				repo.EXPECT().Record(gomock.Any()).DoAndReturn(func(finding *entity.PaymentReconciliation) error {
+					assert.Equal(t, entity.ReconciliationMissingAtGateway, finding.Outcome)
+					return nil
+				})
+			},
+		},
+		{
+			name:    "failed attempt settled late pays the item",
+			outcome: repository.FakeOutcome{Statuses: []string{"settlement"}},
+			charged: 150000,
+			local:   "failed",
+			setup: func(repo *mocks.MockPaymentReconciliationRepository) {
+				repo.EXPECT().ApplyStatus("YDR-W42", "paid").Return(false, nil)
+				repo.EXPECT().Record(gomock.Any()).DoAndReturn(func(finding *entity.PaymentReconciliation) error {
+					assert.Equal(t, entity.ReconciliationMatched, finding.Outcome)
+					assert.Equal(t, "failed", finding.LocalStatus)
+					return nil
+				})
+			},
+		},
+		{
+			name:    "late settlement of an item no longer owed is orphaned",
+			outcome: repository.FakeOutcome{Statuses: []string{"settlement"}},
+			charged: 150000,
+			local:   "failed",
+			setup: func(repo *mocks.MockPaymentReconciliationRepository) {
+				repo.EXPECT().ApplyStatus("YDR-W42", "paid").Return(true, nil)
+				repo.EXPECT().Record(gomock.Any()).DoAndReturn(func(finding *entity.PaymentReconciliation) error {
+					assert.Equal(t, entity.ReconciliationOrphaned, finding.Outcome)
+					assert.Equal(t, "settlement", finding.GatewayStatus)
+					assert.Equal(t, "paid", finding.AppliedStatus)
+					return nil
+				})
+			},
+		},
+		{
+			name:    "pending attempt of a defaulted winner settling is orphaned",
+			outcome: repository.FakeOutcome{Statuses: []string{"settlement"}},
+			charged: 150000,
+			setup: func(repo *mocks.MockPaymentReconciliationRepository) {
+				repo.EXPECT().ApplyStatus("YDR-W42", "paid").Return(true, nil)
+				repo.EXPECT().Record(gomock.Any()).DoAndReturn(func(finding *entity.PaymentReconciliation) error {
+					assert.Equal(t, entity.ReconciliationOrphaned, finding.Outcome)
+					assert.Equal(t, "pending", finding.LocalStatus)
+					return nil
+				})
+			},
+		},
+		{
+			name:    "failed attempt the gateway failed too",
+			outcome: repository.FakeOutcome{Statuses: []string{"expire"}},
+			charged: 150000,
+			local:   "failed",
+			setup:   func(repo *mocks.MockPaymentReconciliationRepository) {},
+		},
+		{
+			name:    "status not applied is not recorded",
+			outcome: repository.FakeOutcome{Statuses: []string{"settlement"}},
+			charged: 150000,
+			setup: func(repo *mocks.MockPaymentReconciliationRepository) {
+				repo.EXPECT().ApplyStatus("YDR-W42", "paid").Return(false, errors.New("db down"))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockPaymentReconciliationRepository(ctrl)
+			gateway := repository.NewFakeGateway("fake-server-key")
+			if !tt.noCharge {
+				gateway.Script(tt.outcome)
+				_, err := gateway.Charge(dto.ChargeRequest{OrderId: "YDR-W42", PaymentMethod: dto.PaymentMethodQris, Amount: tt.charged})
+				assert.NoError(t, err)
+			}
+			logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+			reconciliationService := NewPaymentReconciliationService(mockRepo, gateway, logger)
+
+			local := tt.local
+			if local == "" {
+				local = "pending"
+			}
+			attempt := entity.Payment{Id: 7, UserId: 2, AuctionItemId: 5, OrderId: "YDR-W42", Amount: 150000, Status: local}
+			mockRepo.EXPECT().GetAttemptsToReconcile(0, gomock.Any(), gomock.Any(), ReconciliationBatchSize).
+				DoAndReturn(func(_ int, createdBefore time.Time, failedSince time.Time, _ int) ([]entity.Payment, error) {
+					assert.WithinDuration(t, time.Now().Add(-ReconciliationGracePeriod), createdBefore, time.Second)
+					assert.WithinDuration(t, time.Now().Add(-ReconciliationLookback), failedSince, time.Second)
+					return []entity.Payment{attempt}, nil
+				})
+			tt.setup(mockRepo)
+
+			assert.NoError(t, reconciliationService.Reconcile())
+		})
+	}
+
+	t.Run("pages through full batches", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := mocks.NewMockPaymentReconciliationRepository(ctrl)
+		gateway := repository.NewFakeGateway("fake-server-key")
+		logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+		reconciliationService := NewPaymentReconciliationService(mockRepo, gateway, logger)
+
+		batch := make([]entity.Payment, ReconciliationBatchSize)
+		for i := range batch {
+			gateway.Script(repository.FakeOutcome{Statuses: []string{"pending"}})
+			orderID := fmt.Sprintf("YDR-A%d", i+1)
+			_, err := gateway.Charge(dto.ChargeRequest{OrderId: orderID, PaymentMethod: dto.PaymentMethodQris, Amount: 1000})
+			assert.NoError(t, err)
+			batch[i] = entity.Payment{Id: i + 1, OrderId: orderID, Amount: 1000, Status: "pending"}
+		}
+
+		gomock.InOrder(
+			mockRepo.EXPECT().GetAttemptsToReconcile(0, gomock.Any(), gomock.Any(), ReconciliationBatchSize).Return(batch, nil),
+			mockRepo.EXPECT().GetAttemptsToReconcile(ReconciliationBatchSize, gomock.Any(), gomock.Any(), ReconciliationBatchSize).Return(nil, nil),
+		)
+
+		assert.NoError(t, reconciliationService.Reconcile())
+	})
+
+	t.Run("pending lookup fails", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := mocks.NewMockPaymentReconciliationRepository(ctrl)
+		logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+		reconciliationService := NewPaymentReconciliationService(mockRepo, repository.NewFakeGateway("fake-server-key"), logger)
+
+		mockRepo.EXPECT().GetAttemptsToReconcile(0, gomock.Any(), gomock.Any(), ReconciliationBatchSize).Return(nil, errors.New("db down"))
+
+		assert.Error(t, reconciliationService.Reconcile())
+	})
+}
+
+func TestPaymentReconciliationService_Report(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockPaymentReconciliationRepository(ctrl)
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	reconciliationService := NewPaymentReconciliationService(mockRepo, repository.NewFakeGateway("fake-server-key"), logger)
+
+	from := time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+	findings := []entity.PaymentReconciliation{
+		{OrderID: "YDR-A1", Outcome: entity.ReconciliationMatched},
+		{OrderID: "YDR-A2", Outcome: entity.ReconciliationMatched},
+		{OrderID: "YDR-A3", Outcome: entity.ReconciliationAmountMismatch},
+		{OrderID: "YDR-X9", Outcome: entity.ReconciliationOrphaned},
+		{OrderID: "YDR-A4", Outcome: entity.ReconciliationMissingAtGateway},
+	}
+	mockRepo.EXPECT().GetFindings(from, to).Return(findings, nil)
+
+	report, err := reconciliationService.Report(from, to)
+	assert.NoError(t, err)
+	assert.Equal(t, dto.ReconciliationSummary{Matched: 2, AmountMismatch: 1, Orphaned: 1, MissingAtGateway: 1}, report.Summary)
+	assert.Len(t, report.Findings, 5)
+
+	_, err = reconciliationService.Report(to, from)
+	assert.ErrorIs(t, err, ErrInvalidDate)
+}
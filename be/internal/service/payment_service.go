@@ -1,76 +1,358 @@
 package service
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"milestone3/be/internal/dto"
 	"milestone3/be/internal/entity"
+	"milestone3/be/internal/repository"
+	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 type PaymentRepository interface {
-	Create(payment *entity.Payment, orderId string) (error)
-	CreateMidtrans(payment entity.Payment, orderId string) (res dto.PaymentResponse, err error)
-	CheckPaymentStatusMidtrans(orderId string) (res dto.CheckPaymentStatusResponse, err error)
+	CreatePending(payment *entity.Payment) (error)
+	CreateAttempt(payment *entity.Payment, winnerPaymentId int) (error)
+	ApplyStatus(orderId string, status string) (orphaned bool, err error)
+	BeginRefund(refund *entity.PaymentRefund) (payment entity.Payment, err error)
+	CompleteRefund(refund *entity.PaymentRefund, auctionItemId int, gatewayStatus string) (error)
+	FailRefund(refund entity.PaymentRefund, reason string) (error)
+	GetRefunds(paymentId int) (refunds []entity.PaymentRefund, err error)
 	GetById(id int) (payment entity.Payment, err error)
+	GetByOrderId(orderId string) (payment entity.Payment, err error)
 	GetAll() (payment []entity.Payment, err error)
+	RecordOrphan(finding *entity.PaymentReconciliation) (error)
 
-	//getting amount from bid
-	GetBidByAuctionId(auctionItemId int) (bid entity.Bid, err error)
+	// the payer and the amount come from the open winner payment, never from the client
+	GetAuctionItem(auctionItemId int) (item entity.AuctionItem, err error)
+	GetOpenWinnerPayment(auctionItemId int) (payment entity.Payment, err error)
+}
+
+// PaymentGateway charges and refunds the payments, midtrans in production and a scripted fake offline
+type PaymentGateway interface {
+	Charge(req dto.ChargeRequest) (res dto.PaymentResponse, err error)
+	Status(orderId string) (res dto.GatewayStatus, err error)
+	Refund(req dto.RefundRequest) (res dto.RefundResponse, err error)
+	// ParseNotification verifies and decodes a notification the gateway posts to us
+	ParseNotification(body []byte) (res dto.GatewayStatus, err error)
 }
 
 type PaymentServ struct {
 	paymentRepo PaymentRepository
+	gateway PaymentGateway
+	// how long a winner has to pay before the deadline job defaults them
+	winnerPaymentDeadline time.Duration
 }
 
-func NewPaymentService(pr PaymentRepository) *PaymentServ {
-	return &PaymentServ{paymentRepo: pr}
+func NewPaymentService(pr PaymentRepository, gateway PaymentGateway, winnerPaymentDeadline time.Duration) *PaymentServ {
+	return &PaymentServ{paymentRepo: pr, gateway: gateway, winnerPaymentDeadline: winnerPaymentDeadline}
 }
 
+// CreatePayment charges the winner of a finished item. the amount and the payer are the ones of the
+// open winner payment, opened from the finalized winning bid or from a second chance offer
 func (ps *PaymentServ) CreatePayment(req dto.PaymentRequest, userId int, auctionItemId int) (res dto.PaymentResponse, err error) {
-	//WIP
-	//get amount from bid
-	bid, err := ps.paymentRepo.GetBidByAuctionId(auctionItemId)
+	item, err := ps.paymentRepo.GetAuctionItem(auctionItemId)
 	if err != nil {
-		log.Printf("error getting bid by auction id %s", err)
+		log.Printf("error getting auction item %d %s", auctionItemId, err)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return dto.PaymentResponse{}, ErrAuctionNotFoundID
+		}
 		return dto.PaymentResponse{}, err
 	}
-	
-	if req.Amount < bid.Amount {
-		return dto.PaymentResponse{}, fmt.Errorf("amount req is less than amount from bid")
+
+	switch item.Status {
+	case "finished":
+	case "sold":
+		return dto.PaymentResponse{}, ErrItemAlreadyPaid
+	default:
+		return dto.PaymentResponse{}, ErrItemNotFinished
+	}
+
+	winnerPayment, err := ps.paymentRepo.GetOpenWinnerPayment(auctionItemId)
+	if err != nil {
+		log.Printf("error getting winner payment of item %d %s", auctionItemId, err)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return dto.PaymentResponse{}, ErrWinnerPaymentNotOpen
+		}
+		return dto.PaymentResponse{}, err
+	}
+
+	if winnerPayment.UserId != userId {
+		return dto.PaymentResponse{}, ErrNotAuctionWinner
+	}
+
+	method := req.PaymentMethod
+	if method == "" {
+		method = dto.PaymentMethodQris
 	}
-	//get auction for auctionItemId to check if auctionitemid exist or not
+
 	//random id for order id
 	uuid := uuid.New()
-	orderId := fmt.Sprintf("YDR-%d", uuid.ID())
-	
 	payment := entity.Payment{
-		Amount: req.Amount,
+		Amount: winnerPayment.Amount,
 		UserId: userId,
-		//hard code for now 
 		AuctionItemId: auctionItemId,
+		OrderId: fmt.Sprintf("YDR-%d", uuid.ID()),
+		PaymentMethod: method,
 	}
 
-	
-	log.Println("disini nih")
-	resp, _ := ps.paymentRepo.CreateMidtrans(payment, orderId)
-	
-	if err := ps.paymentRepo.Create(&payment, resp.OrderId); err != nil {
+	if err := ps.paymentRepo.CreateAttempt(&payment, winnerPayment.Id); err != nil {
 		log.Printf("error create payment %s", err)
+		if errors.Is(err, repository.ErrDuplicatePayment) {
+			return dto.PaymentResponse{}, ErrDuplicatePayment
+		}
+		return dto.PaymentResponse{}, err
+	}
+
+	resp, err := ps.gateway.Charge(dto.ChargeRequest{
+		OrderId: payment.OrderId,
+		PaymentMethod: method,
+		Amount: payment.Amount,
+		CustomerName: winnerPayment.User.Name,
+		CustomerEmail: winnerPayment.User.Email,
+	})
+	if err != nil {
+		log.Printf("error charge payment %s", err)
+		// the attempt never reached the gateway, the winner can try again
+		if _, err := ps.paymentRepo.ApplyStatus(payment.OrderId, "failed"); err != nil {
+			log.Printf("error fail payment %s %s", payment.OrderId, err)
+		}
 		return dto.PaymentResponse{}, err
 	}
+
 	return resp, nil
 }
 
-func (ps *PaymentServ) CheckPaymentStatusMidtrans(orderId string) (res dto.CheckPaymentStatusResponse, err error) {
-	resp, _:= ps.paymentRepo.CheckPaymentStatusMidtrans(orderId)
-	// if err != nil {
-	// 	log.Printf("error check payment %s", err)
-	// 	return dto.CheckPaymentStatusResponse{}, err
-	// }
+// OpenWinnerPayment handles the item won and second chance outbox events by opening a pending
+// payment with a deadline for the winner. the order id comes from the event id so a redelivered
+// event finds the same payment
+func (ps *PaymentServ) OpenWinnerPayment(event entity.OutboxEvent) error {
+	var outcome entity.AuctionOutcome
+	if err := json.Unmarshal([]byte(event.Payload), &outcome); err != nil {
+		log.Printf("error decoding item won event %d %s", event.ID, err)
+		return err
+	}
 
-	return resp, nil
+	dueAt := time.Now().UTC().Add(ps.winnerPaymentDeadline)
+	payment := entity.Payment{
+		UserId: int(outcome.WinnerID),
+		AuctionItemId: int(outcome.ItemID),
+		Amount: outcome.Amount,
+		Status: "pending",
+		OrderId: fmt.Sprintf("YDR-W%d", event.ID),
+		DueAt: &dueAt,
+	}
+
+	if err := ps.paymentRepo.CreatePending(&payment); err != nil {
+		log.Printf("error open winner payment %s", err)
+		return err
+	}
+	return nil
+}
+
+func (ps *PaymentServ) CheckPaymentStatus(orderId string) (res dto.CheckPaymentStatusResponse, err error) {
+	resp, err := ps.gateway.Status(orderId)
+	if err != nil {
+		log.Printf("error check payment %s", err)
+		return dto.CheckPaymentStatusResponse{}, err
+	}
+
+	if resp.PaymentStatus != "" {
+		payment, err := ps.paymentRepo.GetByOrderId(orderId)
+		if err != nil {
+			log.Printf("error get payment %s %s", orderId, err)
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return dto.CheckPaymentStatusResponse{}, ErrPaymentNotFound
+			}
+			return dto.CheckPaymentStatusResponse{}, err
+		}
+		if err := ps.applyGatewayStatus(payment, resp); err != nil {
+			log.Printf("error apply payment status %s", err)
+			return dto.CheckPaymentStatusResponse{}, err
+		}
+	}
+
+	res = dto.CheckPaymentStatusResponse{
+		OrderId: resp.OrderId,
+		TransactionId: resp.TransactionId,
+		PaymentStatus: resp.TransactionStatus,
+		FraudStatus: resp.FraudStatus,
+	}
+	return res, nil
+}
+
+// HandleNotification applies a notification posted by the gateway. the gateway verifies it came
+// from them, applying the same notification twice leaves the payment unchanged
+func (ps *PaymentServ) HandleNotification(body []byte) error {
+	notif, err := ps.gateway.ParseNotification(body)
+	if err != nil {
+		log.Printf("rejected payment notification %s", err)
+		if err == repository.ErrInvalidNotificationSignature {
+			return ErrInvalidSignature
+		}
+		return ErrInvalidNotification
+	}
+
+	payment, err := ps.paymentRepo.GetByOrderId(notif.OrderId)
+	if err != nil {
+		log.Printf("notification for unknown order %s %s", notif.OrderId, err)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			// money the gateway holds for an order we never created, finance sees it in the reconciliation report
+			if err := ps.paymentRepo.RecordOrphan(&entity.PaymentReconciliation{
+				OrderID: notif.OrderId,
+				Outcome: entity.ReconciliationOrphaned,
+				GatewayStatus: notif.TransactionStatus,
+				GatewayAmount: notif.Amount,
+			}); err != nil {
+				log.Printf("error record orphaned order %s %s", notif.OrderId, err)
+			}
+		}
+		return ErrPaymentNotFound
+	}
+
+	if notif.Amount != payment.Amount {
+		log.Printf("notification amount %.2f does not match payment %d", notif.Amount, payment.Id)
+		return ErrPaymentAmountMismatch
+	}
+
+	if notif.PaymentStatus == "" {
+		return nil
+	}
+
+	if err := ps.applyGatewayStatus(payment, notif); err != nil {
+		log.Printf("error apply notification for order %s %s", notif.OrderId, err)
+		return err
+	}
+	return nil
+}
+
+// applyGatewayStatus applies what the gateway reported for the payment, a settlement the item
+// cannot take anymore goes to the reconciliation report as orphaned for finance to refund
+func (ps *PaymentServ) applyGatewayStatus(payment entity.Payment, status dto.GatewayStatus) error {
+	finding, err := applyGatewayStatus(ps.paymentRepo.ApplyStatus, payment, status)
+	if err != nil {
+		return err
+	}
+	if finding.Outcome != entity.ReconciliationOrphaned {
+		return nil
+	}
+
+	log.Printf("payment %s settled after its winner payment closed", payment.OrderId)
+	if err := ps.paymentRepo.RecordOrphan(&finding); err != nil {
+		log.Printf("error record orphaned payment %s %s", payment.OrderId, err)
+	}
+	return nil
+}
+
+// applyGatewayStatus is how the notification, the status check and the reconciliation apply a
+// gateway status, so they cannot disagree about it. the finding tells the outcome: matched, or
+// orphaned for a settlement of an attempt whose winner payment already closed, which is kept paid
+// but sells nothing. apply is the ApplyStatus of the payment or the reconciliation repository
+func applyGatewayStatus(apply func(orderId string, status string) (orphaned bool, err error), payment entity.Payment, status dto.GatewayStatus) (entity.PaymentReconciliation, error) {
+	paymentID := payment.Id
+	finding := entity.PaymentReconciliation{
+		PaymentID:     &paymentID,
+		OrderID:       payment.OrderId,
+		Outcome:       entity.ReconciliationMatched,
+		LocalStatus:   payment.Status,
+		LocalAmount:   payment.Amount,
+		GatewayStatus: status.TransactionStatus,
+		GatewayAmount: status.Amount,
+		AppliedStatus: status.PaymentStatus,
+	}
+
+	orphaned, err := apply(payment.OrderId, status.PaymentStatus)
+	if err != nil {
+		return entity.PaymentReconciliation{}, err
+	}
+	if orphaned {
+		finding.Outcome = entity.ReconciliationOrphaned
+	}
+	return finding, nil
+}
+
+// RefundPayment refunds a paid payment in full or in part through the gateway. the payment is
+// refund_pending while the gateway works on it and refunded or partially_refunded after, a refused
+// refund puts it back
+func (ps *PaymentServ) RefundPayment(paymentId int, actorId int, req dto.PaymentRefundRequest) (res dto.PaymentRefundResponse, err error) {
+	refund := entity.PaymentRefund{
+		PaymentId: paymentId,
+		Amount: req.Amount,
+		Reason: req.Reason,
+		ActorId: actorId,
+		ReopenItem: req.ReopenItem,
+	}
+
+	payment, err := ps.paymentRepo.BeginRefund(&refund)
+	if err != nil {
+		log.Printf("error begin refund of payment %d %s", paymentId, err)
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			return dto.PaymentRefundResponse{}, ErrPaymentNotFound
+		case errors.Is(err, repository.ErrPaymentNotRefundable):
+			return dto.PaymentRefundResponse{}, ErrPaymentNotRefundable
+		case errors.Is(err, repository.ErrRefundExceedsPayment):
+			return dto.PaymentRefundResponse{}, ErrRefundExceedsPayment
+		}
+		return dto.PaymentRefundResponse{}, err
+	}
+
+	resp, err := ps.gateway.Refund(dto.RefundRequest{
+		OrderId: payment.OrderId,
+		RefundKey: fmt.Sprintf("YDR-R%d", refund.Id),
+		Amount: refund.Amount,
+		Reason: refund.Reason,
+	})
+	if err != nil {
+		log.Printf("gateway refused refund %d of payment %d %s", refund.Id, paymentId, err)
+		if err := ps.paymentRepo.FailRefund(refund, err.Error()); err != nil {
+			log.Printf("error fail refund %d %s", refund.Id, err)
+			return dto.PaymentRefundResponse{}, err
+		}
+		return dto.PaymentRefundResponse{}, ErrRefundRejected
+	}
+
+	if err := ps.paymentRepo.CompleteRefund(&refund, payment.AuctionItemId, resp.Status); err != nil {
+		// the gateway refunded already, the refund stays pending until it is recorded
+		log.Printf("error complete refund %d of payment %d %s", refund.Id, paymentId, err)
+		return dto.PaymentRefundResponse{}, err
+	}
+
+	return paymentRefundResponse(refund), nil
+}
+
+func (ps *PaymentServ) GetRefunds(paymentId int) (res []dto.PaymentRefundResponse, err error) {
+	refunds, err := ps.paymentRepo.GetRefunds(paymentId)
+	if err != nil {
+		log.Printf("failed get refunds of payment %d %s", paymentId, err)
+		return []dto.PaymentRefundResponse{}, err
+	}
+
+	res = []dto.PaymentRefundResponse{}
+	for _, refund := range refunds {
+		res = append(res, paymentRefundResponse(refund))
+	}
+	return res, nil
+}
+
+func paymentRefundResponse(refund entity.PaymentRefund) dto.PaymentRefundResponse {
+	return dto.PaymentRefundResponse{
+		Id: refund.Id,
+		PaymentId: refund.PaymentId,
+		Amount: refund.Amount,
+		Reason: refund.Reason,
+		ActorId: refund.ActorId,
+		Status: refund.Status,
+		GatewayStatus: refund.GatewayStatus,
+		LastError: refund.LastError,
+		ReopenItem: refund.ReopenItem,
+		CreatedAt: refund.CreatedAt,
+		RefundedAt: refund.RefundedAt,
+	}
 }
 
 func (ps *PaymentServ) GetPaymentById(id int) (res dto.PaymentInfoResponse, err error) {
@@ -88,6 +370,7 @@ func (ps *PaymentServ) GetPaymentById(id int) (res dto.PaymentInfoResponse, err
 		Status: resp.Status,
 		// PaymentStatus: resp.PaymentStatus,
 		Amount: resp.Amount,
+		PaymentMethod: resp.PaymentMethod,
 	}
 
 	return res, nil
@@ -109,6 +392,7 @@ func (ps *PaymentServ) GetAllPayment() (res []dto.PaymentInfoResponse, err error
 		Status: payment.Status,
 		// PaymentStatus: payment.PaymentStatus,
 		Amount: payment.Amount,
+		PaymentMethod: payment.PaymentMethod,
 		})
 	}
 
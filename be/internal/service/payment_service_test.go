@@ -1,93 +1,161 @@
 package service
 
-// import (
-// 	"errors"
-// 	"testing"
-
-// 	"milestone3/be/internal/dto"
-// 	"milestone3/be/internal/entity"
-// 	"milestone3/be/internal/mocks"
-
-// 	"github.com/golang/mock/gomock"
-// 	"github.com/stretchr/testify/assert"
-// )
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"milestone3/be/internal/dto"
+	"milestone3/be/internal/entity"
+	"milestone3/be/internal/mocks"
+	"milestone3/be/internal/repository"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestPaymentService_CreatePayment(t *testing.T) {
+	finished := entity.AuctionItem{ID: 7, Status: "finished"}
+	winnerPayment := entity.Payment{Id: 40, UserId: 3, AuctionItemId: 7, Amount: 150000, Status: "pending", OrderId: "YDR-W42"}
+
+	tests := []struct {
+		name     string
+		userId   int
+		setup    func(repo *mocks.MockPaymentRepository, gateway *repository.FakeGateway)
+		wantErr  error
+		wantPaid float64
+	}{
+		{
+			name:   "winner pays the winning bid",
+			userId: 3,
+			setup: func(repo *mocks.MockPaymentRepository, gateway *repository.FakeGateway) {
+				repo.EXPECT().GetAuctionItem(7).Return(finished, nil)
+				repo.EXPECT().GetOpenWinnerPayment(7).Return(winnerPayment, nil)
+				repo.EXPECT().CreateAttempt(gomock.Any(), 40).DoAndReturn(func(payment *entity.Payment, winnerPaymentId int) error {
+					assert.Equal(t, 3, payment.UserId)
+					assert.Equal(t, dto.PaymentMethodQris, payment.PaymentMethod)
+					return nil
+				})
+			},
+			wantPaid: 150000,
+		},
+		{
+			name:   "second chance bidder pays their own bid",
+			userId: 8,
+			setup: func(repo *mocks.MockPaymentRepository, gateway *repository.FakeGateway) {
+				repo.EXPECT().GetAuctionItem(7).Return(finished, nil)
+				repo.EXPECT().GetOpenWinnerPayment(7).Return(entity.Payment{Id: 41, UserId: 8, AuctionItemId: 7, Amount: 120000, Status: "pending"}, nil)
+				repo.EXPECT().CreateAttempt(gomock.Any(), 41).Return(nil)
+			},
+			wantPaid: 120000,
+		},
+		{
+			name:   "unknown item",
+			userId: 3,
+			setup: func(repo *mocks.MockPaymentRepository, gateway *repository.FakeGateway) {
+				repo.EXPECT().GetAuctionItem(7).Return(entity.AuctionItem{}, gorm.ErrRecordNotFound)
+			},
+			wantErr: ErrAuctionNotFoundID,
+		},
+		{
+			name:   "item still ongoing",
+			userId: 3,
+			setup: func(repo *mocks.MockPaymentRepository, gateway *repository.FakeGateway) {
+				repo.EXPECT().GetAuctionItem(7).Return(entity.AuctionItem{ID: 7, Status: "ongoing"}, nil)
+			},
+			wantErr: ErrItemNotFinished,
+		},
+		{
+			name:   "item closed unsold",
+			userId: 3,
+			setup: func(repo *mocks.MockPaymentRepository, gateway *repository.FakeGateway) {
+				repo.EXPECT().GetAuctionItem(7).Return(entity.AuctionItem{ID: 7, Status: "unsold"}, nil)
+			},
+			wantErr: ErrItemNotFinished,
+		},
+		{
+			name:   "item already paid",
+			userId: 3,
+			setup: func(repo *mocks.MockPaymentRepository, gateway *repository.FakeGateway) {
+				repo.EXPECT().GetAuctionItem(7).Return(entity.AuctionItem{ID: 7, Status: "sold"}, nil)
+			},
+			wantErr: ErrItemAlreadyPaid,
+		},
+		{
+			name:   "winner payment not opened yet",
+			userId: 3,
+			setup: func(repo *mocks.MockPaymentRepository, gateway *repository.FakeGateway) {
+				repo.EXPECT().GetAuctionItem(7).Return(finished, nil)
+				repo.EXPECT().GetOpenWinnerPayment(7).Return(entity.Payment{}, gorm.ErrRecordNotFound)
+			},
+			wantErr: ErrWinnerPaymentNotOpen,
+		},
+		{
+			name:   "caller is not the winner",
+			userId: 5,
+			setup: func(repo *mocks.MockPaymentRepository, gateway *repository.FakeGateway) {
+				repo.EXPECT().GetAuctionItem(7).Return(finished, nil)
+				repo.EXPECT().GetOpenWinnerPayment(7).Return(winnerPayment, nil)
+			},
+			wantErr: ErrNotAuctionWinner,
+		},
+		{
+			name:   "payment already in progress",
+			userId: 3,
+			setup: func(repo *mocks.MockPaymentRepository, gateway *repository.FakeGateway) {
+				repo.EXPECT().GetAuctionItem(7).Return(finished, nil)
+				repo.EXPECT().GetOpenWinnerPayment(7).Return(winnerPayment, nil)
+				repo.EXPECT().CreateAttempt(gomock.Any(), 40).Return(repository.ErrDuplicatePayment)
+			},
+			wantErr: ErrDuplicatePayment,
+		},
+		{
+			name:   "gateway refuses the charge",
+			userId: 3,
+			setup: func(repo *mocks.MockPaymentRepository, gateway *repository.FakeGateway) {
+				gateway.Script(repository.FakeOutcome{ChargeErr: errors.New("gateway unavailable")})
+				repo.EXPECT().GetAuctionItem(7).Return(finished, nil)
+				repo.EXPECT().GetOpenWinnerPayment(7).Return(winnerPayment, nil)
+				repo.EXPECT().CreateAttempt(gomock.Any(), 40).Return(nil)
+				repo.EXPECT().ApplyStatus(gomock.Any(), "failed").Return(false, nil)
+			},
+			wantErr: errors.New("gateway unavailable"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockPaymentRepository(ctrl)
+			gateway := repository.NewFakeGateway("key")
+			paymentService := NewPaymentService(mockRepo, gateway, 48*time.Hour)
+			tt.setup(mockRepo, gateway)
+
+			res, err := paymentService.CreatePayment(dto.PaymentRequest{}, tt.userId, 7)
+			if tt.wantErr != nil {
+				assert.EqualError(t, err, tt.wantErr.Error())
+				return
+			}
+
+			assert.NoError(t, err)
+			status, err := gateway.Status(res.OrderId)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantPaid, status.Amount)
+		})
+	}
+}
 
 // add method on interface and the whole mock is generated so i intentionally comment this test (rafly) //
 
-// func TestPaymentService_CreatePayment(t *testing.T) {
-// 	ctrl := gomock.NewController(t)
-// 	defer ctrl.Finish()
-
-// 	mockRepo := mocks.NewMockPaymentRepository(ctrl)
-// 	paymentService := NewPaymentService(mockRepo)
-
-// 	tests := []struct {
-// 		name          string
-// 		req           dto.PaymentRequest
-// 		userId        int
-// 		auctionItemId int
-// 		setup         func()
-// 		wantErr       bool
-// 	}{
-// 		{
-// 			name: "successful payment creation",
-// 			req: dto.PaymentRequest{
-// 				Amount: 100000,
-// 			},
-// 			userId:        1,
-// 			auctionItemId: 1,
-// 			setup: func() {
-// 				mockRepo.EXPECT().CreateMidtrans(gomock.Any(), gomock.Any()).Return(dto.PaymentResponse{
-// 					OrderId:        "YDR-123",
-// 					TransactionId:  "TXN-123",
-// 					PaymentLinkUrl: "https://payment.link",
-// 				}, nil)
-// 				mockRepo.EXPECT().Create(gomock.Any(), "YDR-123").Return(nil)
-// 			},
-// 			wantErr: false,
-// 		},
-// 		{
-// 			name: "repository create error",
-// 			req: dto.PaymentRequest{
-// 				Amount: 100000,
-// 			},
-// 			userId:        1,
-// 			auctionItemId: 1,
-// 			setup: func() {
-// 				mockRepo.EXPECT().CreateMidtrans(gomock.Any(), gomock.Any()).Return(dto.PaymentResponse{
-// 					OrderId:        "YDR-123",
-// 					TransactionId:  "TXN-123",
-// 					PaymentLinkUrl: "https://payment.link",
-// 				}, nil)
-// 				mockRepo.EXPECT().Create(gomock.Any(), "YDR-123").Return(errors.New("db error"))
-// 			},
-// 			wantErr: true,
-// 		},
-// 	}
-
-// 	for _, tt := range tests {
-// 		t.Run(tt.name, func(t *testing.T) {
-// 			tt.setup()
-			
-// 			result, err := paymentService.CreatePayment(tt.req, tt.userId, tt.auctionItemId)
-			
-// 			if tt.wantErr {
-// 				assert.Error(t, err)
-// 			} else {
-// 				assert.NoError(t, err)
-// 				assert.Equal(t, "YDR-123", result.OrderId)
-// 				assert.Equal(t, "TXN-123", result.TransactionId)
-// 			}
-// 		})
-// 	}
-// }
-
 // func TestPaymentService_GetPaymentById(t *testing.T) {
 // 	ctrl := gomock.NewController(t)
 // 	defer ctrl.Finish()
 
 // 	mockRepo := mocks.NewMockPaymentRepository(ctrl)
-// 	paymentService := NewPaymentService(mockRepo)
+// 	paymentService := NewPaymentService(mockRepo, nil, 48*time.Hour)
 
 // 	tests := []struct {
 // 		name    string
@@ -122,9 +190,9 @@ package service
 // 	for _, tt := range tests {
 // 		t.Run(tt.name, func(t *testing.T) {
 // 			tt.setup()
-			
+
 // 			result, err := paymentService.GetPaymentById(tt.id)
-			
+
 // 			if tt.wantErr {
 // 				assert.Error(t, err)
 // 				assert.Empty(t, result)
@@ -142,7 +210,7 @@ package service
 // 	defer ctrl.Finish()
 
 // 	mockRepo := mocks.NewMockPaymentRepository(ctrl)
-// 	paymentService := NewPaymentService(mockRepo)
+// 	paymentService := NewPaymentService(mockRepo, nil, 48*time.Hour)
 
 // 	tests := []struct {
 // 		name    string
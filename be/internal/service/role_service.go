@@ -0,0 +1,77 @@
+package service
+
+import (
+	"errors"
+	"log"
+
+	"milestone3/be/internal/dto"
+	"milestone3/be/internal/entity"
+	"milestone3/be/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+type RoleRepository interface {
+	ListRoles() (roles []entity.Role, err error)
+	AssignRole(userID int, role string) error
+}
+
+// LiveAccessTokenRepository finds the access tokens of a user that have not expired yet
+type LiveAccessTokenRepository interface {
+	ListLiveAccessTokens(userID int) (tokens []entity.RefreshToken, err error)
+}
+
+type RoleServ struct {
+	roleRepo  RoleRepository
+	tokenRepo LiveAccessTokenRepository
+	denyList  TokenDenyList
+}
+
+func NewRoleService(rr RoleRepository, tr LiveAccessTokenRepository, dl TokenDenyList) *RoleServ {
+	return &RoleServ{roleRepo: rr, tokenRepo: tr, denyList: dl}
+}
+
+func (rs *RoleServ) ListRoles() (res []dto.RoleResponse, err error) {
+	roles, err := rs.roleRepo.ListRoles()
+	if err != nil {
+		log.Println("failed list roles")
+		return nil, err
+	}
+
+	res = make([]dto.RoleResponse, 0, len(roles))
+	for _, r := range roles {
+		permissions := make([]string, 0, len(r.Permissions))
+		for _, p := range r.Permissions {
+			permissions = append(permissions, p.Name)
+		}
+		res = append(res, dto.RoleResponse{Name: r.Name, Description: r.Description, Permissions: permissions})
+	}
+	return res, nil
+}
+
+// AssignRole gives the user role. the access tokens the user holds carry the permissions of the old
+// role, so they are denied and the next refresh issues one with the new permissions. nobody changes
+// their own role, an admin cannot lock themselves out
+func (rs *RoleServ) AssignRole(actorID int, userID int, role string) error {
+	if actorID == userID {
+		return ErrCannotChangeOwnRole
+	}
+
+	if err := rs.roleRepo.AssignRole(userID, role); err != nil {
+		if errors.Is(err, repository.ErrRoleNotFound) {
+			return ErrRoleNotFound
+		}
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrUserNotFound
+		}
+		log.Println("failed assign role")
+		return err
+	}
+
+	tokens, err := rs.tokenRepo.ListLiveAccessTokens(userID)
+	if err != nil {
+		log.Println("failed list live access tokens")
+		return err
+	}
+	return denyAccessTokens(rs.denyList, tokens)
+}
@@ -0,0 +1,117 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"milestone3/be/internal/dto"
+	"milestone3/be/internal/entity"
+	"milestone3/be/internal/mocks"
+	"milestone3/be/internal/repository"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestRoleService_ListRoles(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRoleRepo := mocks.NewMockRoleRepository(ctrl)
+	roleService := NewRoleService(mockRoleRepo, mocks.NewMockLiveAccessTokenRepository(ctrl), mocks.NewMockTokenDenyList(ctrl))
+
+	t.Run("maps permissions to names", func(t *testing.T) {
+		mockRoleRepo.EXPECT().ListRoles().Return([]entity.Role{
+			{Name: entity.RoleUser, Description: "Donor and bidder"},
+			{Name: entity.RoleVerifier, Description: "Checks donations", Permissions: []entity.Permission{
+				{Name: entity.PermDonationReadAll}, {Name: entity.PermDonationVerify},
+			}},
+		}, nil)
+
+		res, err := roleService.ListRoles()
+		assert.NoError(t, err)
+		assert.Equal(t, []dto.RoleResponse{
+			{Name: entity.RoleUser, Description: "Donor and bidder", Permissions: []string{}},
+			{Name: entity.RoleVerifier, Description: "Checks donations", Permissions: []string{entity.PermDonationReadAll, entity.PermDonationVerify}},
+		}, res)
+	})
+
+	t.Run("repository error", func(t *testing.T) {
+		mockRoleRepo.EXPECT().ListRoles().Return(nil, errors.New("db error"))
+
+		_, err := roleService.ListRoles()
+		assert.Error(t, err)
+	})
+}
+
+func TestRoleService_AssignRole(t *testing.T) {
+	live := []entity.RefreshToken{
+		{ID: 3, UserID: 2, AccessJTI: "jti-live", AccessExpiresAt: time.Now().Add(10 * time.Minute)},
+	}
+
+	tests := []struct {
+		name    string
+		actorID int
+		role    string
+		setup   func(roles *mocks.MockRoleRepository, tokens *mocks.MockLiveAccessTokenRepository, denyList *mocks.MockTokenDenyList)
+		wantErr error
+	}{
+		{
+			name:    "assigns the role and denies the live access tokens",
+			actorID: 1,
+			role:    entity.RoleVerifier,
+			setup: func(roles *mocks.MockRoleRepository, tokens *mocks.MockLiveAccessTokenRepository, denyList *mocks.MockTokenDenyList) {
+				roles.EXPECT().AssignRole(2, entity.RoleVerifier).Return(nil)
+				tokens.EXPECT().ListLiveAccessTokens(2).Return(live, nil)
+				denyList.EXPECT().Deny("jti-live", gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name:    "own role",
+			actorID: 2,
+			role:    entity.RoleUser,
+			setup: func(roles *mocks.MockRoleRepository, tokens *mocks.MockLiveAccessTokenRepository, denyList *mocks.MockTokenDenyList) {
+			},
+			wantErr: ErrCannotChangeOwnRole,
+		},
+		{
+			name:    "unknown role",
+			actorID: 1,
+			role:    "superuser",
+			setup: func(roles *mocks.MockRoleRepository, tokens *mocks.MockLiveAccessTokenRepository, denyList *mocks.MockTokenDenyList) {
+				roles.EXPECT().AssignRole(2, "superuser").Return(repository.ErrRoleNotFound)
+			},
+			wantErr: ErrRoleNotFound,
+		},
+		{
+			name:    "unknown user",
+			actorID: 1,
+			role:    entity.RoleInstitution,
+			setup: func(roles *mocks.MockRoleRepository, tokens *mocks.MockLiveAccessTokenRepository, denyList *mocks.MockTokenDenyList) {
+				roles.EXPECT().AssignRole(2, entity.RoleInstitution).Return(gorm.ErrRecordNotFound)
+			},
+			wantErr: ErrUserNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			roles := mocks.NewMockRoleRepository(ctrl)
+			tokens := mocks.NewMockLiveAccessTokenRepository(ctrl)
+			denyList := mocks.NewMockTokenDenyList(ctrl)
+			tt.setup(roles, tokens, denyList)
+
+			err := NewRoleService(roles, tokens, denyList).AssignRole(tt.actorID, 2, tt.role)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -1,13 +1,25 @@
 package service
 
 import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"errors"
+	"fmt"
 	"log"
+	"net/url"
+	"strings"
+	"time"
 
+	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
 
+	"milestone3/be/config"
 	"milestone3/be/internal/dto"
 	"milestone3/be/internal/entity"
+	"milestone3/be/internal/repository"
 	"milestone3/be/internal/utils"
 
 	"gorm.io/gorm"
@@ -17,14 +29,59 @@ type UserRepository interface {
 	Create(user *entity.Users) error
 	GetByEmail(email string) (user entity.Users, err error)
 	GetById(id int) (user entity.Users, err error)
+	MarkEmailVerified(id int) error
+	UpdatePassword(id int, passwordHash string) error
+	GetPermissions(role string) (permissions []string, err error)
+	UpdateProfile(id int, name, phone, address string) error
+	ListUsers(filter repository.UserFilter, page, limit int) (users []entity.Users, total int64, err error)
+	Suspend(id int, reason string) error
+	Unsuspend(id int) error
+	HasOpenActivity(id int) (open bool, err error)
+	Anonymize(id int) (objectNames []string, err error)
+}
+
+type RefreshTokenRepository interface {
+	Create(token *entity.RefreshToken) error
+	GetByHash(tokenHash string) (token entity.RefreshToken, err error)
+	Rotate(usedID uint, next *entity.RefreshToken) error
+	RevokeFamily(familyID string) (tokens []entity.RefreshToken, err error)
+	RevokeUser(userID int) (tokens []entity.RefreshToken, err error)
+}
+
+// UserTokenRepository keeps the single-use tokens mailed for email verification and password reset
+type UserTokenRepository interface {
+	Create(token *entity.UserToken) error
+	Consume(tokenHash string, purpose string) (token entity.UserToken, err error)
+}
+
+// TokenDenyList revokes access tokens by their jti until they expire
+type TokenDenyList interface {
+	Deny(jti string, ttl time.Duration) error
+}
+
+type Mailer interface {
+	Send(msg dto.MailMessage) error
+}
+
+// SuspensionList marks suspended users for the auth middleware to reject
+type SuspensionList interface {
+	Suspend(userID int) error
+	Lift(userID int) error
 }
 
 type UserServ struct {
-	userRepo UserRepository
+	userRepo         UserRepository
+	refreshTokenRepo RefreshTokenRepository
+	userTokenRepo    UserTokenRepository
+	denyList         TokenDenyList
+	suspensions      SuspensionList
+	mailer           Mailer
+	privateStore     repository.ObjectStore
+	authCfg          config.AuthConfig
 }
 
-func NewUserService(ur UserRepository) *UserServ {
-	return &UserServ{userRepo: ur}
+func NewUserService(ur UserRepository, rr RefreshTokenRepository, tr UserTokenRepository, dl TokenDenyList, sl SuspensionList, mailer Mailer, privateStore repository.ObjectStore, authCfg config.AuthConfig) *UserServ {
+	return &UserServ{userRepo: ur, refreshTokenRepo: rr, userTokenRepo: tr, denyList: dl, suspensions: sl, mailer: mailer, privateStore: privateStore, authCfg: authCfg}
 }
 
 func (us *UserServ) CreateUser(req dto.UserRequest) (res dto.UserResponse, err error) {
@@ -46,6 +103,11 @@ func (us *UserServ) CreateUser(req dto.UserRequest) (res dto.UserResponse, err e
 		return dto.UserResponse{}, err
 	}
 
+	// the account exists either way, the user can ask for the email again
+	if err := us.sendVerificationEmail(user); err != nil {
+		log.Printf("failed send verification email to user %d: %v", user.Id, err)
+	}
+
 	//get user id to show in the response
 	userInfo, err := us.GetUserById(user.Id)
 	if err != nil {
@@ -64,32 +126,504 @@ func (us *UserServ) GetUserById(id int) (res dto.UserResponse, err error) {
 	}
 
 	userInfo := dto.UserResponse{
-		Id:    user.Id,
-		Name:  user.Name,
-		Email: user.Email,
-		Role:  user.Role,
+		Id:            user.Id,
+		Name:          user.Name,
+		Email:         user.Email,
+		Role:          user.Role,
+		EmailVerified: user.EmailVerifiedAt != nil,
+		Phone:         user.Phone,
+		Address:       user.Address,
 	}
 
 	return userInfo, nil
 }
 
-func (us *UserServ) GetUserByEmail(email, password string) (accessToken string, err error) {
+func (us *UserServ) GetUserByEmail(email, password string) (res dto.TokenResponse, err error) {
 	user, err := us.userRepo.GetByEmail(email)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return "", ErrInvalidCredential
+			return dto.TokenResponse{}, ErrInvalidCredential
 		}
-		return "", err
+		return dto.TokenResponse{}, err
 	}
 
 	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil {
-		return "", ErrInvalidCredential
+		return dto.TokenResponse{}, ErrInvalidCredential
+	}
+	if user.SuspendedAt != nil {
+		return dto.TokenResponse{}, ErrAccountSuspended
+	}
+
+	// every login starts a new refresh token family
+	return us.issueTokens(user, uuid.NewString(), 0)
+}
+
+// RefreshToken trades a refresh token for a new access token and the next refresh token of its family.
+// a token already rotated means someone else holds a copy, so the family is revoked for both of them
+func (us *UserServ) RefreshToken(refreshToken string) (res dto.TokenResponse, err error) {
+	used, err := us.refreshTokenRepo.GetByHash(hashToken(refreshToken))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return dto.TokenResponse{}, ErrInvalidRefreshToken
+		}
+		return dto.TokenResponse{}, err
+	}
+
+	if used.RevokedAt != nil {
+		log.Printf("refresh token reuse detected, revoking family %s of user %d", used.FamilyID, used.UserID)
+		if err := us.revokeFamily(used.FamilyID); err != nil {
+			return dto.TokenResponse{}, err
+		}
+		return dto.TokenResponse{}, ErrRefreshTokenReused
+	}
+	if time.Now().After(used.ExpiresAt) {
+		return dto.TokenResponse{}, ErrInvalidRefreshToken
+	}
+
+	// the role and its permissions may have changed since the last token
+	user, err := us.userRepo.GetById(used.UserID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return dto.TokenResponse{}, ErrInvalidRefreshToken
+		}
+		return dto.TokenResponse{}, err
+	}
+	if user.SuspendedAt != nil {
+		return dto.TokenResponse{}, ErrAccountSuspended
+	}
+
+	res, err = us.issueTokens(user, used.FamilyID, used.ID)
+	if errors.Is(err, repository.ErrRefreshTokenRevoked) {
+		// a concurrent request rotated the same token first
+		log.Printf("refresh token reuse detected, revoking family %s of user %d", used.FamilyID, used.UserID)
+		if err := us.revokeFamily(used.FamilyID); err != nil {
+			return dto.TokenResponse{}, err
+		}
+		return dto.TokenResponse{}, ErrRefreshTokenReused
+	}
+	return res, err
+}
+
+// Logout denies the access token until it expires and revokes the family of the refresh token, when given
+func (us *UserServ) Logout(userID int, accessJTI string, accessExpiresAt time.Time, refreshToken string) error {
+	if err := us.denyList.Deny(accessJTI, time.Until(accessExpiresAt)); err != nil {
+		log.Println("failed deny access token")
+		return err
+	}
+
+	if refreshToken == "" {
+		return nil
+	}
+	token, err := us.refreshTokenRepo.GetByHash(hashToken(refreshToken))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	// a refresh token of somebody else is not for this user to revoke
+	if token.UserID != userID {
+		return nil
+	}
+	return us.revokeFamily(token.FamilyID)
+}
+
+// VerifyEmail marks the email of the user the token was mailed to as verified, a token works once
+func (us *UserServ) VerifyEmail(token string) error {
+	used, err := us.userTokenRepo.Consume(hashToken(token), entity.UserTokenEmailVerification)
+	if err != nil {
+		if errors.Is(err, repository.ErrUserTokenInvalid) {
+			return ErrInvalidVerificationToken
+		}
+		return err
+	}
+
+	if err := us.userRepo.MarkEmailVerified(used.UserID); err != nil {
+		log.Println("failed mark email verified")
+		return err
+	}
+	return nil
+}
+
+// ResendVerificationEmail mails a new verification link, voiding the earlier ones. whether the
+// account exists or is verified already is not told, so the endpoint cannot probe for emails
+func (us *UserServ) ResendVerificationEmail(email string) error {
+	user, err := us.userRepo.GetByEmail(email)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	if user.EmailVerifiedAt != nil {
+		return nil
+	}
+
+	return us.sendVerificationEmail(user)
+}
+
+// ForgotPassword mails a password reset link, unknown emails are ignored without telling
+func (us *UserServ) ForgotPassword(email string) error {
+	user, err := us.userRepo.GetByEmail(email)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	token, err := us.createUserToken(user.Id, entity.UserTokenPasswordReset, us.authCfg.PasswordResetTTL)
+	if err != nil {
+		return err
+	}
+
+	return us.mailer.Send(dto.MailMessage{
+		To:      user.Email,
+		Subject: "Reset your Your Donate Rise password",
+		Body: fmt.Sprintf("Hi %s,\n\n"+
+			"Someone asked to reset the password of your account. Choose a new password here:\n\n"+
+			"%s/reset-password?token=%s\n\n"+
+			"The link works once and expires in %s. If you did not ask for it, ignore this email, your password stays as it is.\n",
+			user.Name, us.authCfg.AppURL, url.QueryEscape(token), us.authCfg.PasswordResetTTL),
+	})
+}
+
+// ResetPassword sets the password of the user the reset token was mailed to and signs them out
+// everywhere. following the link proves the email, so an unverified one gets verified too
+func (us *UserServ) ResetPassword(token, password string) error {
+	used, err := us.userTokenRepo.Consume(hashToken(token), entity.UserTokenPasswordReset)
+	if err != nil {
+		if errors.Is(err, repository.ErrUserTokenInvalid) {
+			return ErrInvalidResetToken
+		}
+		return err
+	}
+
+	passHash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		log.Println("error encrypt password")
+		return err
+	}
+	if err := us.userRepo.UpdatePassword(used.UserID, string(passHash)); err != nil {
+		log.Println("failed update password")
+		return err
+	}
+	if err := us.userRepo.MarkEmailVerified(used.UserID); err != nil {
+		log.Println("failed mark email verified")
+		return err
 	}
 
-	token, err := utils.GenerateJwtToken(email, user.Role, user.Id)
+	return us.revokeUser(used.UserID)
+}
+
+// UpdateProfile changes the name, phone and address given in req and returns the updated user
+func (us *UserServ) UpdateProfile(id int, req dto.UpdateProfileRequest) (res dto.UserResponse, err error) {
+	user, err := us.userRepo.GetById(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return dto.UserResponse{}, ErrUserNotFound
+		}
+		return dto.UserResponse{}, err
+	}
+
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if len(name) < 3 {
+			return dto.UserResponse{}, ErrInvalidUser
+		}
+		user.Name = name
+	}
+	if req.Phone != nil {
+		user.Phone = *req.Phone
+	}
+	if req.Address != nil {
+		user.Address = strings.TrimSpace(*req.Address)
+	}
+
+	if err := us.userRepo.UpdateProfile(id, user.Name, user.Phone, user.Address); err != nil {
+		log.Println("failed update profile")
+		return dto.UserResponse{}, err
+	}
+	return us.GetUserById(id)
+}
+
+// ChangePassword sets a new password once the current one is given again. every session of the user
+// is signed out, and a new one is started for the caller
+func (us *UserServ) ChangePassword(id int, currentPassword, newPassword string) (res dto.TokenResponse, err error) {
+	user, err := us.reauthenticate(id, currentPassword)
 	if err != nil {
+		return dto.TokenResponse{}, err
+	}
+
+	passHash, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		log.Println("error encrypt password")
+		return dto.TokenResponse{}, err
+	}
+	if err := us.userRepo.UpdatePassword(id, string(passHash)); err != nil {
+		log.Println("failed update password")
+		return dto.TokenResponse{}, err
+	}
+
+	if err := us.revokeUser(id); err != nil {
+		return dto.TokenResponse{}, err
+	}
+	return us.issueTokens(user, uuid.NewString(), 0)
+}
+
+// DeleteAccount signs the user out everywhere and wipes everything personal from the account once the
+// password is given again. bids, donations and payments stay for the records, pointing at a user that
+// tells nothing about who it was, the donations without what the donor wrote and without their photos.
+// it waits until nothing of the user is running anymore
+func (us *UserServ) DeleteAccount(id int, password string) error {
+	if _, err := us.reauthenticate(id, password); err != nil {
+		return err
+	}
+
+	open, err := us.userRepo.HasOpenActivity(id)
+	if err != nil {
+		log.Println("failed check open activity")
+		return err
+	}
+	if open {
+		return ErrAccountHasOpenActivity
+	}
+
+	if err := us.revokeUser(id); err != nil {
+		return err
+	}
+	objectNames, err := us.userRepo.Anonymize(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrUserNotFound
+		}
+		log.Println("failed anonymize user")
+		return err
+	}
+
+	// the rows are gone already, a photo left behind is logged and does not fail the deletion
+	for _, name := range objectNames {
+		if err := us.privateStore.Delete(context.Background(), name); err != nil {
+			log.Printf("failed delete donation photo %s: %v", name, err)
+		}
+	}
+	return nil
+}
+
+// ListUsers returns a page of the users matching req with the total count
+func (us *UserServ) ListUsers(req dto.UserListRequest) (res []dto.AdminUserResponse, total int64, err error) {
+	filter := repository.UserFilter{Search: strings.TrimSpace(req.Search), Role: req.Role, Status: req.Status}
+	users, total, err := us.userRepo.ListUsers(filter, req.Page, req.Limit)
+	if err != nil {
+		log.Println("failed list users")
+		return nil, 0, err
+	}
+
+	res = make([]dto.AdminUserResponse, 0, len(users))
+	for _, u := range users {
+		status := "active"
+		if u.DeletedAt != nil {
+			status = "deleted"
+		} else if u.SuspendedAt != nil {
+			status = "suspended"
+		}
+		res = append(res, dto.AdminUserResponse{
+			Id:               u.Id,
+			Name:             u.Name,
+			Email:            u.Email,
+			Role:             u.Role,
+			EmailVerified:    u.EmailVerifiedAt != nil,
+			Phone:            u.Phone,
+			Status:           status,
+			SuspendedAt:      u.SuspendedAt,
+			SuspensionReason: u.SuspensionReason,
+			DeletedAt:        u.DeletedAt,
+			CreatedAt:        u.CreatedAt,
+		})
+	}
+	return res, total, nil
+}
+
+// SuspendUser keeps the user out until unsuspended: the sessions of the user are signed out and the
+// auth middleware rejects the user from then on. nobody suspends themselves
+func (us *UserServ) SuspendUser(actorID, userID int, reason string) error {
+	if actorID == userID {
+		return ErrCannotSuspendSelf
+	}
+
+	if err := us.userRepo.Suspend(userID, strings.TrimSpace(reason)); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrUserNotFound
+		}
+		log.Println("failed suspend user")
+		return err
+	}
+	if err := us.suspensions.Suspend(userID); err != nil {
+		log.Println("failed mark user suspended")
+		return err
+	}
+	return us.revokeUser(userID)
+}
+
+// UnsuspendUser lets the user sign in again
+func (us *UserServ) UnsuspendUser(userID int) error {
+	if err := us.userRepo.Unsuspend(userID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrUserNotFound
+		}
+		log.Println("failed unsuspend user")
+		return err
+	}
+	if err := us.suspensions.Lift(userID); err != nil {
+		log.Println("failed lift user suspension")
+		return err
+	}
+	return nil
+}
+
+// reauthenticate checks password against the one of the user, for changes a stolen access token must not make
+func (us *UserServ) reauthenticate(id int, password string) (entity.Users, error) {
+	user, err := us.userRepo.GetById(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entity.Users{}, ErrUserNotFound
+		}
+		return entity.Users{}, err
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil {
+		return entity.Users{}, ErrWrongPassword
+	}
+	return user, nil
+}
+
+// revokeUser revokes every refresh token of the user and denies the access tokens still alive
+func (us *UserServ) revokeUser(id int) error {
+	tokens, err := us.refreshTokenRepo.RevokeUser(id)
+	if err != nil {
+		log.Println("failed revoke refresh tokens")
+		return err
+	}
+	return denyAccessTokens(us.denyList, tokens)
+}
+
+func (us *UserServ) sendVerificationEmail(user entity.Users) error {
+	token, err := us.createUserToken(user.Id, entity.UserTokenEmailVerification, us.authCfg.EmailVerificationTTL)
+	if err != nil {
+		return err
+	}
+
+	return us.mailer.Send(dto.MailMessage{
+		To:      user.Email,
+		Subject: "Verify your Your Donate Rise email",
+		Body: fmt.Sprintf("Hi %s,\n\n"+
+			"Please verify your email to start bidding and donating:\n\n"+
+			"%s/auth/verify-email?token=%s\n\n"+
+			"The link expires in %s. If you did not create an account, ignore this email.\n",
+			user.Name, us.authCfg.AppURL, url.QueryEscape(token), us.authCfg.EmailVerificationTTL),
+	})
+}
+
+// createUserToken stores the hash of a new token for purpose and returns the token to mail
+func (us *UserServ) createUserToken(userID int, purpose string, ttl time.Duration) (string, error) {
+	token, err := newOpaqueToken()
+	if err != nil {
+		log.Println("failed generate user token")
 		return "", err
 	}
 
+	if err := us.userTokenRepo.Create(&entity.UserToken{
+		UserID:    userID,
+		Purpose:   purpose,
+		TokenHash: hashToken(token),
+		ExpiresAt: time.Now().Add(ttl),
+	}); err != nil {
+		log.Println("failed create user token")
+		return "", err
+	}
 	return token, nil
 }
+
+// issueTokens signs an access token and stores its refresh token in the family, rotating usedID unless it is 0
+func (us *UserServ) issueTokens(user entity.Users, familyID string, usedID uint) (dto.TokenResponse, error) {
+	permissions, err := us.userRepo.GetPermissions(user.Role)
+	if err != nil {
+		log.Println("failed get role permissions")
+		return dto.TokenResponse{}, err
+	}
+
+	now := time.Now()
+	accessExpiresAt := now.Add(us.authCfg.AccessTokenTTL)
+	accessToken, jti, err := utils.GenerateJwtToken(user.Email, user.Role, user.Id, user.EmailVerifiedAt != nil, permissions, accessExpiresAt)
+	if err != nil {
+		return dto.TokenResponse{}, err
+	}
+
+	refreshToken, err := newOpaqueToken()
+	if err != nil {
+		log.Println("failed generate refresh token")
+		return dto.TokenResponse{}, err
+	}
+	next := entity.RefreshToken{
+		UserID:          user.Id,
+		FamilyID:        familyID,
+		TokenHash:       hashToken(refreshToken),
+		AccessJTI:       jti,
+		AccessExpiresAt: accessExpiresAt,
+		ExpiresAt:       now.Add(us.authCfg.RefreshTokenTTL),
+	}
+
+	if usedID == 0 {
+		err = us.refreshTokenRepo.Create(&next)
+	} else {
+		err = us.refreshTokenRepo.Rotate(usedID, &next)
+	}
+	if err != nil {
+		return dto.TokenResponse{}, err
+	}
+
+	return dto.TokenResponse{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int(us.authCfg.AccessTokenTTL.Seconds()),
+	}, nil
+}
+
+// revokeFamily revokes the refresh tokens of the family and denies the access tokens they issued
+func (us *UserServ) revokeFamily(familyID string) error {
+	tokens, err := us.refreshTokenRepo.RevokeFamily(familyID)
+	if err != nil {
+		log.Println("failed revoke refresh token family")
+		return err
+	}
+	return denyAccessTokens(us.denyList, tokens)
+}
+
+// denyAccessTokens denies the access tokens still alive that were issued along the refresh tokens
+func denyAccessTokens(denyList TokenDenyList, tokens []entity.RefreshToken) error {
+	for _, t := range tokens {
+		if t.AccessJTI == "" || time.Now().After(t.AccessExpiresAt) {
+			continue
+		}
+		if err := denyList.Deny(t.AccessJTI, time.Until(t.AccessExpiresAt)); err != nil {
+			log.Println("failed deny access token")
+			return err
+		}
+	}
+	return nil
+}
+
+// newOpaqueToken is a random token for refresh tokens and the tokens mailed to users
+func newOpaqueToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashToken is what the database keeps of an opaque token, a leaked table does not hand out sessions
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -3,22 +3,36 @@ package service
 import (
 	"errors"
 	"testing"
+	"time"
 
+	"milestone3/be/config"
 	"milestone3/be/internal/dto"
 	"milestone3/be/internal/entity"
 	"milestone3/be/internal/mocks"
+	"milestone3/be/internal/repository"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
 )
 
+var testAuthConfig = config.AuthConfig{
+	AccessTokenTTL:       15 * time.Minute,
+	RefreshTokenTTL:      720 * time.Hour,
+	EmailVerificationTTL: 48 * time.Hour,
+	PasswordResetTTL:     30 * time.Minute,
+	AppURL:               "http://localhost:8080",
+}
+
 func TestUserService_CreateUser(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockUserRepository(ctrl)
-	userService := NewUserService(mockRepo)
+	mockUserTokenRepo := mocks.NewMockUserTokenRepository(ctrl)
+	mockMailer := mocks.NewMockMailer(ctrl)
+	userService := NewUserService(mockRepo, mocks.NewMockRefreshTokenRepository(ctrl), mockUserTokenRepo, mocks.NewMockTokenDenyList(ctrl), mocks.NewMockSuspensionList(ctrl), mockMailer, nil, testAuthConfig)
 
 	tests := []struct {
 		name    string
@@ -33,8 +47,44 @@ func TestUserService_CreateUser(t *testing.T) {
 				Email:    "test@example.com",
 				Password: "password123",
 			},
+			setup: func() {
+				mockRepo.EXPECT().Create(gomock.Any()).DoAndReturn(func(user *entity.Users) error {
+					assert.Nil(t, user.EmailVerifiedAt)
+					user.Id = 1
+					return nil
+				})
+				mockUserTokenRepo.EXPECT().Create(gomock.Any()).DoAndReturn(func(token *entity.UserToken) error {
+					assert.Equal(t, 1, token.UserID)
+					assert.Equal(t, entity.UserTokenEmailVerification, token.Purpose)
+					assert.Len(t, token.TokenHash, 64)
+					assert.WithinDuration(t, time.Now().Add(testAuthConfig.EmailVerificationTTL), token.ExpiresAt, time.Minute)
+					return nil
+				})
+				mockMailer.EXPECT().Send(gomock.Any()).DoAndReturn(func(msg dto.MailMessage) error {
+					assert.Equal(t, "test@example.com", msg.To)
+					assert.Contains(t, msg.Body, testAuthConfig.AppURL+"/auth/verify-email?token=")
+					return nil
+				})
+				mockRepo.EXPECT().GetById(1).Return(entity.Users{
+					Id:    1,
+					Name:  "Test User",
+					Email: "test@example.com",
+					Role:  "donor",
+				}, nil)
+			},
+			wantErr: false,
+		},
+		{
+			name: "failing to mail the verification link does not fail registration",
+			req: dto.UserRequest{
+				Name:     "Test User",
+				Email:    "test@example.com",
+				Password: "password123",
+			},
 			setup: func() {
 				mockRepo.EXPECT().Create(gomock.Any()).Return(nil)
+				mockUserTokenRepo.EXPECT().Create(gomock.Any()).Return(nil)
+				mockMailer.EXPECT().Send(gomock.Any()).Return(errors.New("smtp down"))
 				mockRepo.EXPECT().GetById(gomock.Any()).Return(entity.Users{
 					Id:    1,
 					Name:  "Test User",
@@ -65,6 +115,8 @@ func TestUserService_CreateUser(t *testing.T) {
 			},
 			setup: func() {
 				mockRepo.EXPECT().Create(gomock.Any()).Return(nil)
+				mockUserTokenRepo.EXPECT().Create(gomock.Any()).Return(nil)
+				mockMailer.EXPECT().Send(gomock.Any()).Return(nil)
 				mockRepo.EXPECT().GetById(gomock.Any()).Return(entity.Users{}, errors.New("user not found"))
 			},
 			wantErr: true,
@@ -94,7 +146,7 @@ func TestUserService_GetUserById(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockUserRepository(ctrl)
-	userService := NewUserService(mockRepo)
+	userService := NewUserService(mockRepo, mocks.NewMockRefreshTokenRepository(ctrl), mocks.NewMockUserTokenRepository(ctrl), mocks.NewMockTokenDenyList(ctrl), mocks.NewMockSuspensionList(ctrl), mocks.NewMockMailer(ctrl), nil, testAuthConfig)
 
 	tests := []struct {
 		name    string
@@ -148,8 +200,10 @@ func TestUserService_GetUserByEmail(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
+	t.Setenv("SECRET_KEY", "test-secret")
 	mockRepo := mocks.NewMockUserRepository(ctrl)
-	userService := NewUserService(mockRepo)
+	mockRefreshRepo := mocks.NewMockRefreshTokenRepository(ctrl)
+	userService := NewUserService(mockRepo, mockRefreshRepo, mocks.NewMockUserTokenRepository(ctrl), mocks.NewMockTokenDenyList(ctrl), mocks.NewMockSuspensionList(ctrl), mocks.NewMockMailer(ctrl), nil, testAuthConfig)
 
 	// Create a hashed password for testing
 	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.DefaultCost)
@@ -161,6 +215,25 @@ func TestUserService_GetUserByEmail(t *testing.T) {
 		setup    func()
 		wantErr  bool
 	}{
+		{
+			name:     "successful login starts a token family",
+			email:    "test@example.com",
+			password: "password123",
+			setup: func() {
+				user := entity.Users{Id: 1, Email: "test@example.com", Password: string(hashedPassword), Role: "donor"}
+				mockRepo.EXPECT().GetByEmail("test@example.com").Return(user, nil)
+				mockRepo.EXPECT().GetPermissions("donor").Return([]string{}, nil)
+				mockRefreshRepo.EXPECT().Create(gomock.Any()).DoAndReturn(func(token *entity.RefreshToken) error {
+					assert.Equal(t, 1, token.UserID)
+					assert.NotEmpty(t, token.FamilyID)
+					assert.Len(t, token.TokenHash, 64)
+					assert.NotEmpty(t, token.AccessJTI)
+					assert.WithinDuration(t, time.Now().Add(testAuthConfig.RefreshTokenTTL), token.ExpiresAt, time.Minute)
+					return nil
+				})
+			},
+			wantErr: false,
+		},
 		{
 			name:     "user not found",
 			email:    "notfound@example.com",
@@ -186,6 +259,17 @@ func TestUserService_GetUserByEmail(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name:     "suspended user",
+			email:    "test@example.com",
+			password: "password123",
+			setup: func() {
+				suspendedAt := time.Now()
+				user := entity.Users{Id: 1, Email: "test@example.com", Password: string(hashedPassword), Role: "donor", SuspendedAt: &suspendedAt}
+				mockRepo.EXPECT().GetByEmail("test@example.com").Return(user, nil)
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -199,8 +283,634 @@ func TestUserService_GetUserByEmail(t *testing.T) {
 				assert.Empty(t, token)
 			} else {
 				assert.NoError(t, err)
-				assert.NotEmpty(t, token)
+				assert.NotEmpty(t, token.AccessToken)
+				assert.NotEmpty(t, token.RefreshToken)
+				assert.Equal(t, 900, token.ExpiresIn)
+			}
+		})
+	}
+}
+
+func TestUserService_RefreshToken(t *testing.T) {
+	t.Setenv("SECRET_KEY", "test-secret")
+	user := entity.Users{Id: 1, Email: "test@example.com", Role: "donor"}
+	revokedAt := time.Now().Add(-time.Minute)
+	active := entity.RefreshToken{ID: 7, UserID: 1, FamilyID: "family-1", ExpiresAt: time.Now().Add(time.Hour)}
+	family := []entity.RefreshToken{
+		{ID: 7, FamilyID: "family-1", AccessJTI: "jti-old", AccessExpiresAt: time.Now().Add(-time.Minute)},
+		{ID: 8, FamilyID: "family-1", AccessJTI: "jti-live", AccessExpiresAt: time.Now().Add(10 * time.Minute)},
+	}
+
+	tests := []struct {
+		name    string
+		setup   func(users *mocks.MockUserRepository, tokens *mocks.MockRefreshTokenRepository, denyList *mocks.MockTokenDenyList)
+		wantErr error
+	}{
+		{
+			name: "rotates within the family",
+			setup: func(users *mocks.MockUserRepository, tokens *mocks.MockRefreshTokenRepository, denyList *mocks.MockTokenDenyList) {
+				tokens.EXPECT().GetByHash(hashToken("refresh-1")).Return(active, nil)
+				users.EXPECT().GetById(1).Return(user, nil)
+				users.EXPECT().GetPermissions("donor").Return([]string{}, nil)
+				tokens.EXPECT().Rotate(uint(7), gomock.Any()).DoAndReturn(func(_ uint, next *entity.RefreshToken) error {
+					assert.Equal(t, "family-1", next.FamilyID)
+					assert.NotEqual(t, hashToken("refresh-1"), next.TokenHash)
+					return nil
+				})
+			},
+		},
+		{
+			name: "reused token revokes the family",
+			setup: func(users *mocks.MockUserRepository, tokens *mocks.MockRefreshTokenRepository, denyList *mocks.MockTokenDenyList) {
+				used := active
+				used.RevokedAt = &revokedAt
+				tokens.EXPECT().GetByHash(hashToken("refresh-1")).Return(used, nil)
+				tokens.EXPECT().RevokeFamily("family-1").Return(family, nil)
+				// only the access token still alive needs denying
+				denyList.EXPECT().Deny("jti-live", gomock.Any()).Return(nil)
+			},
+			wantErr: ErrRefreshTokenReused,
+		},
+		{
+			name: "concurrent rotation counts as reuse",
+			setup: func(users *mocks.MockUserRepository, tokens *mocks.MockRefreshTokenRepository, denyList *mocks.MockTokenDenyList) {
+				tokens.EXPECT().GetByHash(hashToken("refresh-1")).Return(active, nil)
+				users.EXPECT().GetById(1).Return(user, nil)
+				users.EXPECT().GetPermissions("donor").Return([]string{}, nil)
+				tokens.EXPECT().Rotate(uint(7), gomock.Any()).Return(repository.ErrRefreshTokenRevoked)
+				tokens.EXPECT().RevokeFamily("family-1").Return(family, nil)
+				denyList.EXPECT().Deny("jti-live", gomock.Any()).Return(nil)
+			},
+			wantErr: ErrRefreshTokenReused,
+		},
+		{
+			name: "suspended user",
+			setup: func(users *mocks.MockUserRepository, tokens *mocks.MockRefreshTokenRepository, denyList *mocks.MockTokenDenyList) {
+				suspended := user
+				suspended.SuspendedAt = &revokedAt
+				tokens.EXPECT().GetByHash(hashToken("refresh-1")).Return(active, nil)
+				users.EXPECT().GetById(1).Return(suspended, nil)
+			},
+			wantErr: ErrAccountSuspended,
+		},
+		{
+			name: "expired token",
+			setup: func(users *mocks.MockUserRepository, tokens *mocks.MockRefreshTokenRepository, denyList *mocks.MockTokenDenyList) {
+				expired := active
+				expired.ExpiresAt = time.Now().Add(-time.Second)
+				tokens.EXPECT().GetByHash(hashToken("refresh-1")).Return(expired, nil)
+			},
+			wantErr: ErrInvalidRefreshToken,
+		},
+		{
+			name: "unknown token",
+			setup: func(users *mocks.MockUserRepository, tokens *mocks.MockRefreshTokenRepository, denyList *mocks.MockTokenDenyList) {
+				tokens.EXPECT().GetByHash(hashToken("refresh-1")).Return(entity.RefreshToken{}, gorm.ErrRecordNotFound)
+			},
+			wantErr: ErrInvalidRefreshToken,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			users := mocks.NewMockUserRepository(ctrl)
+			tokens := mocks.NewMockRefreshTokenRepository(ctrl)
+			denyList := mocks.NewMockTokenDenyList(ctrl)
+			userService := NewUserService(users, tokens, mocks.NewMockUserTokenRepository(ctrl), denyList, mocks.NewMockSuspensionList(ctrl), mocks.NewMockMailer(ctrl), nil, testAuthConfig)
+			tt.setup(users, tokens, denyList)
+
+			res, err := userService.RefreshToken("refresh-1")
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Empty(t, res)
+			} else {
+				assert.NoError(t, err)
+				assert.NotEmpty(t, res.AccessToken)
+				assert.NotEqual(t, "refresh-1", res.RefreshToken)
+			}
+		})
+	}
+}
+
+func TestUserService_Logout(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	tokens := mocks.NewMockRefreshTokenRepository(ctrl)
+	denyList := mocks.NewMockTokenDenyList(ctrl)
+	userService := NewUserService(mocks.NewMockUserRepository(ctrl), tokens, mocks.NewMockUserTokenRepository(ctrl), denyList, mocks.NewMockSuspensionList(ctrl), mocks.NewMockMailer(ctrl), nil, testAuthConfig)
+	expiresAt := time.Now().Add(10 * time.Minute)
+
+	t.Run("denies the access token and revokes the refresh family", func(t *testing.T) {
+		denyList.EXPECT().Deny("jti-1", gomock.Any()).DoAndReturn(func(_ string, ttl time.Duration) error {
+			assert.InDelta(t, (10 * time.Minute).Seconds(), ttl.Seconds(), 5)
+			return nil
+		})
+		tokens.EXPECT().GetByHash(hashToken("refresh-1")).Return(entity.RefreshToken{UserID: 1, FamilyID: "family-1"}, nil)
+		tokens.EXPECT().RevokeFamily("family-1").Return(nil, nil)
+
+		assert.NoError(t, userService.Logout(1, "jti-1", expiresAt, "refresh-1"))
+	})
+
+	t.Run("refresh token of another user is left alone", func(t *testing.T) {
+		denyList.EXPECT().Deny("jti-1", gomock.Any()).Return(nil)
+		tokens.EXPECT().GetByHash(hashToken("refresh-2")).Return(entity.RefreshToken{UserID: 2, FamilyID: "family-2"}, nil)
+
+		assert.NoError(t, userService.Logout(1, "jti-1", expiresAt, "refresh-2"))
+	})
+
+	t.Run("without a refresh token", func(t *testing.T) {
+		denyList.EXPECT().Deny("jti-1", gomock.Any()).Return(nil)
+
+		assert.NoError(t, userService.Logout(1, "jti-1", expiresAt, ""))
+	})
+}
+
+func TestUserService_VerifyEmail(t *testing.T) {
+	tests := []struct {
+		name    string
+		setup   func(users *mocks.MockUserRepository, userTokens *mocks.MockUserTokenRepository)
+		wantErr error
+	}{
+		{
+			name: "marks the email verified",
+			setup: func(users *mocks.MockUserRepository, userTokens *mocks.MockUserTokenRepository) {
+				userTokens.EXPECT().Consume(hashToken("verify-1"), entity.UserTokenEmailVerification).Return(entity.UserToken{UserID: 1}, nil)
+				users.EXPECT().MarkEmailVerified(1).Return(nil)
+			},
+		},
+		{
+			name: "used or expired token",
+			setup: func(users *mocks.MockUserRepository, userTokens *mocks.MockUserTokenRepository) {
+				userTokens.EXPECT().Consume(hashToken("verify-1"), entity.UserTokenEmailVerification).Return(entity.UserToken{}, repository.ErrUserTokenInvalid)
+			},
+			wantErr: ErrInvalidVerificationToken,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			users := mocks.NewMockUserRepository(ctrl)
+			userTokens := mocks.NewMockUserTokenRepository(ctrl)
+			userService := NewUserService(users, mocks.NewMockRefreshTokenRepository(ctrl), userTokens, mocks.NewMockTokenDenyList(ctrl), mocks.NewMockSuspensionList(ctrl), mocks.NewMockMailer(ctrl), nil, testAuthConfig)
+			tt.setup(users, userTokens)
+
+			err := userService.VerifyEmail("verify-1")
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestUserService_ResendVerificationEmail(t *testing.T) {
+	verifiedAt := time.Now()
+
+	tests := []struct {
+		name  string
+		setup func(users *mocks.MockUserRepository, userTokens *mocks.MockUserTokenRepository, mailer *mocks.MockMailer)
+	}{
+		{
+			name: "mails a new link to an unverified account",
+			setup: func(users *mocks.MockUserRepository, userTokens *mocks.MockUserTokenRepository, mailer *mocks.MockMailer) {
+				users.EXPECT().GetByEmail("test@example.com").Return(entity.Users{Id: 1, Email: "test@example.com"}, nil)
+				userTokens.EXPECT().Create(gomock.Any()).Return(nil)
+				mailer.EXPECT().Send(gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name: "verified account gets nothing",
+			setup: func(users *mocks.MockUserRepository, userTokens *mocks.MockUserTokenRepository, mailer *mocks.MockMailer) {
+				users.EXPECT().GetByEmail("test@example.com").Return(entity.Users{Id: 1, EmailVerifiedAt: &verifiedAt}, nil)
+			},
+		},
+		{
+			name: "unknown email is not told apart",
+			setup: func(users *mocks.MockUserRepository, userTokens *mocks.MockUserTokenRepository, mailer *mocks.MockMailer) {
+				users.EXPECT().GetByEmail("test@example.com").Return(entity.Users{}, gorm.ErrRecordNotFound)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			users := mocks.NewMockUserRepository(ctrl)
+			userTokens := mocks.NewMockUserTokenRepository(ctrl)
+			mailer := mocks.NewMockMailer(ctrl)
+			userService := NewUserService(users, mocks.NewMockRefreshTokenRepository(ctrl), userTokens, mocks.NewMockTokenDenyList(ctrl), mocks.NewMockSuspensionList(ctrl), mailer, nil, testAuthConfig)
+			tt.setup(users, userTokens, mailer)
+
+			assert.NoError(t, userService.ResendVerificationEmail("test@example.com"))
+		})
+	}
+}
+
+func TestUserService_ForgotPassword(t *testing.T) {
+	tests := []struct {
+		name    string
+		setup   func(users *mocks.MockUserRepository, userTokens *mocks.MockUserTokenRepository, mailer *mocks.MockMailer)
+		wantErr bool
+	}{
+		{
+			name: "mails a reset link",
+			setup: func(users *mocks.MockUserRepository, userTokens *mocks.MockUserTokenRepository, mailer *mocks.MockMailer) {
+				users.EXPECT().GetByEmail("test@example.com").Return(entity.Users{Id: 1, Name: "Test User", Email: "test@example.com"}, nil)
+				userTokens.EXPECT().Create(gomock.Any()).DoAndReturn(func(token *entity.UserToken) error {
+					assert.Equal(t, entity.UserTokenPasswordReset, token.Purpose)
+					assert.WithinDuration(t, time.Now().Add(testAuthConfig.PasswordResetTTL), token.ExpiresAt, time.Minute)
+					return nil
+				})
+				mailer.EXPECT().Send(gomock.Any()).DoAndReturn(func(msg dto.MailMessage) error {
+					assert.Equal(t, "test@example.com", msg.To)
+					assert.Contains(t, msg.Body, testAuthConfig.AppURL+"/reset-password?token=")
+					return nil
+				})
+			},
+		},
+		{
+			name: "unknown email is not told apart",
+			setup: func(users *mocks.MockUserRepository, userTokens *mocks.MockUserTokenRepository, mailer *mocks.MockMailer) {
+				users.EXPECT().GetByEmail("test@example.com").Return(entity.Users{}, gorm.ErrRecordNotFound)
+			},
+		},
+		{
+			name: "mailer error",
+			setup: func(users *mocks.MockUserRepository, userTokens *mocks.MockUserTokenRepository, mailer *mocks.MockMailer) {
+				users.EXPECT().GetByEmail("test@example.com").Return(entity.Users{Id: 1, Email: "test@example.com"}, nil)
+				userTokens.EXPECT().Create(gomock.Any()).Return(nil)
+				mailer.EXPECT().Send(gomock.Any()).Return(errors.New("smtp down"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			users := mocks.NewMockUserRepository(ctrl)
+			userTokens := mocks.NewMockUserTokenRepository(ctrl)
+			mailer := mocks.NewMockMailer(ctrl)
+			userService := NewUserService(users, mocks.NewMockRefreshTokenRepository(ctrl), userTokens, mocks.NewMockTokenDenyList(ctrl), mocks.NewMockSuspensionList(ctrl), mailer, nil, testAuthConfig)
+			tt.setup(users, userTokens, mailer)
+
+			err := userService.ForgotPassword("test@example.com")
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestUserService_ResetPassword(t *testing.T) {
+	t.Run("sets the password and signs out every session", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		users := mocks.NewMockUserRepository(ctrl)
+		tokens := mocks.NewMockRefreshTokenRepository(ctrl)
+		userTokens := mocks.NewMockUserTokenRepository(ctrl)
+		denyList := mocks.NewMockTokenDenyList(ctrl)
+		userService := NewUserService(users, tokens, userTokens, denyList, mocks.NewMockSuspensionList(ctrl), mocks.NewMockMailer(ctrl), nil, testAuthConfig)
+
+		userTokens.EXPECT().Consume(hashToken("reset-1"), entity.UserTokenPasswordReset).Return(entity.UserToken{UserID: 1}, nil)
+		users.EXPECT().UpdatePassword(1, gomock.Any()).DoAndReturn(func(_ int, passwordHash string) error {
+			assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(passwordHash), []byte("newpassword123")))
+			return nil
+		})
+		users.EXPECT().MarkEmailVerified(1).Return(nil)
+		tokens.EXPECT().RevokeUser(1).Return([]entity.RefreshToken{
+			{AccessJTI: "jti-live", AccessExpiresAt: time.Now().Add(10 * time.Minute)},
+		}, nil)
+		denyList.EXPECT().Deny("jti-live", gomock.Any()).Return(nil)
+
+		assert.NoError(t, userService.ResetPassword("reset-1", "newpassword123"))
+	})
+
+	t.Run("used or expired token", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		userTokens := mocks.NewMockUserTokenRepository(ctrl)
+		userService := NewUserService(mocks.NewMockUserRepository(ctrl), mocks.NewMockRefreshTokenRepository(ctrl), userTokens, mocks.NewMockTokenDenyList(ctrl), mocks.NewMockSuspensionList(ctrl), mocks.NewMockMailer(ctrl), nil, testAuthConfig)
+
+		userTokens.EXPECT().Consume(hashToken("reset-1"), entity.UserTokenPasswordReset).Return(entity.UserToken{}, repository.ErrUserTokenInvalid)
+
+		assert.ErrorIs(t, userService.ResetPassword("reset-1", "newpassword123"), ErrInvalidResetToken)
+	})
+}
+
+func TestUserService_UpdateProfile(t *testing.T) {
+	name := "  New Name "
+	phone := "+6281234567890"
+	empty := ""
+	short := "ab"
+
+	tests := []struct {
+		name    string
+		req     dto.UpdateProfileRequest
+		setup   func(users *mocks.MockUserRepository)
+		wantErr error
+	}{
+		{
+			name: "changes the fields given",
+			req:  dto.UpdateProfileRequest{Name: &name, Phone: &phone},
+			setup: func(users *mocks.MockUserRepository) {
+				users.EXPECT().GetById(1).Return(entity.Users{Id: 1, Name: "Old Name", Address: "Jl. Merdeka 1"}, nil)
+				users.EXPECT().UpdateProfile(1, "New Name", "+6281234567890", "Jl. Merdeka 1").Return(nil)
+				users.EXPECT().GetById(1).Return(entity.Users{Id: 1, Name: "New Name", Phone: "+6281234567890", Address: "Jl. Merdeka 1"}, nil)
+			},
+		},
+		{
+			name: "empty address clears it",
+			req:  dto.UpdateProfileRequest{Address: &empty},
+			setup: func(users *mocks.MockUserRepository) {
+				users.EXPECT().GetById(1).Return(entity.Users{Id: 1, Name: "Old Name", Address: "Jl. Merdeka 1"}, nil)
+				users.EXPECT().UpdateProfile(1, "Old Name", "", "").Return(nil)
+				users.EXPECT().GetById(1).Return(entity.Users{Id: 1, Name: "Old Name"}, nil)
+			},
+		},
+		{
+			name: "name too short once trimmed",
+			req:  dto.UpdateProfileRequest{Name: &short},
+			setup: func(users *mocks.MockUserRepository) {
+				users.EXPECT().GetById(1).Return(entity.Users{Id: 1, Name: "Old Name"}, nil)
+			},
+			wantErr: ErrInvalidUser,
+		},
+		{
+			name: "user not found",
+			req:  dto.UpdateProfileRequest{Name: &name},
+			setup: func(users *mocks.MockUserRepository) {
+				users.EXPECT().GetById(1).Return(entity.Users{}, gorm.ErrRecordNotFound)
+			},
+			wantErr: ErrUserNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			users := mocks.NewMockUserRepository(ctrl)
+			userService := NewUserService(users, mocks.NewMockRefreshTokenRepository(ctrl), mocks.NewMockUserTokenRepository(ctrl), mocks.NewMockTokenDenyList(ctrl), mocks.NewMockSuspensionList(ctrl), mocks.NewMockMailer(ctrl), nil, testAuthConfig)
+			tt.setup(users)
+
+			_, err := userService.UpdateProfile(1, tt.req)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
 			}
 		})
 	}
-}
\ No newline at end of file
+}
+
+func TestUserService_ChangePassword(t *testing.T) {
+	t.Setenv("SECRET_KEY", "test-secret")
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.DefaultCost)
+	user := entity.Users{Id: 1, Email: "test@example.com", Password: string(hashedPassword), Role: "user"}
+
+	t.Run("signs out every session and starts a new one", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		users := mocks.NewMockUserRepository(ctrl)
+		tokens := mocks.NewMockRefreshTokenRepository(ctrl)
+		denyList := mocks.NewMockTokenDenyList(ctrl)
+		userService := NewUserService(users, tokens, mocks.NewMockUserTokenRepository(ctrl), denyList, mocks.NewMockSuspensionList(ctrl), mocks.NewMockMailer(ctrl), nil, testAuthConfig)
+
+		users.EXPECT().GetById(1).Return(user, nil)
+		users.EXPECT().UpdatePassword(1, gomock.Any()).DoAndReturn(func(_ int, passwordHash string) error {
+			assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(passwordHash), []byte("newpassword123")))
+			return nil
+		})
+		tokens.EXPECT().RevokeUser(1).Return([]entity.RefreshToken{
+			{AccessJTI: "jti-live", AccessExpiresAt: time.Now().Add(10 * time.Minute)},
+		}, nil)
+		denyList.EXPECT().Deny("jti-live", gomock.Any()).Return(nil)
+		users.EXPECT().GetPermissions("user").Return([]string{}, nil)
+		tokens.EXPECT().Create(gomock.Any()).Return(nil)
+
+		res, err := userService.ChangePassword(1, "password123", "newpassword123")
+		assert.NoError(t, err)
+		assert.NotEmpty(t, res.AccessToken)
+		assert.NotEmpty(t, res.RefreshToken)
+	})
+
+	t.Run("wrong current password", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		users := mocks.NewMockUserRepository(ctrl)
+		userService := NewUserService(users, mocks.NewMockRefreshTokenRepository(ctrl), mocks.NewMockUserTokenRepository(ctrl), mocks.NewMockTokenDenyList(ctrl), mocks.NewMockSuspensionList(ctrl), mocks.NewMockMailer(ctrl), nil, testAuthConfig)
+
+		users.EXPECT().GetById(1).Return(user, nil)
+
+		_, err := userService.ChangePassword(1, "wrongpassword", "newpassword123")
+		assert.ErrorIs(t, err, ErrWrongPassword)
+	})
+}
+
+func TestUserService_DeleteAccount(t *testing.T) {
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.DefaultCost)
+	user := entity.Users{Id: 1, Email: "test@example.com", Password: string(hashedPassword), Role: "user"}
+
+	tests := []struct {
+		name     string
+		password string
+		setup    func(users *mocks.MockUserRepository, tokens *mocks.MockRefreshTokenRepository, denyList *mocks.MockTokenDenyList, store *mocks.MockObjectStore)
+		wantErr  error
+	}{
+		{
+			name:     "signs out and anonymizes",
+			password: "password123",
+			setup: func(users *mocks.MockUserRepository, tokens *mocks.MockRefreshTokenRepository, denyList *mocks.MockTokenDenyList, store *mocks.MockObjectStore) {
+				users.EXPECT().GetById(1).Return(user, nil)
+				users.EXPECT().HasOpenActivity(1).Return(false, nil)
+				tokens.EXPECT().RevokeUser(1).Return([]entity.RefreshToken{
+					{AccessJTI: "jti-live", AccessExpiresAt: time.Now().Add(10 * time.Minute)},
+				}, nil)
+				denyList.EXPECT().Deny("jti-live", gomock.Any()).Return(nil)
+				users.EXPECT().Anonymize(1).Return(nil, nil)
+			},
+		},
+		{
+			name:     "deletes the donation photos no auction item shows",
+			password: "password123",
+			setup: func(users *mocks.MockUserRepository, tokens *mocks.MockRefreshTokenRepository, denyList *mocks.MockTokenDenyList, store *mocks.MockObjectStore) {
+				users.EXPECT().GetById(1).Return(user, nil)
+				users.EXPECT().HasOpenActivity(1).Return(false, nil)
+				tokens.EXPECT().RevokeUser(1).Return(nil, nil)
+				users.EXPECT().Anonymize(1).Return([]string{"donations/private/1_a.jpg", "donations/private/1_a_thumb.jpg"}, nil)
+				store.EXPECT().Delete(gomock.Any(), "donations/private/1_a.jpg").Return(nil)
+				// a photo left behind does not fail the deletion
+				store.EXPECT().Delete(gomock.Any(), "donations/private/1_a_thumb.jpg").Return(errors.New("bucket unavailable"))
+			},
+		},
+		{
+			name:     "wrong password",
+			password: "wrongpassword",
+			setup: func(users *mocks.MockUserRepository, tokens *mocks.MockRefreshTokenRepository, denyList *mocks.MockTokenDenyList, store *mocks.MockObjectStore) {
+				users.EXPECT().GetById(1).Return(user, nil)
+			},
+			wantErr: ErrWrongPassword,
+		},
+		{
+			name:     "running bid, payment or donation",
+			password: "password123",
+			setup: func(users *mocks.MockUserRepository, tokens *mocks.MockRefreshTokenRepository, denyList *mocks.MockTokenDenyList, store *mocks.MockObjectStore) {
+				users.EXPECT().GetById(1).Return(user, nil)
+				users.EXPECT().HasOpenActivity(1).Return(true, nil)
+			},
+			wantErr: ErrAccountHasOpenActivity,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			users := mocks.NewMockUserRepository(ctrl)
+			tokens := mocks.NewMockRefreshTokenRepository(ctrl)
+			denyList := mocks.NewMockTokenDenyList(ctrl)
+			store := mocks.NewMockObjectStore(ctrl)
+			userService := NewUserService(users, tokens, mocks.NewMockUserTokenRepository(ctrl), denyList, mocks.NewMockSuspensionList(ctrl), mocks.NewMockMailer(ctrl), store, testAuthConfig)
+			tt.setup(users, tokens, denyList, store)
+
+			err := userService.DeleteAccount(1, tt.password)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestUserService_ListUsers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	users := mocks.NewMockUserRepository(ctrl)
+	userService := NewUserService(users, mocks.NewMockRefreshTokenRepository(ctrl), mocks.NewMockUserTokenRepository(ctrl), mocks.NewMockTokenDenyList(ctrl), mocks.NewMockSuspensionList(ctrl), mocks.NewMockMailer(ctrl), nil, testAuthConfig)
+
+	now := time.Now()
+	users.EXPECT().ListUsers(repository.UserFilter{Search: "budi", Role: "user"}, 2, 10).Return([]entity.Users{
+		{Id: 3, Name: "Budi", Role: "user", EmailVerifiedAt: &now},
+		{Id: 2, Name: "Budi S", Role: "user", SuspendedAt: &now, SuspensionReason: "fake bids"},
+		{Id: 1, Name: "Deleted user", Role: "user", DeletedAt: &now},
+	}, int64(13), nil)
+
+	res, total, err := userService.ListUsers(dto.UserListRequest{Search: " budi ", Role: "user", Page: 2, Limit: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(13), total)
+	assert.Len(t, res, 3)
+	assert.Equal(t, "active", res[0].Status)
+	assert.True(t, res[0].EmailVerified)
+	assert.Equal(t, "suspended", res[1].Status)
+	assert.Equal(t, "fake bids", res[1].SuspensionReason)
+	assert.Equal(t, "deleted", res[2].Status)
+}
+
+func TestUserService_SuspendUser(t *testing.T) {
+	tests := []struct {
+		name    string
+		actorID int
+		setup   func(users *mocks.MockUserRepository, tokens *mocks.MockRefreshTokenRepository, denyList *mocks.MockTokenDenyList, suspensions *mocks.MockSuspensionList)
+		wantErr error
+	}{
+		{
+			name:    "marks the user and signs out every session",
+			actorID: 1,
+			setup: func(users *mocks.MockUserRepository, tokens *mocks.MockRefreshTokenRepository, denyList *mocks.MockTokenDenyList, suspensions *mocks.MockSuspensionList) {
+				users.EXPECT().Suspend(2, "fake bids").Return(nil)
+				suspensions.EXPECT().Suspend(2).Return(nil)
+				tokens.EXPECT().RevokeUser(2).Return([]entity.RefreshToken{
+					{AccessJTI: "jti-live", AccessExpiresAt: time.Now().Add(10 * time.Minute)},
+				}, nil)
+				denyList.EXPECT().Deny("jti-live", gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name:    "yourself",
+			actorID: 2,
+			setup: func(users *mocks.MockUserRepository, tokens *mocks.MockRefreshTokenRepository, denyList *mocks.MockTokenDenyList, suspensions *mocks.MockSuspensionList) {
+			},
+			wantErr: ErrCannotSuspendSelf,
+		},
+		{
+			name:    "unknown or deleted user",
+			actorID: 1,
+			setup: func(users *mocks.MockUserRepository, tokens *mocks.MockRefreshTokenRepository, denyList *mocks.MockTokenDenyList, suspensions *mocks.MockSuspensionList) {
+				users.EXPECT().Suspend(2, "fake bids").Return(gorm.ErrRecordNotFound)
+			},
+			wantErr: ErrUserNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			users := mocks.NewMockUserRepository(ctrl)
+			tokens := mocks.NewMockRefreshTokenRepository(ctrl)
+			denyList := mocks.NewMockTokenDenyList(ctrl)
+			suspensions := mocks.NewMockSuspensionList(ctrl)
+			userService := NewUserService(users, tokens, mocks.NewMockUserTokenRepository(ctrl), denyList, suspensions, mocks.NewMockMailer(ctrl), nil, testAuthConfig)
+			tt.setup(users, tokens, denyList, suspensions)
+
+			err := userService.SuspendUser(tt.actorID, 2, " fake bids ")
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestUserService_UnsuspendUser(t *testing.T) {
+	t.Run("lifts the suspension", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		users := mocks.NewMockUserRepository(ctrl)
+		suspensions := mocks.NewMockSuspensionList(ctrl)
+		userService := NewUserService(users, mocks.NewMockRefreshTokenRepository(ctrl), mocks.NewMockUserTokenRepository(ctrl), mocks.NewMockTokenDenyList(ctrl), suspensions, mocks.NewMockMailer(ctrl), nil, testAuthConfig)
+
+		users.EXPECT().Unsuspend(2).Return(nil)
+		suspensions.EXPECT().Lift(2).Return(nil)
+
+		assert.NoError(t, userService.UnsuspendUser(2))
+	})
+
+	t.Run("unknown user", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		users := mocks.NewMockUserRepository(ctrl)
+		userService := NewUserService(users, mocks.NewMockRefreshTokenRepository(ctrl), mocks.NewMockUserTokenRepository(ctrl), mocks.NewMockTokenDenyList(ctrl), mocks.NewMockSuspensionList(ctrl), mocks.NewMockMailer(ctrl), nil, testAuthConfig)
+
+		users.EXPECT().Unsuspend(2).Return(gorm.ErrRecordNotFound)
+
+		assert.ErrorIs(t, userService.UnsuspendUser(2), ErrUserNotFound)
+	})
+}
@@ -1,6 +1,10 @@
 package utils
 
-import "github.com/labstack/echo/v4"
+import (
+	"slices"
+
+	"github.com/labstack/echo/v4"
+)
 
 // GetUserID reads user id from context (set by auth middleware).
 // Returns (0,false) when not present/invalid.
@@ -20,11 +24,25 @@ func GetUserID(c echo.Context) (uint, bool) {
 	}
 }
 
-// IsAdmin reads "is_admin" flag from context (set by auth middleware).
-func IsAdmin(c echo.Context) bool {
-	v := c.Get("is_admin")
-	if b, ok := v.(bool); ok && b {
-		return true
-	}
-	return false
+// GetRole reads the role name from context (set by auth middleware), "" when not present.
+func GetRole(c echo.Context) string {
+	role, _ := c.Get("role").(string)
+	return role
+}
+
+// GetPermissions reads the permissions of the role from context (set by auth middleware).
+func GetPermissions(c echo.Context) []string {
+	permissions, _ := c.Get("permissions").([]string)
+	return permissions
+}
+
+// HasPermission tells whether the role of the request holds permission.
+func HasPermission(c echo.Context, permission string) bool {
+	return slices.Contains(GetPermissions(c), permission)
+}
+
+// IsEmailVerified reads "email_verified" flag from context (set by auth middleware).
+func IsEmailVerified(c echo.Context) bool {
+	v, ok := c.Get("email_verified").(bool)
+	return ok && v
 }
@@ -0,0 +1,271 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"net/http"
+)
+
+const (
+	ImageDisplayMaxSide   = 1280
+	ImageThumbnailMaxSide = 320
+	// ImageMaxPixels guards the decoder against small files that expand to huge images
+	ImageMaxPixels = 40_000_000
+
+	imageJPEGQuality = 85
+)
+
+var (
+	ErrUnsupportedImage = errors.New("only jpeg, png and gif images are allowed")
+	ErrImageTooLarge    = errors.New("image is too large")
+)
+
+// ImageVariant is an encoded image ready to upload, Ext includes the dot
+type ImageVariant struct {
+	Data        []byte
+	ContentType string
+	Ext         string
+}
+
+// ProcessedImage is an upload re-encoded without its metadata, with the variants scaled to fit
+// ImageDisplayMaxSide and ImageThumbnailMaxSide. Width and Height are those of the original
+type ProcessedImage struct {
+	Original  ImageVariant
+	Display   ImageVariant
+	Thumbnail ImageVariant
+	Width     int
+	Height    int
+}
+
+// ProcessImage checks the upload by its content rather than its declared type and rejects anything
+// over maxBytes. Re-encoding drops EXIF and every other metadata block, the JPEG orientation is
+// applied to the pixels first so photos taken sideways still show upright
+func ProcessImage(r io.Reader, maxBytes int64) (ProcessedImage, error) {
+	data, err := io.ReadAll(io.LimitReader(r, maxBytes+1))
+	if err != nil {
+		return ProcessedImage{}, err
+	}
+	if int64(len(data)) > maxBytes {
+		return ProcessedImage{}, fmt.Errorf("%w: exceeds %d bytes", ErrImageTooLarge, maxBytes)
+	}
+
+	contentType := http.DetectContentType(data)
+	if contentType != "image/jpeg" && contentType != "image/png" && contentType != "image/gif" {
+		return ProcessedImage{}, ErrUnsupportedImage
+	}
+
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return ProcessedImage{}, ErrUnsupportedImage
+	}
+	if cfg.Width*cfg.Height > ImageMaxPixels {
+		return ProcessedImage{}, fmt.Errorf("%w: exceeds %d pixels", ErrImageTooLarge, ImageMaxPixels)
+	}
+
+	var src image.Image
+	switch contentType {
+	case "image/jpeg":
+		src, err = jpeg.Decode(bytes.NewReader(data))
+	case "image/png":
+		src, err = png.Decode(bytes.NewReader(data))
+	case "image/gif":
+		// only the first frame is kept
+		src, err = gif.Decode(bytes.NewReader(data))
+	}
+	if err != nil {
+		return ProcessedImage{}, ErrUnsupportedImage
+	}
+
+	img := toRGBA(src)
+	if contentType == "image/jpeg" {
+		img = applyOrientation(img, jpegOrientation(data))
+	}
+
+	encode := encodePNG
+	if contentType == "image/jpeg" {
+		encode = encodeJPEG
+	}
+
+	var res ProcessedImage
+	res.Width, res.Height = img.Bounds().Dx(), img.Bounds().Dy()
+	if res.Original, err = encode(img); err != nil {
+		return ProcessedImage{}, err
+	}
+	if res.Display, err = encode(resizeToFit(img, ImageDisplayMaxSide)); err != nil {
+		return ProcessedImage{}, err
+	}
+	if res.Thumbnail, err = encode(resizeToFit(img, ImageThumbnailMaxSide)); err != nil {
+		return ProcessedImage{}, err
+	}
+	return res, nil
+}
+
+func encodeJPEG(img image.Image) (ImageVariant, error) {
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: imageJPEGQuality}); err != nil {
+		return ImageVariant{}, err
+	}
+	return ImageVariant{Data: buf.Bytes(), ContentType: "image/jpeg", Ext: ".jpg"}, nil
+}
+
+func encodePNG(img image.Image) (ImageVariant, error) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return ImageVariant{}, err
+	}
+	return ImageVariant{Data: buf.Bytes(), ContentType: "image/png", Ext: ".png"}, nil
+}
+
+func toRGBA(src image.Image) *image.RGBA {
+	b := src.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(dst, dst.Bounds(), src, b.Min, draw.Src)
+	return dst
+}
+
+// resizeToFit scales img down so its longest side is maxSide, averaging the source pixels each
+// target pixel covers. smaller images are returned as they are
+func resizeToFit(img *image.RGBA, maxSide int) *image.RGBA {
+	w, h := img.Bounds().Dx(), img.Bounds().Dy()
+	if w <= maxSide && h <= maxSide {
+		return img
+	}
+
+	dw, dh := maxSide, h*maxSide/w
+	if h > w {
+		dw, dh = w*maxSide/h, maxSide
+	}
+	dw, dh = max(dw, 1), max(dh, 1)
+
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < dh; y++ {
+		sy0, sy1 := y*h/dh, max((y+1)*h/dh, y*h/dh+1)
+		for x := 0; x < dw; x++ {
+			sx0, sx1 := x*w/dw, max((x+1)*w/dw, x*w/dw+1)
+
+			var r, g, b, a, n uint64
+			for sy := sy0; sy < sy1; sy++ {
+				row := img.Pix[sy*img.Stride:]
+				for sx := sx0; sx < sx1; sx++ {
+					p := row[sx*4 : sx*4+4]
+					r, g, b, a = r+uint64(p[0]), g+uint64(p[1]), b+uint64(p[2]), a+uint64(p[3])
+					n++
+				}
+			}
+
+			// RGBA is alpha-premultiplied, averaging the channels keeps it that way
+			d := dst.Pix[y*dst.Stride+x*4:]
+			d[0], d[1], d[2], d[3] = uint8(r/n), uint8(g/n), uint8(b/n), uint8(a/n)
+		}
+	}
+	return dst
+}
+
+// applyOrientation turns the pixels the way the EXIF orientation tag (1 to 8) asks viewers to
+func applyOrientation(img *image.RGBA, orientation int) *image.RGBA {
+	if orientation < 2 || orientation > 8 {
+		return img
+	}
+
+	w, h := img.Bounds().Dx(), img.Bounds().Dy()
+	dw, dh := w, h
+	if orientation >= 5 {
+		dw, dh = h, w
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			var dx, dy int
+			switch orientation {
+			case 2: // mirror horizontally
+				dx, dy = w-1-x, y
+			case 3: // turn 180 degrees
+				dx, dy = w-1-x, h-1-y
+			case 4: // mirror vertically
+				dx, dy = x, h-1-y
+			case 5: // mirror along the top-left diagonal
+				dx, dy = y, x
+			case 6: // turn 90 degrees clockwise
+				dx, dy = h-1-y, x
+			case 7: // mirror along the top-right diagonal
+				dx, dy = h-1-y, w-1-x
+			case 8: // turn 90 degrees counterclockwise
+				dx, dy = y, w-1-x
+			}
+			copy(dst.Pix[dy*dst.Stride+dx*4:dy*dst.Stride+dx*4+4], img.Pix[y*img.Stride+x*4:y*img.Stride+x*4+4])
+		}
+	}
+	return dst
+}
+
+// jpegOrientation reads the orientation tag from the EXIF block of a JPEG, 1 (upright) when there is none
+func jpegOrientation(data []byte) int {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return 1
+	}
+
+	for i := 2; i+4 <= len(data); {
+		if data[i] != 0xFF {
+			return 1
+		}
+		marker := data[i+1]
+		// the metadata segments all come before the image data
+		if marker == 0xDA || marker == 0xD9 {
+			return 1
+		}
+		size := int(binary.BigEndian.Uint16(data[i+2:]))
+		if size < 2 || i+2+size > len(data) {
+			return 1
+		}
+		segment := data[i+4 : i+2+size]
+		if marker == 0xE1 && len(segment) > 6 && string(segment[:6]) == "Exif\x00\x00" {
+			return exifOrientation(segment[6:])
+		}
+		i += 2 + size
+	}
+	return 1
+}
+
+// exifOrientation finds the orientation tag in the first IFD of the TIFF structure of an EXIF block
+func exifOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 1
+	}
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+
+	offset := int(order.Uint32(tiff[4:]))
+	if offset < 8 || offset+2 > len(tiff) {
+		return 1
+	}
+	entries := int(order.Uint16(tiff[offset:]))
+	for k := 0; k < entries; k++ {
+		entry := offset + 2 + k*12
+		if entry+12 > len(tiff) {
+			return 1
+		}
+		if order.Uint16(tiff[entry:]) == 0x0112 {
+			if v := int(order.Uint16(tiff[entry+8:])); v >= 1 && v <= 8 {
+				return v
+			}
+			return 1
+		}
+	}
+	return 1
+}
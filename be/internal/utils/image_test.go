@@ -0,0 +1,131 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testImage(w, h int) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 80, A: 255})
+		}
+	}
+	// a red top-left corner shows where the image ends up after turning it
+	for y := 0; y < 16; y++ {
+		for x := 0; x < 16; x++ {
+			img.Set(x, y, color.RGBA{R: 255, A: 255})
+		}
+	}
+	return img
+}
+
+// withExif inserts an APP1 block with the orientation tag and a GPS look-alike after the SOI marker
+func withExif(jpg []byte, orientation uint16) []byte {
+	var tiff bytes.Buffer
+	tiff.WriteString("II")
+	binary.Write(&tiff, binary.LittleEndian, uint16(42))
+	binary.Write(&tiff, binary.LittleEndian, uint32(8))
+	binary.Write(&tiff, binary.LittleEndian, uint16(1))
+	binary.Write(&tiff, binary.LittleEndian, []uint16{0x0112, 3})
+	binary.Write(&tiff, binary.LittleEndian, uint32(1))
+	binary.Write(&tiff, binary.LittleEndian, []uint16{orientation, 0})
+	binary.Write(&tiff, binary.LittleEndian, uint32(0))
+	tiff.WriteString("GPS-6.2088,106.8456")
+
+	segment := append([]byte("Exif\x00\x00"), tiff.Bytes()...)
+	var out bytes.Buffer
+	out.Write(jpg[:2])
+	out.Write([]byte{0xFF, 0xE1})
+	binary.Write(&out, binary.BigEndian, uint16(len(segment)+2))
+	out.Write(segment)
+	out.Write(jpg[2:])
+	return out.Bytes()
+}
+
+func TestProcessImage(t *testing.T) {
+	t.Run("png variants keep the aspect ratio", func(t *testing.T) {
+		var buf bytes.Buffer
+		assert.NoError(t, png.Encode(&buf, testImage(2000, 1000)))
+
+		res, err := ProcessImage(&buf, 10<<20)
+		assert.NoError(t, err)
+		assert.Equal(t, "image/png", res.Original.ContentType)
+		assert.Equal(t, ".png", res.Thumbnail.Ext)
+
+		for _, v := range []struct {
+			variant ImageVariant
+			w, h    int
+		}{{res.Original, 2000, 1000}, {res.Display, 1280, 640}, {res.Thumbnail, 320, 160}} {
+			cfg, err := png.DecodeConfig(bytes.NewReader(v.variant.Data))
+			assert.NoError(t, err)
+			assert.Equal(t, v.w, cfg.Width)
+			assert.Equal(t, v.h, cfg.Height)
+		}
+	})
+
+	t.Run("jpeg metadata is stripped and the orientation applied", func(t *testing.T) {
+		var buf bytes.Buffer
+		assert.NoError(t, jpeg.Encode(&buf, testImage(200, 100), &jpeg.Options{Quality: 100}))
+		data := withExif(buf.Bytes(), 6)
+		assert.Equal(t, 6, jpegOrientation(data))
+
+		res, err := ProcessImage(bytes.NewReader(data), 10<<20)
+		assert.NoError(t, err)
+		assert.Equal(t, "image/jpeg", res.Original.ContentType)
+		assert.Equal(t, 100, res.Width)
+		assert.Equal(t, 200, res.Height)
+
+		for _, v := range []ImageVariant{res.Original, res.Display, res.Thumbnail} {
+			assert.False(t, bytes.Contains(v.Data, []byte("Exif")))
+			assert.False(t, bytes.Contains(v.Data, []byte("GPS")))
+			assert.Equal(t, 1, jpegOrientation(v.Data))
+		}
+
+		// turned clockwise, the red corner moves to the top right
+		img, err := jpeg.Decode(bytes.NewReader(res.Original.Data))
+		assert.NoError(t, err)
+		r, g, _, _ := img.At(92, 8).RGBA()
+		assert.Greater(t, r>>8, uint32(200))
+		assert.Less(t, g>>8, uint32(60))
+	})
+
+	t.Run("not an image", func(t *testing.T) {
+		_, err := ProcessImage(strings.NewReader("<svg onload=alert(1)></svg>"), 10<<20)
+		assert.ErrorIs(t, err, ErrUnsupportedImage)
+
+		// a jpeg signature with garbage after it
+		_, err = ProcessImage(bytes.NewReader([]byte{0xFF, 0xD8, 0xFF, 0xE0, 0, 0, 1, 2, 3}), 10<<20)
+		assert.ErrorIs(t, err, ErrUnsupportedImage)
+	})
+
+	t.Run("file over the byte limit", func(t *testing.T) {
+		var buf bytes.Buffer
+		assert.NoError(t, png.Encode(&buf, testImage(64, 64)))
+		_, err := ProcessImage(&buf, 100)
+		assert.ErrorIs(t, err, ErrImageTooLarge)
+	})
+
+	t.Run("small file claiming huge dimensions", func(t *testing.T) {
+		var buf bytes.Buffer
+		assert.NoError(t, png.Encode(&buf, testImage(8, 8)))
+		data := buf.Bytes()
+		// IHDR holds the width and height right after the signature, its checksum covers type and data
+		binary.BigEndian.PutUint32(data[16:], 10000)
+		binary.BigEndian.PutUint32(data[20:], 10000)
+		binary.BigEndian.PutUint32(data[29:], crc32.ChecksumIEEE(data[12:29]))
+
+		_, err := ProcessImage(bytes.NewReader(data), 10<<20)
+		assert.ErrorIs(t, err, ErrImageTooLarge)
+	})
+}
@@ -3,35 +3,35 @@ package utils
 import (
 	"log"
 	"os"
-	"strconv"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 )
 
 // for generate and validate jwt token
-func GenerateJwtToken(email, role string, id int) (string, error) {
-	expired_jwt := os.Getenv("EXPIRED_JWT")
-	expired_jwt_int, err := strconv.Atoi(expired_jwt)
-	if err != nil {
-		log.Println("error parsing expired_jwt to int")
-		return "", err
-	}
-	expired := time.Now().Add(time.Hour * time.Duration(expired_jwt_int)).Unix()
-	jwt_claim := jwt.NewWithClaims(jwt.SigningMethodHS256, 
-	jwt.MapClaims{
-		"id": id,
-		"email": email,
-		"role": role,
-		"exp": expired,
-	})
-		
+// the jti identifies the token, so it can be denied before it expires
+// email_verified and the permissions of the role let the routes check without a lookup
+func GenerateJwtToken(email, role string, id int, emailVerified bool, permissions []string, expiresAt time.Time) (tokenString string, jti string, err error) {
+	jti = uuid.NewString()
+	jwt_claim := jwt.NewWithClaims(jwt.SigningMethodHS256,
+		jwt.MapClaims{
+			"id":             id,
+			"email":          email,
+			"role":           role,
+			"email_verified": emailVerified,
+			"permissions":    permissions,
+			"jti":            jti,
+			"iat":            time.Now().Unix(),
+			"exp":            expiresAt.Unix(),
+		})
+
 	secret_key := os.Getenv("SECRET_KEY")
-	tokenString, err := jwt_claim.SignedString([]byte(secret_key))
+	tokenString, err = jwt_claim.SignedString([]byte(secret_key))
 	if err != nil {
 		log.Println("error signed string jwt")
-		return "", err
+		return "", "", err
 	}
 
-	return tokenString, nil
-}
\ No newline at end of file
+	return tokenString, jti, nil
+}
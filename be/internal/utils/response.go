@@ -92,6 +92,13 @@ func BadRequestResponse(c echo.Context, message string) error {
 	return sendResponse(c, http.StatusBadRequest, "error", message, nil)
 }
 
+// BadRequestResponseWithData sends a 400 Bad Request error response that carries extra details
+// Example usage:
+// return utils.BadRequestResponseWithData(c, "bid too low", map[string]interface{}{"min_next_bid": 110000})
+func BadRequestResponseWithData(c echo.Context, message string, data interface{}) error {
+	return sendResponse(c, http.StatusBadRequest, "error", message, data)
+}
+
 // UnauthorizedResponse sends a standard error response with HTTP status 401 Unauthorized
 // Example usage:
 // return utils.UnauthorizedResponse(c, "Unauthorized access")
@@ -120,6 +127,13 @@ func ConflictResponse(c echo.Context, message string) error {
 	return sendResponse(c, http.StatusConflict, "error", message, nil)
 }
 
+// ConflictResponseWithData sends a 409 Conflict error response that carries extra details
+// Example usage:
+// return utils.ConflictResponseWithData(c, "you are already the highest bidder", details)
+func ConflictResponseWithData(c echo.Context, message string, data interface{}) error {
+	return sendResponse(c, http.StatusConflict, "error", message, data)
+}
+
 // UnprocessableEntityResponse sends a standard error response with HTTP status 422 Unprocessable Entity
 // Example usage:
 // return utils.UnprocessableEntityResponse(c, "Unprocessable entity")
@@ -0,0 +1,3 @@
+-- bids now keeps every accepted bid instead of only the winning one
+CREATE INDEX IF NOT EXISTS idx_bids_auction_item_created_at ON bids (auction_item_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_bids_user_id ON bids (user_id);
@@ -0,0 +1,6 @@
+-- items closing below the reserve end as unsold instead of finished
+ALTER TYPE auction_item_status ADD VALUE IF NOT EXISTS 'unsold';
+
+ALTER TABLE auction_items
+    ADD COLUMN IF NOT EXISTS reserve_price INT NULL,
+    ADD COLUMN IF NOT EXISTS buy_now_price INT NULL;
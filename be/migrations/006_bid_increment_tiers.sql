@@ -0,0 +1,17 @@
+-- bid increment ladder, rows without session and item are the global default
+CREATE TABLE IF NOT EXISTS bid_increment_tiers (
+    id SERIAL PRIMARY KEY,
+    session_id INT NULL REFERENCES auction_sessions(id) ON DELETE CASCADE,
+    auction_item_id INT NULL REFERENCES auction_items(id) ON DELETE CASCADE,
+    min_price INT NOT NULL,
+    increment INT NOT NULL DEFAULT 0,
+    percent NUMERIC(5, 2) NOT NULL DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS idx_bid_increment_tiers_session_id ON bid_increment_tiers (session_id);
+CREATE INDEX IF NOT EXISTS idx_bid_increment_tiers_auction_item_id ON bid_increment_tiers (auction_item_id);
+
+-- default ladder: 5% below Rp 1.000.000, a fixed Rp 50.000 from there
+INSERT INTO bid_increment_tiers (min_price, increment, percent) VALUES
+    (0, 0, 5),
+    (1000000, 50000, 0);
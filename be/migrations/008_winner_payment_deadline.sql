@@ -0,0 +1,9 @@
+-- winners who do not pay before due_at are defaulted by the deadline job
+ALTER TYPE payment_status ADD VALUE IF NOT EXISTS 'failed';
+ALTER TYPE payment_status ADD VALUE IF NOT EXISTS 'defaulted';
+
+ALTER TABLE payments
+    ADD COLUMN IF NOT EXISTS due_at TIMESTAMP NULL;
+
+CREATE INDEX IF NOT EXISTS idx_payments_pending_due_at ON payments (due_at) WHERE due_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_payments_auction_item_user ON payments (auction_item_id, user_id);
@@ -0,0 +1,4 @@
+-- a paid winner payment moves its finished item to sold
+ALTER TYPE auction_item_status ADD VALUE IF NOT EXISTS 'sold';
+
+CREATE INDEX IF NOT EXISTS idx_payments_order_id ON payments (order_id);
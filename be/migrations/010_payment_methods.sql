@@ -0,0 +1,3 @@
+-- the method the payer chose: qris, a bank virtual account, an e-wallet or a card through snap
+ALTER TABLE payments
+    ADD COLUMN IF NOT EXISTS payment_method VARCHAR(32) NULL;
@@ -0,0 +1,32 @@
+-- 'git' was a typo in 001_init that nothing writes, it becomes refund_pending
+UPDATE payments SET status = 'failed' WHERE status::text = 'git';
+
+DO $$
+BEGIN
+    IF EXISTS (
+        SELECT 1 FROM pg_enum e JOIN pg_type t ON t.oid = e.enumtypid
+        WHERE t.typname = 'payment_status' AND e.enumlabel = 'git'
+    ) THEN
+        ALTER TYPE payment_status RENAME VALUE 'git' TO 'refund_pending';
+    END IF;
+END $$;
+
+ALTER TYPE payment_status ADD VALUE IF NOT EXISTS 'refund_pending';
+ALTER TYPE payment_status ADD VALUE IF NOT EXISTS 'refunded';
+
+-- full or partial refunds issued by an admin, a payment can be refunded in several parts
+CREATE TABLE IF NOT EXISTS payment_refunds (
+    id SERIAL PRIMARY KEY,
+    payment_id INT NOT NULL REFERENCES payments(id),
+    amount INT NOT NULL CHECK (amount > 0),
+    reason TEXT NOT NULL,
+    actor_id INT NOT NULL REFERENCES users(id),
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    gateway_status VARCHAR(50),
+    last_error TEXT,
+    reopen_item BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP DEFAULT NOW(),
+    refunded_at TIMESTAMP NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_payment_refunds_payment_id ON payment_refunds (payment_id);
@@ -0,0 +1,18 @@
+-- findings of the payment reconciliation job, downloaded by finance as a monthly report.
+-- a finding is recorded once per order, outcome and gateway status however often the job sees it
+CREATE TABLE IF NOT EXISTS payment_reconciliations (
+    id BIGSERIAL PRIMARY KEY,
+    payment_id INT NULL REFERENCES payments(id),
+    order_id VARCHAR(200) NOT NULL,
+    outcome VARCHAR(30) NOT NULL,
+    local_status VARCHAR(30),
+    gateway_status VARCHAR(50) NOT NULL DEFAULT '',
+    local_amount INT,
+    gateway_amount INT,
+    applied_status VARCHAR(30),
+    created_at TIMESTAMP DEFAULT NOW(),
+    UNIQUE (order_id, outcome, gateway_status)
+);
+
+CREATE INDEX IF NOT EXISTS idx_payment_reconciliations_created_at ON payment_reconciliations (created_at);
+CREATE INDEX IF NOT EXISTS idx_payments_pending_attempts ON payments (id) WHERE status = 'pending' AND due_at IS NULL;
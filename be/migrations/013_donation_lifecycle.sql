@@ -0,0 +1,24 @@
+-- donations are picked up and inspected before the verification decision, and end auctioned,
+-- distributed, rejected or cancelled by the donor
+ALTER TYPE donation_status ADD VALUE IF NOT EXISTS 'rejected';
+ALTER TYPE donation_status ADD VALUE IF NOT EXISTS 'awaiting_pickup';
+ALTER TYPE donation_status ADD VALUE IF NOT EXISTS 'received';
+ALTER TYPE donation_status ADD VALUE IF NOT EXISTS 'auctioned';
+ALTER TYPE donation_status ADD VALUE IF NOT EXISTS 'distributed';
+ALTER TYPE donation_status ADD VALUE IF NOT EXISTS 'cancelled_by_donor';
+
+ALTER TABLE donations ADD COLUMN IF NOT EXISTS rejection_reason TEXT;
+
+-- every status transition, the donor can follow their donation through it
+CREATE TABLE IF NOT EXISTS donation_status_history (
+    id SERIAL PRIMARY KEY,
+    donation_id INT NOT NULL REFERENCES donations(id) ON DELETE CASCADE,
+    from_status donation_status NOT NULL,
+    to_status donation_status NOT NULL,
+    reason TEXT,
+    actor_id INT NOT NULL REFERENCES users(id),
+    actor_role VARCHAR(20) NOT NULL,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_donation_status_history_donation ON donation_status_history (donation_id, created_at);
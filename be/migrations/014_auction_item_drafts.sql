@@ -0,0 +1,46 @@
+-- a donation verified for auction gets a draft auction item, an admin reviews it and schedules it
+ALTER TYPE auction_item_status ADD VALUE IF NOT EXISTS 'draft';
+
+-- one auction item per donation, and never without one. which item a donation keeps is not ours to
+-- guess, so existing rows that break this stop the migration until an admin fixes them
+DO $$
+DECLARE
+    missing INT;
+    duplicated INT;
+    dangling INT;
+BEGIN
+    SELECT COUNT(*) INTO missing FROM auction_items WHERE donation_id IS NULL;
+    SELECT COUNT(*) INTO duplicated FROM (
+        SELECT donation_id FROM auction_items WHERE donation_id IS NOT NULL GROUP BY donation_id HAVING COUNT(*) > 1
+    ) d;
+    SELECT COUNT(*) INTO dangling FROM auction_items i
+        WHERE i.donation_id IS NOT NULL AND NOT EXISTS (SELECT 1 FROM donations d WHERE d.id = i.donation_id);
+
+    IF missing > 0 OR duplicated > 0 OR dangling > 0 THEN
+        RAISE EXCEPTION 'auction_items.donation_id must be set, unique and point at a donation: % item(s) without a donation, % donation(s) with more than one item, % item(s) of a missing donation. Link or delete these items, then migrate again', missing, duplicated, dangling;
+    END IF;
+END $$;
+
+ALTER TABLE auction_items ALTER COLUMN donation_id SET NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS uq_auction_items_donation_id ON auction_items (donation_id);
+
+DO $$
+BEGIN
+    IF NOT EXISTS (
+        SELECT 1 FROM pg_constraint
+        WHERE conrelid = 'auction_items'::regclass AND contype = 'f'
+          AND confrelid = 'donations'::regclass
+    ) THEN
+        ALTER TABLE auction_items
+            ADD CONSTRAINT auction_items_donation_id_fkey FOREIGN KEY (donation_id) REFERENCES donations(id);
+    END IF;
+END $$;
+
+-- photos copied from the donation
+CREATE TABLE IF NOT EXISTS auction_item_photos (
+    id SERIAL PRIMARY KEY,
+    auction_item_id INT NOT NULL REFERENCES auction_items(id) ON DELETE CASCADE,
+    url VARCHAR(255)
+);
+
+CREATE INDEX IF NOT EXISTS idx_auction_item_photos_item ON auction_item_photos (auction_item_id);
@@ -0,0 +1,9 @@
+-- uploads are re-encoded without metadata and stored with a display and a thumbnail variant
+ALTER TABLE donation_photos
+    ADD COLUMN IF NOT EXISTS thumbnail_url VARCHAR(255),
+    ADD COLUMN IF NOT EXISTS display_url VARCHAR(255);
+
+ALTER TABLE articles
+    ADD COLUMN IF NOT EXISTS image TEXT,
+    ADD COLUMN IF NOT EXISTS image_thumbnail TEXT,
+    ADD COLUMN IF NOT EXISTS image_display TEXT;
@@ -0,0 +1,68 @@
+-- roles hold permissions, a user holds one role by name; verifiers review donations and
+-- institutions confirm the ones they receive
+CREATE TABLE IF NOT EXISTS roles (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(32) NOT NULL
+);
+ALTER TABLE roles ADD COLUMN IF NOT EXISTS description TEXT;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_roles_name ON roles (name);
+
+INSERT INTO roles (name, description) VALUES
+    ('user', 'Donates and bids'),
+    ('verifier', 'Picks up, inspects and verifies donations'),
+    ('institution', 'Receives the donations verified for donation'),
+    ('admin', 'Runs the platform')
+ON CONFLICT (name) DO UPDATE SET description = EXCLUDED.description;
+
+-- users.role held the id of the role, it now holds the name
+DO $$
+BEGIN
+    IF (SELECT data_type FROM information_schema.columns
+        WHERE table_name = 'users' AND column_name = 'role') = 'integer' THEN
+        ALTER TABLE users DROP CONSTRAINT IF EXISTS users_role_fkey;
+        ALTER TABLE users ADD COLUMN role_name VARCHAR(32);
+        UPDATE users SET role_name = roles.name FROM roles WHERE roles.id = users.role;
+        ALTER TABLE users DROP COLUMN role;
+        ALTER TABLE users RENAME COLUMN role_name TO role;
+    END IF;
+END $$;
+
+UPDATE users SET role = 'user' WHERE role IS NULL OR role NOT IN (SELECT name FROM roles);
+ALTER TABLE users ALTER COLUMN role SET DEFAULT 'user';
+ALTER TABLE users ALTER COLUMN role SET NOT NULL;
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_role_name_fkey;
+ALTER TABLE users ADD CONSTRAINT users_role_name_fkey
+    FOREIGN KEY (role) REFERENCES roles (name) ON UPDATE CASCADE ON DELETE RESTRICT;
+
+CREATE TABLE IF NOT EXISTS permissions (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(64) NOT NULL UNIQUE,
+    description TEXT
+);
+
+INSERT INTO permissions (name, description) VALUES
+    ('donation:read_all', 'List and read every donation'),
+    ('donation:verify', 'Move donations through pickup, inspection and verification'),
+    ('donation:distribute', 'Confirm a donation reached its institution'),
+    ('donation:manage', 'Edit and delete any donation'),
+    ('final_donation:read_all', 'List every final donation'),
+    ('auction:manage', 'Manage auction items, sessions and bid increments'),
+    ('article:manage', 'Publish, edit and delete articles'),
+    ('payment:refund', 'Refund payments'),
+    ('report:read', 'Read the dashboard and reports'),
+    ('role:assign', 'List roles and assign them to users')
+ON CONFLICT (name) DO UPDATE SET description = EXCLUDED.description;
+
+CREATE TABLE IF NOT EXISTS role_permissions (
+    role_id INT NOT NULL REFERENCES roles(id) ON DELETE CASCADE,
+    permission_id INT NOT NULL REFERENCES permissions(id) ON DELETE CASCADE,
+    PRIMARY KEY (role_id, permission_id)
+);
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT roles.id, permissions.id
+FROM roles JOIN permissions ON
+    roles.name = 'admin'
+    OR (roles.name = 'verifier' AND permissions.name IN ('donation:read_all', 'donation:verify'))
+    OR (roles.name = 'institution' AND permissions.name IN ('donation:distribute', 'final_donation:read_all'))
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,16 @@
+-- phone and address are where won items are shipped to. a deleted account keeps its row so bids,
+-- donations and payments still point at it, with everything personal wiped and deleted_at set
+ALTER TABLE users ADD COLUMN IF NOT EXISTS phone VARCHAR(32) NOT NULL DEFAULT '';
+ALTER TABLE users ADD COLUMN IF NOT EXISTS address TEXT NOT NULL DEFAULT '';
+ALTER TABLE users ADD COLUMN IF NOT EXISTS suspended_at TIMESTAMP;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS suspension_reason TEXT NOT NULL DEFAULT '';
+ALTER TABLE users ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;
+
+INSERT INTO permissions (name, description) VALUES
+    ('user:manage', 'List, search and suspend users')
+ON CONFLICT (name) DO UPDATE SET description = EXCLUDED.description;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT roles.id, permissions.id
+FROM roles JOIN permissions ON roles.name = 'admin' AND permissions.name = 'user:manage'
+ON CONFLICT DO NOTHING;
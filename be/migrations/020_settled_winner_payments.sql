@@ -0,0 +1,3 @@
+-- a winner payment is settled once the winner paid the item through a charge attempt,
+-- the deadline job only looks at winner payments still pending
+ALTER TYPE payment_status ADD VALUE IF NOT EXISTS 'settled';
@@ -0,0 +1,3 @@
+-- a partly refunded payment stays partially_refunded until all of it is returned, payments
+-- refunded in part before this are moved to it in 022 once the new value is committed
+ALTER TYPE payment_status ADD VALUE IF NOT EXISTS 'partially_refunded';
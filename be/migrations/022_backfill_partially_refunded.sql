@@ -0,0 +1,4 @@
+-- payments refunded in part before 021 were marked refunded
+UPDATE payments p SET status = 'partially_refunded'
+WHERE p.status = 'refunded'
+  AND (SELECT COALESCE(SUM(r.amount), 0) FROM payment_refunds r WHERE r.payment_id = p.id AND r.status = 'refunded') < p.amount;
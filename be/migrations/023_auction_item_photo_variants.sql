@@ -0,0 +1,17 @@
+-- photos copied from a donation are object names in the private bucket, signed on every response.
+-- bidders get the display and thumbnail variants instead of the original upload
+ALTER TABLE auction_item_photos
+    ADD COLUMN IF NOT EXISTS thumbnail_url VARCHAR(255),
+    ADD COLUMN IF NOT EXISTS private BOOLEAN NOT NULL DEFAULT FALSE;
+
+UPDATE auction_item_photos SET private = TRUE
+WHERE url NOT LIKE 'http://%' AND url NOT LIKE 'https://%';
+
+UPDATE auction_item_photos aip
+SET url = COALESCE(NULLIF(dp.display_url, ''), aip.url),
+    thumbnail_url = dp.thumbnail_url
+FROM auction_items ai, donation_photos dp
+WHERE ai.id = aip.auction_item_id
+  AND dp.donation_id = ai.donation_id
+  AND dp.url = aip.url
+  AND aip.private;
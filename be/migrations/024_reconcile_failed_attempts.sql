@@ -0,0 +1,2 @@
+-- the reconciliation job checks failed and expired attempts again for a week, the gateway can still settle them
+CREATE INDEX IF NOT EXISTS idx_payments_failed_attempts ON payments (created_at) WHERE status = 'failed' AND due_at IS NULL;